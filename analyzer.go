@@ -0,0 +1,141 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"fmt"
+	"time"
+)
+
+// Verdict is the outcome of an Analyzer for one evaluation cycle.
+type Verdict string
+
+const (
+	// VerdictContinue lets the session go on unchanged.
+	VerdictContinue Verdict = "continue"
+	// VerdictStepDown keeps the session but marks it as stepped down, for the
+	// enforcement point to restrict it, e.g. to read-only access.
+	VerdictStepDown Verdict = "step-down"
+	// VerdictRevoke revokes the session.
+	VerdictRevoke Verdict = "revoke"
+)
+
+// AttrSteppedDown is the session attribute set to true when an Analyzer steps
+// a session down. Conditions such as attribute_equals can test it.
+const AttrSteppedDown = "stepped_down"
+
+// EventSessionSteppedDown is emitted when an Analyzer steps a session down.
+const EventSessionSteppedDown EventType = "session_stepped_down"
+
+// UsageFeatures describes a session's usage at one evaluation cycle.
+type UsageFeatures struct {
+	SessionID  string
+	Subject    string
+	Action     string
+	Object     string
+	Purpose    string
+	Attributes map[string]interface{}
+	// Duration is how long the session has been in use.
+	Duration time.Duration
+	// Cycle counts the evaluation cycles of the session, starting at 1.
+	Cycle int
+	// FailingConditions lists the conditions failing within their dwell time.
+	FailingConditions []string
+	// Environment holds the environment attributes, e.g. the threat level.
+	Environment map[string]interface{}
+	Time        time.Time
+}
+
+// Analyzer inspects a session on every monitoring cycle, after its conditions
+// passed, e.g. a behavioral anomaly detector or an ML model. An error is
+// logged and treated as VerdictContinue, so an unavailable model does not
+// revoke every session.
+type Analyzer interface {
+	Analyze(features UsageFeatures) (Verdict, error)
+}
+
+// AnalyzerFunc adapts a function to the Analyzer interface.
+type AnalyzerFunc func(features UsageFeatures) (Verdict, error)
+
+// Analyze implements Analyzer.
+func (f AnalyzerFunc) Analyze(features UsageFeatures) (Verdict, error) {
+	return f(features)
+}
+
+// WithAnalyzer sets the analyzer invoked on each monitoring cycle.
+func WithAnalyzer(analyzer Analyzer) Option {
+	return func(u *UconEnforcer) {
+		u.analyzer = analyzer
+	}
+}
+
+// analyzeSession runs the analyzer for one cycle and returns whether the
+// session must be revoked.
+func (u *UconEnforcer) analyzeSession(session *Session, now time.Time, cycle int, trace *TraceEntry) bool {
+	if u.analyzer == nil {
+		return false
+	}
+	record := session.ToRecord()
+	features := UsageFeatures{
+		SessionID:  record.ID,
+		Subject:    record.Subject,
+		Action:     record.Action,
+		Object:     record.Object,
+		Purpose:    record.Purpose,
+		Attributes: record.Attributes,
+		Duration:   now.Sub(record.StartTime),
+		Cycle:      cycle,
+		Time:       now,
+	}
+	environment, err := u.GetEnvironmentAttributes()
+	if err != nil {
+		u.logger.Log(LevelWarn, "failed to get environment attributes", map[string]interface{}{"session": record.ID, "error": err.Error()})
+	}
+	features.Environment = environment
+	for _, result := range trace.Conditions {
+		if !result.Passed {
+			features.FailingConditions = append(features.FailingConditions, result.ID)
+		}
+	}
+
+	verdict, err := u.analyzer.Analyze(features)
+	if err != nil {
+		u.logger.Log(LevelWarn, "analyzer failed", map[string]interface{}{"session": record.ID, "error": err.Error()})
+		verdict = VerdictContinue
+	}
+	trace.Verdict = verdict
+
+	switch verdict {
+	case VerdictRevoke:
+		return true
+	case VerdictStepDown:
+		if session.GetAttribute(AttrSteppedDown) == true {
+			return false
+		}
+		if err := session.UpdateAttribute(AttrSteppedDown, true); err != nil {
+			u.logger.Log(LevelWarn, "failed to step down session", map[string]interface{}{"session": record.ID, "error": err.Error()})
+			return false
+		}
+		u.emit(Event{
+			Type:      EventSessionSteppedDown,
+			SessionID: record.ID,
+			Message:   fmt.Sprintf("session %s stepped down by analyzer", record.ID),
+		})
+	case VerdictContinue:
+	default:
+		u.logger.Log(LevelWarn, "unknown analyzer verdict", map[string]interface{}{"session": record.ID, "verdict": string(verdict)})
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAnalyzer(t *testing.T) {
+	var mu sync.Mutex
+	var seen []UsageFeatures
+	analyzer := AnalyzerFunc(func(f UsageFeatures) (Verdict, error) {
+		mu.Lock()
+		seen = append(seen, f)
+		mu.Unlock()
+		switch f.Cycle {
+		case 1:
+			return "", errors.New("model unavailable")
+		case 2, 3:
+			return VerdictStepDown, nil
+		default:
+			return VerdictRevoke, nil
+		}
+	})
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithAnalyzer(analyzer))
+
+	stepDowns := 0
+	uconE.AddEventListener(func(event Event) {
+		if event.Type == EventSessionSteppedDown {
+			mu.Lock()
+			stepDowns++
+			mu.Unlock()
+		}
+	})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"role": "user"})
+	session, _ := uconE.EnforceWithSession(sessionID)
+	if session == nil {
+		t.Fatal("Expected access")
+	}
+	time.Sleep(1100 * time.Millisecond)
+	if session.IfActive() {
+		_ = uconE.StopMonitoring(sessionID)
+		t.Fatal("Expected the analyzer to revoke the session")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(seen) != 4 || seen[0].Subject != "alice" || seen[0].Attributes["role"] != "user" {
+		t.Fatalf("Unexpected features %+v", seen)
+	}
+	if seen[3].Duration <= seen[0].Duration {
+		t.Error("Expected the usage duration to grow")
+	}
+	if stepDowns != 1 || session.GetAttribute(AttrSteppedDown) != true {
+		t.Errorf("Expected one step-down, got %d", stepDowns)
+	}
+
+	trace, _ := uconE.GetSessionTrace(sessionID)
+	if len(trace) != 4 || trace[0].Verdict != VerdictContinue || trace[1].Verdict != VerdictStepDown {
+		t.Fatalf("Unexpected trace %+v", trace)
+	}
+	if trace[3].Verdict != VerdictRevoke || trace[3].StopReason == "" {
+		t.Errorf("Expected the last entry to record the revocation, got %+v", trace[3])
+	}
+}
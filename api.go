@@ -0,0 +1,747 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AdminRevokeReason is the stop reason of sessions revoked through the REST API
+// when the request does not give one.
+const AdminRevokeReason = "revoked by administrator"
+
+// Defaults of the window and limit query parameters of the /stats routes.
+const (
+	DefaultStatsWindow = time.Hour
+	DefaultStatsLimit  = 10
+)
+
+// CreateSessionRequest is the body of POST /sessions.
+type CreateSessionRequest struct {
+	Subject    string                 `json:"subject"`
+	Action     string                 `json:"action"`
+	Object     string                 `json:"object"`
+	Purpose    string                 `json:"purpose,omitempty"`
+	Attributes map[string]interface{} `json:"attributes"`
+	// BreakGlass, if set, is the justification of a break-glass session,
+	// see CreateBreakGlassSession.
+	BreakGlass string `json:"break_glass,omitempty"`
+}
+
+// BreakGlassReviewRequest is the body of POST /break-glass/reviews/{id}.
+type BreakGlassReviewRequest struct {
+	Reviewer string `json:"reviewer"`
+	Notes    string `json:"notes,omitempty"`
+}
+
+// EnforceResponse is the body returned by POST /sessions/{id}/enforce.
+type EnforceResponse struct {
+	Allowed    bool           `json:"allowed"`
+	Reason     string         `json:"reason,omitempty"`
+	Code       ReasonCode     `json:"code,omitempty"`
+	Session    *SessionRecord `json:"session,omitempty"`
+	Directives []Directive    `json:"directives,omitempty"`
+	Degraded   bool           `json:"degraded,omitempty"`
+	// RetryAfter and MissingAttributes are the hints of a denial, see Decision.
+	RetryAfter        *time.Time `json:"retry_after,omitempty"`
+	MissingAttributes []string   `json:"missing_attributes,omitempty"`
+}
+
+// FulfillRequest is the body of POST /sessions/{id}/fulfill.
+type FulfillRequest struct {
+	ObligationID string                 `json:"obligation_id"`
+	Evidence     map[string]interface{} `json:"evidence,omitempty"`
+}
+
+// ApproveRequest is the body of POST /sessions/{id}/approve.
+type ApproveRequest struct {
+	Approver string `json:"approver"`
+}
+
+// RevokeSessionsRequest is the body of POST /revocations. The reason
+// defaults to AdminRevokeReason.
+type RevokeSessionsRequest struct {
+	SessionSelector
+	Reason string `json:"reason,omitempty"`
+}
+
+// RejectRequest is the body of POST /sessions/{id}/reject.
+type RejectRequest struct {
+	Approver string `json:"approver"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// RevokeRequest is the optional body of POST /sessions/{id}/revoke.
+type RevokeRequest struct {
+	Reason string `json:"reason"`
+}
+
+// RevokeMySessionsRequest is the optional body of
+// POST /subjects/{sub}/sessions/revoke; without session ids every session of
+// the subject is revoked.
+type RevokeMySessionsRequest struct {
+	Reason     string   `json:"reason,omitempty"`
+	SessionIDs []string `json:"session_ids,omitempty"`
+}
+
+// RolloutRequest is the body of PUT /conditions/{id}/rollout and
+// PUT /obligations/{id}/rollout.
+type RolloutRequest struct {
+	Percent int `json:"percent"`
+}
+
+type apiHandler struct {
+	e IUconEnforcer
+}
+
+// NewAPIHandler returns an http.Handler exposing the enforcer as a JSON REST API:
+//
+//	GET    /sessions                   list sessions
+//	POST   /sessions                   create a session, or a break-glass session (CreateSessionRequest)
+//	GET    /sessions/{id}              get a session
+//	DELETE /sessions/{id}              delete a stopped session
+//	POST   /sessions/{id}/enforce      Decide (EnforceResponse)
+//	PATCH  /sessions/{id}/attributes   update attributes from a JSON object
+//	POST   /sessions/{id}/stop         StopMonitoring
+//	POST   /sessions/{id}/heartbeat    Heartbeat
+//	POST   /sessions/{id}/fulfill      acknowledge an obligation (FulfillRequest)
+//	GET    /sessions/{id}/fulfillments caller-fulfilled obligations and their deadlines
+//	POST   /sessions/{id}/approve      four-eyes approval of the session (ApproveRequest)
+//	POST   /sessions/{id}/reject       reject the four-eyes approval of the session (RejectRequest)
+//	GET    /sessions/{id}/trace        last monitoring evaluations (GetSessionTrace)
+//	GET    /sessions/{id}/rules        conditions and obligations applying to the session (EffectiveRules)
+//	POST   /sessions/{id}/revoke       stop a session with a reason (RevokeRequest)
+//	GET    /subjects/{sub}/sessions    the subject's active sessions, redacted (GetMySessions)
+//	POST   /subjects/{sub}/sessions/revoke
+//	                                   sign the subject out (RevokeMySessionsRequest)
+//	GET    /break-glass/reviews        break-glass review records; ?pending=true for outstanding ones
+//	POST   /break-glass/reviews/{id}   review a break-glass session (BreakGlassReviewRequest)
+//	GET    /approvals                  sessions waiting for four-eyes approval
+//	POST   /revocations/preview        what revoking the selected sessions would affect (SessionSelector, RevocationImpact)
+//	POST   /revocations                revoke the selected sessions (RevokeSessionsRequest)
+//	GET    /objects/{obj}/usage        usage ledger of the object; ?from=&to= in RFC 3339 (GetObjectUsageHistory)
+//	GET    /conditions                 list conditions
+//	POST   /conditions                 add or replace a condition
+//	DELETE /conditions/{id}            remove a condition
+//	PUT    /conditions/{id}/rollout    enforce a condition for a percentage of subjects (RolloutRequest)
+//	GET    /obligations                list obligations
+//	POST   /obligations                add or replace an obligation
+//	DELETE /obligations/{id}           remove an obligation
+//	PUT    /obligations/{id}/rollout   enforce an obligation for a percentage of subjects (RolloutRequest)
+//	GET    /stats                      dashboard aggregates (DashboardStats)
+//	GET    /stats/active-by-object     active sessions by object
+//	GET    /stats/revocations          revocations in the window by reason
+//	GET    /stats/top-subjects         subjects with the most sessions
+//	GET    /metrics                    latency histograms (MetricsSnapshot)
+//	GET    /providers                  attribute provider health
+//	POST   /telemetry                  ingest a batch of telemetry reports ([]TelemetryReport)
+//	GET    /telemetry/mappings         list telemetry mappings
+//	POST   /telemetry/mappings         add or replace a telemetry mapping
+//	POST   /simulate                   would a session be allowed right now (CreateSessionRequest, SimulationResult)
+//	GET    /config                     export the configuration (Config)
+//	PUT    /config                     import a configuration (Config); with
+//	                                   ?dry_run=true, return what it would change (ConfigDiff)
+//
+// The stats routes accept ?window=<duration> (default 1h) and ?limit=<n>
+// (default 10).
+//
+// Errors are returned as {"error": "..."}. The handler performs no
+// authentication; protect it before exposing it beyond trusted callers.
+func NewAPIHandler(e IUconEnforcer) http.Handler {
+	return &apiHandler{e: e}
+}
+
+func (h *apiHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case parts[0] == "sessions":
+		h.serveSessions(w, r, parts[1:])
+	case parts[0] == "subjects" && len(parts) >= 3 && len(parts) <= 4 && parts[2] == "sessions":
+		h.serveSubjectSessions(w, r, parts[1], parts[3:])
+	case parts[0] == "break-glass" && len(parts) >= 2 && len(parts) <= 3 && parts[1] == "reviews":
+		h.serveBreakGlassReviews(w, r, parts[2:])
+	case parts[0] == "approvals" && len(parts) == 1 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, h.e.GetPendingApprovals())
+	case parts[0] == "revocations" && len(parts) <= 2 && r.Method == http.MethodPost:
+		h.serveRevocations(w, r, parts[1:])
+	case parts[0] == "objects" && len(parts) == 3 && parts[2] == "usage" && r.Method == http.MethodGet:
+		h.serveObjectUsage(w, r, parts[1])
+	case parts[0] == "conditions" && len(parts) <= 3:
+		h.serveConditions(w, r, parts[1:])
+	case parts[0] == "obligations" && len(parts) <= 3:
+		h.serveObligations(w, r, parts[1:])
+	case parts[0] == "stats" && len(parts) <= 2:
+		h.serveStats(w, r, parts[1:])
+	case parts[0] == "metrics" && len(parts) == 1 && r.Method == http.MethodGet:
+		h.serveMetrics(w)
+	case parts[0] == "providers" && len(parts) == 1 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, h.e.GetProviderHealth())
+	case parts[0] == "telemetry" && len(parts) <= 2:
+		h.serveTelemetry(w, r, parts[1:])
+	case parts[0] == "simulate" && len(parts) == 1 && r.Method == http.MethodPost:
+		h.serveSimulate(w, r)
+	case parts[0] == "config" && len(parts) == 1:
+		h.serveConfig(w, r)
+	default:
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+	}
+}
+
+func (h *apiHandler) serveSessions(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			sessions := h.e.GetSessions()
+			records := make([]*SessionRecord, 0, len(sessions))
+			for _, session := range sessions {
+				records = append(records, session.ToRecord())
+			}
+			writeJSON(w, http.StatusOK, records)
+		case http.MethodPost:
+			req := &CreateSessionRequest{}
+			if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			}
+			if req.Subject == "" || req.Action == "" || req.Object == "" {
+				writeError(w, http.StatusBadRequest, errors.New("subject, action and object are required"))
+				return
+			}
+			var id string
+			var err error
+			if req.BreakGlass != "" {
+				id, err = h.e.CreateBreakGlassSession(req.Subject, req.Action, req.Object, req.BreakGlass, req.Attributes)
+			} else {
+				id, err = h.e.CreateSessionWithPurpose(req.Subject, req.Action, req.Object, req.Purpose, req.Attributes)
+			}
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, err)
+				return
+			}
+			h.writeSession(w, http.StatusCreated, id)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		}
+		return
+	}
+
+	id := parts[0]
+	session, err := h.e.GetSession(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	action := ""
+	if len(parts) > 1 {
+		action = parts[1]
+	}
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, session.ToRecord())
+	case action == "" && r.Method == http.MethodDelete:
+		if err := h.e.RevokeSession(id); err != nil {
+			writeError(w, http.StatusConflict, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case action == "enforce" && r.Method == http.MethodPost:
+		decision, err := h.e.Decide(id)
+		resp := &EnforceResponse{
+			Allowed:           decision.Allowed,
+			Directives:        decision.Directives,
+			Degraded:          decision.Degraded,
+			RetryAfter:        decision.RetryAfter,
+			MissingAttributes: decision.MissingAttributes,
+		}
+		if err != nil {
+			resp.Reason = err.Error()
+		}
+		switch {
+		case decision.Allowed:
+		case !session.IfActive():
+			resp.Code = ReasonForStop(session.GetStopReason())
+		case err != nil:
+			resp.Code = ReasonFor(err)
+		default:
+			resp.Code = ReasonAccessDenied
+		}
+		if decision.Session != nil {
+			resp.Session = decision.Session.ToRecord()
+		}
+		writeJSON(w, http.StatusOK, resp)
+	case action == "attributes" && r.Method == http.MethodPatch:
+		attributes := make(map[string]interface{})
+		if err := json.NewDecoder(r.Body).Decode(&attributes); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := h.e.UpdateSessionAttributes(id, attributes); err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, ErrSessionNotActive) {
+				status = http.StatusConflict
+			}
+			writeError(w, status, err)
+			return
+		}
+		h.writeSession(w, http.StatusOK, id)
+	case action == "stop" && r.Method == http.MethodPost:
+		if err := h.e.StopMonitoring(id); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		h.writeSession(w, http.StatusOK, id)
+	case action == "heartbeat" && r.Method == http.MethodPost:
+		if err := h.e.Heartbeat(id); err != nil {
+			writeError(w, http.StatusConflict, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case action == "fulfill" && r.Method == http.MethodPost:
+		req := &FulfillRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := h.e.FulfillObligation(id, req.ObligationID, req.Evidence); err != nil {
+			writeError(w, http.StatusConflict, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case action == "fulfillments" && r.Method == http.MethodGet:
+		fulfillments, err := h.e.GetObligationFulfillments(id)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, fulfillments)
+	case action == "approve" && r.Method == http.MethodPost:
+		req := &ApproveRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := h.e.ApproveSession(id, req.Approver); err != nil {
+			status := http.StatusConflict
+			if errors.Is(err, ErrUnauthorizedApprover) {
+				status = http.StatusForbidden
+			}
+			writeError(w, status, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case action == "reject" && r.Method == http.MethodPost:
+		req := &RejectRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := h.e.RejectSession(id, req.Approver, req.Reason); err != nil {
+			status := http.StatusConflict
+			if errors.Is(err, ErrUnauthorizedApprover) {
+				status = http.StatusForbidden
+			}
+			writeError(w, status, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case action == "trace" && r.Method == http.MethodGet:
+		trace, err := h.e.GetSessionTrace(id)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, trace)
+	case action == "rules" && r.Method == http.MethodGet:
+		rules, err := h.e.GetEffectiveRules(id)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, rules)
+	case action == "revoke" && r.Method == http.MethodPost:
+		req := &RevokeRequest{}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+		if req.Reason == "" {
+			req.Reason = AdminRevokeReason
+		}
+		if err := session.Stop(req.Reason); err != nil {
+			writeError(w, http.StatusConflict, err)
+			return
+		}
+		h.writeSession(w, http.StatusOK, id)
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("no route for %s %s", r.Method, r.URL.Path))
+	}
+}
+
+func (h *apiHandler) serveSubjectSessions(w http.ResponseWriter, r *http.Request, subject string, parts []string) {
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, h.e.GetMySessions(subject))
+	case len(parts) == 1 && parts[0] == "revoke" && r.Method == http.MethodPost:
+		req := &RevokeMySessionsRequest{}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+		revoked, err := h.e.RevokeMySessions(subject, req.Reason, req.SessionIDs...)
+		if err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]int{"revoked": revoked})
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("no route for %s %s", r.Method, r.URL.Path))
+	}
+}
+
+func (h *apiHandler) serveRevocations(w http.ResponseWriter, r *http.Request, parts []string) {
+	switch {
+	case len(parts) == 1 && parts[0] == "preview":
+		selector := SessionSelector{}
+		if err := json.NewDecoder(r.Body).Decode(&selector); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		impact, err := h.e.PreviewRevocation(selector)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, impact)
+	case len(parts) == 0:
+		req := &RevokeSessionsRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if req.Reason == "" {
+			req.Reason = AdminRevokeReason
+		}
+		revoked, err := h.e.RevokeSessions(req.SessionSelector, req.Reason)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]int{"revoked": revoked})
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("no route for %s %s", r.Method, r.URL.Path))
+	}
+}
+
+func (h *apiHandler) serveObjectUsage(w http.ResponseWriter, r *http.Request, object string) {
+	timeRange := TimeRange{}
+	for param, t := range map[string]**time.Time{"from": &timeRange.From, "to": &timeRange.To} {
+		if value := r.URL.Query().Get(param); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("invalid %s: %w", param, err))
+				return
+			}
+			*t = &parsed
+		}
+	}
+	history, err := h.e.GetObjectUsageHistory(object, timeRange)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, history)
+}
+
+func (h *apiHandler) serveBreakGlassReviews(w http.ResponseWriter, r *http.Request, parts []string) {
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, h.e.GetBreakGlassReviews(r.URL.Query().Get("pending") == "true"))
+	case len(parts) == 1 && r.Method == http.MethodPost:
+		req := &BreakGlassReviewRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		err := h.e.ReviewBreakGlass(parts[0], req.Reviewer, req.Notes)
+		switch {
+		case errors.Is(err, ErrReviewNotFound):
+			writeError(w, http.StatusNotFound, err)
+		case err != nil:
+			writeError(w, http.StatusBadRequest, err)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("no route for %s %s", r.Method, r.URL.Path))
+	}
+}
+
+func (h *apiHandler) serveConditions(w http.ResponseWriter, r *http.Request, parts []string) {
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, h.e.GetConditions())
+	case len(parts) == 0 && r.Method == http.MethodPost:
+		condition := &Condition{}
+		if err := json.NewDecoder(r.Body).Decode(condition); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if condition.ID == "" || condition.Name == "" {
+			writeError(w, http.StatusBadRequest, errors.New("id and name are required"))
+			return
+		}
+		if err := h.e.AddCondition(condition); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, condition)
+	case len(parts) == 1 && r.Method == http.MethodDelete:
+		if err := h.e.RemoveCondition(parts[0]); err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case len(parts) == 2 && parts[1] == "rollout" && r.Method == http.MethodPut:
+		req := &RolloutRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := validateRollout(req.Percent); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := h.e.SetConditionRollout(parts[0], req.Percent); err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+	}
+}
+
+func (h *apiHandler) serveObligations(w http.ResponseWriter, r *http.Request, parts []string) {
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, h.e.GetObligations())
+	case len(parts) == 0 && r.Method == http.MethodPost:
+		obligation := &Obligation{}
+		if err := json.NewDecoder(r.Body).Decode(obligation); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if obligation.ID == "" || obligation.Name == "" {
+			writeError(w, http.StatusBadRequest, errors.New("id and name are required"))
+			return
+		}
+		if err := h.e.AddObligation(obligation); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, obligation)
+	case len(parts) == 1 && r.Method == http.MethodDelete:
+		if err := h.e.RemoveObligation(parts[0]); err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case len(parts) == 2 && parts[1] == "rollout" && r.Method == http.MethodPut:
+		req := &RolloutRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := validateRollout(req.Percent); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := h.e.SetObligationRollout(parts[0], req.Percent); err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+	}
+}
+
+func (h *apiHandler) serveStats(w http.ResponseWriter, r *http.Request, parts []string) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	window := DefaultStatsWindow
+	if v := r.URL.Query().Get("window"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid window %q", v))
+			return
+		}
+		window = d
+	}
+	limit := DefaultStatsLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid limit %q", v))
+			return
+		}
+		limit = n
+	}
+
+	stats := h.e.GetDashboardStats(window, limit)
+	if len(parts) == 0 {
+		writeJSON(w, http.StatusOK, stats)
+		return
+	}
+	switch parts[0] {
+	case "active-by-object":
+		writeJSON(w, http.StatusOK, stats.ActiveByObject)
+	case "revocations":
+		writeJSON(w, http.StatusOK, stats.RevocationsByReason)
+	case "top-subjects":
+		writeJSON(w, http.StatusOK, stats.TopSubjects)
+	default:
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+	}
+}
+
+func (h *apiHandler) serveMetrics(w http.ResponseWriter) {
+	metrics, ok := h.e.GetMetrics().(interface{ Snapshot() *MetricsSnapshot })
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("metrics are exported elsewhere"))
+		return
+	}
+	writeJSON(w, http.StatusOK, metrics.Snapshot())
+}
+
+func (h *apiHandler) writeSession(w http.ResponseWriter, status int, id string) {
+	session, err := h.e.GetSession(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, status, session.ToRecord())
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func (h *apiHandler) serveTelemetry(w http.ResponseWriter, r *http.Request, parts []string) {
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodPost:
+		var reports []TelemetryReport
+		if err := json.NewDecoder(r.Body).Decode(&reports); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		updated, err := h.e.IngestTelemetry(reports)
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]int{"updated": updated})
+	case len(parts) == 1 && parts[0] == "mappings" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, h.e.GetTelemetryMappings())
+	case len(parts) == 1 && parts[0] == "mappings" && r.Method == http.MethodPost:
+		mapping := TelemetryMapping{}
+		if err := json.NewDecoder(r.Body).Decode(&mapping); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := h.e.AddTelemetryMapping(mapping); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, mapping)
+	default:
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+	}
+}
+
+func (h *apiHandler) serveSimulate(w http.ResponseWriter, r *http.Request) {
+	req := &CreateSessionRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.e.SimulateSession(&SessionRequest{
+		Subject:    req.Subject,
+		Action:     req.Action,
+		Object:     req.Object,
+		Purpose:    req.Purpose,
+		Attributes: req.Attributes,
+	})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (h *apiHandler) serveConfig(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		data, err := h.e.ExportConfig()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(data)
+	case http.MethodPut:
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if r.URL.Query().Get("dry_run") == "true" {
+			diff, err := h.e.DiffConfig(data)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, diff)
+			return
+		}
+		if err := h.e.ImportConfig(data); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAPIHandler(t *testing.T) {
+	uconE := GetUconEnforcer()
+	handler := NewAPIHandler(uconE)
+
+	do := func(method string, path string, body string, out interface{}) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if out != nil {
+			if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+				t.Fatalf("%s %s: invalid response %q: %v", method, path, rec.Body.String(), err)
+			}
+		}
+		return rec.Code
+	}
+
+	condition := `{"id":"location_always","name":"location","kind":"always","expr":"office"}`
+	if code := do(http.MethodPost, "/conditions", condition, nil); code != http.StatusCreated {
+		t.Fatalf("Expected 201 adding condition, got %d", code)
+	}
+	var conditions []*Condition
+	if do(http.MethodGet, "/conditions", "", &conditions); len(conditions) != 1 || conditions[0].ID != "location_always" {
+		t.Errorf("Unexpected conditions: %+v", conditions)
+	}
+	var simulation SimulationResult
+	if code := do(http.MethodPost, "/simulate", `{"subject":"alice","action":"read","object":"document1","attributes":{"location":"home"}}`, &simulation); code != http.StatusOK || simulation.Allowed {
+		t.Errorf("Expected 200 denying alice at home, got %d with %+v", code, simulation)
+	}
+	if code := do(http.MethodPost, "/simulate", `{"subject":"alice"}`, nil); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 simulating an incomplete request, got %d", code)
+	}
+
+	created := &SessionRecord{}
+	body := `{"subject":"alice","action":"read","object":"document1","attributes":{"location":"office"}}`
+	if code := do(http.MethodPost, "/sessions", body, created); code != http.StatusCreated || created.ID == "" {
+		t.Fatalf("Expected 201 creating session, got %d %+v", code, created)
+	}
+	if code := do(http.MethodPost, "/sessions", `{"subject":"alice"}`, nil); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for incomplete session, got %d", code)
+	}
+
+	path := "/sessions/" + created.ID
+	resp := &EnforceResponse{}
+	if do(http.MethodPost, path+"/enforce", "", resp); !resp.Allowed {
+		t.Errorf("Expected session to be allowed, got %+v", resp)
+	}
+	var mine []SubjectSession
+	if code := do(http.MethodGet, "/subjects/alice/sessions", "", &mine); code != http.StatusOK || len(mine) != 1 || mine[0].Object != "document1" {
+		t.Errorf("Expected alice's session, got %d %+v", code, mine)
+	}
+	if code := do(http.MethodPost, "/subjects/bob/sessions/revoke", `{"session_ids":["`+created.ID+`"]}`, nil); code != http.StatusNotFound {
+		t.Errorf("Expected 404 revoking alice's session as bob, got %d", code)
+	}
+	impact := &RevocationImpact{}
+	if code := do(http.MethodPost, "/revocations/preview", `{"subject":"alice"}`, impact); code != http.StatusOK || impact.Sessions != 1 || impact.Affected[0].SessionID != created.ID {
+		t.Errorf("Expected alice's session in the revocation preview, got %d %+v", code, impact)
+	}
+	if code := do(http.MethodPost, "/revocations", `{}`, nil); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 revoking without a selector, got %d", code)
+	}
+
+	updated := &SessionRecord{}
+	do(http.MethodPatch, path+"/attributes", `{"location":"home"}`, updated)
+	if updated.Attributes["location"] != "home" {
+		t.Errorf("Expected updated attribute, got %+v", updated.Attributes)
+	}
+
+	var records []*SessionRecord
+	if do(http.MethodGet, "/sessions", "", &records); len(records) != 1 {
+		t.Errorf("Expected 1 session, got %d", len(records))
+	}
+
+	if code := do(http.MethodDelete, path, "", nil); code != http.StatusConflict {
+		t.Errorf("Expected 409 deleting an active session, got %d", code)
+	}
+	revoked := &SessionRecord{}
+	do(http.MethodPost, path+"/revoke", `{"reason":"policy violation"}`, revoked)
+	if revoked.Active || revoked.StopReason != "policy violation" {
+		t.Errorf("Expected revoked session, got %+v", revoked)
+	}
+	if code := do(http.MethodDelete, path, "", nil); code != http.StatusNoContent {
+		t.Errorf("Expected 204 deleting a stopped session, got %d", code)
+	}
+	var usage []ObjectUsage
+	if code := do(http.MethodGet, "/objects/document1/usage?from=2000-01-01T00:00:00Z", "", &usage); code != http.StatusOK || len(usage) != 1 || usage[0].SessionID != created.ID {
+		t.Errorf("Expected the revoked session in the usage ledger, got %d %+v", code, usage)
+	}
+	if code := do(http.MethodGet, "/objects/document1/usage?to=yesterday", "", nil); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid time, got %d", code)
+	}
+	if code := do(http.MethodGet, path, "", nil); code != http.StatusNotFound {
+		t.Errorf("Expected 404 for deleted session, got %d", code)
+	}
+
+	if code := do(http.MethodPut, "/conditions/location_always/rollout", `{"percent":25}`, nil); code != http.StatusNoContent {
+		t.Errorf("Expected 204 ramping condition, got %d", code)
+	}
+	if do(http.MethodGet, "/conditions", "", &conditions); !conditions[0].Shadow || conditions[0].Rollout != 25 {
+		t.Errorf("Expected the condition to be enforced for 25%% of subjects, got %+v", conditions[0])
+	}
+	if code := do(http.MethodPut, "/conditions/location_always/rollout", `{"percent":101}`, nil); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid rollout, got %d", code)
+	}
+	if code := do(http.MethodPut, "/obligations/unknown/rollout", `{"percent":50}`, nil); code != http.StatusNotFound {
+		t.Errorf("Expected 404 ramping unknown obligation, got %d", code)
+	}
+	if code := do(http.MethodDelete, "/conditions/location_always", "", nil); code != http.StatusNoContent {
+		t.Errorf("Expected 204 removing condition, got %d", code)
+	}
+	if code := do(http.MethodDelete, "/conditions/location_always", "", nil); code != http.StatusNotFound {
+		t.Errorf("Expected 404 removing unknown condition, got %d", code)
+	}
+	var cfg Config
+	if code := do(http.MethodGet, "/config", "", &cfg); code != http.StatusOK || cfg.Version != ConfigVersion {
+		t.Errorf("Expected 200 exporting the configuration, got %d with %+v", code, cfg)
+	}
+	if code := do(http.MethodPut, "/config", `{"version":1,"conditions":[{"id":"vip","name":"vip_level","kind":"always","expr":"1"}]}`, nil); code != http.StatusNoContent {
+		t.Errorf("Expected 204 importing a configuration, got %d", code)
+	}
+	if do(http.MethodGet, "/conditions", "", &conditions); len(conditions) != 1 || conditions[0].ID != "vip" {
+		t.Errorf("Expected the imported conditions, got %+v", conditions)
+	}
+	var diff ConfigDiff
+	if code := do(http.MethodPut, "/config?dry_run=true", `{"version":1}`, &diff); code != http.StatusOK || len(diff.Conditions.Removed) != 1 {
+		t.Errorf("Expected 200 with the removed condition for a dry run, got %d with %+v", code, diff)
+	}
+	if do(http.MethodGet, "/conditions", "", &conditions); len(conditions) != 1 {
+		t.Errorf("Expected a dry run to keep the conditions, got %+v", conditions)
+	}
+	if code := do(http.MethodPut, "/config", `{"version":0}`, nil); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 importing an invalid configuration, got %d", code)
+	}
+	if code := do(http.MethodGet, "/unknown", "", nil); code != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown route, got %d", code)
+	}
+}
@@ -0,0 +1,407 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultApprovalTimeout is how long a four_eyes_approval obligation waits
+// for approval unless its Expr sets another timeout.
+const DefaultApprovalTimeout = 15 * time.Minute
+
+// ApprovalTimeoutReason is the stop reason of sessions that were not
+// approved in time.
+const ApprovalTimeoutReason = "approval timed out"
+
+// ApprovalRejectedReason is the stop reason of sessions whose approval was
+// rejected.
+const ApprovalRejectedReason = "approval rejected"
+
+// ApproveAction is the policy action an approver must be allowed on the
+// object of a session to approve or reject it.
+const ApproveAction = "approve"
+
+// Events of four-eyes approvals.
+const (
+	// EventApprovalRequested is emitted when a session starts waiting for
+	// approval. Data carries the session fields and the "deadline".
+	EventApprovalRequested EventType = "approval_requested"
+	// EventSessionApproved is emitted when a session is approved. Data
+	// carries the session fields and the "approver".
+	EventSessionApproved EventType = "session_approved"
+	// EventApprovalRejected is emitted when the approval of a session is
+	// rejected. Data carries the session fields, the "approver" and the
+	// "reason".
+	EventApprovalRejected EventType = "approval_rejected"
+)
+
+var (
+	// ErrPendingApproval is returned by the four_eyes_approval obligation,
+	// and so by enforcement, while the session waits for approval.
+	ErrPendingApproval = errors.New("session pending approval")
+	// ErrNoPendingApproval is returned by ApproveSession for sessions that
+	// do not wait for approval.
+	ErrNoPendingApproval = errors.New("no pending approval")
+	// ErrSelfApproval is returned by ApproveSession when the approver is the
+	// session subject.
+	ErrSelfApproval = errors.New("sessions cannot be approved by their own subject")
+	// ErrUnauthorizedApprover is returned by ApproveSession when the policy
+	// does not allow the approver ApproveAction on the session object.
+	ErrUnauthorizedApprover = errors.New("approver is not allowed to approve the session")
+	// ErrApprovalRejected is returned by the four_eyes_approval obligation
+	// for sessions whose approval was rejected.
+	ErrApprovalRejected = errors.New("approval rejected")
+)
+
+// ApprovalState is the state of a four-eyes approval.
+type ApprovalState string
+
+// Approval states.
+const (
+	ApprovalPending  ApprovalState = "pending"
+	ApprovalApproved ApprovalState = "approved"
+	ApprovalRejected ApprovalState = "rejected"
+	ApprovalExpired  ApprovalState = "expired"
+)
+
+// Approval records the four-eyes approval of a session.
+type Approval struct {
+	SessionID   string        `json:"session_id"`
+	Subject     string        `json:"subject"`
+	Action      string        `json:"action"`
+	Object      string        `json:"object"`
+	State       ApprovalState `json:"state"`
+	RequestedAt time.Time     `json:"requested_at"`
+	Deadline    time.Time     `json:"deadline"`
+	// Ticket is the reference of the request in the ApprovalProvider.
+	Ticket     string     `json:"ticket,omitempty"`
+	Approver   string     `json:"approver,omitempty"`
+	ApprovedAt *time.Time `json:"approved_at,omitempty"`
+	// Reason is why the approval was rejected.
+	Reason string `json:"reason,omitempty"`
+}
+
+// ApprovalDecision is the state of a request in an external approval system.
+type ApprovalDecision struct {
+	// State is ApprovalPending, ApprovalApproved or ApprovalRejected.
+	State    ApprovalState
+	Approver string
+	Reason   string
+}
+
+// ApprovalProvider connects four-eyes approvals to an external approval
+// system, such as a ticketing tool or a chat workflow, see
+// WithApprovalProvider.
+type ApprovalProvider interface {
+	// RequestApproval files a request for the approval of a session and
+	// returns its ticket in the external system.
+	RequestApproval(approval Approval) (string, error)
+	// CheckApproval polls the state of a request.
+	CheckApproval(ticket string) (ApprovalDecision, error)
+	// CancelApproval withdraws a request that is no longer needed because
+	// the session stopped or the approval timed out.
+	CancelApproval(ticket string) error
+}
+
+// WithApprovalProvider files the approvals requested by four_eyes_approval
+// obligations with an external approval system. Each later enforcement of a
+// waiting session polls the request; the system can also call back with
+// ApproveSession or RejectSession. Requests are cancelled when the session
+// stops or the approval times out.
+func WithApprovalProvider(provider ApprovalProvider) Option {
+	return func(u *UconEnforcer) {
+		u.approvalProvider = provider
+	}
+}
+
+// approval is an Approval with the timer ending its wait.
+type approval struct {
+	Approval
+	timer *time.Timer
+}
+
+// executeFourEyesApproval is the "four_eyes_approval" obligation, usually a
+// pre obligation on sensitive objects. It passes once a second party has
+// approved the session with ApproveSession or in the ApprovalProvider. Until
+// then it fails with ErrPendingApproval, and the first execution requests
+// the approval; a session not approved within the timeout is revoked with
+// ApprovalTimeoutReason, and a rejected one with ApprovalRejectedReason.
+// Expr: empty or the timeout, e.g. "30m".
+func (u *UconEnforcer) executeFourEyesApproval(expr string, session *Session) error {
+	timeout := DefaultApprovalTimeout
+	if expr = strings.TrimSpace(expr); expr != "" {
+		d, err := time.ParseDuration(expr)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid approval timeout %s", expr)
+		}
+		timeout = d
+	}
+
+	id := session.GetId()
+	if session.IsDryRun() {
+		return u.dryRunApproval(id)
+	}
+	u.mu.Lock()
+	provider := u.approvalProvider
+	a, requested := u.approvals[id]
+	if !requested {
+		now := time.Now()
+		a = &approval{Approval: Approval{
+			SessionID:   id,
+			Subject:     session.GetSubject(),
+			Action:      session.GetAction(),
+			Object:      session.GetObject(),
+			State:       ApprovalPending,
+			RequestedAt: now,
+			Deadline:    now.Add(timeout),
+		}}
+		a.timer = time.AfterFunc(timeout, func() { u.expireApproval(id) })
+		if u.approvals == nil {
+			u.approvals = make(map[string]*approval)
+		}
+		u.approvals[id] = a
+	}
+	current := a.Approval
+	u.mu.Unlock()
+
+	switch {
+	case !requested:
+		if err := u.requestApproval(provider, session, current); err != nil {
+			return err
+		}
+	case current.State == ApprovalPending && provider != nil && current.Ticket != "":
+		decision, err := provider.CheckApproval(current.Ticket)
+		if err != nil {
+			return fmt.Errorf("approval provider: %w: %w", ErrDependencyUnavailable, err)
+		}
+		if decision.State != ApprovalPending {
+			if err := u.decideApproval(id, decision.State, decision.Approver, decision.Reason); err != nil {
+				return err
+			}
+			// A rejection stops the session, which drops the approval.
+			current.State, current.Approver = decision.State, decision.Approver
+		}
+	}
+
+	return approvalError(current)
+}
+
+// dryRunApproval is executeFourEyesApproval for a dry run: it reports the
+// state of an existing approval without requesting one or asking the
+// approval provider.
+func (u *UconEnforcer) dryRunApproval(id string) error {
+	current, requested := u.GetApproval(id)
+	if !requested {
+		return fmt.Errorf("session %s: %w", id, ErrPendingApproval)
+	}
+	return approvalError(current)
+}
+
+// approvalError returns nil for an approved session, else why it waits or
+// was rejected.
+func approvalError(current Approval) error {
+	switch current.State {
+	case ApprovalApproved:
+		return nil
+	case ApprovalRejected:
+		return fmt.Errorf("session %s: %w by %s", current.SessionID, ErrApprovalRejected, current.Approver)
+	default:
+		return fmt.Errorf("session %s: %w until %s", current.SessionID, ErrPendingApproval, current.Deadline.Format(time.RFC3339))
+	}
+}
+
+// requestApproval announces a new approval request and files it with the
+// provider, if any. If filing fails the request is dropped, so that the next
+// enforcement files it again.
+func (u *UconEnforcer) requestApproval(provider ApprovalProvider, session *Session, request Approval) error {
+	id := session.GetId()
+	if provider != nil {
+		ticket, err := provider.RequestApproval(request)
+		u.mu.Lock()
+		a := u.approvals[id]
+		if err != nil {
+			a.timer.Stop()
+			delete(u.approvals, id)
+		} else {
+			a.Ticket = ticket
+		}
+		u.mu.Unlock()
+		if err != nil {
+			return fmt.Errorf("approval provider: %w: %w", ErrDependencyUnavailable, err)
+		}
+	}
+
+	fields := sessionFields(session)
+	fields["deadline"] = request.Deadline
+	u.logger.Log(LevelInfo, "approval requested", fields)
+	u.emit(Event{
+		Type:      EventApprovalRequested,
+		SessionID: id,
+		Message:   fmt.Sprintf("session %s of %s on %s waits for approval until %s", id, session.GetSubject(), session.GetObject(), request.Deadline.Format(time.RFC3339)),
+		Data:      fields,
+	})
+	return nil
+}
+
+// ApproveSession approves a session waiting for four-eyes approval, so that
+// its next enforcement passes the four_eyes_approval obligation. The
+// approver must not be the session subject and must be allowed
+// ApproveAction on the session object. The approval is logged and emitted as
+// an EventSessionApproved.
+func (u *UconEnforcer) ApproveSession(sessionID string, approver string) error {
+	return u.decideApproval(sessionID, ApprovalApproved, approver, "")
+}
+
+// RejectSession rejects the approval of a session waiting for four-eyes
+// approval and revokes the session with ApprovalRejectedReason. The approver
+// must be allowed ApproveAction on the session object. The rejection is
+// logged and emitted as an EventApprovalRejected.
+func (u *UconEnforcer) RejectSession(sessionID string, approver string, reason string) error {
+	return u.decideApproval(sessionID, ApprovalRejected, approver, reason)
+}
+
+// decideApproval records the decision on a pending approval.
+func (u *UconEnforcer) decideApproval(sessionID string, state ApprovalState, approver string, reason string) error {
+	session, err := u.GetSession(sessionID)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(approver) == "" {
+		return errors.New("approver cannot be empty")
+	}
+	if approver == session.GetSubject() {
+		return fmt.Errorf("%w: %s", ErrSelfApproval, approver)
+	}
+	allowed, err := u.Enforce(approver, session.GetObject(), ApproveAction)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return fmt.Errorf("%w: %s on %s", ErrUnauthorizedApprover, approver, session.GetObject())
+	}
+
+	u.mu.Lock()
+	a, ok := u.approvals[sessionID]
+	if !ok || a.State != ApprovalPending {
+		u.mu.Unlock()
+		return fmt.Errorf("%w for session %s", ErrNoPendingApproval, sessionID)
+	}
+	a.timer.Stop()
+	a.State = state
+	a.Approver = approver
+	now := time.Now()
+	a.ApprovedAt = &now
+	a.Reason = reason
+	u.mu.Unlock()
+
+	fields := sessionFields(session)
+	fields["approver"] = approver
+	if state == ApprovalRejected {
+		fields["reason"] = reason
+		u.logger.Log(LevelInfo, "session approval rejected", fields)
+		u.emit(Event{
+			Type:      EventApprovalRejected,
+			SessionID: sessionID,
+			Message:   fmt.Sprintf("approval of session %s rejected by %s: %s", sessionID, approver, reason),
+			Data:      fields,
+		})
+		if session.IfActive() {
+			_ = session.Stop(ApprovalRejectedReason)
+		}
+		return nil
+	}
+	u.logger.Log(LevelInfo, "session approved", fields)
+	u.emit(Event{
+		Type:      EventSessionApproved,
+		SessionID: sessionID,
+		Message:   fmt.Sprintf("session %s approved by %s", sessionID, approver),
+		Data:      fields,
+	})
+	return nil
+}
+
+// GetApproval returns the four-eyes approval of a session. The approval is
+// dropped when the session stops.
+func (u *UconEnforcer) GetApproval(sessionID string) (Approval, bool) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	a, ok := u.approvals[sessionID]
+	if !ok {
+		return Approval{}, false
+	}
+	return a.Approval, true
+}
+
+// GetPendingApprovals returns the approvals sessions wait for, oldest first.
+func (u *UconEnforcer) GetPendingApprovals() []Approval {
+	u.mu.RLock()
+	var pending []Approval
+	for _, a := range u.approvals {
+		if a.State == ApprovalPending {
+			pending = append(pending, a.Approval)
+		}
+	}
+	u.mu.RUnlock()
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].RequestedAt.Before(pending[j].RequestedAt)
+	})
+	return pending
+}
+
+// expireApproval revokes a session still waiting for approval.
+func (u *UconEnforcer) expireApproval(sessionID string) {
+	if !u.endApproval(sessionID) {
+		return
+	}
+	if session, err := u.GetSession(sessionID); err == nil && session.IfActive() {
+		_ = session.Stop(ApprovalTimeoutReason)
+	}
+}
+
+// cancelApproval stops waiting for the approval of a stopped session and
+// drops its record.
+func (u *UconEnforcer) cancelApproval(sessionID string) {
+	u.endApproval(sessionID)
+	u.mu.Lock()
+	delete(u.approvals, sessionID)
+	u.mu.Unlock()
+}
+
+// endApproval expires a pending approval and withdraws its request from the
+// provider. It reports whether the approval was pending.
+func (u *UconEnforcer) endApproval(sessionID string) bool {
+	u.mu.Lock()
+	a, ok := u.approvals[sessionID]
+	if !ok || a.State != ApprovalPending {
+		u.mu.Unlock()
+		return false
+	}
+	a.timer.Stop()
+	a.State = ApprovalExpired
+	ticket, provider := a.Ticket, u.approvalProvider
+	u.mu.Unlock()
+
+	if provider != nil && ticket != "" {
+		if err := provider.CancelApproval(ticket); err != nil {
+			u.logger.Log(LevelWarn, "failed to cancel approval request", map[string]interface{}{"session": sessionID, "ticket": ticket, "error": err.Error()})
+		}
+	}
+	return true
+}
@@ -0,0 +1,237 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFourEyesApproval(t *testing.T) {
+	uconE := GetUconEnforcer().(*UconEnforcer)
+	var mu sync.Mutex
+	events := make(map[EventType]int)
+	uconE.AddEventListener(func(event Event) {
+		mu.Lock()
+		events[event.Type]++
+		mu.Unlock()
+	})
+	_ = uconE.AddObligation(&Obligation{ID: "approval", Name: "four_eyes_approval", Kind: "pre", Expr: "1h"})
+	_, _ = uconE.AddPolicy("bob", "document1", ApproveAction)
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{})
+
+	if err := uconE.ApproveSession(sessionID, "bob"); !errors.Is(err, ErrNoPendingApproval) {
+		t.Errorf("Expected ErrNoPendingApproval before the request, got %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		granted, err := uconE.EnforceWithSession(sessionID)
+		if granted != nil || !errors.Is(err, ErrPendingApproval) || ReasonFor(err) != ReasonApprovalPending {
+			t.Fatalf("Expected the session to wait for approval, got %v", err)
+		}
+	}
+	if pending := uconE.GetPendingApprovals(); len(pending) != 1 || pending[0].SessionID != sessionID || pending[0].Subject != "alice" {
+		t.Fatalf("Expected one pending approval, got %+v", pending)
+	} else if encoded, _ := json.Marshal(pending[0]); strings.Contains(string(encoded), "approved_at") {
+		t.Errorf("Expected a pending approval to omit approved_at, got %s", encoded)
+	}
+
+	if err := uconE.ApproveSession(sessionID, "alice"); !errors.Is(err, ErrSelfApproval) {
+		t.Errorf("Expected ErrSelfApproval, got %v", err)
+	}
+	if err := uconE.ApproveSession(sessionID, "mallory"); !errors.Is(err, ErrUnauthorizedApprover) {
+		t.Errorf("Expected ErrUnauthorizedApprover, got %v", err)
+	}
+	if err := uconE.RejectSession(sessionID, "mallory", "no"); !errors.Is(err, ErrUnauthorizedApprover) {
+		t.Errorf("Expected ErrUnauthorizedApprover on rejection, got %v", err)
+	}
+	if err := uconE.ApproveSession(sessionID, "bob"); err != nil {
+		t.Fatal(err)
+	}
+	if granted, err := uconE.EnforceWithSession(sessionID); granted == nil || err != nil {
+		t.Fatalf("Expected the approved session to be granted, got %v", err)
+	}
+	approval, ok := uconE.GetApproval(sessionID)
+	if !ok || approval.State != ApprovalApproved || approval.Approver != "bob" || approval.ApprovedAt == nil {
+		t.Errorf("Expected the approval to be recorded, got %+v", approval)
+	}
+	if pending := uconE.GetPendingApprovals(); len(pending) != 0 {
+		t.Errorf("Expected no pending approvals, got %+v", pending)
+	}
+	_ = uconE.StopMonitoring(sessionID)
+	if _, ok := uconE.GetApproval(sessionID); ok {
+		t.Error("Expected the approval to be dropped when the session stops")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if events[EventApprovalRequested] != 1 || events[EventSessionApproved] != 1 {
+		t.Errorf("Expected one request and one approval event, got %v", events)
+	}
+}
+
+func TestFourEyesApprovalTimeout(t *testing.T) {
+	uconE := GetUconEnforcer().(*UconEnforcer)
+	_ = uconE.AddObligation(&Obligation{ID: "approval", Name: "four_eyes_approval", Kind: "pre", Expr: "50ms"})
+	_, _ = uconE.AddPolicy("bob", "document1", ApproveAction)
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{})
+	if _, err := uconE.EnforceWithSession(sessionID); !errors.Is(err, ErrPendingApproval) {
+		t.Fatalf("Expected the session to wait for approval, got %v", err)
+	}
+
+	session, _ := uconE.GetSession(sessionID)
+	waitFor(t, func() bool { return !session.IfActive() })
+	if session.GetStopReason() != ApprovalTimeoutReason {
+		t.Errorf("Expected the session to time out, got %q", session.GetStopReason())
+	}
+	if approval, ok := uconE.GetApproval(sessionID); ok {
+		t.Errorf("Expected the expired approval to be dropped, got %+v", approval)
+	}
+	if err := uconE.ApproveSession(sessionID, "bob"); !errors.Is(err, ErrNoPendingApproval) {
+		t.Errorf("Expected late approvals to be refused, got %v", err)
+	}
+}
+
+func TestRejectSession(t *testing.T) {
+	uconE := GetUconEnforcer().(*UconEnforcer)
+	_ = uconE.AddObligation(&Obligation{ID: "approval", Name: "four_eyes_approval", Kind: "pre", Expr: "1h"})
+	_, _ = uconE.AddPolicy("bob", "document1", ApproveAction)
+	var rejected Event
+	uconE.AddEventListener(func(event Event) {
+		if event.Type == EventApprovalRejected {
+			rejected = event
+		}
+	})
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{})
+	if _, err := uconE.EnforceWithSession(sessionID); !errors.Is(err, ErrPendingApproval) {
+		t.Fatalf("Expected the session to wait for approval, got %v", err)
+	}
+
+	if err := uconE.RejectSession(sessionID, "bob", "not on call"); err != nil {
+		t.Fatal(err)
+	}
+	session, _ := uconE.GetSession(sessionID)
+	if session.IfActive() || session.GetStopReason() != ApprovalRejectedReason {
+		t.Errorf("Expected the session to be revoked, got %q", session.GetStopReason())
+	}
+	if rejected.Data["approver"] != "bob" || rejected.Data["reason"] != "not on call" {
+		t.Errorf("Expected the rejection to be emitted, got %+v", rejected)
+	}
+	if err := uconE.ApproveSession(sessionID, "bob"); !errors.Is(err, ErrNoPendingApproval) {
+		t.Errorf("Expected a rejected approval to stay rejected, got %v", err)
+	}
+}
+
+type fakeApprovalProvider struct {
+	mu        sync.Mutex
+	requests  []Approval
+	decision  ApprovalDecision
+	cancelled []string
+	fail      error
+}
+
+func (p *fakeApprovalProvider) RequestApproval(approval Approval) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.fail != nil {
+		return "", p.fail
+	}
+	p.requests = append(p.requests, approval)
+	return "TICKET-" + approval.SessionID, nil
+}
+
+func (p *fakeApprovalProvider) CheckApproval(ticket string) (ApprovalDecision, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.decision, p.fail
+}
+
+func (p *fakeApprovalProvider) CancelApproval(ticket string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cancelled = append(p.cancelled, ticket)
+	return nil
+}
+
+func (p *fakeApprovalProvider) set(decision ApprovalDecision, fail error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.decision, p.fail = decision, fail
+}
+
+func TestApprovalProvider(t *testing.T) {
+	provider := &fakeApprovalProvider{fail: errors.New("service desk down")}
+	uconE := NewUconEnforcer(GetUconEnforcer().(*UconEnforcer).Enforcer, WithApprovalProvider(provider))
+	_ = uconE.AddObligation(&Obligation{ID: "approval", Name: "four_eyes_approval", Kind: "pre", Expr: "1h"})
+	_, _ = uconE.AddPolicy("bob", "document1", ApproveAction)
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{})
+
+	if _, err := uconE.EnforceWithSession(sessionID); !errors.Is(err, ErrDependencyUnavailable) {
+		t.Fatalf("Expected the provider failure to be reported, got %v", err)
+	}
+	if _, ok := uconE.GetApproval(sessionID); ok {
+		t.Fatal("Expected a request that could not be filed to be dropped")
+	}
+
+	provider.set(ApprovalDecision{State: ApprovalPending}, nil)
+	for i := 0; i < 2; i++ {
+		if _, err := uconE.EnforceWithSession(sessionID); !errors.Is(err, ErrPendingApproval) {
+			t.Fatalf("Expected the session to wait for approval, got %v", err)
+		}
+	}
+	approval, _ := uconE.GetApproval(sessionID)
+	if len(provider.requests) != 1 || provider.requests[0].Subject != "alice" || approval.Ticket != "TICKET-"+sessionID {
+		t.Fatalf("Expected one filed request, got %+v and %+v", provider.requests, approval)
+	}
+
+	provider.set(ApprovalDecision{State: ApprovalApproved, Approver: "alice"}, nil)
+	if _, err := uconE.EnforceWithSession(sessionID); !errors.Is(err, ErrSelfApproval) {
+		t.Errorf("Expected self-approval in the provider to be refused, got %v", err)
+	}
+	provider.set(ApprovalDecision{State: ApprovalApproved, Approver: "mallory"}, nil)
+	if _, err := uconE.EnforceWithSession(sessionID); !errors.Is(err, ErrUnauthorizedApprover) {
+		t.Errorf("Expected an unauthorized approver in the provider to be refused, got %v", err)
+	}
+	provider.set(ApprovalDecision{State: ApprovalApproved, Approver: "bob"}, nil)
+	if granted, err := uconE.EnforceWithSession(sessionID); granted == nil || err != nil {
+		t.Fatalf("Expected the approved session to be granted, got %v", err)
+	}
+	if approval, _ := uconE.GetApproval(sessionID); approval.State != ApprovalApproved || approval.Approver != "bob" {
+		t.Errorf("Expected the provider's approval to be recorded, got %+v", approval)
+	}
+
+	provider.set(ApprovalDecision{State: ApprovalRejected, Approver: "bob", Reason: "no ticket"}, nil)
+	rejectedID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{})
+	_, _ = uconE.EnforceWithSession(rejectedID)
+	if _, err := uconE.EnforceWithSession(rejectedID); !errors.Is(err, ErrApprovalRejected) || ReasonFor(err) != ReasonSessionRevoked {
+		t.Fatalf("Expected the rejection to deny the session, got %v", err)
+	}
+	session, _ := uconE.GetSession(rejectedID)
+	if session.IfActive() || session.GetStopReason() != ApprovalRejectedReason {
+		t.Errorf("Expected the rejected session to be revoked, got %q", session.GetStopReason())
+	}
+
+	provider.set(ApprovalDecision{State: ApprovalPending}, nil)
+	stoppedID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{})
+	_, _ = uconE.EnforceWithSession(stoppedID)
+	_ = uconE.StopMonitoring(stoppedID)
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	if len(provider.cancelled) != 1 || provider.cancelled[0] != "TICKET-"+stoppedID {
+		t.Errorf("Expected the request of the stopped session to be cancelled, got %v", provider.cancelled)
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrAuditTampered is wrapped by errors of audit logs and proofs whose
+// hashes do not match their entries.
+var ErrAuditTampered = errors.New("audit log tampered with")
+
+// AuditEntry is one event of an AuditLog. Its hash covers the hash of the
+// previous entry, so changing, removing or reordering entries breaks every
+// hash after it.
+type AuditEntry struct {
+	Seq uint64 `json:"seq"`
+	// Event is the event as encoded by Event.MarshalJSON.
+	Event    json.RawMessage `json:"event"`
+	PrevHash string          `json:"prev_hash"`
+	Hash     string          `json:"hash"`
+}
+
+// DecodeEvent decodes the event of the entry.
+func (e *AuditEntry) DecodeEvent() (Event, error) {
+	var event Event
+	err := json.Unmarshal(e.Event, &event)
+	return event, err
+}
+
+// leaf returns the hash of the entry's own content.
+func (e *AuditEntry) leaf() []byte {
+	h := sha256.New()
+	h.Write([]byte(strconv.FormatUint(e.Seq, 10) + ":"))
+	h.Write(e.Event)
+	return h.Sum(nil)
+}
+
+// chainHash returns the hash of an entry with the given previous hash and leaf.
+func chainHash(prevHash string, leaf []byte) (string, error) {
+	prev, err := hex.DecodeString(prevHash)
+	if err != nil {
+		return "", fmt.Errorf("%w: malformed hash %q", ErrAuditTampered, prevHash)
+	}
+	sum := sha256.Sum256(append(prev, leaf...))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// auditGenesis is the previous hash of the first entry.
+var auditGenesis = hex.EncodeToString(make([]byte, sha256.Size))
+
+// AuditLog is an append-only, hash-chained log of events, e.g. to keep a
+// tamper-evident usage history. Register its Listener with AddEventListener.
+type AuditLog struct {
+	mu       sync.Mutex
+	entries  []AuditEntry
+	appended []time.Time // when each entry was appended, for Prune
+	pruned   uint64      // number of entries removed by Prune
+	base     string      // hash of the last pruned entry
+	w        io.Writer
+}
+
+// NewAuditLog creates an empty AuditLog that also writes every entry as a
+// line of JSON to w, if not nil.
+func NewAuditLog(w io.Writer) *AuditLog {
+	return &AuditLog{w: w, base: auditGenesis}
+}
+
+// Append adds an event to the log and returns its entry.
+func (l *AuditLog) Append(event Event) (AuditEntry, error) {
+	encoded, err := json.Marshal(event)
+	if err != nil {
+		return AuditEntry{}, err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entry := AuditEntry{Seq: l.pruned + uint64(len(l.entries)) + 1, Event: encoded, PrevHash: l.headLocked()}
+	if entry.Hash, err = chainHash(entry.PrevHash, entry.leaf()); err != nil {
+		return AuditEntry{}, err
+	}
+	if l.w != nil {
+		line, err := json.Marshal(&entry)
+		if err != nil {
+			return AuditEntry{}, err
+		}
+		if _, err := l.w.Write(append(line, '\n')); err != nil {
+			return AuditEntry{}, fmt.Errorf("failed to write audit entry %d: %w", entry.Seq, err)
+		}
+	}
+	l.entries = append(l.entries, entry)
+	l.appended = append(l.appended, time.Now())
+	return entry, nil
+}
+
+// Listener returns an EventListener appending every event to the log.
+// Append errors are dropped.
+func (l *AuditLog) Listener() EventListener {
+	return func(event Event) {
+		_, _ = l.Append(event)
+	}
+}
+
+// Head returns the hash of the last entry. Publishing it, e.g. in a daily
+// report, lets anyone holding it detect later tampering.
+func (l *AuditLog) Head() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.headLocked()
+}
+
+func (l *AuditLog) headLocked() string {
+	if len(l.entries) == 0 {
+		return l.base
+	}
+	return l.entries[len(l.entries)-1].Hash
+}
+
+// Entries returns the entries of the log not removed by Prune, oldest first.
+func (l *AuditLog) Entries() []AuditEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]AuditEntry(nil), l.entries...)
+}
+
+// Verify recomputes the hash chain of the log. After Prune, the chain is
+// verified from the hash of the last pruned entry.
+func (l *AuditLog) Verify() error {
+	l.mu.Lock()
+	entries := append([]AuditEntry(nil), l.entries...)
+	base, first := l.base, l.pruned+1
+	l.mu.Unlock()
+	_, err := verifyAuditChain(entries, base, first)
+	return err
+}
+
+// Prune removes the oldest entries that are older than policy.MaxAge or
+// beyond policy.MaxEntries, and returns how many it removed. Sequence numbers
+// and the head are unaffected, so published heads and proofs of the
+// remaining entries stay valid. Entries already written to w are kept.
+func (l *AuditLog) Prune(policy RetentionPolicy, now time.Time) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n := policy.excess(l.appended, now)
+	if n == 0 {
+		return 0
+	}
+	l.base = l.entries[n-1].Hash
+	l.pruned += uint64(n)
+	l.entries = append([]AuditEntry(nil), l.entries[n:]...)
+	l.appended = append([]time.Time(nil), l.appended[n:]...)
+	return n
+}
+
+// AuditProof proves that an entry is part of the log whose last hash is
+// Head, without revealing the other entries: Following are the content
+// hashes of the entries after it, oldest first.
+type AuditProof struct {
+	Entry     AuditEntry `json:"entry"`
+	Following []string   `json:"following"`
+	Head      string     `json:"head"`
+}
+
+// Proof returns the inclusion proof of the entry with the given sequence number.
+func (l *AuditLog) Proof(seq uint64) (*AuditProof, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if seq <= l.pruned || seq > l.pruned+uint64(len(l.entries)) {
+		return nil, fmt.Errorf("audit entry %d not found", seq)
+	}
+	index := seq - l.pruned
+	proof := &AuditProof{Entry: l.entries[index-1], Head: l.headLocked()}
+	for _, entry := range l.entries[index:] {
+		proof.Following = append(proof.Following, hex.EncodeToString(entry.leaf()))
+	}
+	return proof, nil
+}
+
+// VerifyAuditProof checks that the entry of a proof is part of the log whose
+// last hash is head, e.g. a published AuditLog.Head.
+func VerifyAuditProof(proof *AuditProof, head string) error {
+	hash, err := chainHash(proof.Entry.PrevHash, proof.Entry.leaf())
+	if err != nil {
+		return err
+	}
+	if hash != proof.Entry.Hash {
+		return fmt.Errorf("%w: entry %d does not match its hash", ErrAuditTampered, proof.Entry.Seq)
+	}
+	for _, following := range proof.Following {
+		leaf, err := hex.DecodeString(following)
+		if err != nil {
+			return fmt.Errorf("%w: malformed hash %q", ErrAuditTampered, following)
+		}
+		if hash, err = chainHash(hash, leaf); err != nil {
+			return err
+		}
+	}
+	if hash != head || proof.Head != head {
+		return fmt.Errorf("%w: entry %d does not lead to head %s", ErrAuditTampered, proof.Entry.Seq, head)
+	}
+	return nil
+}
+
+// VerifyAuditLog verifies an audit log written by an AuditLog, one JSON
+// entry per line, and returns its head hash.
+func VerifyAuditLog(r io.Reader) (string, error) {
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return "", fmt.Errorf("%w: malformed entry after %d entries: %v", ErrAuditTampered, len(entries), err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return verifyAuditEntries(entries)
+}
+
+func verifyAuditEntries(entries []AuditEntry) (string, error) {
+	return verifyAuditChain(entries, auditGenesis, 1)
+}
+
+// verifyAuditChain verifies entries starting with sequence number first,
+// whose previous hash is head.
+func verifyAuditChain(entries []AuditEntry, head string, first uint64) (string, error) {
+	for i := range entries {
+		entry := &entries[i]
+		if entry.Seq != first+uint64(i) || entry.PrevHash != head {
+			return "", fmt.Errorf("%w: entry %d is out of sequence", ErrAuditTampered, entry.Seq)
+		}
+		hash, err := chainHash(entry.PrevHash, entry.leaf())
+		if err != nil {
+			return "", err
+		}
+		if hash != entry.Hash {
+			return "", fmt.Errorf("%w: entry %d does not match its hash", ErrAuditTampered, entry.Seq)
+		}
+		head = hash
+	}
+	return head, nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAuditLog(t *testing.T) {
+	var out bytes.Buffer
+	log := NewAuditLog(&out)
+	uconE := GetUconEnforcer()
+	uconE.AddEventListener(log.Listener())
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	if _, err := uconE.EnforceWithSession(sessionID); err != nil {
+		t.Fatal(err)
+	}
+	_ = uconE.StopMonitoring(sessionID)
+	if _, err := log.Append(Event{Type: "custom", Message: "<&>"}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := log.Entries()
+	if len(entries) != 3 || entries[0].PrevHash != auditGenesis || entries[1].PrevHash != entries[0].Hash {
+		t.Fatalf("Expected three chained entries, got %+v", entries)
+	}
+	if event, err := entries[1].DecodeEvent(); err != nil || event.Type != EventSessionStopped || event.SessionID != sessionID {
+		t.Errorf("Expected the stop event, got %+v (%v)", event, err)
+	}
+	if err := log.Verify(); err != nil {
+		t.Errorf("Expected the log to verify, got %v", err)
+	}
+	head, err := VerifyAuditLog(bytes.NewReader(out.Bytes()))
+	if err != nil || head != log.Head() {
+		t.Errorf("Expected the written log to verify to %s, got %s (%v)", log.Head(), head, err)
+	}
+
+	tampered := strings.Replace(out.String(), `"allowed":true`, `"allowed":false`, 1)
+	if tampered == out.String() {
+		t.Fatal("Expected the decision to be in the written log")
+	}
+	if _, err := VerifyAuditLog(strings.NewReader(tampered)); !errors.Is(err, ErrAuditTampered) {
+		t.Errorf("Expected a changed entry to be detected, got %v", err)
+	}
+	lines := strings.SplitAfter(out.String(), "\n")
+	if _, err := VerifyAuditLog(strings.NewReader(lines[0] + lines[2])); !errors.Is(err, ErrAuditTampered) {
+		t.Errorf("Expected a removed entry to be detected, got %v", err)
+	}
+}
+
+func TestAuditProof(t *testing.T) {
+	log := NewAuditLog(nil)
+	for i := 0; i < 5; i++ {
+		_, _ = log.Append(Event{Type: "usage", SessionID: "session_" + string(rune('a'+i))})
+	}
+	head := log.Head()
+
+	proof, err := log.Proof(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proof.Following) != 3 {
+		t.Errorf("Expected the hashes of the three following entries, got %d", len(proof.Following))
+	}
+	if err := VerifyAuditProof(proof, head); err != nil {
+		t.Errorf("Expected the proof to verify, got %v", err)
+	}
+	if err := VerifyAuditProof(proof, auditGenesis); !errors.Is(err, ErrAuditTampered) {
+		t.Errorf("Expected a wrong head to be rejected, got %v", err)
+	}
+	proof.Entry.Event = []byte(`{"schema_version":1,"type":"usage","session_id":"session_z","time":"0001-01-01T00:00:00Z"}`)
+	if err := VerifyAuditProof(proof, head); !errors.Is(err, ErrAuditTampered) {
+		t.Errorf("Expected a changed entry to be rejected, got %v", err)
+	}
+	if _, err := log.Proof(6); err == nil {
+		t.Error("Expected an unknown entry to have no proof")
+	}
+}
@@ -0,0 +1,279 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultBreakGlassTTL is how long break-glass sessions last by default.
+const DefaultBreakGlassTTL = 15 * time.Minute
+
+// BreakGlassExpiredReason is the stop reason of break-glass sessions that
+// outlived the break-glass TTL.
+const BreakGlassExpiredReason = "break-glass session expired"
+
+// EventBreakGlass is emitted when a break-glass session is created, to alert
+// on emergency access. Data carries the session fields and the
+// "justification".
+const EventBreakGlass EventType = "break_glass"
+
+var (
+	// ErrBreakGlassExpired is returned when enforcing a break-glass session
+	// that outlived the break-glass TTL.
+	ErrBreakGlassExpired = errors.New("break-glass session expired")
+	// ErrNoJustification is returned by CreateBreakGlassSession without a
+	// justification.
+	ErrNoJustification = errors.New("break-glass access needs a justification")
+	// ErrReviewNotFound is returned by ReviewBreakGlass for sessions that
+	// were not break-glass sessions.
+	ErrReviewNotFound = errors.New("break-glass review not found")
+)
+
+// BreakGlassReview is the review record every break-glass session leaves
+// behind. It stays pending until ReviewBreakGlass records the outcome.
+type BreakGlassReview struct {
+	SessionID     string     `json:"session_id"`
+	Subject       string     `json:"subject"`
+	Action        string     `json:"action"`
+	Object        string     `json:"object"`
+	Justification string     `json:"justification"`
+	StartTime     time.Time  `json:"start_time"`
+	EndTime       *time.Time `json:"end_time,omitempty"`
+	StopReason    string     `json:"stop_reason,omitempty"`
+	Reviewer      string     `json:"reviewer,omitempty"`
+	ReviewedAt    *time.Time `json:"reviewed_at,omitempty"`
+	Notes         string     `json:"notes,omitempty"`
+}
+
+// BreakGlassOutcome is the outcome of the review of a break-glass session.
+// It is kept in the session record, so reviews survive restarts.
+type BreakGlassOutcome struct {
+	Reviewer   string    `json:"reviewer"`
+	ReviewedAt time.Time `json:"reviewed_at"`
+	Notes      string    `json:"notes,omitempty"`
+}
+
+func (o *BreakGlassOutcome) clone() *BreakGlassOutcome {
+	if o == nil {
+		return nil
+	}
+	c := *o
+	return &c
+}
+
+// Pending reports whether the review is still outstanding.
+func (r *BreakGlassReview) Pending() bool {
+	return r.Reviewer == ""
+}
+
+// WithBreakGlassTTL sets how long break-glass sessions last before they are
+// revoked; the default is DefaultBreakGlassTTL.
+func WithBreakGlassTTL(ttl time.Duration) Option {
+	return func(u *UconEnforcer) {
+		u.breakGlassTTL = ttl
+	}
+}
+
+// CreateBreakGlassSession creates a session for emergency access. Break-glass
+// sessions pass the conditions marked BreakGlassBypass, additionally carry
+// the obligations marked BreakGlass, are revoked with
+// BreakGlassExpiredReason once the break-glass TTL has passed, and leave a
+// BreakGlassReview. Creating one logs a warning and emits an EventBreakGlass.
+func (u *UconEnforcer) CreateBreakGlassSession(sub string, act string, obj string, justification string, attributes map[string]interface{}) (string, error) {
+	justification = strings.TrimSpace(justification)
+	if justification == "" {
+		return "", ErrNoJustification
+	}
+	id, err := u.createSession(sub, act, obj, "", attributes)
+	if err != nil {
+		return "", err
+	}
+	session, err := u.GetSession(id)
+	if err != nil {
+		return "", err
+	}
+	if err := session.setBreakGlass(justification); err != nil {
+		return "", err
+	}
+	u.trackBreakGlassReview(session)
+
+	fields := sessionFields(session)
+	fields["justification"] = justification
+	u.logger.Log(LevelWarn, "break-glass session created", fields)
+	u.emit(Event{
+		Type:      EventBreakGlass,
+		SessionID: id,
+		Message:   fmt.Sprintf("break-glass access by %s to %s: %s", sub, obj, justification),
+		Data:      fields,
+	})
+	return id, nil
+}
+
+// GetBreakGlassReviews returns the review records of break-glass sessions,
+// oldest first; with pendingOnly only those not reviewed yet.
+func (u *UconEnforcer) GetBreakGlassReviews(pendingOnly bool) []BreakGlassReview {
+	u.mu.RLock()
+	reviews := make([]BreakGlassReview, 0, len(u.breakGlassReviews))
+	for _, review := range u.breakGlassReviews {
+		if !pendingOnly || review.Pending() {
+			reviews = append(reviews, *review)
+		}
+	}
+	u.mu.RUnlock()
+
+	for i := range reviews {
+		session, err := u.GetSession(reviews[i].SessionID)
+		if err != nil {
+			continue
+		}
+		if !session.IfActive() {
+			end := session.GetEndTime()
+			reviews[i].EndTime = &end
+			reviews[i].StopReason = session.GetStopReason()
+		}
+		if outcome := session.getBreakGlassReview(); outcome != nil {
+			reviews[i].setOutcome(outcome)
+		}
+	}
+	sort.Slice(reviews, func(i, j int) bool {
+		return reviews[i].StartTime.Before(reviews[j].StartTime)
+	})
+	return reviews
+}
+
+// ReviewBreakGlass records the review of a break-glass session, in its
+// session record as well.
+func (u *UconEnforcer) ReviewBreakGlass(sessionID string, reviewer string, notes string) error {
+	if strings.TrimSpace(reviewer) == "" {
+		return errors.New("reviewer cannot be empty")
+	}
+	u.mu.RLock()
+	_, ok := u.breakGlassReviews[sessionID]
+	u.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrReviewNotFound, sessionID)
+	}
+
+	outcome := &BreakGlassOutcome{Reviewer: reviewer, ReviewedAt: time.Now(), Notes: notes}
+	if session, err := u.GetSession(sessionID); err == nil {
+		if err := session.setBreakGlassReview(outcome); err != nil {
+			return err
+		}
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if review, ok := u.breakGlassReviews[sessionID]; ok {
+		review.setOutcome(outcome)
+	}
+	return nil
+}
+
+// trackBreakGlassReview adds the review record of a break-glass session,
+// e.g. of one restored from the session store.
+func (u *UconEnforcer) trackBreakGlassReview(session *Session) {
+	record := session.ToRecord()
+	review := &BreakGlassReview{
+		SessionID:     record.ID,
+		Subject:       record.Subject,
+		Action:        record.Action,
+		Object:        record.Object,
+		Justification: record.BreakGlass,
+		StartTime:     record.StartTime,
+	}
+	if record.Review != nil {
+		review.setOutcome(record.Review)
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.breakGlassReviews == nil {
+		u.breakGlassReviews = make(map[string]*BreakGlassReview)
+	}
+	u.breakGlassReviews[record.ID] = review
+}
+
+func (r *BreakGlassReview) setOutcome(outcome *BreakGlassOutcome) {
+	r.Reviewer = outcome.Reviewer
+	reviewedAt := outcome.ReviewedAt
+	r.ReviewedAt = &reviewedAt
+	r.Notes = outcome.Notes
+}
+
+// conditionApplies reports whether a condition applies to a session: it is
+// restricted to none or the session's purpose, and not bypassed by a
+// break-glass session.
+func conditionApplies(condition *Condition, session *Session) bool {
+	return matchesPurpose(condition.Purposes, session) && !(condition.BreakGlassBypass && session.IsBreakGlass())
+}
+
+// obligationApplies reports whether an obligation applies to a session: it
+// is restricted to none or the session's purpose, and to break-glass
+// sessions only if it is marked BreakGlass.
+func obligationApplies(obligation *Obligation, session *Session) bool {
+	return matchesPurpose(obligation.Purposes, session) && (!obligation.BreakGlass || session.IsBreakGlass())
+}
+
+// breakGlassDeadline returns when a break-glass session expires.
+func (u *UconEnforcer) breakGlassDeadline(session *Session) (time.Time, bool) {
+	if !session.IsBreakGlass() || u.breakGlassTTL <= 0 {
+		return time.Time{}, false
+	}
+	return session.GetStartTime().Add(u.breakGlassTTL), true
+}
+
+// breakGlassExpired reports whether a break-glass session outlived the TTL.
+func (u *UconEnforcer) breakGlassExpired(session *Session, now time.Time) bool {
+	deadline, ok := u.breakGlassDeadline(session)
+	return ok && !now.Before(deadline)
+}
+
+// IsBreakGlass reports whether the session was created with
+// CreateBreakGlassSession.
+func (s *Session) IsBreakGlass() bool {
+	return s.GetBreakGlassJustification() != ""
+}
+
+// GetBreakGlassJustification returns the justification of a break-glass
+// session, or "".
+func (s *Session) GetBreakGlassJustification() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.breakGlass
+}
+
+func (s *Session) setBreakGlass(justification string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.breakGlass = justification
+	return s.persistLocked()
+}
+
+func (s *Session) setBreakGlassReview(outcome *BreakGlassOutcome) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.review = outcome.clone()
+	return s.persistLocked()
+}
+
+func (s *Session) getBreakGlassReview() *BreakGlassOutcome {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.review.clone()
+}
@@ -0,0 +1,165 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBreakGlassSession(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithBreakGlassTTL(time.Hour)).(*UconEnforcer)
+	var mu sync.Mutex
+	var alerts []Event
+	uconE.AddEventListener(func(event Event) {
+		if event.Type == EventBreakGlass {
+			mu.Lock()
+			alerts = append(alerts, event)
+			mu.Unlock()
+		}
+	})
+	_ = uconE.AddCondition(&Condition{ID: "department", Name: "attribute_equals", Kind: "always", Expr: "department:cardiology", BreakGlassBypass: true})
+	_ = uconE.AddObligation(&Obligation{ID: "page", Name: "set_attribute", Kind: "pre", Expr: "paged:true", BreakGlass: true})
+
+	if _, err := uconE.CreateBreakGlassSession("alice", "read", "document1", " ", nil); !errors.Is(err, ErrNoJustification) {
+		t.Errorf("Expected ErrNoJustification, got %v", err)
+	}
+
+	normal, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"department": "oncology"})
+	if granted, _ := uconE.EnforceWithSession(normal); granted != nil {
+		t.Error("Expected the condition to deny an ordinary session")
+	}
+
+	id, err := uconE.CreateBreakGlassSession("alice", "read", "document1", "patient in ER", map[string]interface{}{"department": "oncology"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	granted, err := uconE.EnforceWithSession(id)
+	if granted == nil || err != nil {
+		t.Fatalf("Expected the break-glass session to bypass the condition, got %v", err)
+	}
+	if !granted.IsBreakGlass() || granted.ToRecord().BreakGlass != "patient in ER" || granted.GetAttribute("paged") != true {
+		t.Errorf("Expected a break-glass session with its obligation executed, got %+v", granted.ToRecord())
+	}
+	if session, _ := uconE.GetSession(normal); session.GetAttribute("paged") != nil {
+		t.Error("Expected break-glass obligations to skip ordinary sessions")
+	}
+	mu.Lock()
+	if len(alerts) != 1 || alerts[0].Data["justification"] != "patient in ER" {
+		t.Errorf("Expected one break-glass alert, got %+v", alerts)
+	}
+	mu.Unlock()
+
+	pending := uconE.GetBreakGlassReviews(true)
+	if len(pending) != 1 || pending[0].SessionID != id || !pending[0].Pending() {
+		t.Fatalf("Expected a pending review, got %+v", pending)
+	}
+	if err := uconE.ReviewBreakGlass(normal, "bob", ""); !errors.Is(err, ErrReviewNotFound) {
+		t.Errorf("Expected ErrReviewNotFound for an ordinary session, got %v", err)
+	}
+	if err := uconE.ReviewBreakGlass(id, "bob", "justified"); err != nil {
+		t.Fatal(err)
+	}
+	if pending := uconE.GetBreakGlassReviews(true); len(pending) != 0 {
+		t.Errorf("Expected no pending reviews, got %+v", pending)
+	}
+	if reviews := uconE.GetBreakGlassReviews(false); len(reviews) != 1 || reviews[0].Reviewer != "bob" {
+		t.Errorf("Expected the reviewed record, got %+v", reviews)
+	}
+}
+
+func TestBreakGlassTTL(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithBreakGlassTTL(50*time.Millisecond)).(*UconEnforcer)
+	id, _ := uconE.CreateBreakGlassSession("alice", "read", "document1", "outage", nil)
+	if mine := uconE.GetMySessions("alice"); len(mine) != 1 || mine[0].ExpiresAt.IsZero() {
+		t.Errorf("Expected the TTL to expire the session, got %+v", mine)
+	}
+	time.Sleep(60 * time.Millisecond)
+
+	if _, err := uconE.EnforceWithSession(id); !errors.Is(err, ErrBreakGlassExpired) || ReasonFor(err) != ReasonSessionRevoked {
+		t.Errorf("Expected ErrBreakGlassExpired, got %v", err)
+	}
+	session, _ := uconE.GetSession(id)
+	if session.IfActive() || session.GetStopReason() != BreakGlassExpiredReason {
+		t.Errorf("Expected the session to be revoked, got %q", session.GetStopReason())
+	}
+	if reviews := uconE.GetBreakGlassReviews(true); len(reviews) != 1 || reviews[0].StopReason != BreakGlassExpiredReason {
+		t.Errorf("Expected the review to record the revocation, got %+v", reviews)
+	}
+}
+
+func TestBreakGlassMonitoring(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithBreakGlassTTL(100*time.Millisecond)).(*UconEnforcer)
+	id, _ := uconE.CreateBreakGlassSession("alice", "read", "document1", "outage", nil)
+	if granted, err := uconE.EnforceWithSession(id); granted == nil {
+		t.Fatalf("Expected access, got %v", err)
+	}
+	session, _ := uconE.GetSession(id)
+	waitFor(t, func() bool { return !session.IfActive() })
+	if session.GetStopReason() != BreakGlassExpiredReason {
+		t.Errorf("Expected the monitor to revoke the session, got %q", session.GetStopReason())
+	}
+}
+
+func TestBreakGlassReviewSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.wal")
+	store, _ := NewWALSessionStore(path)
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithSessionStore(store))
+	pendingID, _ := uconE.CreateBreakGlassSession("alice", "read", "document1", "outage", nil)
+	reviewedID, _ := uconE.CreateBreakGlassSession("bob", "read", "document1", "incident", nil)
+	if err := uconE.ReviewBreakGlass(reviewedID, "carol", "justified"); err != nil {
+		t.Fatal(err)
+	}
+	_ = store.Close()
+
+	reopened, err := NewWALSessionStore(path)
+	if err != nil {
+		t.Fatalf("Failed to replay WAL: %v", err)
+	}
+	defer reopened.Close()
+	restarted := NewUconEnforcer(e, WithSessionStore(reopened))
+
+	reviews := restarted.GetBreakGlassReviews(false)
+	if len(reviews) != 2 {
+		t.Fatalf("Expected both reviews to survive the restart, got %+v", reviews)
+	}
+	for _, review := range reviews {
+		switch review.SessionID {
+		case pendingID:
+			if !review.Pending() || review.Justification != "outage" {
+				t.Errorf("Expected a pending review, got %+v", review)
+			}
+			if data, _ := json.Marshal(review); strings.Contains(string(data), "reviewed_at") || strings.Contains(string(data), "end_time") {
+				t.Errorf("Expected a pending review of an active session to leave out its times, got %s", data)
+			}
+		case reviewedID:
+			if review.Reviewer != "carol" || review.Notes != "justified" || review.ReviewedAt == nil {
+				t.Errorf("Expected the recorded review, got %+v", review)
+			}
+		}
+	}
+	if session, _ := restarted.GetSession(pendingID); session == nil || !session.IsBreakGlass() {
+		t.Error("Expected the session to remain a break-glass session")
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// timeLedger accumulates the session time used per subject, object class and period.
+type timeLedger struct {
+	used map[string]time.Duration
+	mu   sync.Mutex
+}
+
+func newTimeLedger() *timeLedger {
+	return &timeLedger{used: make(map[string]time.Duration)}
+}
+
+func timeLedgerKey(subject string, class string, periodStart time.Time) string {
+	return subject + "\x00" + class + "\x00" + periodStart.Format(time.RFC3339)
+}
+
+// periodStart returns the start of the day, week (Monday) or month containing t.
+func periodStart(period string, t time.Time) (time.Time, error) {
+	year, month, day := t.Date()
+	switch period {
+	case "day":
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location()), nil
+	case "week":
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, t.Location()), nil
+	case "month":
+		return time.Date(year, month, 1, 0, 0, 0, 0, t.Location()), nil
+	default:
+		return time.Time{}, fmt.Errorf("invalid period %q, expected day, week or month", period)
+	}
+}
+
+// GetTimeUsage returns how much session time subject has used on an object
+// class in the current day, week or month, as accounted by time_budget conditions.
+func (u *UconEnforcer) GetTimeUsage(subject string, class string, period string) (time.Duration, error) {
+	start, err := periodStart(period, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	u.timeLedger.mu.Lock()
+	defer u.timeLedger.mu.Unlock()
+	return u.timeLedger.used[timeLedgerKey(subject, class, start)], nil
+}
+
+// checkTimeBudget limits the total session time of a subject per object class
+// and period, across sessions. Each evaluation charges the time elapsed since
+// the previous one, so the budget can run out mid-session. The object class is
+// the value of the "by" attribute, or the session object.
+// Expr: "<duration> per day|week|month [by <attribute>]",
+// e.g. "10h per month by content_type".
+func (u *UconEnforcer) checkTimeBudget(expr string, session *Session) (bool, error) {
+	fields := strings.Fields(expr)
+	if (len(fields) != 3 && len(fields) != 5) || fields[1] != "per" || (len(fields) == 5 && fields[3] != "by") {
+		return false, fmt.Errorf("invalid expression format: %s, expected '<duration> per day|week|month [by <attribute>]'", expr)
+	}
+	budget, err := time.ParseDuration(fields[0])
+	if err != nil {
+		return false, fmt.Errorf("invalid budget in %s: %w", expr, err)
+	}
+	now := time.Now()
+	start, err := periodStart(fields[2], now)
+	if err != nil {
+		return false, err
+	}
+	class := session.GetObject()
+	if len(fields) == 5 {
+		val := session.GetAttribute(fields[4])
+		if val == nil {
+			return false, &MissingAttributeError{Key: fields[4]}
+		}
+		class = fmt.Sprint(val)
+	}
+
+	from := session.chargeTime(expr, now)
+	if from.Before(start) {
+		from = start
+	}
+	key := timeLedgerKey(session.GetSubject(), class, start)
+	u.timeLedger.mu.Lock()
+	defer u.timeLedger.mu.Unlock()
+	used := u.timeLedger.used[key]
+	if now.After(from) {
+		used += now.Sub(from)
+	}
+	// A dry run only previews the charge.
+	if !session.IsDryRun() {
+		u.timeLedger.used[key] = used
+	}
+	return used < budget, nil
+}
+
+// chargeTime records that the session's time up to now has been charged to
+// a budget and returns when it was last charged, or the session start.
+func (s *Session) chargeTime(budget string, now time.Time) time.Time {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.chargedAt == nil {
+		s.chargedAt = make(map[string]time.Time)
+	}
+	last, ok := s.chargedAt[budget]
+	if !ok {
+		last = s.startTime
+	}
+	s.chargedAt[budget] = now
+	return last
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPeriodStart(t *testing.T) {
+	now := time.Date(2025, time.March, 13, 15, 4, 5, 0, time.UTC) // a Thursday
+	tests := map[string]time.Time{
+		"day":   time.Date(2025, time.March, 13, 0, 0, 0, 0, time.UTC),
+		"week":  time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC),
+		"month": time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for period, want := range tests {
+		if got, err := periodStart(period, now); err != nil || !got.Equal(want) {
+			t.Errorf("%s: got %v, %v, want %v", period, got, err, want)
+		}
+	}
+	if _, err := periodStart("year", now); err == nil {
+		t.Error("Expected an error for an unknown period")
+	}
+}
+
+func TestTimeBudget(t *testing.T) {
+	uconE := GetUconEnforcer()
+	_ = uconE.AddCondition(&Condition{ID: "streaming", Name: "time_budget", Kind: "always", Expr: "700ms per day by content_type"})
+
+	enforce := func(class string) *Session {
+		sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"content_type": class})
+		session, err := uconE.EnforceWithSession(sessionID)
+		if err != nil {
+			t.Fatalf("EnforceWithSession: %v", err)
+		}
+		return session
+	}
+
+	first := enforce("video")
+	second := enforce("video")
+	if first == nil || second == nil {
+		t.Fatal("Expected access within the budget")
+	}
+
+	// Two concurrent sessions use up the budget twice as fast.
+	time.Sleep(700 * time.Millisecond)
+	if first.IfActive() || second.IfActive() {
+		t.Fatal("Expected sessions to be revoked once the shared budget is exhausted")
+	}
+	if used, _ := uconE.GetTimeUsage("alice", "video", "day"); used < 700*time.Millisecond {
+		t.Errorf("Expected at least the budget to be used, got %v", used)
+	}
+
+	if enforce("video") != nil {
+		t.Error("Expected new sessions to be denied for the rest of the period")
+	}
+	if other := enforce("music"); other == nil {
+		t.Error("Expected another object class to have its own budget")
+	} else {
+		_ = uconE.StopMonitoring(other.GetId())
+	}
+}
+
+func TestTimeBudgetDryRun(t *testing.T) {
+	uconE := GetUconEnforcer()
+	_ = uconE.AddCondition(&Condition{ID: "budget", Name: "time_budget", Kind: "always", Expr: "10h per day"})
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	if ok, err := uconE.EvaluateConditions(sessionID); !ok || err != nil {
+		t.Fatalf("Expected the session within its budget, got %v, %v", ok, err)
+	}
+	used, _ := uconE.GetTimeUsage("alice", "document1", "day")
+
+	// An edited budget expression is charged from the session start.
+	config := []byte(`{"version": 1, "conditions": [{"id": "budget", "name": "time_budget", "kind": "always", "expr": "11h per day"}]}`)
+	if _, err := uconE.DiffConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uconE.SimulateSession(&SessionRequest{Subject: "alice", Action: "read", Object: "document1"}); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := uconE.GetTimeUsage("alice", "document1", "day"); after != used {
+		t.Errorf("Expected dry runs to leave the time usage at %v, got %v", used, after)
+	}
+}
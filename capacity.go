@@ -0,0 +1,148 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"time"
+)
+
+// CapacityPolicy decides what happens to a session that would exceed the
+// maximum number of monitored sessions.
+type CapacityPolicy int
+
+const (
+	// CapacityReject refuses the session with ErrCapacityExceeded.
+	CapacityReject CapacityPolicy = iota
+	// CapacityQueue waits up to DefaultCapacityQueueTimeout for a monitored
+	// session to stop, then refuses the session with ErrCapacityExceeded.
+	CapacityQueue
+	// CapacityDegrade monitors the session anyway, but only every
+	// DefaultDegradedMonitorInterval.
+	CapacityDegrade
+	// CapacityWaitlist refuses the session with an error wrapping
+	// ErrCapacityExceeded and ErrWaitlisted, and puts it on a waitlist. When
+	// a slot frees, the first session on the waitlist gets a reservation
+	// for it, see EventWaitlistAdmitted.
+	CapacityWaitlist
+)
+
+const (
+	// DefaultMonitorInterval is how often a monitored session is evaluated.
+	DefaultMonitorInterval = 200 * time.Millisecond
+	// DefaultDegradedMonitorInterval is how often sessions monitored beyond
+	// capacity under CapacityDegrade are evaluated.
+	DefaultDegradedMonitorInterval = 2 * time.Second
+	// DefaultCapacityQueueTimeout is how long CapacityQueue waits for a slot.
+	DefaultCapacityQueueTimeout = 5 * time.Second
+)
+
+// ErrCapacityExceeded is returned when a session cannot be monitored because
+// the maximum number of monitored sessions is reached.
+var ErrCapacityExceeded = errors.New("monitoring capacity exceeded")
+
+// WithMaxMonitoredSessions caps the number of concurrently monitored
+// sessions, each of which costs a goroutine and its evaluations. Sessions
+// beyond max are handled according to policy.
+func WithMaxMonitoredSessions(max int, policy CapacityPolicy) Option {
+	return func(u *UconEnforcer) {
+		u.maxMonitored = max
+		u.capacityPolicy = policy
+	}
+}
+
+// reserveMonitoring marks a session as monitored and returns the interval to
+// monitor it at, or 0 if it is already monitored. When the maximum number of
+// monitored sessions is reached, it evicts a lower priority session or
+// applies the capacity policy.
+func (u *UconEnforcer) reserveMonitoring(session *Session) (time.Duration, error) {
+	sessionID, priority := session.GetId(), session.GetPriority()
+	deadline := time.Now().Add(DefaultCapacityQueueTimeout)
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for {
+		if u.monitoringActive[sessionID] {
+			return 0, nil
+		}
+		if u.capacityPolicy == CapacityWaitlist && len(u.waitlist) > 0 && u.waitlist[0] != sessionID {
+			// Free slots belong to the sessions waiting for one.
+			return 0, u.waitlistLocked(sessionID)
+		}
+		if u.maxMonitored <= 0 || u.monitoredCountLocked() < u.maxMonitored {
+			u.monitoringActive[sessionID] = true
+			u.leaveWaitlistLocked(sessionID)
+			return priority.monitorInterval(), nil
+		}
+		if victim := u.evictionCandidateLocked(priority); victim != nil {
+			// Stopping the victim releases its slot, which takes u.mu.
+			u.mu.Unlock()
+			u.evict(victim)
+			u.mu.Lock()
+			continue
+		}
+
+		switch u.capacityPolicy {
+		case CapacityDegrade:
+			u.monitoringActive[sessionID] = true
+			return DefaultDegradedMonitorInterval, nil
+		case CapacityQueue:
+			wait := time.Until(deadline)
+			if wait <= 0 {
+				return 0, ErrCapacityExceeded
+			}
+			freed := u.slotFreed
+			u.mu.Unlock()
+			select {
+			case <-freed:
+			case <-time.After(wait):
+			}
+			u.mu.Lock()
+		case CapacityWaitlist:
+			return 0, u.waitlistLocked(sessionID)
+		default:
+			return 0, ErrCapacityExceeded
+		}
+	}
+}
+
+// evict runs the post obligations of a monitored session and stops it with
+// EvictedReason, like StopMonitoring.
+func (u *UconEnforcer) evict(session *Session) {
+	if err := u.ExecuteObligationsByType(session.GetId(), "post"); err != nil {
+		u.logger.Log(LevelWarn, "post obligations failed for evicted session", map[string]interface{}{"session": session.GetId(), "error": err.Error()})
+	}
+	u.stopMonitored(session, &TraceEntry{Time: time.Now()}, EvictedReason)
+}
+
+// releaseMonitoring frees the monitoring slot of a session and wakes the
+// sessions queued for one. The session's monitoring lease, if any, is given up.
+func (u *UconEnforcer) releaseMonitoring(sessionID string) {
+	u.mu.Lock()
+	if !u.monitoringActive[sessionID] {
+		u.mu.Unlock()
+		return
+	}
+	delete(u.monitoringActive, sessionID)
+	close(u.slotFreed)
+	u.slotFreed = make(chan struct{})
+	u.admitWaitlistLocked()
+	u.mu.Unlock()
+	u.releaseLease(sessionID)
+}
+
+func (u *UconEnforcer) monitoredCountLocked() int {
+	// Sessions leave monitoringActive when their slot is released.
+	return len(u.monitoringActive)
+}
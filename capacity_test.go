@@ -0,0 +1,89 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMaxMonitoredSessionsReject(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithMaxMonitoredSessions(1, CapacityReject))
+
+	first, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	second, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	if session, err := uconE.EnforceWithSession(first); session == nil {
+		t.Fatalf("Expected access, got %v", err)
+	}
+	if _, err := uconE.EnforceWithSession(first); err != nil {
+		t.Errorf("Expected a monitored session to be enforced again, got %v", err)
+	}
+	if session, err := uconE.EnforceWithSession(second); session != nil || !errors.Is(err, ErrCapacityExceeded) {
+		t.Fatalf("Expected ErrCapacityExceeded, got %v", err)
+	}
+
+	_ = uconE.StopMonitoring(first)
+	if session, err := uconE.EnforceWithSession(second); session == nil {
+		t.Fatalf("Expected the freed slot to be reused, got %v", err)
+	}
+	_ = uconE.StopMonitoring(second)
+}
+
+func TestMaxMonitoredSessionsQueue(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithMaxMonitoredSessions(1, CapacityQueue))
+
+	first, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	second, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	_, _ = uconE.EnforceWithSession(first)
+
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		_ = uconE.StopMonitoring(first)
+	}()
+	start := time.Now()
+	if session, err := uconE.EnforceWithSession(second); session == nil {
+		t.Fatalf("Expected the queued session to be granted, got %v", err)
+	}
+	if waited := time.Since(start); waited < 250*time.Millisecond || waited > 2*time.Second {
+		t.Errorf("Expected to wait for the slot, waited %v", waited)
+	}
+	_ = uconE.StopMonitoring(second)
+}
+
+func TestMaxMonitoredSessionsDegrade(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithMaxMonitoredSessions(1, CapacityDegrade))
+
+	first, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	second, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	_, _ = uconE.EnforceWithSession(first)
+	if session, err := uconE.EnforceWithSession(second); session == nil {
+		t.Fatalf("Expected the degraded session to be granted, got %v", err)
+	}
+	defer func() {
+		_ = uconE.StopMonitoring(first)
+		_ = uconE.StopMonitoring(second)
+	}()
+
+	time.Sleep(time.Second)
+	normal, _ := uconE.GetSessionTrace(first)
+	degraded, _ := uconE.GetSessionTrace(second)
+	if len(normal) < 4 || len(degraded) != 0 {
+		t.Errorf("Expected the second session to be evaluated less often, got %d and %d evaluations", len(normal), len(degraded))
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCertificateAttribute is the session attribute holding the client
+// certificate checked by the client_certificate condition.
+const DefaultCertificateAttribute = "client_cert"
+
+// RevocationChecker reports whether a certificate has been revoked, e.g. by
+// consulting a CRL or an OCSP responder. It is called on every evaluation of
+// the client_certificate condition, so implementations should cache answers.
+type RevocationChecker interface {
+	IsRevoked(cert *x509.Certificate) (bool, error)
+}
+
+// WithRevocationChecker sets the revocation checker of the client_certificate condition.
+func WithRevocationChecker(checker RevocationChecker) Option {
+	return func(u *UconEnforcer) {
+		u.revocationChecker = checker
+	}
+}
+
+// EncodeCertificate returns the PEM encoding of cert, suitable as the
+// session attribute checked by client_certificate. A *x509.Certificate is
+// accepted as well, but does not survive session stores.
+func EncodeCertificate(cert *x509.Certificate) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+}
+
+// checkClientCertificate is the "client_certificate" condition. It passes
+// while the session-bound client certificate is within its validity period,
+// matches the required issuer and SAN, and is not revoked.
+// Expr: "[attr=<key>]; [issuer=<name>]; [san=<pattern>]", e.g.
+// "issuer=Device CA; san=*.devices.example.com". The issuer matches the
+// issuer common name or full name; the SAN pattern (path.Match syntax)
+// matches any DNS name, email address, URI or IP address.
+func (u *UconEnforcer) checkClientCertificate(expr string, session *Session) (bool, error) {
+	key, issuer, san := DefaultCertificateAttribute, "", ""
+	for _, option := range strings.Split(expr, ";") {
+		if option = strings.TrimSpace(option); option == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(option, "=")
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(name) {
+		case "attr":
+			key = value
+		case "issuer":
+			issuer = value
+		case "san":
+			san = value
+		default:
+			ok = false
+		}
+		if !ok || value == "" {
+			return false, fmt.Errorf("invalid option %q in %s, expected attr=, issuer= or san=", option, expr)
+		}
+	}
+
+	cert, err := sessionCertificate(session, key)
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+	if now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		return false, nil
+	}
+	if issuer != "" && cert.Issuer.CommonName != issuer && cert.Issuer.String() != issuer {
+		return false, nil
+	}
+	if san != "" && !matchesSAN(cert, san) {
+		return false, nil
+	}
+	if u.revocationChecker != nil {
+		revoked, err := u.revocationChecker.IsRevoked(cert)
+		if err != nil {
+			return false, fmt.Errorf("revocation check of certificate %s failed: %w", cert.SerialNumber, err)
+		}
+		if revoked {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func sessionCertificate(session *Session, key string) (*x509.Certificate, error) {
+	switch v := session.GetAttribute(key).(type) {
+	case *x509.Certificate:
+		return v, nil
+	case string:
+		block, _ := pem.Decode([]byte(v))
+		if block == nil || block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("%s attribute is not a PEM certificate", key)
+		}
+		return x509.ParseCertificate(block.Bytes)
+	case nil:
+		return nil, &MissingAttributeError{Key: key}
+	default:
+		return nil, fmt.Errorf("%s attribute is not a certificate", key)
+	}
+}
+
+func matchesSAN(cert *x509.Certificate, pattern string) bool {
+	names := append([]string{}, cert.DNSNames...)
+	names = append(names, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		names = append(names, uri.String())
+	}
+	for _, ip := range cert.IPAddresses {
+		names = append(names, ip.String())
+	}
+	for _, name := range names {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// CRLChecker is a RevocationChecker backed by certificate revocation lists.
+// Feed it the current CRLs of the issuing CAs with Update.
+type CRLChecker struct {
+	revoked map[string]map[string]bool // issuer -> serial numbers
+	mu      sync.RWMutex
+}
+
+// NewCRLChecker creates a CRLChecker with no revocations.
+func NewCRLChecker() *CRLChecker {
+	return &CRLChecker{revoked: make(map[string]map[string]bool)}
+}
+
+// Update replaces the revocations of the CRL's issuer with those in the DER
+// encoded CRL. If issuerCert is not nil, the CRL signature is verified
+// against it.
+func (c *CRLChecker) Update(der []byte, issuerCert *x509.Certificate) error {
+	crl, err := x509.ParseRevocationList(der)
+	if err != nil {
+		return fmt.Errorf("invalid CRL: %w", err)
+	}
+	if issuerCert != nil {
+		if err := crl.CheckSignatureFrom(issuerCert); err != nil {
+			return fmt.Errorf("invalid CRL signature: %w", err)
+		}
+	}
+	serials := make(map[string]bool, len(crl.RevokedCertificateEntries))
+	for _, entry := range crl.RevokedCertificateEntries {
+		serials[entry.SerialNumber.String()] = true
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.revoked[string(crl.RawIssuer)] = serials
+	return nil
+}
+
+// Revoke marks a certificate serial number of an issuer as revoked, e.g. on
+// a push notification from the CA.
+func (c *CRLChecker) Revoke(issuer *x509.Certificate, serial *big.Int) error {
+	if issuer == nil || serial == nil {
+		return errors.New("issuer and serial are required")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	serials := c.revoked[string(issuer.RawSubject)]
+	if serials == nil {
+		serials = make(map[string]bool)
+		c.revoked[string(issuer.RawSubject)] = serials
+	}
+	serials[serial.String()] = true
+	return nil
+}
+
+// IsRevoked implements RevocationChecker.
+func (c *CRLChecker) IsRevoked(cert *x509.Certificate) (bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.revoked[string(cert.RawIssuer)][cert.SerialNumber.String()], nil
+}
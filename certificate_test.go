@@ -0,0 +1,121 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func newTestCA(t *testing.T) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Device CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, _ := x509.ParseCertificate(der)
+	return ca, key
+}
+
+func newTestClientCert(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey, serial int64, dnsName string) *x509.Certificate {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: dnsName},
+		DNSNames:     []string{dnsName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return cert
+}
+
+func TestClientCertificateCondition(t *testing.T) {
+	ca, caKey := newTestCA(t)
+	crls := NewCRLChecker()
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithRevocationChecker(crls))
+	_ = uconE.AddCondition(&Condition{ID: "device_cert", Name: "client_certificate", Kind: "always", Expr: "issuer=Device CA; san=*.devices.example.com"})
+
+	enforce := func(cert interface{}) (*Session, error) {
+		sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"client_cert": cert})
+		return uconE.EnforceWithSession(sessionID)
+	}
+
+	if session, _ := enforce(newTestClientCert(t, ca, caKey, 2, "sensor.other.example.com")); session != nil {
+		t.Error("Expected a certificate with a foreign SAN to be refused")
+	}
+	if _, err := enforce("not a certificate"); err == nil {
+		t.Error("Expected an invalid certificate attribute to fail")
+	}
+
+	cert := newTestClientCert(t, ca, caKey, 3, "sensor7.devices.example.com")
+	session, err := enforce(EncodeCertificate(cert))
+	if err != nil || session == nil {
+		t.Fatalf("Expected the device certificate to be accepted, got %v", err)
+	}
+
+	// The CA revokes the certificate mid-session.
+	der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:                    big.NewInt(1),
+		ThisUpdate:                time.Now(),
+		NextUpdate:                time.Now().Add(time.Hour),
+		RevokedCertificateEntries: []x509.RevocationListEntry{{SerialNumber: cert.SerialNumber, RevocationTime: time.Now()}},
+	}, ca, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := crls.Update(der, ca); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	time.Sleep(400 * time.Millisecond)
+	if session.IfActive() {
+		_ = uconE.StopMonitoring(session.GetId())
+		t.Error("Expected the session to be revoked with the certificate")
+	}
+}
+
+func TestCRLCheckerRevoke(t *testing.T) {
+	ca, caKey := newTestCA(t)
+	cert := newTestClientCert(t, ca, caKey, 4, "sensor8.devices.example.com")
+	crls := NewCRLChecker()
+	if revoked, _ := crls.IsRevoked(cert); revoked {
+		t.Error("Expected the certificate not to be revoked")
+	}
+	_ = crls.Revoke(ca, cert.SerialNumber)
+	if revoked, _ := crls.IsRevoked(cert); !revoked {
+		t.Error("Expected the certificate to be revoked")
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// channelBindingLabel is the exporter label of tls-exporter channel bindings (RFC 9266).
+const channelBindingLabel = "EXPORTER-Channel-Binding"
+
+var (
+	// ErrChannelMismatch is returned when a channel-bound session is used over a
+	// different TLS connection, e.g. with a stolen session token.
+	ErrChannelMismatch = errors.New("request arrived over a different TLS channel")
+	// ErrChannelAlreadyBound is returned by BindTLSChannel for sessions that
+	// are already bound.
+	ErrChannelAlreadyBound = errors.New("session is already bound to a TLS channel")
+)
+
+// TLSChannelBinding returns the hex encoded SHA-256 hash of the keying
+// material exported from a TLS connection (RFC 9266). It identifies the
+// connection without revealing key material. TLS 1.2 connections need the
+// extended master secret extension.
+func TLSChannelBinding(state *tls.ConnectionState) (string, error) {
+	if state == nil {
+		return "", errors.New("not a TLS connection")
+	}
+	material, err := state.ExportKeyingMaterial(channelBindingLabel, nil, 32)
+	if err != nil {
+		return "", fmt.Errorf("cannot export keying material: %w", err)
+	}
+	sum := sha256.Sum256(material)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// BindTLSChannel binds a session to the TLS connection it was established
+// over. Requests for the session over other connections are refused by
+// AuthorizeHTTPRequest. The binding is kept apart from the session
+// attributes and set once: it cannot be changed or cleared afterwards.
+func (u *UconEnforcer) BindTLSChannel(sessionID string, state *tls.ConnectionState) error {
+	session, err := u.GetSession(sessionID)
+	if err != nil {
+		return err
+	}
+	if session.getChannelBinding() != "" {
+		return ErrChannelAlreadyBound
+	}
+	binding, err := TLSChannelBinding(state)
+	if err != nil {
+		return err
+	}
+	return session.bindChannel(binding)
+}
+
+// VerifyChannelBinding checks that a request arrived over the TLS channel
+// its session is bound to. Sessions never bound pass; a bound session fails
+// unless the request carries the same channel.
+func VerifyChannelBinding(session *Session, state *tls.ConnectionState) error {
+	bound := session.getChannelBinding()
+	if bound == "" {
+		return nil
+	}
+	binding, err := TLSChannelBinding(state)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrChannelMismatch, err)
+	}
+	if subtle.ConstantTimeCompare([]byte(binding), []byte(bound)) != 1 {
+		return ErrChannelMismatch
+	}
+	return nil
+}
+
+// AuthorizeHTTPRequest is AuthorizeRequest for a request over a possibly
+// channel-bound session: requests arriving over a different TLS channel than
+// the session is bound to are answered with 403 before enforcement.
+func AuthorizeHTTPRequest(e IUconEnforcer, sessionID string, r *http.Request) (*Session, int, error) {
+	if sessionID != "" {
+		if session, err := e.GetSession(sessionID); err == nil {
+			if err := VerifyChannelBinding(session, r.TLS); err != nil {
+				return nil, http.StatusForbidden, fmt.Errorf("%w: %w", ErrSessionDenied, err)
+			}
+		}
+	}
+	return AuthorizeRequest(e, sessionID)
+}
+
+// checkChannelBound is the "channel_bound" condition: the session is bound to
+// a TLS channel with BindTLSChannel, so AuthorizeHTTPRequest refuses it over
+// other channels.
+func checkChannelBound(expr string, session *Session) (bool, error) {
+	return session.getChannelBinding() != "", nil
+}
+
+func (s *Session) bindChannel(binding string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if !s.hasFlag(flagActive) {
+		return ErrSessionNotActive
+	}
+	if s.binding != "" {
+		return ErrChannelAlreadyBound
+	}
+	s.binding = binding
+	return s.persistLocked()
+}
+
+func (s *Session) getChannelBinding() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.binding
+}
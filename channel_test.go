@@ -0,0 +1,89 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTLSChannelBinding(t *testing.T) {
+	uconE := GetUconEnforcer()
+	_ = uconE.AddCondition(&Condition{ID: "bound", Name: "channel_bound", Kind: "always"})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+		if err := uconE.BindTLSChannel(sessionID, r.TLS); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(sessionID))
+	})
+	mux.Handle("/docs", NewHTTPMiddleware(uconE, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+	server := httptest.NewTLSServer(mux)
+	defer server.Close()
+
+	client := server.Client()
+	resp, err := client.Get(server.URL + "/login")
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 64)
+	n, _ := resp.Body.Read(buf)
+	resp.Body.Close()
+	sessionID := string(buf[:n])
+	defer uconE.StopMonitoring(sessionID)
+
+	get := func(client *http.Client) int {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/docs", nil)
+		req.Header.Set(DefaultSessionHeader, sessionID)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if status := get(client); status != http.StatusOK {
+		t.Errorf("Expected 200 over the bound connection, got %d", status)
+	}
+
+	// Neither an attribute update nor a second binding can move the session.
+	_ = uconE.UpdateSessionAttribute(sessionID, "tls_channel_binding", "")
+	session, _ := uconE.GetSession(sessionID)
+	if err := uconE.BindTLSChannel(sessionID, &tls.ConnectionState{}); !errors.Is(err, ErrChannelAlreadyBound) {
+		t.Error("Expected a bound session not to be rebound")
+	}
+	if restored := NewSession(session.ToRecord(), nil, nil); restored.getChannelBinding() == "" {
+		t.Error("Expected the binding to survive the session record")
+	}
+
+	// A thief replays the session id over its own connection.
+	thief := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: client.Transport.(*http.Transport).TLSClientConfig.RootCAs}}}
+	if status := get(thief); status != http.StatusForbidden {
+		t.Errorf("Expected 403 over another connection, got %d", status)
+	}
+
+	// Unbound sessions do not satisfy channel_bound.
+	unboundID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	if session, _ := uconE.EnforceWithSession(unboundID); session != nil {
+		t.Error("Expected an unbound session to be refused")
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command uconctl is a command line client for uconserver.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/casbin/casbin-ucon"
+)
+
+const usage = `Usage: uconctl [-server URL] <command> [arguments]
+
+Sessions:
+  sessions list
+  sessions get ID
+  sessions create SUBJECT ACTION OBJECT [KEY=VALUE ...]
+  sessions enforce ID
+  sessions set ID KEY=VALUE [KEY=VALUE ...]
+  sessions stop ID
+  sessions heartbeat ID
+  sessions fulfill ID OBLIGATION [KEY=VALUE ...]
+  sessions fulfillments ID
+  sessions trace ID
+  sessions revoke ID [REASON]
+  sessions delete ID
+
+Conditions:
+  conditions list
+  conditions add ID NAME KIND EXPR
+  conditions remove ID
+
+Obligations:
+  obligations list
+  obligations add ID NAME KIND EXPR [SCHEDULE]
+  obligations remove ID
+`
+
+type client struct {
+	server string
+	http   *http.Client
+}
+
+func (c *client) do(method string, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, strings.TrimRight(c.server, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		apiErr := map[string]string{}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		if apiErr["error"] != "" {
+			return fmt.Errorf("%s: %s", resp.Status, apiErr["error"])
+		}
+		return fmt.Errorf("%s", resp.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func main() {
+	flags := flag.NewFlagSet("uconctl", flag.ExitOnError)
+	server := flags.String("server", envOr("UCON_SERVER", "http://localhost:8080"), "uconserver base URL (or $UCON_SERVER)")
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	_ = flags.Parse(os.Args[1:])
+
+	c := &client{server: *server, http: &http.Client{Timeout: 30 * time.Second}}
+	if err := run(c, flags.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "uconctl:", err)
+		os.Exit(1)
+	}
+}
+
+func run(c *client, args []string) error {
+	if len(args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	resource, command, args := args[0], args[1], args[2:]
+
+	switch resource + " " + command {
+	case "sessions list":
+		var records []*ucon.SessionRecord
+		if err := c.do(http.MethodGet, "/sessions", nil, &records); err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tSUBJECT\tACTION\tOBJECT\tACTIVE\tSTARTED\tSTOP REASON")
+		for _, r := range records {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%v\t%s\t%s\n", r.ID, r.Subject, r.Action, r.Object,
+				r.Active, r.StartTime.Format(time.RFC3339), strings.TrimSpace(r.StopReason))
+		}
+		return w.Flush()
+	case "sessions get":
+		return printResult(c, http.MethodGet, "/sessions/"+arg(args, 0), nil)
+	case "sessions create":
+		if len(args) < 3 {
+			return fmt.Errorf("sessions create needs SUBJECT ACTION OBJECT")
+		}
+		attributes, err := parseAssignments(args[3:])
+		if err != nil {
+			return err
+		}
+		return printResult(c, http.MethodPost, "/sessions", &ucon.CreateSessionRequest{
+			Subject: args[0], Action: args[1], Object: args[2], Attributes: attributes,
+		})
+	case "sessions enforce":
+		return printResult(c, http.MethodPost, "/sessions/"+arg(args, 0)+"/enforce", nil)
+	case "sessions set":
+		attributes, err := parseAssignments(args[1:])
+		if err != nil {
+			return err
+		}
+		return printResult(c, http.MethodPatch, "/sessions/"+arg(args, 0)+"/attributes", attributes)
+	case "sessions stop":
+		return printResult(c, http.MethodPost, "/sessions/"+arg(args, 0)+"/stop", nil)
+	case "sessions heartbeat":
+		return c.do(http.MethodPost, "/sessions/"+arg(args, 0)+"/heartbeat", nil, nil)
+	case "sessions fulfill":
+		if len(args) < 2 {
+			return fmt.Errorf("sessions fulfill needs ID OBLIGATION")
+		}
+		evidence, err := parseAssignments(args[2:])
+		if err != nil {
+			return err
+		}
+		return c.do(http.MethodPost, "/sessions/"+arg(args, 0)+"/fulfill",
+			&ucon.FulfillRequest{ObligationID: args[1], Evidence: evidence}, nil)
+	case "sessions fulfillments":
+		return printResult(c, http.MethodGet, "/sessions/"+arg(args, 0)+"/fulfillments", nil)
+	case "sessions trace":
+		return printResult(c, http.MethodGet, "/sessions/"+arg(args, 0)+"/trace", nil)
+	case "sessions revoke":
+		return printResult(c, http.MethodPost, "/sessions/"+arg(args, 0)+"/revoke",
+			&ucon.RevokeRequest{Reason: strings.Join(args[min(1, len(args)):], " ")})
+	case "sessions delete":
+		return c.do(http.MethodDelete, "/sessions/"+arg(args, 0), nil, nil)
+	case "conditions list":
+		return printResult(c, http.MethodGet, "/conditions", nil)
+	case "conditions add":
+		if len(args) != 4 {
+			return fmt.Errorf("conditions add needs ID NAME KIND EXPR")
+		}
+		return printResult(c, http.MethodPost, "/conditions", &ucon.Condition{ID: args[0], Name: args[1], Kind: args[2], Expr: args[3]})
+	case "conditions remove":
+		return c.do(http.MethodDelete, "/conditions/"+arg(args, 0), nil, nil)
+	case "obligations list":
+		return printResult(c, http.MethodGet, "/obligations", nil)
+	case "obligations add":
+		if len(args) != 4 && len(args) != 5 {
+			return fmt.Errorf("obligations add needs ID NAME KIND EXPR [SCHEDULE]")
+		}
+		obligation := &ucon.Obligation{ID: args[0], Name: args[1], Kind: args[2], Expr: args[3]}
+		if len(args) == 5 {
+			obligation.Schedule = args[4]
+		}
+		return printResult(c, http.MethodPost, "/obligations", obligation)
+	case "obligations remove":
+		return c.do(http.MethodDelete, "/obligations/"+arg(args, 0), nil, nil)
+	default:
+		return fmt.Errorf("unknown command %q, run uconctl -h for usage", resource+" "+command)
+	}
+}
+
+func printResult(c *client, method string, path string, body interface{}) error {
+	var result interface{}
+	if err := c.do(method, path, body, &result); err != nil {
+		return err
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(result)
+}
+
+// arg returns the i-th argument escaped for use in a URL path.
+func arg(args []string, i int) string {
+	if i >= len(args) {
+		return ""
+	}
+	return url.PathEscape(args[i])
+}
+
+// parseAssignments parses KEY=VALUE arguments; values that are valid JSON
+// (numbers, booleans, quoted strings) are decoded, others are kept as strings.
+func parseAssignments(args []string) (map[string]interface{}, error) {
+	attributes := make(map[string]interface{}, len(args))
+	for _, a := range args {
+		key, value, ok := strings.Cut(a, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid attribute %q, expected KEY=VALUE", a)
+		}
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(value), &decoded); err == nil {
+			attributes[key] = decoded
+		} else {
+			attributes[key] = value
+		}
+	}
+	return attributes, nil
+}
+
+func envOr(name string, def string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return def
+}
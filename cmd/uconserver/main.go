@@ -0,0 +1,265 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command uconserver runs a UCON enforcer as a standalone service exposing
+// the REST API of ucon.NewAPIHandler.
+//
+// Usage:
+//
+//	uconserver -config uconserver.json
+//	uconserver -config candidate.json -replay decisions.jsonl
+//
+// The configuration file is JSON:
+//
+//	{
+//	  "listen": ":8080",
+//	  "model": "model.conf",
+//	  "policy": "policy.csv",
+//	  "session_store": {"type": "wal", "path": "/var/lib/ucon/sessions.wal"},
+//	  "failure_policy": "closed",
+//	  "state_file": "/var/lib/ucon/state.json",
+//	  "decision_log": "/var/log/ucon/decisions.jsonl",
+//	  "conditions": [{"id": "office", "name": "location", "kind": "always", "expr": "office"}],
+//	  "obligations": [{"id": "log", "name": "access_logging", "kind": "post", "expr": "access"}],
+//	  "telemetry_mappings": [{"signal": "screen.locked", "attribute": "screen_locked"}]
+//	}
+//
+// session_store is optional; its type is "file" (a directory) or "wal" (a log file).
+// state_file is optional; the enforcer state is loaded from it at startup if
+// it exists and written to it on shutdown, so a replacement process picks up
+// live sessions, pending obligations and monitoring state.
+// decision_log is optional; access decisions are appended to it as JSON
+// lines. With -replay, the decisions of such a log are replayed against the
+// configured policy and conditions instead of serving: the changed decisions
+// are printed as JSON and the exit status is 1 if there are any.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/casbin/casbin-ucon"
+	"github.com/casbin/casbin/v2"
+)
+
+type storeConfig struct {
+	Type string `json:"type"`
+	Path string `json:"path"`
+}
+
+type config struct {
+	Listen        string            `json:"listen"`
+	Model         string            `json:"model"`
+	Policy        string            `json:"policy"`
+	SessionStore  *storeConfig      `json:"session_store"`
+	FailurePolicy string            `json:"failure_policy"`
+	StateFile     string            `json:"state_file"`
+	DecisionLog   string            `json:"decision_log"`
+	Conditions    []ucon.Condition  `json:"conditions"`
+	Obligations   []ucon.Obligation `json:"obligations"`
+
+	TelemetryMappings []ucon.TelemetryMapping `json:"telemetry_mappings"`
+}
+
+func loadConfig(path string) (*config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &config{Listen: ":8080"}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if cfg.Model == "" {
+		return nil, errors.New("config: model is required")
+	}
+	return cfg, nil
+}
+
+func newEnforcer(cfg *config) (ucon.IUconEnforcer, error) {
+	var e *casbin.Enforcer
+	var err error
+	if cfg.Policy != "" {
+		e, err = casbin.NewEnforcer(cfg.Model, cfg.Policy)
+	} else {
+		e, err = casbin.NewEnforcer(cfg.Model)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []ucon.Option
+	if cfg.SessionStore != nil {
+		var store ucon.SessionStore
+		switch cfg.SessionStore.Type {
+		case "file":
+			store, err = ucon.NewFileSessionStore(cfg.SessionStore.Path)
+		case "wal":
+			store, err = ucon.NewWALSessionStore(cfg.SessionStore.Path)
+		default:
+			err = fmt.Errorf("config: unknown session store type %q", cfg.SessionStore.Type)
+		}
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, ucon.WithSessionStore(store))
+	}
+	if cfg.DecisionLog != "" {
+		f, err := os.OpenFile(cfg.DecisionLog, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, ucon.WithDecisionLog(f))
+	}
+
+	uconE := ucon.NewUconEnforcer(e, opts...)
+	switch cfg.FailurePolicy {
+	case "", "closed":
+	case "open":
+		uconE.SetFailurePolicy(ucon.FailOpen)
+	default:
+		return nil, fmt.Errorf("config: unknown failure policy %q", cfg.FailurePolicy)
+	}
+	for i := range cfg.Conditions {
+		if err := uconE.AddCondition(&cfg.Conditions[i]); err != nil {
+			return nil, err
+		}
+	}
+	for i := range cfg.Obligations {
+		if err := uconE.AddObligation(&cfg.Obligations[i]); err != nil {
+			return nil, err
+		}
+	}
+	for _, mapping := range cfg.TelemetryMappings {
+		if err := uconE.AddTelemetryMapping(mapping); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.StateFile != "" {
+		data, err := os.ReadFile(cfg.StateFile)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		if err == nil {
+			if err := uconE.LoadState(data); err != nil {
+				return nil, fmt.Errorf("%s: %w", cfg.StateFile, err)
+			}
+		}
+	}
+	return uconE, nil
+}
+
+func main() {
+	configPath := flag.String("config", "uconserver.json", "path to the configuration file")
+	replayPath := flag.String("replay", "", "replay a decision log against the configuration and exit")
+	flag.Parse()
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *replayPath != "" {
+		// Replaying must not record decisions or touch the live sessions.
+		cfg.DecisionLog, cfg.SessionStore, cfg.StateFile = "", nil, ""
+	}
+	uconE, err := newEnforcer(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *replayPath != "" {
+		changed, err := replay(uconE, *replayPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if changed {
+			os.Exit(1)
+		}
+		return
+	}
+
+	server := &http.Server{
+		Addr:              cfg.Listen,
+		Handler:           ucon.NewAPIHandler(uconE),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("uconserver listening on %s", cfg.Listen)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+	<-shutdownDone
+	if cfg.StateFile != "" {
+		if err := saveState(uconE, cfg.StateFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// replay replays a decision log and prints the report, returning whether any
+// decision changed.
+func replay(uconE ucon.IUconEnforcer, path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	records, err := ucon.ReadDecisionLog(f)
+	if err != nil {
+		return false, err
+	}
+	report, err := uconE.ReplayDecisions(records)
+	if err != nil {
+		return false, err
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return false, err
+	}
+	return len(report.Changed) > 0, nil
+}
+
+// saveState writes the enforcer state through a temporary file so that a
+// crash while writing does not leave a truncated snapshot behind.
+func saveState(uconE ucon.IUconEnforcer, path string) error {
+	data, err := uconE.DumpState()
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
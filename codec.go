@@ -0,0 +1,41 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import "encoding/json"
+
+// Codec serializes the records of FileSessionStore and the entries of
+// WALSessionStore. JSONCodec is the default; ProtobufCodec is a compact
+// binary alternative. Adapt other formats, such as a msgpack library, by
+// implementing the interface.
+type Codec interface {
+	// Name identifies the format; FileSessionStore uses it as file extension.
+	Name() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// JSONCodec is the Codec using encoding/json. Numbers in attributes are
+// decoded as float64.
+type JSONCodec struct{}
+
+// Name implements Codec.
+func (JSONCodec) Name() string { return "json" }
+
+// Marshal implements Codec.
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+// Unmarshal implements Codec.
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
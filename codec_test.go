@@ -0,0 +1,112 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// countingCodec is a Codec wrapping JSONCodec, like an adapter of a
+// third-party library would.
+type countingCodec struct {
+	JSONCodec
+	marshaled *int
+}
+
+func (c countingCodec) Name() string { return "counted" }
+
+func (c countingCodec) Marshal(v interface{}) ([]byte, error) {
+	*c.marshaled++
+	return c.JSONCodec.Marshal(v)
+}
+
+func TestFileSessionStoreCodec(t *testing.T) {
+	for _, codec := range []Codec{ProtobufCodec{}, countingCodec{marshaled: new(int)}} {
+		dir := t.TempDir()
+		store, err := NewFileSessionStoreWithCodec(dir, codec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e := GetUconEnforcer().(*UconEnforcer).Enforcer
+		uconE := NewUconEnforcer(e, WithSessionStore(store))
+		sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"visits": 1})
+		if _, err := store.IncrementAttribute(sessionID, "visits", 2); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, sessionID+"."+codec.Name())); err != nil {
+			t.Errorf("%s: expected the session file to be named after the codec: %v", codec.Name(), err)
+		}
+
+		records, err := store.LoadSessions()
+		if err != nil || len(records) != 1 || records[0].Subject != "alice" {
+			t.Fatalf("%s: expected the session to load, got %+v (%v)", codec.Name(), records, err)
+		}
+		if visits, _ := toInt64(records[0].Attributes["visits"]); visits != 3 {
+			t.Errorf("%s: expected 3 visits, got %v", codec.Name(), records[0].Attributes["visits"])
+		}
+		if counting, ok := codec.(countingCodec); ok && *counting.marshaled == 0 {
+			t.Error("Expected the custom codec to be used")
+		}
+	}
+	if _, err := NewFileSessionStoreWithCodec(t.TempDir(), nil); err == nil {
+		t.Error("Expected a nil codec to be refused")
+	}
+}
+
+func TestWALSessionStoreCodec(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.wal")
+	store, err := NewWALSessionStoreWithCodec(path, ProtobufCodec{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithSessionStore(store))
+	aliceID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"location": "office"})
+	_ = uconE.UpdateSessionAttribute(aliceID, "location", "home")
+	_, _ = store.IncrementAttribute(aliceID, "visits", 5)
+	_ = store.Close()
+
+	// A crash in the middle of an append leaves a torn final entry.
+	f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+	_, _ = f.Write([]byte{40, 8, 99})
+	_ = f.Close()
+
+	reopened, err := NewWALSessionStoreWithCodec(path, ProtobufCodec{})
+	if err != nil {
+		t.Fatalf("Failed to replay WAL: %v", err)
+	}
+	// The torn entry is cut off, so entries appended after it replay.
+	_, _ = reopened.IncrementAttribute(aliceID, "visits", 1)
+	_ = reopened.Close()
+	reopened, err = NewWALSessionStoreWithCodec(path, ProtobufCodec{})
+	if err != nil {
+		t.Fatalf("Failed to replay WAL: %v", err)
+	}
+	if err := reopened.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	_ = reopened.Close()
+	compacted, err := NewWALSessionStoreWithCodec(path, ProtobufCodec{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer compacted.Close()
+	records, _ := compacted.LoadSessions()
+	if len(records) != 1 || records[0].Attributes["location"] != "home" || records[0].Attributes["visits"] != int64(6) {
+		t.Errorf("Expected the replayed session with its integer attribute, got %+v", records)
+	}
+}
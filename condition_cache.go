@@ -0,0 +1,101 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+// pureConditions are the built-in conditions whose result depends only on
+// their expression and the attributes the session sees. While those
+// attributes are unchanged, monitoring reuses their last result instead of
+// evaluating them again on every tick.
+var pureConditions = map[string]bool{
+	"attribute_equals":  true,
+	"attribute_in":      true,
+	"numeric_threshold": true,
+	"regex_match":       true,
+	"purpose_in":        true,
+	"location":          true,
+	"vip_level":         true,
+}
+
+// attributeVersion identifies the state of the attributes of a session and
+// of the subject attributes it falls back to.
+type attributeVersion struct {
+	session  uint64
+	subjects uint64
+}
+
+// cachedCondition is the last result of a pure condition in a monitoring loop.
+type cachedCondition struct {
+	name       string
+	expr       string
+	attributes []string
+	shadow     bool
+	rollout    int
+	version    attributeVersion       // for pure built-ins
+	values     map[string]interface{} // for conditions declaring Attributes
+	passed     bool
+}
+
+// isPureCondition reports whether conditions of the given name can be cached.
+// Replacing a built-in handler with RegisterConditionHandler makes it impure,
+// since nothing is known about the new handler.
+func (u *UconEnforcer) isPureCondition(name string) bool {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return pureConditions[name] && !u.customConditions[name]
+}
+
+// evaluateCachedCondition evaluates a condition at a monitoring tick, reusing
+// the result cached in results when the condition is pure and neither it nor
+// the session's attributes changed since. A condition declaring Attributes is
+// treated as pure and only the values of those attributes are compared. It
+// returns whether the result came from the cache. Only successful evaluations
+// are cached, and nothing is cached under FailOpen, which turns errors into
+// passes.
+func (u *UconEnforcer) evaluateCachedCondition(condition *Condition, session *Session, results map[string]cachedCondition) (bool, bool, error) {
+	pure := len(condition.Attributes) > 0 || u.isPureCondition(condition.Name)
+	if !pure || u.getFailurePolicy() == FailOpen || !conditionApplies(condition, session) {
+		delete(results, condition.ID)
+		passed, err := u.evaluateCondition(condition, session)
+		return passed, false, err
+	}
+	// The version is read first: a change made during the evaluation then
+	// invalidates the result at the next tick instead of being hidden by it.
+	var version attributeVersion
+	var values map[string]interface{}
+	if len(condition.Attributes) > 0 {
+		values = attributeSnapshot(condition, session)
+	} else {
+		version = session.attributeVersion()
+	}
+	if cached, ok := results[condition.ID]; ok && cached.matches(condition, version, values) {
+		return cached.passed, true, nil
+	}
+	passed, err := u.evaluateCondition(condition, session)
+	if err != nil {
+		delete(results, condition.ID)
+		return passed, false, err
+	}
+	results[condition.ID] = cachedCondition{
+		name:       condition.Name,
+		expr:       condition.Expr,
+		attributes: condition.Attributes,
+		shadow:     condition.Shadow,
+		rollout:    condition.Rollout,
+		version:    version,
+		values:     values,
+		passed:     passed,
+	}
+	return passed, false, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConditionResultCache(t *testing.T) {
+	uconE := GetUconEnforcer().(*UconEnforcer)
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"department": "eng"})
+	session, _ := uconE.GetSession(sessionID)
+	condition := &Condition{ID: "dept", Name: "attribute_equals", Kind: "always", Expr: "department:eng"}
+	results := make(map[string]cachedCondition)
+
+	evaluate := func(wantPassed bool, wantCached bool) {
+		t.Helper()
+		passed, cached, err := uconE.evaluateCachedCondition(condition, session, results)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if passed != wantPassed || cached != wantCached {
+			t.Errorf("Expected passed=%v cached=%v, got passed=%v cached=%v", wantPassed, wantCached, passed, cached)
+		}
+	}
+	evaluate(true, false)
+	evaluate(true, true)
+
+	// Writing the same value keeps the result; a different one invalidates it.
+	uconE.UpdateSessionAttribute(sessionID, "department", "eng")
+	evaluate(true, true)
+	uconE.UpdateSessionAttribute(sessionID, "department", "sales")
+	evaluate(false, false)
+	evaluate(false, true)
+
+	// Subject attributes are seen by the session, so they invalidate it too.
+	sessionID, _ = uconE.CreateSession("bob", "read", "document1", nil)
+	session, _ = uconE.GetSession(sessionID)
+	results = make(map[string]cachedCondition)
+	uconE.SetSubjectAttributes("bob", map[string]interface{}{"department": "sales"})
+	evaluate(false, false)
+	evaluate(false, true)
+	uconE.SetSubjectAttributes("bob", map[string]interface{}{"department": "eng"})
+	evaluate(true, false)
+	evaluate(true, true)
+
+	// A changed expression is evaluated again.
+	condition = &Condition{ID: "dept", Name: "attribute_equals", Kind: "always", Expr: "department:sales"}
+	evaluate(false, false)
+
+	// So is a condition moved in or out of shadow mode.
+	condition = &Condition{ID: "dept", Name: "attribute_equals", Kind: "always", Expr: "department:sales", Shadow: true}
+	evaluate(true, false)
+	evaluate(true, true)
+	condition = &Condition{ID: "dept", Name: "attribute_equals", Kind: "always", Expr: "department:sales"}
+	evaluate(false, false)
+
+	// Replacing a built-in handler makes its conditions uncacheable.
+	uconE.RegisterConditionHandler("attribute_equals", func(expr string, session SessionView) (bool, error) {
+		return true, nil
+	})
+	evaluate(true, false)
+	evaluate(true, false)
+}
+
+func TestMonitoringReusesPureConditions(t *testing.T) {
+	uconE := GetUconEnforcer()
+	uconE.AddCondition(&Condition{ID: "dept", Name: "attribute_equals", Kind: "always", Expr: "department:eng"})
+	uconE.AddCondition(&Condition{ID: "young", Name: "session_age_below", Kind: "always", Expr: "1h"})
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"department": "eng"})
+	if _, err := uconE.EnforceWithSession(sessionID); err != nil {
+		t.Fatal(err)
+	}
+	defer uconE.StopMonitoring(sessionID)
+	time.Sleep(3*DefaultMonitorInterval + DefaultMonitorInterval/2)
+
+	trace, err := uconE.GetSessionTrace(sessionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trace) < 2 {
+		t.Fatalf("Expected at least 2 evaluations, got %d", len(trace))
+	}
+	last := trace[len(trace)-1]
+	for _, result := range last.Conditions {
+		if want := result.ID == "dept"; result.Cached != want {
+			t.Errorf("Expected condition %s cached=%v, got %v", result.ID, want, result.Cached)
+		}
+		if !result.Passed {
+			t.Errorf("Expected condition %s to pass", result.ID)
+		}
+	}
+}
@@ -0,0 +1,354 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// builtinConditions returns the ready-made condition handlers, keyed by the
+// Condition.Name that selects them.
+func builtinConditions() map[string]conditionFunc {
+	return map[string]conditionFunc{
+		"attribute_equals":  checkAttributeEquals,
+		"attribute_in":      checkAttributeIn,
+		"numeric_threshold": checkNumericThreshold,
+		"session_age_below": checkSessionAgeBelow,
+		"weekday_in":        checkWeekdayIn,
+		"purpose_in":        checkPurposeIn,
+		"channel_bound":     checkChannelBound,
+		"impersonation":     checkImpersonation,
+	}
+}
+
+// splitKeyValue splits "key:value" at the first colon.
+func splitKeyValue(expr string) (string, string, error) {
+	i := strings.Index(expr, ":")
+	if i < 0 {
+		return "", "", fmt.Errorf("invalid expression format: %s, expected 'key:value'", expr)
+	}
+	return strings.TrimSpace(expr[:i]), strings.TrimSpace(expr[i+1:]), nil
+}
+
+// checkAttributeEquals passes when an attribute equals a value.
+// Expr: "key:value", e.g. "department:engineering".
+func checkAttributeEquals(expr string, session *Session) (bool, error) {
+	key, expected, err := splitKeyValue(expr)
+	if err != nil {
+		return false, err
+	}
+	actual := session.GetAttribute(key)
+	if actual == nil {
+		return false, &MissingAttributeError{Key: key}
+	}
+	return fmt.Sprint(actual) == expected, nil
+}
+
+// checkAttributeIn passes when an attribute is one of a list of values.
+// Expr: "key:v1,v2,...", e.g. "country:DE,FR,NL".
+func checkAttributeIn(expr string, session *Session) (bool, error) {
+	key, list, err := splitKeyValue(expr)
+	if err != nil {
+		return false, err
+	}
+	actual := session.GetAttribute(key)
+	if actual == nil {
+		return false, &MissingAttributeError{Key: key}
+	}
+	value := fmt.Sprint(actual)
+	for _, candidate := range strings.Split(list, ",") {
+		if strings.TrimSpace(candidate) == value {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// checkNumericThreshold compares a numeric attribute with a threshold.
+// Expr: "key op number" with op one of <, <=, >, >=, ==, !=,
+// e.g. "downloads_remaining > 0".
+func checkNumericThreshold(expr string, session *Session) (bool, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 3 {
+		return false, fmt.Errorf("invalid expression format: %s, expected 'key op number'", expr)
+	}
+	key, op := fields[0], fields[1]
+	threshold, err := strconv.ParseFloat(fields[2], 64)
+	if err != nil {
+		return false, fmt.Errorf("invalid threshold in %s: %w", expr, err)
+	}
+	val := session.GetAttribute(key)
+	if val == nil {
+		return false, &MissingAttributeError{Key: key}
+	}
+	value, ok := toFloat64(val)
+	if !ok {
+		return false, fmt.Errorf("%s attribute not a number", key)
+	}
+
+	return compareNumbers(value, op, threshold, expr)
+}
+
+// compareNumbers applies a comparison operator of a "key op number" expression.
+func compareNumbers(value float64, op string, threshold float64, expr string) (bool, error) {
+	switch op {
+	case "<":
+		return value < threshold, nil
+	case "<=":
+		return value <= threshold, nil
+	case ">":
+		return value > threshold, nil
+	case ">=":
+		return value >= threshold, nil
+	case "==":
+		return value == threshold, nil
+	case "!=":
+		return value != threshold, nil
+	default:
+		return false, fmt.Errorf("invalid operator %s in %s", op, expr)
+	}
+}
+
+// checkRegexMatch passes when a string attribute matches a regular expression.
+// Expr: "key:pattern", e.g. "email:@example\.com$".
+func (u *UconEnforcer) checkRegexMatch(expr string, session *Session) (bool, error) {
+	key, pattern, err := splitKeyValue(expr)
+	if err != nil {
+		return false, err
+	}
+	val := session.GetAttribute(key)
+	if val == nil {
+		return false, &MissingAttributeError{Key: key}
+	}
+	value, ok := val.(string)
+	if !ok {
+		return false, fmt.Errorf("%s attribute not a string", key)
+	}
+	re, err := u.expressions.regexes.compile(pattern)
+	if err != nil {
+		return false, fmt.Errorf("invalid pattern in %s: %w", expr, err)
+	}
+	return re.MatchString(value), nil
+}
+
+// checkSessionAgeBelow passes while the session is younger than a duration.
+// Expr: a Go duration, e.g. "8h" or "30m".
+func checkSessionAgeBelow(expr string, session *Session) (bool, error) {
+	maxAge, err := time.ParseDuration(strings.TrimSpace(expr))
+	if err != nil {
+		return false, fmt.Errorf("invalid duration %s: %w", expr, err)
+	}
+	return time.Since(session.GetStartTime()) < maxAge, nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// checkWeekdayIn passes on the listed days of the week. Expr: a comma
+// separated list of days and day ranges, optionally followed by "@" and an
+// IANA time zone, e.g. "mon-fri" or "sat,sun@Europe/Berlin".
+func checkWeekdayIn(expr string, session *Session) (bool, error) {
+	now := time.Now()
+	days := expr
+	if i := strings.Index(expr, "@"); i >= 0 {
+		loc, err := time.LoadLocation(strings.TrimSpace(expr[i+1:]))
+		if err != nil {
+			return false, fmt.Errorf("invalid time zone in %s: %w", expr, err)
+		}
+		now, days = now.In(loc), expr[:i]
+	}
+
+	allowed, err := parseWeekdays(days)
+	if err != nil {
+		return false, err
+	}
+	return allowed[now.Weekday()], nil
+}
+
+func parseWeekdays(days string) (map[time.Weekday]bool, error) {
+	allowed := make(map[time.Weekday]bool)
+	for _, part := range strings.Split(days, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		ends := strings.SplitN(part, "-", 2)
+		first, ok := weekdays[ends[0]]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %s", ends[0])
+		}
+		last := first
+		if len(ends) == 2 {
+			if last, ok = weekdays[ends[1]]; !ok {
+				return nil, fmt.Errorf("invalid weekday %s", ends[1])
+			}
+		}
+		for d := first; ; d = (d + 1) % 7 {
+			allowed[d] = true
+			if d == last {
+				break
+			}
+		}
+	}
+	return allowed, nil
+}
+
+// toFloat64 converts numeric attribute values to float64.
+func toFloat64(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	}
+	if n, ok := toInt64(val); ok {
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// checkMaxConcurrentSources limits how many distinct values of an attribute
+// (IP address, device id, ...) the active, granted sessions of a subject may
+// have at the same time, to detect shared credentials. The earliest sources
+// keep access; a session from one source too many fails the condition.
+// Expr: "key:max", e.g. "ip:1".
+func (u *UconEnforcer) checkMaxConcurrentSources(expr string, session *Session) (bool, error) {
+	key, limitText, err := splitKeyValue(expr)
+	if err != nil {
+		return false, err
+	}
+	limit, err := strconv.Atoi(limitText)
+	if err != nil || limit < 1 {
+		return false, fmt.Errorf("invalid source limit in %s, expected a positive integer", expr)
+	}
+	own := session.GetAttribute(key)
+	if own == nil {
+		return false, &MissingAttributeError{Key: key}
+	}
+
+	// Sessions are ordered by start time, so earlier sources are allowed first.
+	sessions := u.GetSessions()
+	if session.dryRun && session.origin == nil {
+		// A simulated session is not stored, and would start after the others.
+		sessions = append(sessions, session)
+	}
+	allowed := make(map[string]bool, limit)
+	for _, other := range sessions {
+		if session.origin != nil && other == session.origin {
+			other = session // a dry run sees the attributes of its copy
+		}
+		if other != session {
+			record := other.ToRecord()
+			if record.Subject != session.GetSubject() || !record.Active || !record.Monitored {
+				continue
+			}
+		}
+		source := other.GetAttribute(key)
+		if source == nil || allowed[fmt.Sprint(source)] {
+			continue
+		}
+		if len(allowed) == limit {
+			break
+		}
+		allowed[fmt.Sprint(source)] = true
+	}
+	return allowed[fmt.Sprint(own)], nil
+}
+
+// Events emitted by the expiry condition.
+const (
+	// EventExpiryWarning is emitted once per session when an expiry date is
+	// within the warning window.
+	EventExpiryWarning EventType = "expiry_warning"
+	// EventExpiryGrace is emitted once per session when an expiry date has
+	// passed and the grace window has started.
+	EventExpiryGrace EventType = "expiry_grace"
+)
+
+// parseExpiry parses the expression of an expiry condition into the
+// attribute and the grace and warning windows.
+func parseExpiry(expr string) (key string, grace time.Duration, warn time.Duration, err error) {
+	fields := strings.Fields(expr)
+	if len(fields) == 0 {
+		return "", 0, 0, fmt.Errorf("invalid expression format: %s, expected 'key [grace=dur] [warn=dur]'", expr)
+	}
+	for _, field := range fields[1:] {
+		name, value, _ := strings.Cut(field, "=")
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return "", 0, 0, fmt.Errorf("invalid duration in %s", field)
+		}
+		switch name {
+		case "grace":
+			grace = d
+		case "warn":
+			warn = d
+		default:
+			return "", 0, 0, fmt.Errorf("unknown option %s in %s", name, expr)
+		}
+	}
+	return fields[0], grace, warn, nil
+}
+
+// checkExpiry passes until a subscription or entitlement expiry date stored in
+// an attribute (RFC 3339, "2006-01-02" or Unix seconds) plus an optional grace
+// window has passed. Warning events are emitted before expiry and when the
+// grace window starts.
+// Expr: "key [grace=<duration>] [warn=<duration>]",
+// e.g. "license_expiry grace=72h warn=168h".
+func (u *UconEnforcer) checkExpiry(expr string, session *Session) (bool, error) {
+	key, grace, warn, err := parseExpiry(expr)
+	if err != nil {
+		return false, err
+	}
+
+	val := session.GetAttribute(key)
+	if val == nil {
+		return false, &MissingAttributeError{Key: key}
+	}
+	expiry, err := toTime(val)
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+	data := map[string]interface{}{"attribute": key, "expiry": expiry, "subject": session.GetSubject()}
+	switch {
+	case now.Before(expiry.Add(-warn)):
+		return true, nil
+	case now.Before(expiry):
+		if !session.dryRun && session.markOnce(string(EventExpiryWarning)+":"+key) {
+			u.emit(Event{Type: EventExpiryWarning, SessionID: session.GetId(), Time: now, Data: data,
+				Message: fmt.Sprintf("%s of %s expires at %s", key, session.GetSubject(), expiry.Format(time.RFC3339))})
+		}
+		return true, nil
+	case now.Before(expiry.Add(grace)):
+		if !session.dryRun && session.markOnce(string(EventExpiryGrace)+":"+key) {
+			data["revoke_at"] = expiry.Add(grace)
+			u.emit(Event{Type: EventExpiryGrace, SessionID: session.GetId(), Time: now, Data: data,
+				Message: fmt.Sprintf("%s of %s expired, access ends at %s", key, session.GetSubject(), expiry.Add(grace).Format(time.RFC3339))})
+		}
+		return true, nil
+	default:
+		return false, nil
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuiltinConditions(t *testing.T) {
+	uconE := GetUconEnforcer()
+	u := uconE.(*UconEnforcer)
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{
+		"department":          "engineering",
+		"country":             "DE",
+		"downloads_remaining": 3,
+		"risk_score":          12.5,
+		"email":               "alice@example.com",
+	})
+	session, _ := uconE.GetSession(sessionID)
+
+	today := strings.ToLower(time.Now().Weekday().String()[:3])
+	tomorrow := strings.ToLower(time.Now().Add(24 * time.Hour).Weekday().String()[:3])
+
+	tests := []struct {
+		name string
+		expr string
+		want bool
+	}{
+		{"attribute_equals", "department:engineering", true},
+		{"attribute_equals", "department:sales", false},
+		{"attribute_in", "country:FR, DE ,NL", true},
+		{"attribute_in", "country:US,CA", false},
+		{"numeric_threshold", "downloads_remaining > 0", true},
+		{"numeric_threshold", "risk_score >= 50", false},
+		{"regex_match", `email:@example\.com$`, true},
+		{"regex_match", `email:^bob@`, false},
+		{"session_age_below", "1h", true},
+		{"session_age_below", "1ns", false},
+		{"weekday_in", today, true},
+		{"weekday_in", tomorrow, false},
+		{"weekday_in", "mon-sun", true},
+		{"weekday_in", "sat-fri@UTC", true},
+	}
+	for _, tt := range tests {
+		_ = uconE.AddCondition(&Condition{ID: "c", Name: tt.name, Kind: "always", Expr: tt.expr})
+		got, err := uconE.EvaluateConditions(sessionID)
+		if err != nil {
+			t.Errorf("%s %q: unexpected error: %v", tt.name, tt.expr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s %q = %v, want %v", tt.name, tt.expr, got, tt.want)
+		}
+	}
+
+	invalid := []struct{ name, expr string }{
+		{"attribute_equals", "missing:x"},
+		{"attribute_in", "no_separator"},
+		{"numeric_threshold", "department > 1"},
+		{"numeric_threshold", "downloads_remaining ~ 1"},
+		{"regex_match", "email:("},
+		{"session_age_below", "soon"},
+		{"weekday_in", "someday"},
+	}
+	for _, tt := range invalid {
+		handler := u.conditionHandlers[tt.name]
+		if _, err := handler(tt.expr, session); err == nil {
+			t.Errorf("%s %q: expected an error", tt.name, tt.expr)
+		}
+	}
+}
+
+func TestConditionDwell(t *testing.T) {
+	uconE := GetUconEnforcer()
+	_ = uconE.AddCondition(&Condition{ID: "geofence", Name: "location", Kind: "always", Expr: "office", Dwell: 700 * time.Millisecond})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"location": "office"})
+	session, _ := uconE.EnforceWithSession(sessionID)
+	if session == nil {
+		t.Fatal("Expected access")
+	}
+
+	// Brief excursions shorter than the dwell time are ignored.
+	for i := 0; i < 2; i++ {
+		_ = uconE.UpdateSessionAttribute(sessionID, "location", "street")
+		time.Sleep(300 * time.Millisecond)
+		_ = uconE.UpdateSessionAttribute(sessionID, "location", "office")
+		time.Sleep(300 * time.Millisecond)
+	}
+	if !session.IfActive() {
+		t.Fatalf("Expected oscillating condition not to revoke, stopped: %q", session.GetStopReason())
+	}
+
+	_ = uconE.UpdateSessionAttribute(sessionID, "location", "street")
+	time.Sleep(1200 * time.Millisecond)
+	if session.IfActive() {
+		t.Error("Expected session to be revoked after the dwell time")
+	}
+}
+
+func TestMaxConcurrentSources(t *testing.T) {
+	uconE := GetUconEnforcer()
+	_ = uconE.AddCondition(&Condition{ID: "single_ip", Name: "max_concurrent_sources", Kind: "always", Expr: "ip:1"})
+
+	enforce := func(subject string, ip string) (string, *Session) {
+		sessionID, _ := uconE.CreateSession(subject, "read", "document1", map[string]interface{}{"ip": ip})
+		session, err := uconE.EnforceWithSession(sessionID)
+		if err != nil {
+			t.Fatalf("EnforceWithSession: %v", err)
+		}
+		return sessionID, session
+	}
+
+	firstID, first := enforce("alice", "10.0.0.1")
+	if first == nil {
+		t.Fatal("Expected first session to be granted")
+	}
+	if _, s := enforce("alice", "10.0.0.1"); s == nil {
+		t.Error("Expected a second session from the same IP to be granted")
+	}
+	if _, s := enforce("alice", "192.168.1.7"); s != nil {
+		t.Error("Expected a session from another IP to be denied")
+	}
+	if _, s := enforce("bob", "192.168.1.7"); s == nil {
+		t.Error("Expected another subject to be unaffected")
+	}
+
+	_ = uconE.StopMonitoring(firstID)
+	time.Sleep(300 * time.Millisecond)
+	if !first.IfActive() && first.GetStopReason() != NormalStopReason {
+		t.Errorf("Unexpected revocation of the first session: %q", first.GetStopReason())
+	}
+}
+
+func TestExpiryCondition(t *testing.T) {
+	uconE := GetUconEnforcer()
+	u := uconE.(*UconEnforcer)
+	var events []EventType
+	uconE.AddEventListener(func(event Event) {
+		events = append(events, event.Type)
+	})
+
+	now := time.Now()
+	tests := []struct {
+		expiry interface{}
+		expr   string
+		want   bool
+		event  EventType
+	}{
+		{now.Add(30 * 24 * time.Hour).Format(time.RFC3339), "license_expiry warn=168h", true, ""},
+		{now.Add(24 * time.Hour).Format(time.RFC3339), "license_expiry warn=168h", true, EventExpiryWarning},
+		{now.Add(-time.Hour).Unix(), "license_expiry grace=72h", true, EventExpiryGrace},
+		{now.Add(-time.Hour), "license_expiry", false, ""},
+		{now.Add(-100 * time.Hour).Format("2006-01-02"), "license_expiry grace=72h", false, ""},
+	}
+	for _, tt := range tests {
+		events = nil
+		id, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"license_expiry": tt.expiry})
+		session, _ := uconE.GetSession(id)
+		for i := 0; i < 2; i++ {
+			got, err := u.checkExpiry(tt.expr, session)
+			if err != nil || got != tt.want {
+				t.Errorf("%v %q: got %v, %v, want %v", tt.expiry, tt.expr, got, err, tt.want)
+			}
+		}
+		if tt.event == "" && len(events) != 0 || tt.event != "" && (len(events) != 1 || events[0] != tt.event) {
+			t.Errorf("%v %q: expected one %q event, got %v", tt.expiry, tt.expr, tt.event, events)
+		}
+	}
+
+	session := &Session{attributes: map[string]interface{}{"license_expiry": "soon"}}
+	for _, expr := range []string{"license_expiry", "license_expiry grace=forever", "license_expiry after=1h", "missing"} {
+		if _, err := u.checkExpiry(expr, session); err == nil {
+			t.Errorf("%q: expected an error", expr)
+		}
+	}
+}
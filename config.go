@@ -0,0 +1,196 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// ConfigVersion is the version of the document written by ExportConfig.
+const ConfigVersion = 1
+
+// Config is the configuration of an enforcer, as exported by ExportConfig:
+// its rules, rule sets, attribute schema, telemetry mappings and the
+// settings that can change at runtime. Sessions are not part of it; see
+// DumpState. FailurePolicy is "closed" (the default) or "open".
+type Config struct {
+	Version           int                `json:"version"`
+	FailurePolicy     string             `json:"failure_policy,omitempty"`
+	StrictAttributes  bool               `json:"strict_attributes,omitempty"`
+	Conditions        []Condition        `json:"conditions"`
+	Obligations       []Obligation       `json:"obligations"`
+	RuleSets          []RuleSet          `json:"rule_sets,omitempty"`
+	ActiveRuleSet     string             `json:"active_rule_set,omitempty"`
+	AttributeSchema   []AttributeSchema  `json:"attribute_schema,omitempty"`
+	TelemetryMappings []TelemetryMapping `json:"telemetry_mappings,omitempty"`
+}
+
+var failurePolicyNames = map[FailurePolicy]string{
+	FailClosed: "closed",
+	FailOpen:   "open",
+}
+
+// ExportConfig serializes the enforcer's configuration to a JSON document
+// that ImportConfig applies to another enforcer, e.g. to promote rules from
+// staging to production.
+func (u *UconEnforcer) ExportConfig() ([]byte, error) {
+	cfg := &Config{
+		Version:           ConfigVersion,
+		FailurePolicy:     failurePolicyNames[u.getFailurePolicy()],
+		Conditions:        u.conditionList(),
+		Obligations:       u.obligationList(),
+		AttributeSchema:   u.GetAttributeSchema(),
+		TelemetryMappings: u.GetTelemetryMappings(),
+	}
+	u.mu.RLock()
+	cfg.StrictAttributes = u.strictAttributes
+	cfg.ActiveRuleSet = u.ruleSets.active
+	for _, set := range u.ruleSets.versions {
+		cfg.RuleSets = append(cfg.RuleSets, *set)
+	}
+	u.mu.RUnlock()
+	sort.Slice(cfg.RuleSets, func(i, j int) bool { return cfg.RuleSets[i].Version < cfg.RuleSets[j].Version })
+	return json.MarshalIndent(cfg, "", "  ")
+}
+
+// ImportConfig applies a document written by ExportConfig. The whole document
+// is validated before anything changes. The rules, attribute schema,
+// telemetry mappings and settings are replaced, the rules atomically as by
+// ActivateRuleSet, so RollbackRuleSet undoes the import of the rules. Rule
+// sets are added; a version the enforcer already has must be identical.
+// DiffConfig shows what an import would change without applying it.
+func (u *UconEnforcer) ImportConfig(data []byte) error {
+	imported, err := u.parseConfig(data)
+	if err != nil {
+		return err
+	}
+
+	u.mu.Lock()
+	if err := u.checkRuleSetsLocked(imported); err != nil {
+		u.mu.Unlock()
+		return err
+	}
+	if u.ruleSets.versions == nil {
+		u.ruleSets.versions = make(map[string]*RuleSet)
+	}
+	for version, set := range imported.sets {
+		if _, exists := u.ruleSets.versions[version]; !exists {
+			u.ruleSets.versions[version] = set
+		}
+	}
+	previous := u.activeRules()
+	u.ruleSets.history = append(u.ruleSets.history, previous)
+	if len(u.ruleSets.history) > MaxRuleSetHistory {
+		u.ruleSets.history = u.ruleSets.history[1:]
+	}
+	u.installRules(imported.ActiveRuleSet, imported.conditions, imported.obligations)
+	u.attributeSchema = imported.schema
+	u.strictAttributes = imported.StrictAttributes
+	u.telemetryMappings = imported.mappings
+	u.failurePolicy = imported.policy
+	u.mu.Unlock()
+
+	u.rulesChanged(previous.Version, imported.ActiveRuleSet)
+	return nil
+}
+
+// importedConfig is a validated Config, indexed the way the enforcer keeps it.
+type importedConfig struct {
+	*Config
+	policy      FailurePolicy
+	conditions  map[string]Condition
+	obligations map[string]Obligation
+	sets        map[string]*RuleSet
+	schema      map[string]AttributeSchema
+	mappings    map[string]TelemetryMapping
+}
+
+// parseConfig decodes and validates a document written by ExportConfig.
+func (u *UconEnforcer) parseConfig(data []byte) (*importedConfig, error) {
+	cfg := &Config{}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if cfg.Version != ConfigVersion {
+		return nil, fmt.Errorf("unsupported config version %d", cfg.Version)
+	}
+	imported := &importedConfig{Config: cfg}
+
+	switch cfg.FailurePolicy {
+	case "", "closed":
+		imported.policy = FailClosed
+	case "open":
+		imported.policy = FailOpen
+	default:
+		return nil, fmt.Errorf("config: unknown failure policy %q", cfg.FailurePolicy)
+	}
+	var err error
+	imported.conditions, imported.obligations, err = prepareRuleSet(&RuleSet{Conditions: cfg.Conditions, Obligations: cfg.Obligations})
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	imported.sets = make(map[string]*RuleSet, len(cfg.RuleSets))
+	for i := range cfg.RuleSets {
+		set := &cfg.RuleSets[i]
+		if set.Version == "" {
+			return nil, fmt.Errorf("config: rule set version cannot be empty")
+		}
+		if _, _, err := prepareRuleSet(set); err != nil {
+			return nil, fmt.Errorf("config: rule set %s: %w", set.Version, err)
+		}
+		imported.sets[set.Version] = set
+	}
+	imported.schema = make(map[string]AttributeSchema, len(cfg.AttributeSchema))
+	for i := range cfg.AttributeSchema {
+		if err := cfg.AttributeSchema[i].validate(); err != nil {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+		imported.schema[cfg.AttributeSchema[i].Name] = cfg.AttributeSchema[i]
+	}
+	imported.mappings = make(map[string]TelemetryMapping, len(cfg.TelemetryMappings))
+	for _, mapping := range cfg.TelemetryMappings {
+		if err := u.validateTelemetryMapping(mapping); err != nil {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+		imported.mappings[mapping.Signal] = mapping
+	}
+	return imported, nil
+}
+
+// checkRuleSetsLocked checks that the imported rule sets agree with those
+// of the enforcer. u.mu must be held.
+func (u *UconEnforcer) checkRuleSetsLocked(imported *importedConfig) error {
+	for version, set := range imported.sets {
+		if existing, exists := u.ruleSets.versions[version]; exists && !sameJSON(existing, set) {
+			return fmt.Errorf("config: rule set %s differs from the existing version", version)
+		}
+	}
+	active := imported.ActiveRuleSet
+	if _, exists := u.ruleSets.versions[active]; active != "" && !exists && imported.sets[active] == nil {
+		return fmt.Errorf("config: cannot find active rule set %s", active)
+	}
+	return nil
+}
+
+// sameJSON reports whether a and b serialize to the same JSON, so that rules
+// compare equal whether or not their empty lists are nil.
+func sameJSON(a interface{}, b interface{}) bool {
+	aData, aErr := json.Marshal(a)
+	bData, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && bytes.Equal(aData, bData)
+}
@@ -0,0 +1,175 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// ConfigDiff is what ImportConfig would change, as returned by DiffConfig.
+// AffectedSessions are the active sessions that would fail an added or
+// changed condition, and so be denied or revoked once it is applied.
+type ConfigDiff struct {
+	Conditions        ItemDiff          `json:"conditions"`
+	Obligations       ItemDiff          `json:"obligations"`
+	AttributeSchema   ItemDiff          `json:"attribute_schema"`
+	TelemetryMappings ItemDiff          `json:"telemetry_mappings"`
+	AddedRuleSets     []string          `json:"added_rule_sets,omitempty"`
+	Settings          []SettingChange   `json:"settings,omitempty"`
+	AffectedSessions  []AffectedSession `json:"affected_sessions,omitempty"`
+}
+
+// ItemDiff lists the IDs of added, removed and changed items: rule IDs,
+// attribute names or telemetry signals.
+type ItemDiff struct {
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	Changed []string `json:"changed,omitempty"`
+}
+
+// SettingChange is a setting that would change: "failure_policy",
+// "strict_attributes" or "active_rule_set".
+type SettingChange struct {
+	Name string `json:"name"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// AffectedSession is an active session that would fail the imported
+// conditions, and why.
+type AffectedSession struct {
+	SessionID string `json:"session_id"`
+	Subject   string `json:"subject"`
+	Action    string `json:"action"`
+	Object    string `json:"object"`
+	Reason    string `json:"reason"`
+}
+
+// Empty reports whether importing the configuration would change nothing.
+func (d *ConfigDiff) Empty() bool {
+	return d.Conditions.empty() && d.Obligations.empty() && d.AttributeSchema.empty() &&
+		d.TelemetryMappings.empty() && len(d.AddedRuleSets) == 0 && len(d.Settings) == 0
+}
+
+func (d *ItemDiff) empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffConfig validates a document written by ExportConfig like ImportConfig
+// and returns what importing it would change, without applying it. The
+// added and changed conditions are evaluated against the active sessions to
+// find those the import would deny or revoke.
+func (u *UconEnforcer) DiffConfig(data []byte) (*ConfigDiff, error) {
+	imported, err := u.parseConfig(data)
+	if err != nil {
+		return nil, err
+	}
+
+	u.mu.RLock()
+	if err := u.checkRuleSetsLocked(imported); err != nil {
+		u.mu.RUnlock()
+		return nil, err
+	}
+	diff := &ConfigDiff{
+		Conditions:        diffItems(u.conditions, imported.conditions),
+		Obligations:       diffItems(u.obligations, imported.obligations),
+		AttributeSchema:   diffItems(u.attributeSchema, imported.schema),
+		TelemetryMappings: diffItems(u.telemetryMappings, imported.mappings),
+	}
+	for version := range imported.sets {
+		if _, exists := u.ruleSets.versions[version]; !exists {
+			diff.AddedRuleSets = append(diff.AddedRuleSets, version)
+		}
+	}
+	diff.Settings = appendSetting(diff.Settings, "failure_policy", failurePolicyNames[u.failurePolicy], failurePolicyNames[imported.policy])
+	diff.Settings = appendSetting(diff.Settings, "strict_attributes", strconv.FormatBool(u.strictAttributes), strconv.FormatBool(imported.StrictAttributes))
+	diff.Settings = appendSetting(diff.Settings, "active_rule_set", u.ruleSets.active, imported.ActiveRuleSet)
+	u.mu.RUnlock()
+	sort.Strings(diff.AddedRuleSets)
+
+	var conditions []Condition
+	for _, id := range append(append([]string(nil), diff.Conditions.Added...), diff.Conditions.Changed...) {
+		conditions = append(conditions, imported.conditions[id])
+	}
+	sort.Slice(conditions, func(i, j int) bool { return conditions[i].ID < conditions[j].ID })
+	if len(conditions) > 0 {
+		for _, session := range u.GetSessions() {
+			if !session.IfActive() {
+				continue
+			}
+			if reason := u.failingCondition(conditions, session, imported.policy); reason != "" {
+				diff.AffectedSessions = append(diff.AffectedSessions, AffectedSession{
+					SessionID: session.GetId(),
+					Subject:   session.GetSubject(),
+					Action:    session.GetAction(),
+					Object:    session.GetObject(),
+					Reason:    reason,
+				})
+			}
+		}
+		sort.Slice(diff.AffectedSessions, func(i, j int) bool {
+			return diff.AffectedSessions[i].SessionID < diff.AffectedSessions[j].SessionID
+		})
+	}
+	return diff, nil
+}
+
+// failingCondition returns why the session fails one of the conditions under
+// the given failure policy, or "" if it passes them all. The conditions are
+// dry-run, so the session and the enforcer are left as they are.
+func (u *UconEnforcer) failingCondition(conditions []Condition, session *Session, policy FailurePolicy) string {
+	for _, condition := range conditions {
+		cond := condition // Create a copy to avoid memory aliasing
+		passed, err := u.dryRunCondition(&cond, session, policy)
+		if err != nil {
+			return err.Error()
+		}
+		if !passed {
+			return fmt.Sprintf("condition %s not met", cond.ID)
+		}
+	}
+	return ""
+}
+
+// diffItems compares the items of the enforcer with those of a configuration.
+func diffItems[T any](current map[string]T, next map[string]T) ItemDiff {
+	diff := ItemDiff{}
+	for id, item := range next {
+		existing, exists := current[id]
+		if !exists {
+			diff.Added = append(diff.Added, id)
+		} else if !sameJSON(existing, item) {
+			diff.Changed = append(diff.Changed, id)
+		}
+	}
+	for id := range current {
+		if _, exists := next[id]; !exists {
+			diff.Removed = append(diff.Removed, id)
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Changed)
+	return diff
+}
+
+func appendSetting(settings []SettingChange, name string, from string, to string) []SettingChange {
+	if from == to {
+		return settings
+	}
+	return append(settings, SettingChange{Name: name, From: from, To: to})
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiffConfig(t *testing.T) {
+	uconE := GetUconEnforcer()
+	_ = uconE.AddCondition(&Condition{ID: "legacy", Name: "attribute_equals", Kind: "always", Expr: "department:eng"})
+	_ = uconE.AddCondition(&Condition{ID: "vip", Name: "vip_level", Kind: "always", Expr: "1"})
+	_ = uconE.AddObligation(&Obligation{ID: "audit", Name: "notify", Kind: "post"})
+	_ = uconE.DeclareAttribute(&AttributeSchema{Name: "location", Type: AttributeString})
+	_, _ = uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"location": "office", "vip_level": 1})
+	home, _ := uconE.CreateSession("bob", "read", "document1", map[string]interface{}{"location": "home", "vip_level": 1})
+	stopped, _ := uconE.CreateSession("alice", "write", "document1", map[string]interface{}{"location": "home", "vip_level": 1})
+	_ = uconE.StopMonitoring(stopped)
+
+	before, _ := uconE.ExportConfig()
+	data := []byte(`{
+		"version": 1,
+		"failure_policy": "open",
+		"conditions": [
+			{"id": "geofence", "name": "location", "kind": "always", "expr": "office"},
+			{"id": "vip", "name": "vip_level", "kind": "always", "expr": "1", "purposes": []}
+		],
+		"obligations": [{"id": "audit", "name": "notify", "kind": "post", "shadow": true}],
+		"rule_sets": [{"version": "v1"}],
+		"attribute_schema": [{"name": "location", "type": "string", "allowed": ["office", "home"]}]
+	}`)
+	diff, err := uconE.DiffConfig(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := uconE.ExportConfig(); string(after) != string(before) {
+		t.Fatal("Expected a dry run to change nothing")
+	}
+
+	if !reflect.DeepEqual(diff.Conditions, ItemDiff{Added: []string{"geofence"}, Removed: []string{"legacy"}}) {
+		t.Errorf("Unexpected condition changes %+v", diff.Conditions)
+	}
+	if !reflect.DeepEqual(diff.Obligations, ItemDiff{Changed: []string{"audit"}}) {
+		t.Errorf("Unexpected obligation changes %+v", diff.Obligations)
+	}
+	if !reflect.DeepEqual(diff.AttributeSchema, ItemDiff{Changed: []string{"location"}}) || !diff.TelemetryMappings.empty() {
+		t.Errorf("Unexpected schema changes %+v and mapping changes %+v", diff.AttributeSchema, diff.TelemetryMappings)
+	}
+	if !reflect.DeepEqual(diff.AddedRuleSets, []string{"v1"}) {
+		t.Errorf("Unexpected rule sets %v", diff.AddedRuleSets)
+	}
+	if !reflect.DeepEqual(diff.Settings, []SettingChange{{Name: "failure_policy", From: "closed", To: "open"}}) {
+		t.Errorf("Unexpected settings %+v", diff.Settings)
+	}
+	want := []AffectedSession{{SessionID: home, Subject: "bob", Action: "read", Object: "document1", Reason: "condition geofence not met"}}
+	if !reflect.DeepEqual(diff.AffectedSessions, want) {
+		t.Errorf("Expected only bob's active session at home to be affected, got %+v", diff.AffectedSessions)
+	}
+
+	// A configuration exported from the enforcer itself changes nothing.
+	if diff, err := uconE.DiffConfig(before); err != nil || !diff.Empty() {
+		t.Errorf("Expected an empty diff, got %+v, %v", diff, err)
+	}
+	if _, err := uconE.DiffConfig([]byte(`{"version": 1, "failure_policy": "sometimes"}`)); err == nil {
+		t.Error("Expected a dry run to validate the configuration")
+	}
+}
+
+func TestDiffConfigDryRun(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithNegativeCache(time.Minute))
+	u := uconE.(*UconEnforcer)
+	var events []EventType
+	uconE.AddEventListener(func(event Event) {
+		events = append(events, event.Type)
+	})
+	_ = uconE.RegisterConditionHandler("risk_score", func(expr string, session SessionView) (bool, error) {
+		return false, fmt.Errorf("risk service: %w", ErrDependencyUnavailable)
+	})
+	expiry := time.Now().Add(24 * time.Hour).Format(time.RFC3339)
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"license_expiry": expiry})
+	events = nil
+
+	config := `{
+		"version": 1,
+		"failure_policy": "%s",
+		"conditions": [
+			{"id": "license", "name": "expiry", "kind": "always", "expr": "license_expiry warn=168h"},
+			{"id": "risk", "name": "risk_score", "kind": "always"}
+		]
+	}`
+	diff, err := uconE.DiffConfig([]byte(fmt.Sprintf(config, "closed")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.AffectedSessions) != 1 || !strings.Contains(diff.AffectedSessions[0].Reason, "risk service") {
+		t.Errorf("Expected the risk failure to affect the session, got %+v", diff.AffectedSessions)
+	}
+	if len(events) != 0 {
+		t.Errorf("Expected a dry run to emit no events, got %v", events)
+	}
+	if len(u.negativeCache.entries) != 0 {
+		t.Errorf("Expected a dry run to leave the negative cache empty, got %v", u.negativeCache.entries)
+	}
+	if snapshot := u.GetMetrics().(*InMemoryMetrics).Snapshot(); len(snapshot.Latency[MetricCondition]) != 0 || len(snapshot.Counters) != 0 {
+		t.Errorf("Expected a dry run to record no metrics, got %+v", snapshot)
+	}
+
+	// The imported failure policy applies, not the current one.
+	diff, err = uconE.DiffConfig([]byte(fmt.Sprintf(config, "open")))
+	if err != nil || len(diff.AffectedSessions) != 0 {
+		t.Errorf("Expected no affected sessions when failing open, got %+v, %v", diff, err)
+	}
+
+	// The warning is still sent once the condition is enforced.
+	_ = uconE.AddCondition(&Condition{ID: "license", Name: "expiry", Kind: "always", Expr: "license_expiry warn=168h"})
+	if ok, err := uconE.EvaluateConditions(sessionID); !ok || err != nil || len(events) != 1 || events[0] != EventExpiryWarning {
+		t.Errorf("Expected the expiry warning when enforced, got %v, %v, events %v", ok, err, events)
+	}
+}
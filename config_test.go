@@ -0,0 +1,87 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestConfigExportImport(t *testing.T) {
+	staging := GetUconEnforcer()
+	_ = staging.AddRuleSet(&RuleSet{Version: "v1", Conditions: []Condition{{ID: "geofence", Name: "location", Kind: "always", Expr: "office"}}})
+	_ = staging.ActivateRuleSet("v1")
+	_ = staging.AddObligation(&Obligation{ID: "audit", Name: "notify", Kind: "post", Shadow: true, Rollout: 10})
+	_ = staging.DeclareAttribute(&AttributeSchema{Name: "location", Type: AttributeString, Allowed: []interface{}{"office", "home"}})
+	_ = staging.AddTelemetryMapping(TelemetryMapping{Signal: "gps", Attribute: "location"})
+	staging.SetFailurePolicy(FailOpen)
+	data, err := staging.ExportConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	production := GetUconEnforcer().(*UconEnforcer)
+	_ = production.AddCondition(&Condition{ID: "legacy", Name: "attribute_equals", Kind: "always", Expr: "department:eng"})
+	_ = production.DeclareAttribute(&AttributeSchema{Name: "department", Type: AttributeString})
+	if err := production.ImportConfig(data); err != nil {
+		t.Fatal(err)
+	}
+	exported, _ := production.ExportConfig()
+	if !bytes.Equal(exported, data) {
+		t.Errorf("Expected the imported configuration to be exported unchanged, got\n%s\nwant\n%s", exported, data)
+	}
+	if production.getFailurePolicy() != FailOpen || production.GetActiveRuleSet() != "v1" {
+		t.Error("Expected the failure policy and the active rule set to be imported")
+	}
+	if schema := production.GetAttributeSchema(); len(schema) != 1 || schema[0].Name != "location" {
+		t.Errorf("Expected the attribute schema to be replaced, got %+v", schema)
+	}
+	if err := production.ActivateRuleSet("v1"); err != nil {
+		t.Errorf("Expected the imported rule set to be activatable, got %v", err)
+	}
+
+	// Importing the same document again is harmless; a conflicting rule set is not.
+	if err := production.ImportConfig(data); err != nil {
+		t.Errorf("Expected importing the same configuration again to succeed, got %v", err)
+	}
+	i := strings.LastIndex(string(data), `"expr": "office"`)
+	conflicting := string(data[:i]) + `"expr": "lab"` + string(data[i+len(`"expr": "office"`):])
+	if err := production.ImportConfig([]byte(conflicting)); err == nil {
+		t.Error("Expected an error importing a different rule set under an existing version")
+	}
+	for _, invalid := range []string{
+		`{"version": 2}`,
+		`{"version": 1, "failure_policy": "sometimes"}`,
+		`{"version": 1, "attribute_schema": [{"name": "level", "type": "float"}]}`,
+		`{"version": 1, "active_rule_set": "v9"}`,
+		`not json`,
+	} {
+		if err := production.ImportConfig([]byte(invalid)); err == nil {
+			t.Errorf("Expected an error importing %s", invalid)
+		}
+	}
+	if exported, _ := production.ExportConfig(); !bytes.Equal(exported, data) {
+		t.Error("Expected rejected imports to change nothing")
+	}
+
+	// The import of the rules can be rolled back like an activation.
+	for i := 0; i < 3; i++ {
+		_ = production.RollbackRuleSet()
+	}
+	if conditions := production.GetConditions(); len(conditions) != 1 || conditions[0].ID != "legacy" {
+		t.Errorf("Expected the rules from before the import, got %+v", conditions)
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ConsentWithdrawnReason is the stop reason of sessions revoked because consent was withdrawn.
+const ConsentWithdrawnReason = "consent withdrawn"
+
+// ErrNoConsent is returned by the consent_check obligation when no valid consent exists.
+var ErrNoConsent = errors.New("no valid consent")
+
+// ConsentStore is a registry of consents given by data subjects for the use
+// of their data for a purpose.
+type ConsentStore interface {
+	// HasConsent reports whether dataSubject currently consents to data being
+	// used for purpose.
+	HasConsent(dataSubject string, data string, purpose string) (bool, error)
+}
+
+// ConsentNotifier is implemented by consent stores that report withdrawals,
+// so that sessions relying on the consent are revoked at once instead of at
+// the next ongoing check.
+type ConsentNotifier interface {
+	OnWithdraw(listener func(dataSubject string, data string, purpose string))
+}
+
+// WithConsentStore sets the consent registry queried by the consent_check obligation.
+func WithConsentStore(store ConsentStore) Option {
+	return func(u *UconEnforcer) {
+		u.consents = store
+	}
+}
+
+// watchConsents revokes active sessions relying on a consent when the
+// consent store reports that it was withdrawn.
+func (u *UconEnforcer) watchConsents() {
+	notifier, ok := u.consents.(ConsentNotifier)
+	if !ok {
+		return
+	}
+	notifier.OnWithdraw(func(dataSubject string, data string, purpose string) {
+		for _, session := range u.GetSessions() {
+			key, relies := session.consentKey()
+			if relies && key == consentKey(dataSubject, data, purpose) && session.IfActive() {
+				_ = session.Stop(ConsentWithdrawnReason)
+			}
+		}
+	})
+}
+
+// executeConsentCheck is the "consent_check" obligation. It fails unless the
+// data subject consents to the session object being used for the session
+// purpose. Expr names the attribute holding the data subject; if empty the
+// session subject is the data subject.
+func (u *UconEnforcer) executeConsentCheck(expr string, session *Session) error {
+	if u.consents == nil {
+		return errors.New("no consent store configured")
+	}
+	dataSubject := session.GetSubject()
+	if key := strings.TrimSpace(expr); key != "" {
+		val := session.GetAttribute(key)
+		if val == nil {
+			return &MissingAttributeError{Key: key}
+		}
+		dataSubject = fmt.Sprint(val)
+	}
+
+	ok, err := u.consents.HasConsent(dataSubject, session.GetObject(), session.GetPurpose())
+	if err != nil {
+		return fmt.Errorf("consent lookup failed: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("%w: %s for %s of %s", ErrNoConsent, session.GetPurpose(), session.GetObject(), dataSubject)
+	}
+	session.setConsentKey(consentKey(dataSubject, session.GetObject(), session.GetPurpose()))
+	return nil
+}
+
+func consentKey(dataSubject string, data string, purpose string) string {
+	return dataSubject + "\x00" + data + "\x00" + purpose
+}
+
+func (s *Session) setConsentKey(key string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.consent = key
+}
+
+func (s *Session) consentKey() (string, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.consent, s.consent != ""
+}
+
+// Consent is a consent recorded in a MemoryConsentStore.
+type Consent struct {
+	DataSubject string
+	Data        string
+	Purpose     string
+	// ExpiresAt ends the consent; zero means it does not expire.
+	ExpiresAt time.Time
+}
+
+// MemoryConsentStore is an in-memory ConsentStore and ConsentNotifier.
+type MemoryConsentStore struct {
+	consents  map[string]Consent
+	listeners []func(dataSubject string, data string, purpose string)
+	mu        sync.RWMutex
+}
+
+// NewMemoryConsentStore creates an empty MemoryConsentStore.
+func NewMemoryConsentStore() *MemoryConsentStore {
+	return &MemoryConsentStore{consents: make(map[string]Consent)}
+}
+
+// Grant records a consent, replacing an earlier one for the same data subject, data and purpose.
+func (s *MemoryConsentStore) Grant(consent Consent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.consents[consentKey(consent.DataSubject, consent.Data, consent.Purpose)] = consent
+}
+
+// Withdraw removes a consent and notifies the withdrawal listeners.
+func (s *MemoryConsentStore) Withdraw(dataSubject string, data string, purpose string) {
+	s.mu.Lock()
+	delete(s.consents, consentKey(dataSubject, data, purpose))
+	listeners := append([]func(string, string, string){}, s.listeners...)
+	s.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(dataSubject, data, purpose)
+	}
+}
+
+// HasConsent implements ConsentStore.
+func (s *MemoryConsentStore) HasConsent(dataSubject string, data string, purpose string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	consent, ok := s.consents[consentKey(dataSubject, data, purpose)]
+	if !ok {
+		return false, nil
+	}
+	return consent.ExpiresAt.IsZero() || time.Now().Before(consent.ExpiresAt), nil
+}
+
+// OnWithdraw implements ConsentNotifier.
+func (s *MemoryConsentStore) OnWithdraw(listener func(dataSubject string, data string, purpose string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = append(s.listeners, listener)
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConsentCheck(t *testing.T) {
+	consents := NewMemoryConsentStore()
+	consents.Grant(Consent{DataSubject: "customer42", Data: "document1", Purpose: "support"})
+
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithConsentStore(consents))
+	_ = uconE.AddObligation(&Obligation{ID: "consent", Name: "consent_check", Kind: "pre", Expr: "owner"})
+
+	owner := map[string]interface{}{"owner": "customer42"}
+	sessionID, _ := uconE.CreateSessionWithPurpose("alice", "read", "document1", "marketing", owner)
+	if session, _ := uconE.EnforceWithSession(sessionID); session != nil {
+		t.Error("Expected access without consent for the purpose to be denied")
+	}
+
+	sessionID, _ = uconE.CreateSessionWithPurpose("alice", "read", "document1", "support", owner)
+	session, err := uconE.EnforceWithSession(sessionID)
+	if err != nil || session == nil {
+		t.Fatalf("Expected access with consent to be granted, got %v", err)
+	}
+
+	consents.Withdraw("customer42", "document1", "support")
+	if session.IfActive() {
+		t.Error("Expected the session to be revoked when consent is withdrawn")
+	}
+	if session.GetStopReason() != ConsentWithdrawnReason {
+		t.Errorf("Expected stop reason %q, got %q", ConsentWithdrawnReason, session.GetStopReason())
+	}
+}
+
+func TestConsentExpiry(t *testing.T) {
+	consents := NewMemoryConsentStore()
+	consents.Grant(Consent{DataSubject: "alice", Data: "document1", Purpose: "support", ExpiresAt: time.Now().Add(300 * time.Millisecond)})
+
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithConsentStore(consents))
+	_ = uconE.AddObligation(&Obligation{ID: "consent", Name: "consent_check", Kind: "ongoing"})
+
+	sessionID, _ := uconE.CreateSessionWithPurpose("alice", "read", "document1", "support", nil)
+	session, err := uconE.EnforceWithSession(sessionID)
+	if err != nil || session == nil {
+		t.Fatalf("Expected access with consent to be granted, got %v", err)
+	}
+	time.Sleep(700 * time.Millisecond)
+	if session.IfActive() {
+		_ = uconE.StopMonitoring(sessionID)
+		t.Error("Expected the session to be revoked once consent expired")
+	}
+}
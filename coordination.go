@@ -0,0 +1,263 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrFenced is returned by a HeartbeatStore for writes of a session carrying
+// an older fencing token than the session's current owner, i.e. writes of a
+// node that lost the session without noticing, e.g. after a long pause.
+var ErrFenced = errors.New("stale fencing token")
+
+// HeartbeatStore is implemented by session stores that coordinate a cluster
+// of enforcers with node heartbeats and fencing tokens, a lighter alternative
+// to consensus: each node keeps one heartbeat alive instead of a lease per
+// session. With Redis, Heartbeat is a SET with expiry of the node's key and
+// ClaimSession a script that sets the owner if it is unset or its node key
+// expired, incrementing the fencing token (SET NX semantics).
+//
+// A store implementing it must reject with ErrFenced any SaveSession whose
+// record has a non-zero Fence lower than the session's current fencing
+// token. Records with a zero Fence, written by nodes that do not monitor the
+// session, are not fenced.
+type HeartbeatStore interface {
+	// Heartbeat marks node alive for ttl.
+	Heartbeat(node string, ttl time.Duration) error
+	// ClaimSession makes node the owner of a session unless another node
+	// with a live heartbeat owns it. It returns the fencing token of the
+	// session, which increases with every change of owner.
+	ClaimSession(id string, node string) (uint64, bool, error)
+	// ReleaseSession gives up node's ownership of a session. Releasing a
+	// session owned by another node, or none, is not an error.
+	ReleaseSession(id string, node string) error
+}
+
+// WithHeartbeatCoordination makes enforcers sharing a session store that
+// implements HeartbeatStore agree on which of them monitors each session,
+// based on node heartbeats: node identifies this enforcer, and its sessions
+// are taken over by the others once its heartbeat is older than ttl
+// (DefaultLeaseTTL if 0). WatchMonitoringLeases sends the heartbeats and
+// must run on every node. Without a HeartbeatStore the option has no effect.
+//
+// A node that was paused for longer than ttl may still believe it monitors
+// sessions taken over by others (split brain). Its writes of those sessions
+// are then rejected with ErrFenced, and once its heartbeats resume it checks
+// its sessions and stops monitoring the lost ones, with EventMonitoringLost.
+func WithHeartbeatCoordination(node string, ttl time.Duration) Option {
+	return func(u *UconEnforcer) {
+		if ttl <= 0 {
+			ttl = DefaultLeaseTTL
+		}
+		u.leaseNode, u.leaseTTL = node, ttl
+		u.heartbeatCoordination = true
+	}
+}
+
+// heartbeats returns the heartbeat store if heartbeat coordination is enabled.
+func (u *UconEnforcer) heartbeats() (HeartbeatStore, bool) {
+	if u.leaseNode == "" || !u.heartbeatCoordination {
+		return nil, false
+	}
+	heartbeats, ok := u.store.(HeartbeatStore)
+	return heartbeats, ok
+}
+
+// heartbeat sends the node's heartbeat. If the previous one succeeded more
+// than a TTL ago, other nodes may have taken over its sessions, so it checks
+// that it still owns every session it monitors.
+func (u *UconEnforcer) heartbeat(now time.Time) {
+	heartbeats, _ := u.heartbeats()
+	if err := heartbeats.Heartbeat(u.leaseNode, u.leaseTTL); err != nil {
+		u.logger.Log(LevelWarn, "failed to send heartbeat", map[string]interface{}{"node": u.leaseNode, "error": err.Error()})
+		return
+	}
+	u.mu.Lock()
+	last := u.lastHeartbeat
+	u.lastHeartbeat = now
+	monitored := make([]string, 0, len(u.monitoringActive))
+	for id := range u.monitoringActive {
+		monitored = append(monitored, id)
+	}
+	u.mu.Unlock()
+	if last.IsZero() || now.Sub(last) < u.leaseTTL {
+		return
+	}
+
+	u.logger.Log(LevelWarn, "heartbeat resumed after a gap, checking monitored sessions", map[string]interface{}{"node": u.leaseNode, "gap": now.Sub(last)})
+	for _, id := range monitored {
+		session, err := u.GetSession(id)
+		if err != nil {
+			continue
+		}
+		if claimed, err := u.claim(id); err == nil && !claimed {
+			u.loseLease(session)
+		}
+	}
+}
+
+// sessionFenced stops monitoring a session whose write was fenced: another
+// node owns it now.
+func (u *UconEnforcer) sessionFenced(sessionID string) {
+	u.mu.RLock()
+	monitored := u.monitoringActive[sessionID]
+	u.mu.RUnlock()
+	if !monitored {
+		return
+	}
+	if session, err := u.GetSession(sessionID); err == nil {
+		u.loseLease(session)
+	}
+}
+
+func (s *Session) setFence(fence uint64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.fence = fence
+}
+
+// fileOwner is the content of a FileSessionStore owner file.
+type fileOwner struct {
+	Node  string `json:"node,omitempty"`
+	Fence uint64 `json:"fence"`
+}
+
+// Heartbeat implements HeartbeatStore. Heartbeats are kept in a "nodes"
+// subdirectory of the store.
+func (fs *FileSessionStore) Heartbeat(node string, ttl time.Duration) error {
+	path, err := fs.heartbeatPath(node)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create heartbeat directory: %w", err)
+	}
+	data, err := json.Marshal(fileLease{Owner: node, Expires: time.Now().Add(ttl)})
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write heartbeat of node %s: %w", node, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write heartbeat of node %s: %w", node, err)
+	}
+	return nil
+}
+
+func (fs *FileSessionStore) heartbeatPath(node string) (string, error) {
+	if _, err := fs.path(node); err != nil {
+		return "", fmt.Errorf("invalid node name %q", node)
+	}
+	return filepath.Join(fs.dir, "nodes", node+".heartbeat"), nil
+}
+
+// alive reports whether node has an unexpired heartbeat.
+func (fs *FileSessionStore) alive(node string) bool {
+	path, err := fs.heartbeatPath(node)
+	if err != nil {
+		return false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	heartbeat := fileLease{}
+	return json.Unmarshal(data, &heartbeat) == nil && time.Now().Before(heartbeat.Expires)
+}
+
+// ClaimSession implements HeartbeatStore.
+func (fs *FileSessionStore) ClaimSession(id string, node string) (uint64, bool, error) {
+	var fence uint64
+	claimed := false
+	err := fs.withLock(id, ".owner", func(path string) error {
+		owner, err := readOwner(path)
+		if err != nil {
+			return err
+		}
+		fence = owner.Fence
+		if owner.Node == node {
+			claimed = true
+			return nil
+		}
+		if owner.Node != "" && fs.alive(owner.Node) {
+			return nil
+		}
+		fence++
+		if err := writeOwner(path, fileOwner{Node: node, Fence: fence}); err != nil {
+			return err
+		}
+		claimed = true
+		return nil
+	})
+	return fence, claimed, err
+}
+
+// ReleaseSession implements HeartbeatStore. The fencing token is kept.
+func (fs *FileSessionStore) ReleaseSession(id string, node string) error {
+	return fs.withLock(id, ".owner", func(path string) error {
+		owner, err := readOwner(path)
+		if err != nil || owner.Node != node {
+			return err
+		}
+		return writeOwner(path, fileOwner{Fence: owner.Fence})
+	})
+}
+
+// checkFence returns ErrFenced if record carries an older fencing token than
+// the current owner of the session. The caller holds the owner lock.
+func checkFence(path string, record *SessionRecord) error {
+	owner, err := readOwner(path)
+	if err != nil {
+		return err
+	}
+	if record.Fence < owner.Fence {
+		return fmt.Errorf("session %s: %w %d, current %d", record.ID, ErrFenced, record.Fence, owner.Fence)
+	}
+	return nil
+}
+
+func readOwner(path string) (fileOwner, error) {
+	owner := fileOwner{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return owner, nil
+	}
+	if err != nil {
+		return owner, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &owner); err != nil {
+		return owner, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return owner, nil
+}
+
+func writeOwner(path string, owner fileOwner) error {
+	data, err := json.Marshal(owner)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFileSessionStoreClaims(t *testing.T) {
+	store, _ := NewFileSessionStore(t.TempDir())
+	claim := func(node string, wantFence uint64, wantClaimed bool) {
+		t.Helper()
+		fence, claimed, err := store.ClaimSession("s1", node)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fence != wantFence || claimed != wantClaimed {
+			t.Errorf("Expected %s to get fence %d claimed=%v, got %d %v", node, wantFence, wantClaimed, fence, claimed)
+		}
+	}
+	store.Heartbeat("a", 50*time.Millisecond)
+	claim("a", 1, true)
+	claim("a", 1, true)
+	claim("b", 1, false)
+	time.Sleep(60 * time.Millisecond)
+	store.Heartbeat("b", time.Minute)
+	claim("b", 2, true)
+
+	// Writes with an older fencing token are rejected, unfenced ones are not.
+	if err := store.SaveSession(&SessionRecord{ID: "s1", Fence: 1}); !errors.Is(err, ErrFenced) {
+		t.Errorf("Expected ErrFenced, got %v", err)
+	}
+	if err := store.SaveSession(&SessionRecord{ID: "s1", Fence: 2}); err != nil {
+		t.Error(err)
+	}
+	if err := store.SaveSession(&SessionRecord{ID: "s1"}); err != nil {
+		t.Error(err)
+	}
+
+	// Releasing keeps the token, so the next owner gets a newer one.
+	store.ReleaseSession("s1", "a")
+	claim("a", 2, false)
+	store.ReleaseSession("s1", "b")
+	claim("a", 3, true)
+
+	if err := store.Heartbeat("../x", time.Second); err == nil {
+		t.Error("Expected an invalid node name to be rejected")
+	}
+}
+
+func TestHeartbeatCoordinationSplitBrain(t *testing.T) {
+	dir := t.TempDir()
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	ttl := 200 * time.Millisecond
+	newNode := func(node string) (*UconEnforcer, chan Event) {
+		store, _ := NewFileSessionStore(dir)
+		u := NewUconEnforcer(e, WithSessionStore(store), WithHeartbeatCoordination(node, ttl)).(*UconEnforcer)
+		events := make(chan Event, 16)
+		u.AddEventListener(func(event Event) {
+			if event.Type == EventMonitoringTakeover || event.Type == EventMonitoringLost {
+				events <- event
+			}
+		})
+		return u, events
+	}
+	nodeA, eventsA := newNode("a")
+	nodeB, eventsB := newNode("b")
+	nodeA.heartbeat(time.Now())
+	nodeB.heartbeat(time.Now())
+
+	sessionID, _ := nodeA.CreateSession("alice", "read", "document1", nil)
+	if _, err := nodeA.EnforceWithSession(sessionID); err != nil {
+		t.Fatal(err)
+	}
+	if err := nodeB.claimExpiredLeases(); err != nil {
+		t.Fatal(err)
+	}
+	if nodeB.MonitoredSessionCount() != 0 {
+		t.Fatal("Expected node b to leave the session to live node a")
+	}
+
+	// Node a pauses: its heartbeat expires and node b takes over, while node
+	// a still believes it monitors the session.
+	time.Sleep(ttl + 50*time.Millisecond)
+	nodeB.heartbeat(time.Now())
+	if err := nodeB.claimExpiredLeases(); err != nil {
+		t.Fatal(err)
+	}
+	if event := <-eventsB; event.Type != EventMonitoringTakeover {
+		t.Fatalf("Expected node b to take over, got %s", event.Type)
+	}
+	if nodeA.MonitoredSessionCount() != 1 || nodeB.MonitoredSessionCount() != 1 {
+		t.Fatal("Expected both nodes to monitor the session during the split brain")
+	}
+
+	// Node a's writes are fenced, so it cannot clobber node b's state, and
+	// it gives up monitoring.
+	if err := nodeA.UpdateSessionAttribute(sessionID, "location", "elsewhere"); !errors.Is(err, ErrFenced) {
+		t.Errorf("Expected ErrFenced, got %v", err)
+	}
+	select {
+	case event := <-eventsA:
+		if event.Type != EventMonitoringLost {
+			t.Errorf("Expected EventMonitoringLost, got %s", event.Type)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected node a to give up the session")
+	}
+	if nodeA.MonitoredSessionCount() != 0 {
+		t.Error("Expected node a to stop monitoring")
+	}
+	if err := nodeB.UpdateSessionAttribute(sessionID, "location", "office"); err != nil {
+		t.Error(err)
+	}
+	store, _ := NewFileSessionStore(dir)
+	records, _ := store.LoadSessions()
+	if len(records) != 1 || records[0].Attributes["location"] != "office" || records[0].Fence != 2 {
+		t.Errorf("Expected node b's state in the store, got %+v", records[0])
+	}
+}
+
+func TestHeartbeatGapRechecksSessions(t *testing.T) {
+	dir := t.TempDir()
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	ttl := 200 * time.Millisecond
+	storeA, _ := NewFileSessionStore(dir)
+	nodeA := NewUconEnforcer(e, WithSessionStore(storeA), WithHeartbeatCoordination("a", ttl)).(*UconEnforcer)
+	lost := make(chan Event, 1)
+	nodeA.AddEventListener(func(event Event) {
+		if event.Type == EventMonitoringLost {
+			lost <- event
+		}
+	})
+	start := time.Now()
+	nodeA.heartbeat(start)
+	sessionID, _ := nodeA.CreateSession("alice", "read", "document1", nil)
+	if _, err := nodeA.EnforceWithSession(sessionID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another node claims the session while node a's heartbeat is expired.
+	time.Sleep(ttl + 50*time.Millisecond)
+	storeB, _ := NewFileSessionStore(dir)
+	storeB.Heartbeat("b", time.Minute)
+	if _, claimed, _ := storeB.ClaimSession(sessionID, "b"); !claimed {
+		t.Fatal("Expected node b to claim the session")
+	}
+
+	// Node a's next heartbeat comes after a gap, so it rechecks its sessions.
+	nodeA.heartbeat(time.Now())
+	select {
+	case <-lost:
+	case <-time.After(time.Second):
+		t.Fatal("Expected node a to notice it lost the session")
+	}
+	if nodeA.MonitoredSessionCount() != 0 {
+		t.Error("Expected node a to stop monitoring")
+	}
+	session, _ := nodeA.GetSession(sessionID)
+	if !session.IfActive() {
+		t.Error("Expected the session to stay active")
+	}
+}
+
+func TestFenceOnlyFromClaim(t *testing.T) {
+	dir := t.TempDir()
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	store, _ := NewFileSessionStore(dir)
+	node := NewUconEnforcer(e, WithSessionStore(store), WithHeartbeatCoordination("a", time.Minute)).(*UconEnforcer)
+	node.heartbeat(time.Now())
+
+	sessionID, _ := node.CreateSession("alice", "read", "document1", nil)
+	if _, err := node.EnforceWithSession(sessionID); err != nil {
+		t.Fatal(err)
+	}
+	session, _ := node.GetSession(sessionID)
+	if fence := session.ToRecord().Fence; fence != 1 {
+		t.Fatalf("Expected the claimed session to carry fence 1, got %d", fence)
+	}
+
+	// A node restoring the session does not own it, so it takes no fence.
+	records, _ := store.LoadSessions()
+	if restored := NewSession(records[0], nil, nil); records[0].Fence != 1 || restored.ToRecord().Fence != 0 {
+		t.Errorf("Expected the restored session to have no fence, got %d", restored.ToRecord().Fence)
+	}
+
+	// Nor does this node once it released the session.
+	_ = node.StopMonitoring(sessionID)
+	if fence := session.ToRecord().Fence; fence != 0 {
+		t.Errorf("Expected the released session to have no fence, got %d", fence)
+	}
+}
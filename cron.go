@@ -0,0 +1,192 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule is a parsed cron specification. It supports the standard
+// five fields (minute, hour, day of month, month, day of week) as well as
+// the descriptors @yearly, @monthly, @weekly, @daily, @hourly and
+// "@every <duration>".
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+
+	every time.Duration
+}
+
+type cronField struct {
+	min, max int
+}
+
+var (
+	minuteField = cronField{0, 59}
+	hourField   = cronField{0, 23}
+	domField    = cronField{1, 31}
+	monthField  = cronField{1, 12}
+	dowField    = cronField{0, 7}
+)
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// parseCronSpec parses a cron specification.
+func parseCronSpec(spec string) (*cronSchedule, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron spec %q: %w", spec, err)
+		}
+		if d < time.Second {
+			return nil, fmt.Errorf("invalid cron spec %q: interval must be at least 1s", spec)
+		}
+		return &cronSchedule{every: d}, nil
+	}
+	if expanded, ok := cronDescriptors[spec]; ok {
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron spec %q: expected 5 fields, got %d", spec, len(fields))
+	}
+
+	s := &cronSchedule{}
+	var err error
+	if s.minute, err = parseCronField(fields[0], minuteField); err != nil {
+		return nil, fmt.Errorf("invalid cron spec %q: minute: %w", spec, err)
+	}
+	if s.hour, err = parseCronField(fields[1], hourField); err != nil {
+		return nil, fmt.Errorf("invalid cron spec %q: hour: %w", spec, err)
+	}
+	if s.dom, err = parseCronField(fields[2], domField); err != nil {
+		return nil, fmt.Errorf("invalid cron spec %q: day of month: %w", spec, err)
+	}
+	if s.month, err = parseCronField(fields[3], monthField); err != nil {
+		return nil, fmt.Errorf("invalid cron spec %q: month: %w", spec, err)
+	}
+	if s.dow, err = parseCronField(fields[4], dowField); err != nil {
+		return nil, fmt.Errorf("invalid cron spec %q: day of week: %w", spec, err)
+	}
+	// Both 0 and 7 mean Sunday.
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domStar = strings.HasPrefix(fields[2], "*")
+	s.dowStar = strings.HasPrefix(fields[4], "*")
+	return s, nil
+}
+
+// parseCronField parses a comma separated list of values, ranges and steps
+// into a bitset.
+func parseCronField(field string, bounds cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			rangePart, step = part[:i], n
+		}
+
+		lo, hi := bounds.min, bounds.max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			ends := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if lo, err = strconv.Atoi(ends[0]); err != nil {
+				return 0, fmt.Errorf("invalid range %q", part)
+			}
+			if hi, err = strconv.Atoi(ends[1]); err != nil {
+				return 0, fmt.Errorf("invalid range %q", part)
+			}
+		default:
+			n, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+			lo = n
+			if step == 1 {
+				hi = n
+			}
+		}
+		if lo < bounds.min || hi > bounds.max || lo > hi {
+			return 0, fmt.Errorf("value %q out of range [%d, %d]", part, bounds.min, bounds.max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// next returns the first activation time strictly after t, or the zero
+// time if the schedule never fires within the next five years.
+func (s *cronSchedule) next(t time.Time) time.Time {
+	if s.every > 0 {
+		return t.Add(s.every)
+	}
+
+	t = t.Add(time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches applies the usual cron rule: when both day of month and day of
+// week are restricted, either one matching is enough.
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronSpecNext(t *testing.T) {
+	base := time.Date(2025, time.March, 14, 10, 17, 42, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"@hourly", time.Date(2025, time.March, 14, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, time.March, 14, 10, 30, 0, 0, time.UTC)},
+		{"0 9-17 * * 1-5", time.Date(2025, time.March, 14, 11, 0, 0, 0, time.UTC)},
+		{"30 8 * * 7", time.Date(2025, time.March, 16, 8, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", base.Add(90 * time.Second)},
+	}
+
+	for _, tt := range tests {
+		schedule, err := parseCronSpec(tt.spec)
+		if err != nil {
+			t.Fatalf("parseCronSpec(%q) failed: %v", tt.spec, err)
+		}
+		if got := schedule.next(base); !got.Equal(tt.want) {
+			t.Errorf("next(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestCronSpecInvalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "@every 10ms", "@sometimes"} {
+		if _, err := parseCronSpec(spec); err == nil {
+			t.Errorf("parseCronSpec(%q) should fail", spec)
+		}
+	}
+}
+
+func TestScheduledObligation(t *testing.T) {
+	uconE := GetUconEnforcer()
+
+	err := uconE.AddObligation(&Obligation{
+		ID:       "hourly_report",
+		Name:     "user_authentication",
+		Kind:     "pre",
+		Expr:     "authenticated:true",
+		Schedule: "@hourly",
+	})
+	if err == nil {
+		t.Fatal("Expected schedule on a pre obligation to be rejected")
+	}
+
+	err = uconE.AddObligation(&Obligation{
+		ID:       "hourly_report",
+		Name:     "user_authentication",
+		Kind:     "ongoing",
+		Expr:     "authenticated:true",
+		Schedule: "@every 1h",
+	})
+	if err != nil {
+		t.Fatalf("Failed to add scheduled obligation: %v", err)
+	}
+
+	// The obligation fails whenever it runs, so an error tells us it fired.
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{
+		"authenticated": "false",
+	})
+	session, _ := uconE.GetSession(sessionID)
+
+	u := uconE.(*UconEnforcer)
+	start := time.Now()
+	nextRun := make(map[string]time.Time)
+	if err := u.executeOngoingObligations(session, start, nextRun, nil); err != nil {
+		t.Fatalf("Scheduled obligation should not run on the first tick: %v", err)
+	}
+	if err := u.executeOngoingObligations(session, start.Add(30*time.Minute), nextRun, nil); err != nil {
+		t.Fatalf("Scheduled obligation should not run before it is due: %v", err)
+	}
+	if err := u.executeOngoingObligations(session, start.Add(61*time.Minute), nextRun, nil); err == nil {
+		t.Fatal("Scheduled obligation should run once it is due")
+	}
+}
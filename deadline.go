@@ -0,0 +1,84 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"fmt"
+	"time"
+)
+
+// OverrunPolicy decides what happens when a monitoring evaluation exceeds
+// its deadline.
+type OverrunPolicy int
+
+const (
+	// OverrunSkip skips the tick. The session keeps running and the
+	// evaluation's result is applied once it finishes.
+	OverrunSkip OverrunPolicy = iota
+	// OverrunFailOpen counts the evaluation as passed and discards its result.
+	OverrunFailOpen
+	// OverrunFailClosed revokes the session.
+	OverrunFailClosed
+)
+
+// MetricEvaluationOverrun counts the evaluations that exceeded their
+// deadline, by overrun policy, in CounterMetrics.
+const MetricEvaluationOverrun = "evaluation_overrun"
+
+var overrunPolicyNames = map[OverrunPolicy]string{
+	OverrunSkip:       "skip",
+	OverrunFailOpen:   "fail_open",
+	OverrunFailClosed: "fail_closed",
+}
+
+// WithEvaluationDeadline bounds each monitoring evaluation (provider refresh,
+// conditions, analyzer and ongoing obligations) to deadline, applying policy
+// to evaluations that take longer. While an evaluation is still running,
+// later ticks of the session are skipped, so evaluations never pile up
+// behind a slow provider.
+func WithEvaluationDeadline(deadline time.Duration, policy OverrunPolicy) Option {
+	return func(u *UconEnforcer) {
+		u.evaluationDeadline = deadline
+		u.overrunPolicy = policy
+	}
+}
+
+// awaitEvaluation waits for the evaluation started at now until its deadline.
+// It returns overrun if the evaluation is still running under OverrunSkip or
+// OverrunFailOpen; under OverrunFailClosed the returned evaluation revokes
+// the session.
+func (u *UconEnforcer) awaitEvaluation(session *Session, now time.Time, results <-chan evaluation) (evaluation, bool) {
+	timer := time.NewTimer(u.evaluationDeadline)
+	defer timer.Stop()
+	select {
+	case result := <-results:
+		return result, false
+	case <-timer.C:
+	}
+
+	u.incCounter(MetricEvaluationOverrun, overrunPolicyNames[u.overrunPolicy])
+	u.logger.Log(LevelWarn, "evaluation exceeded its deadline", map[string]interface{}{
+		"session":  session.GetId(),
+		"deadline": u.evaluationDeadline.String(),
+		"policy":   overrunPolicyNames[u.overrunPolicy],
+	})
+	if u.overrunPolicy != OverrunFailClosed {
+		return evaluation{}, true
+	}
+	return evaluation{
+		trace:  &TraceEntry{Time: now},
+		reason: fmt.Sprintf("Evaluation of session %s exceeded its deadline of %v, revoking...\n", session.GetId(), u.evaluationDeadline),
+	}, false
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEvaluationDeadline(t *testing.T) {
+	for _, tc := range []struct {
+		policy  OverrunPolicy
+		name    string
+		revoked bool
+	}{
+		{OverrunSkip, "skip", true},
+		{OverrunFailOpen, "fail_open", false},
+		{OverrunFailClosed, "fail_closed", true},
+	} {
+		e := GetUconEnforcer().(*UconEnforcer).Enforcer
+		uconE := NewUconEnforcer(e, WithEvaluationDeadline(50*time.Millisecond, tc.policy))
+		// A slow dependency that eventually reports the condition as failed.
+		_ = uconE.RegisterConditionHandler("slow", func(expr string, session SessionView) (bool, error) {
+			if session.GetAttribute("monitoring") == true {
+				time.Sleep(300 * time.Millisecond)
+				return false, nil
+			}
+			return true, nil
+		})
+		_ = uconE.AddCondition(&Condition{ID: "slow", Name: "slow", Kind: "always"})
+
+		sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+		session, _ := uconE.EnforceWithSession(sessionID)
+		if session == nil {
+			t.Fatalf("%s: expected access", tc.name)
+		}
+		_ = session.UpdateAttribute("monitoring", true)
+
+		time.Sleep(700 * time.Millisecond)
+		if revoked := !session.IfActive(); revoked != tc.revoked {
+			t.Errorf("%s: expected revoked=%v, stop reason %q", tc.name, tc.revoked, session.GetStopReason())
+		}
+		if tc.policy == OverrunFailClosed && !strings.Contains(session.GetStopReason(), "deadline") {
+			t.Errorf("%s: unexpected stop reason %q", tc.name, session.GetStopReason())
+		}
+		if tc.policy == OverrunSkip && !strings.Contains(session.GetStopReason(), "Conditions no longer met") {
+			t.Errorf("%s: expected the late result to revoke the session, got %q", tc.name, session.GetStopReason())
+		}
+		counters := uconE.GetMetrics().(*InMemoryMetrics).Snapshot().Counters
+		if counters[MetricEvaluationOverrun][tc.name] == 0 {
+			t.Errorf("%s: expected overruns to be counted, got %v", tc.name, counters)
+		}
+		_ = uconE.StopMonitoring(sessionID)
+	}
+}
+
+func TestTriggeredEvaluationDeadline(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithEvaluationDeadline(50*time.Millisecond, OverrunFailClosed)).(*UconEnforcer)
+	_ = uconE.RegisterConditionHandler("slow", func(expr string, session SessionView) (bool, error) {
+		if session.GetAttribute("risk") == "high" {
+			time.Sleep(300 * time.Millisecond)
+			return false, nil
+		}
+		return true, nil
+	})
+	_ = uconE.AddCondition(&Condition{ID: "slow", Name: "slow", Kind: "always", Attributes: []string{"risk"}})
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"risk": "low"})
+	session, _ := uconE.GetSession(sessionID)
+
+	// Monitor with a long interval, so that only a trigger evaluates the session.
+	uconE.mu.Lock()
+	uconE.monitoringActive[sessionID] = true
+	uconE.mu.Unlock()
+	uconE.startMonitoring(session, time.Hour)
+	waitFor(t, func() bool {
+		uconE.mu.RLock()
+		defer uconE.mu.RUnlock()
+		return uconE.wakeups[sessionID] != nil
+	})
+
+	_ = uconE.UpdateSessionAttribute(sessionID, "risk", "high")
+	waitFor(t, func() bool { return !session.IfActive() })
+	if !strings.Contains(session.GetStopReason(), "deadline") {
+		t.Errorf("Expected the triggered evaluation to overrun its deadline, got %q", session.GetStopReason())
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Directive types attached to decisions by the built-in transformation obligations.
+const (
+	DirectiveMask             = "mask"
+	DirectiveWatermark        = "watermark"
+	DirectiveReduceResolution = "reduce_resolution"
+)
+
+// Directive tells the enforcement point how to modify content before
+// releasing it, e.g. which fields to mask.
+type Directive struct {
+	Type   string            `json:"type"`
+	Fields []string          `json:"fields,omitempty"`
+	Params map[string]string `json:"params,omitempty"`
+}
+
+// Decision is the outcome of Decide: whether access is granted, and the
+// directives the enforcement point must apply to the content it releases.
+type Decision struct {
+	Allowed    bool        `json:"allowed"`
+	Session    *Session    `json:"-"`
+	Directives []Directive `json:"directives,omitempty"`
+	// Degraded is set when access was granted while the session store was
+	// unreachable, under StoreAllowDegraded.
+	Degraded bool `json:"degraded,omitempty"`
+	// RetryAfter is when a denied session may be granted by waiting alone:
+	// the end of the maintenance window of its object, or when the condition
+	// that denied it passes again (weekday_in, time_budget). It is nil if
+	// unknown.
+	RetryAfter *time.Time `json:"retry_after,omitempty"`
+	// MissingAttributes are the attributes a denied session lacks for the
+	// condition that denied it.
+	MissingAttributes []string `json:"missing_attributes,omitempty"`
+}
+
+// Decide enforces a session like EnforceWithSession and returns the decision
+// with the transformation directives attached by the session's obligations.
+func (u *UconEnforcer) Decide(sessionID string) (*Decision, error) {
+	session, err := u.EnforceWithSession(sessionID)
+	if session == nil {
+		decision := &Decision{}
+		u.addHints(decision, sessionID, err)
+		return decision, err
+	}
+	return &Decision{Allowed: true, Session: session, Directives: session.GetDirectives(), Degraded: session.IsStoreDegraded()}, err
+}
+
+// executeMaskFields is the "mask_fields" obligation: Expr lists the fields to mask, comma separated.
+func (u *UconEnforcer) executeMaskFields(expr string, session *Session) error {
+	var fields []string
+	for _, field := range strings.Split(expr, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == 0 {
+		return errors.New("mask_fields needs at least one field")
+	}
+	session.AddDirective(Directive{Type: DirectiveMask, Fields: fields})
+	return nil
+}
+
+// executeWatermark is the "watermark" obligation: Expr is the watermark text,
+// defaulting to the session subject and id.
+func (u *UconEnforcer) executeWatermark(expr string, session *Session) error {
+	text := strings.TrimSpace(expr)
+	if text == "" {
+		text = session.GetSubject() + " " + session.GetId()
+	}
+	session.AddDirective(Directive{Type: DirectiveWatermark, Params: map[string]string{"text": text}})
+	return nil
+}
+
+// executeReduceResolution is the "reduce_resolution" obligation: Expr is the
+// highest resolution the content may be released at, e.g. "720p" or "50%".
+func (u *UconEnforcer) executeReduceResolution(expr string, session *Session) error {
+	max := strings.TrimSpace(expr)
+	if max == "" {
+		return errors.New("reduce_resolution needs a maximum resolution")
+	}
+	session.AddDirective(Directive{Type: DirectiveReduceResolution, Params: map[string]string{"max": max}})
+	return nil
+}
+
+// AddDirective attaches a directive to the session unless an equal one is
+// already attached. Custom obligation handlers use it to require their own
+// content transformations.
+func (s *Session) AddDirective(directive Directive) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, d := range s.directives {
+		if reflect.DeepEqual(d, directive) {
+			return
+		}
+	}
+	s.directives = append(s.directives, directive)
+}
+
+// GetDirectives returns the transformation directives attached to the session.
+func (s *Session) GetDirectives() []Directive {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return append([]Directive(nil), s.directives...)
+}
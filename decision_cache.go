@@ -0,0 +1,120 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"sync"
+	"time"
+)
+
+// decisionCache remembers recent grants of EnforceWithSession. An entry is
+// valid until its TTL expires, the session changes (its version moves) or
+// the conditions or obligations change (the epoch moves).
+type decisionCache struct {
+	ttl     time.Duration
+	epoch   uint64
+	entries map[string]cachedDecision
+	mu      sync.Mutex
+}
+
+type cachedDecision struct {
+	version uint64
+	epoch   uint64
+	expires time.Time
+}
+
+// WithDecisionCache caches grants of EnforceWithSession for ttl, so a session
+// enforced on every request does not rerun its conditions, pre obligations
+// and policy check each time. A cached grant is dropped as soon as the
+// session changes or a condition or obligation is added or removed; policy
+// changes and time-dependent conditions are only picked up once it expires,
+// or after InvalidateDecisions. Denials are never cached.
+func WithDecisionCache(ttl time.Duration) Option {
+	return func(u *UconEnforcer) {
+		if ttl > 0 {
+			u.decisions = &decisionCache{ttl: ttl, entries: make(map[string]cachedDecision)}
+		}
+	}
+}
+
+// InvalidateDecisions drops all cached decisions, e.g. after a policy change.
+func (u *UconEnforcer) InvalidateDecisions() {
+	if u.decisions != nil {
+		u.decisions.invalidate()
+	}
+}
+
+func (c *decisionCache) get(session *Session, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[session.GetId()]
+	if !ok {
+		return false
+	}
+	if entry.epoch != c.epoch || entry.version != session.getVersion() || !now.Before(entry.expires) {
+		delete(c.entries, session.GetId())
+		return false
+	}
+	return true
+}
+
+// put caches a grant of the session at version, evaluated under epoch.
+func (c *decisionCache) put(sessionID string, version uint64, epoch uint64, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if epoch != c.epoch {
+		return
+	}
+	c.entries[sessionID] = cachedDecision{version: version, epoch: epoch, expires: now.Add(c.ttl)}
+}
+
+// cacheDecision caches a grant evaluated at the session version and the
+// epoch taken before evaluation. The version moves during the evaluation
+// when pre obligations or the grant change the session, but also when
+// another writer does after its conditions were evaluated. If it moved, the
+// conditions are dry-run again on the current state, and the grant is only
+// cached, at the current version, if they still pass; later writes make the
+// entry miss.
+func (u *UconEnforcer) cacheDecision(session *Session, version uint64, epoch uint64) {
+	if current := session.getVersion(); current != version {
+		for _, condition := range u.conditionList() {
+			cond := condition // Create a copy to avoid memory aliasing
+			if passed, err := u.dryRunCondition(&cond, session, u.getFailurePolicy()); !passed || err != nil {
+				return
+			}
+		}
+		version = current
+	}
+	u.decisions.put(session.GetId(), version, epoch, time.Now())
+}
+
+func (c *decisionCache) currentEpoch() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.epoch
+}
+
+func (c *decisionCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.epoch++
+	c.entries = make(map[string]cachedDecision)
+}
+
+func (c *decisionCache) remove(sessionID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, sessionID)
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDecisionCache(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithDecisionCache(time.Minute))
+	_ = uconE.AddObligation(&Obligation{ID: "count", Name: "increment_counter", Kind: "pre", Expr: "access_count"})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"role": "user"})
+	defer func() { _ = uconE.StopMonitoring(sessionID) }()
+	for i := 0; i < 3; i++ {
+		if session, err := uconE.EnforceWithSession(sessionID); session == nil || err != nil {
+			t.Fatalf("Expected access, got %v", err)
+		}
+	}
+	session, _ := uconE.GetSession(sessionID)
+	if count := session.GetAttribute("access_count"); count != 1 {
+		t.Fatalf("Expected the pre obligation to run once, got %v", count)
+	}
+
+	// An attribute update invalidates the cached grant.
+	_ = uconE.UpdateSessionAttribute(sessionID, "role", "admin")
+	_, _ = uconE.EnforceWithSession(sessionID)
+	if count := session.GetAttribute("access_count"); count != 2 {
+		t.Fatalf("Expected the pre obligation to run again, got %v", count)
+	}
+
+	// So does a new condition, which now denies access.
+	_ = uconE.AddCondition(&Condition{ID: "role", Name: "attribute_equals", Kind: "one", Expr: "role:user"})
+	if session, _ := uconE.EnforceWithSession(sessionID); session != nil {
+		t.Fatal("Expected the new condition to be evaluated")
+	}
+	_ = uconE.RemoveCondition("role")
+
+	_, _ = uconE.EnforceWithSession(sessionID)
+	uconE.InvalidateDecisions()
+	_, _ = uconE.EnforceWithSession(sessionID)
+	if count := session.GetAttribute("access_count"); count != 4 {
+		t.Fatalf("Expected InvalidateDecisions to drop the grant, got %v", count)
+	}
+}
+
+func TestDecisionCacheExpiry(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithDecisionCache(50*time.Millisecond))
+	_ = uconE.AddObligation(&Obligation{ID: "count", Name: "increment_counter", Kind: "pre", Expr: "access_count"})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	defer func() { _ = uconE.StopMonitoring(sessionID) }()
+	_, _ = uconE.EnforceWithSession(sessionID)
+	time.Sleep(100 * time.Millisecond)
+	session, _ := uconE.EnforceWithSession(sessionID)
+	if session == nil || session.GetAttribute("access_count") != 2 {
+		t.Fatal("Expected the grant to expire")
+	}
+}
+
+func TestDecisionCacheConcurrentWrite(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithDecisionCache(time.Minute))
+	_ = uconE.AddCondition(&Condition{ID: "role", Name: "attribute_equals", Kind: "always", Expr: "role:user"})
+	// Stands in for a writer that changes the session after its conditions
+	// were evaluated, but before the grant is cached.
+	_ = uconE.RegisterObligationHandler("demote", func(expr string, session *Session) error {
+		return session.UpdateAttribute("role", "guest")
+	})
+	_ = uconE.AddObligation(&Obligation{ID: "demote", Name: "demote", Kind: "pre"})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"role": "user"})
+	defer func() { _ = uconE.StopMonitoring(sessionID) }()
+	if session, err := uconE.EnforceWithSession(sessionID); session == nil || err != nil {
+		t.Fatalf("Expected access, got %v", err)
+	}
+	if session, _ := uconE.EnforceWithSession(sessionID); session != nil {
+		t.Fatal("Expected a grant the session no longer satisfies not to be cached")
+	}
+}
+
+func TestDecisionCacheWithProvider(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithDecisionCache(time.Minute))
+	if err := uconE.RegisterAttributeProvider("idp", &fakeProvider{}); err != nil {
+		t.Fatal(err)
+	}
+	_ = uconE.AddObligation(&Obligation{ID: "count", Name: "increment_counter", Kind: "pre", Expr: "access_count"})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	defer func() { _ = uconE.StopMonitoring(sessionID) }()
+	session, _ := uconE.GetSession(sessionID)
+	for i := 0; i < 3; i++ {
+		if granted, err := uconE.EnforceWithSession(sessionID); granted == nil || err != nil {
+			t.Fatalf("Expected access, got %v", err)
+		}
+		// Refetch the provider attributes, as monitoring ticks do.
+		version := session.getVersion()
+		if err := uconE.(*UconEnforcer).refreshAttributes(session); err != nil {
+			t.Fatal(err)
+		}
+		if session.getVersion() != version {
+			t.Fatalf("Expected unchanged provider attributes to keep version %d, got %d", version, session.getVersion())
+		}
+	}
+	if count := session.GetAttribute("access_count"); count != 1 {
+		t.Errorf("Expected refetched provider attributes to keep the cached grant, got %v runs", count)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestDecisionDirectives(t *testing.T) {
+	uconE := GetUconEnforcer()
+	_ = uconE.AddObligation(&Obligation{ID: "mask_pii", Name: "mask_fields", Kind: "pre", Expr: "ssn, email"})
+	_ = uconE.AddObligation(&Obligation{ID: "mark", Name: "watermark", Kind: "pre"})
+	_ = uconE.AddObligation(&Obligation{ID: "preview", Name: "reduce_resolution", Kind: "pre", Expr: "720p"})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	decision, err := uconE.Decide(sessionID)
+	if err != nil || !decision.Allowed {
+		t.Fatalf("Expected access to be granted, got %v", err)
+	}
+	defer uconE.StopMonitoring(sessionID)
+
+	// Obligations run in no particular order, so compare directives by type.
+	expected := []Directive{
+		{Type: DirectiveMask, Fields: []string{"ssn", "email"}},
+		{Type: DirectiveReduceResolution, Params: map[string]string{"max": "720p"}},
+		{Type: DirectiveWatermark, Params: map[string]string{"text": "alice " + sessionID}},
+	}
+	sort.Slice(decision.Directives, func(i, j int) bool {
+		return decision.Directives[i].Type < decision.Directives[j].Type
+	})
+	if !reflect.DeepEqual(decision.Directives, expected) {
+		t.Errorf("Expected directives %+v, got %+v", expected, decision.Directives)
+	}
+
+	// Enforcing again does not duplicate directives.
+	decision, _ = uconE.Decide(sessionID)
+	if len(decision.Directives) != len(expected) {
+		t.Errorf("Expected %d directives after re-enforcement, got %d", len(expected), len(decision.Directives))
+	}
+}
+
+func TestDecisionDenied(t *testing.T) {
+	uconE := GetUconEnforcer()
+	_ = uconE.AddObligation(&Obligation{ID: "mask_pii", Name: "mask_fields", Kind: "pre", Expr: "ssn"})
+
+	sessionID, _ := uconE.CreateSession("bob", "write", "document1", nil)
+	decision, _ := uconE.Decide(sessionID)
+	if decision.Allowed || decision.Session != nil {
+		t.Error("Expected access to be denied")
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// attributeSnapshot returns the current values of the attributes a condition
+// declares it reads.
+func attributeSnapshot(condition *Condition, session *Session) map[string]interface{} {
+	values := make(map[string]interface{}, len(condition.Attributes))
+	for _, key := range condition.Attributes {
+		values[key] = session.GetAttribute(key)
+	}
+	return values
+}
+
+// matches reports whether a cached result is still valid for condition.
+func (c *cachedCondition) matches(condition *Condition, version attributeVersion, values map[string]interface{}) bool {
+	if c.name != condition.Name || c.expr != condition.Expr || !slices.Equal(c.attributes, condition.Attributes) ||
+		c.shadow != condition.Shadow || c.rollout != condition.Rollout {
+		return false
+	}
+	if len(condition.Attributes) > 0 {
+		return reflect.DeepEqual(c.values, values)
+	}
+	return c.version == version
+}
+
+// dependsOn reports whether a condition applying to session declares that it
+// reads one of the keys.
+func dependsOn(condition *Condition, session *Session, keys []string) bool {
+	if !conditionApplies(condition, session) {
+		return false
+	}
+	for _, key := range keys {
+		if slices.Contains(condition.Attributes, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// triggerConditions wakes the monitoring loop of session to check its
+// conditions at once if one of them declares it reads one of the changed
+// keys, instead of waiting for the next tick.
+func (u *UconEnforcer) triggerConditions(session *Session, keys []string) {
+	u.mu.RLock()
+	wake, monitored := u.wakeups[session.GetId()]
+	u.mu.RUnlock()
+	if !monitored {
+		return
+	}
+	for _, condition := range u.conditionList() {
+		if dependsOn(&condition, session, keys) {
+			select {
+			case wake <- struct{}{}:
+			default:
+				// A wakeup is already pending.
+			}
+			return
+		}
+	}
+}
+
+// setAttributes sets attributes of session for the enforcer's own writers,
+// such as telemetry, attribute providers and obligations, and like
+// UpdateSessionAttributes validates them and triggers the conditions that
+// read a changed one. With requireActive it fails with ErrSessionNotActive
+// once the session has stopped.
+func (u *UconEnforcer) setAttributes(session *Session, attributes map[string]interface{}, requireActive bool) error {
+	if err := u.validateAttributes(attributes); err != nil {
+		return err
+	}
+	changed, err := session.updateAttributes(attributes, requireActive)
+	if err == nil && changed && !session.IsDryRun() {
+		u.triggerConditions(session, attributeKeys(attributes))
+	}
+	return err
+}
+
+// incrementAttribute is setAttributes for Session.IncrementAttribute.
+func (u *UconEnforcer) incrementAttribute(session *Session, key string, delta int64) (int64, error) {
+	if err := u.validateIncrement(key); err != nil {
+		return 0, err
+	}
+	val, err := session.IncrementAttribute(key, delta)
+	if err == nil && !session.IsDryRun() {
+		u.triggerConditions(session, []string{key})
+	}
+	return val, err
+}
+
+// attributeKeys returns the keys of attributes.
+func attributeKeys(attributes map[string]interface{}) []string {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// triggerSubjectConditions is triggerConditions for every monitored session
+// of subject, after its subject attributes changed.
+func (u *UconEnforcer) triggerSubjectConditions(subject string, attributes map[string]interface{}) {
+	keys := attributeKeys(attributes)
+	for _, session := range u.GetSessions() {
+		if session.GetSubject() == subject && session.IfActive() {
+			u.triggerConditions(session, keys)
+		}
+	}
+}
+
+// registerWakeup makes wake the channel triggerConditions signals for the
+// monitoring loop of a session. The returned function unregisters it.
+func (u *UconEnforcer) registerWakeup(sessionID string, wake chan struct{}) func() {
+	u.mu.Lock()
+	if u.wakeups == nil {
+		u.wakeups = make(map[string]chan struct{})
+	}
+	u.wakeups[sessionID] = wake
+	u.mu.Unlock()
+	return func() {
+		u.mu.Lock()
+		if u.wakeups[sessionID] == wake {
+			delete(u.wakeups, sessionID)
+		}
+		u.mu.Unlock()
+	}
+}
+
+// evaluateTriggered checks only the conditions of a session, after a change
+// of attributes they depend on. Obligations and analyzers keep running on
+// the regular ticks.
+func (u *UconEnforcer) evaluateTriggered(session *Session, now time.Time, state *monitorState) evaluation {
+	trace := state.resetTrace(now)
+	conditionsOk, err := u.evaluateOngoingConditions(session, now, state, trace)
+	if err != nil {
+		return evaluation{trace: trace, reason: fmt.Sprintf("Error evaluating conditions for session %s: %v\n", session.GetId(), err)}
+	}
+	if !conditionsOk {
+		return evaluation{trace: trace, reason: fmt.Sprintf("Conditions no longer met for session %s, revoking...\n", session.GetId())}
+	}
+	return evaluation{trace: trace}
+}
@@ -0,0 +1,116 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDeclaredAttributesCacheConditions(t *testing.T) {
+	uconE := GetUconEnforcer().(*UconEnforcer)
+	var calls int32
+	uconE.RegisterConditionHandler("low_risk", func(expr string, session SessionView) (bool, error) {
+		atomic.AddInt32(&calls, 1)
+		risk, _ := toInt64(session.GetAttribute("risk"))
+		return risk < 70, nil
+	})
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"risk": 10, "location": "office"})
+	session, _ := uconE.GetSession(sessionID)
+	results := make(map[string]cachedCondition)
+
+	undeclared := &Condition{ID: "risk", Name: "low_risk", Kind: "always"}
+	uconE.evaluateCachedCondition(undeclared, session, results)
+	uconE.evaluateCachedCondition(undeclared, session, results)
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("Expected a custom condition to run on every evaluation, ran %d times", n)
+	}
+
+	declared := &Condition{ID: "risk", Name: "low_risk", Kind: "always", Attributes: []string{"risk"}}
+	atomic.StoreInt32(&calls, 0)
+	uconE.evaluateCachedCondition(declared, session, results)
+	uconE.evaluateCachedCondition(declared, session, results)
+	uconE.UpdateSessionAttribute(sessionID, "location", "home")
+	if _, cached, _ := uconE.evaluateCachedCondition(declared, session, results); !cached {
+		t.Error("Expected a change of an undeclared attribute to keep the result")
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("Expected the declared condition to run once, ran %d times", n)
+	}
+	uconE.UpdateSessionAttribute(sessionID, "risk", 90)
+	if passed, cached, _ := uconE.evaluateCachedCondition(declared, session, results); passed || cached {
+		t.Errorf("Expected a change of a declared attribute to re-evaluate, got passed=%v cached=%v", passed, cached)
+	}
+}
+
+func TestDeclaredAttributesTriggerEvaluation(t *testing.T) {
+	uconE := GetUconEnforcer().(*UconEnforcer)
+	var calls int32
+	uconE.RegisterConditionHandler("low_risk", func(expr string, session SessionView) (bool, error) {
+		atomic.AddInt32(&calls, 1)
+		risk, _ := toInt64(session.GetAttribute("risk"))
+		return risk < 70, nil
+	})
+	uconE.AddCondition(&Condition{ID: "risk", Name: "low_risk", Kind: "always", Attributes: []string{"risk"}})
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"risk": 10})
+	session, _ := uconE.GetSession(sessionID)
+
+	// Monitor with a long interval, so that only a trigger evaluates the session.
+	uconE.mu.Lock()
+	uconE.monitoringActive[sessionID] = true
+	uconE.mu.Unlock()
+	uconE.startMonitoring(session, time.Hour)
+	waitFor(t, func() bool {
+		uconE.mu.RLock()
+		defer uconE.mu.RUnlock()
+		return uconE.wakeups[sessionID] != nil
+	})
+
+	uconE.UpdateSessionAttribute(sessionID, "note", "unrelated")
+	time.Sleep(50 * time.Millisecond)
+	if n := atomic.LoadInt32(&calls); n != 0 {
+		t.Errorf("Expected an undeclared attribute not to trigger an evaluation, ran %d times", n)
+	}
+
+	uconE.UpdateSessionAttribute(sessionID, "risk", 20)
+	waitFor(t, func() bool { return atomic.LoadInt32(&calls) == 1 })
+	if !session.IfActive() {
+		t.Fatal("Expected the session to stay active")
+	}
+
+	uconE.SetSubjectAttributes("alice", map[string]interface{}{"risk": 95})
+	time.Sleep(50 * time.Millisecond)
+	if !session.IfActive() {
+		t.Error("Expected the session's own risk to override the subject's")
+	}
+	uconE.IncrementAttribute(sessionID, "risk", 60)
+	waitFor(t, func() bool { return !session.IfActive() })
+	waitFor(t, func() bool {
+		trace, _ := uconE.GetSessionTrace(sessionID)
+		return len(trace) > 0 && trace[len(trace)-1].StopReason != ""
+	})
+}
+
+// waitFor polls cond for up to a second.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if cond() {
+			return
+		}
+	}
+	t.Fatal("condition not met within a second")
+}
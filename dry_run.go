@@ -0,0 +1,86 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// dryRunCondition evaluates a condition like evaluateCondition, for previews
+// such as DiffConfig, SimulateSession and ReplayDecisions that must not
+// change anything. The handler sees a detached copy of the session, so it
+// cannot write to the session or mark its one-time notices, and handlers
+// with state of their own, like time_budget, leave it alone; no events are
+// emitted, no metrics are recorded and the negative cache is neither read
+// nor filled. A shadowed condition passes, as it does when enforced, and
+// handler errors are treated according to policy.
+func (u *UconEnforcer) dryRunCondition(condition *Condition, session *Session, policy FailurePolicy) (bool, error) {
+	if !conditionApplies(condition, session) {
+		return true, nil
+	}
+	if condition.Shadow && shadowed(condition.ID, condition.Rollout, session) {
+		return true, nil
+	}
+	u.mu.RLock()
+	handler, ok := u.conditionHandlers[condition.Name]
+	u.mu.RUnlock()
+	if !ok {
+		return false, fmt.Errorf("condition %s: %w for %s", condition.ID, ErrUnknownHandler, condition.Name)
+	}
+
+	result, err := u.callConditionHandler(handler, condition, session.dryRunCopy())
+	if err != nil && policy == FailOpen {
+		return true, nil
+	}
+	if err != nil && !errors.Is(err, ErrHandlerPanic) {
+		err = fmt.Errorf("condition %s: %w", condition.ID, err)
+	}
+	return result, err
+}
+
+// IsDryRun reports whether the session is a detached copy made for a dry
+// run, such as of a shadow obligation. Changes to it are discarded, and
+// obligation handlers should skip effects outside the session, like calls
+// to other services, and only report whether they would fail.
+func (s *Session) IsDryRun() bool {
+	return s.dryRun
+}
+
+// dryRunCopy returns a detached copy of the session for dryRunCondition. It
+// is not saved, and shares only the subject attributes with the session.
+func (s *Session) dryRunCopy() *Session {
+	if s.dryRun {
+		return s
+	}
+	s.mutex.RLock()
+	copied := NewSession(s.recordLocked(), nil, nil)
+	copied.subjects = s.subjects
+	copied.priority = s.priority
+	copied.consent = s.consent
+	copied.notices = make(map[string]bool, len(s.notices))
+	for notice := range s.notices {
+		copied.notices[notice] = true
+	}
+	copied.chargedAt = make(map[string]time.Time, len(s.chargedAt))
+	for id, at := range s.chargedAt {
+		copied.chargedAt[id] = at
+	}
+	s.mutex.RUnlock()
+	copied.dryRun = true
+	copied.origin = s
+	return copied
+}
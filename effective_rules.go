@@ -0,0 +1,79 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import "sort"
+
+// EffectiveRules are the conditions and obligations that currently apply to
+// a session, as returned by GetEffectiveRules.
+type EffectiveRules struct {
+	SessionID   string                `json:"session_id"`
+	RuleSet     string                `json:"rule_set,omitempty"`
+	Conditions  []EffectiveCondition  `json:"conditions"`
+	Obligations []EffectiveObligation `json:"obligations"`
+}
+
+// EffectiveCondition is a condition that applies to a session. Enforced is
+// false if the condition only runs in shadow mode for the session's subject.
+type EffectiveCondition struct {
+	Condition
+	Enforced bool `json:"enforced"`
+}
+
+// EffectiveObligation is an obligation that applies to a session. Enforced
+// is false if the obligation only runs in shadow mode for the session's
+// subject.
+type EffectiveObligation struct {
+	Obligation
+	Enforced bool `json:"enforced"`
+}
+
+// GetEffectiveRules returns the conditions and obligations of the active
+// rules that apply to a session: those whose Purposes match it, with whether
+// the session's subject falls inside their rollout. Conditions are ordered
+// by ID and obligations in execution order.
+func (u *UconEnforcer) GetEffectiveRules(sessionID string) (*EffectiveRules, error) {
+	session, err := u.GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	// The rules are read with the rule set version, so that they match.
+	u.mu.RLock()
+	rules := &EffectiveRules{SessionID: sessionID, RuleSet: u.ruleSets.active}
+	conditions := make([]Condition, 0, len(u.conditions))
+	for _, condition := range u.conditions {
+		conditions = append(conditions, condition)
+	}
+	// AddObligation rejects cycles, so ordering cannot fail.
+	obligations, _ := orderObligations(u.obligations)
+	u.mu.RUnlock()
+	sort.Slice(conditions, func(i, j int) bool { return conditions[i].ID < conditions[j].ID })
+
+	for _, condition := range conditions {
+		if !conditionApplies(&condition, session) {
+			continue
+		}
+		enforced := !condition.Shadow || !shadowed(condition.ID, condition.Rollout, session)
+		rules.Conditions = append(rules.Conditions, EffectiveCondition{Condition: condition, Enforced: enforced})
+	}
+	for _, obligation := range obligations {
+		if !obligationApplies(&obligation, session) {
+			continue
+		}
+		enforced := !obligation.Shadow || !shadowed(obligation.ID, obligation.Rollout, session)
+		rules.Obligations = append(rules.Obligations, EffectiveObligation{Obligation: obligation, Enforced: enforced})
+	}
+	return rules, nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestGetEffectiveRules(t *testing.T) {
+	uconE := GetUconEnforcer()
+	_ = uconE.AddRuleSet(&RuleSet{
+		Version: "v1",
+		Conditions: []Condition{
+			{ID: "geofence", Name: "location", Kind: "always", Expr: "office"},
+			{ID: "marketing_only", Name: "attribute_equals", Kind: "always", Expr: "consent:yes", Purposes: []string{"marketing"}},
+			{ID: "risk", Name: "numeric_threshold", Kind: "always", Expr: "risk<70", Shadow: true, Rollout: 50},
+		},
+		Obligations: []Obligation{
+			{ID: "notice", Name: "notify", Kind: "pre", After: []string{"auth"}},
+			{ID: "auth", Name: "user_authentication", Kind: "pre", Expr: "token:ok"},
+		},
+	})
+	_ = uconE.ActivateRuleSet("v1")
+
+	// Find a subject inside the rollout of the risk condition and one outside.
+	var inside, outside string
+	for i := 0; inside == "" || outside == ""; i++ {
+		subject := fmt.Sprintf("user%d", i)
+		session := &Session{subject: subject}
+		if shadowed("risk", 50, session) {
+			outside = subject
+		} else {
+			inside = subject
+		}
+	}
+
+	sessionID, _ := uconE.CreateSession(inside, "read", "document1", nil)
+	rules, err := uconE.GetEffectiveRules(sessionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules.RuleSet != "v1" || len(rules.Conditions) != 2 || rules.Conditions[0].ID != "geofence" || rules.Conditions[1].ID != "risk" {
+		t.Fatalf("Expected the conditions without a purpose, got %+v", rules)
+	}
+	if !rules.Conditions[0].Enforced || !rules.Conditions[1].Enforced {
+		t.Errorf("Expected both conditions to be enforced inside the rollout, got %+v", rules.Conditions)
+	}
+	if len(rules.Obligations) != 2 || rules.Obligations[0].ID != "auth" || rules.Obligations[1].ID != "notice" {
+		t.Errorf("Expected the obligations in execution order, got %+v", rules.Obligations)
+	}
+
+	sessionID, _ = uconE.CreateSessionWithPurpose(outside, "read", "document1", "marketing", nil)
+	rules, _ = uconE.GetEffectiveRules(sessionID)
+	if len(rules.Conditions) != 3 || rules.Conditions[1].ID != "marketing_only" || rules.Conditions[2].Enforced {
+		t.Errorf("Expected the marketing condition to apply and the risk condition to be shadowed, got %+v", rules.Conditions)
+	}
+
+	if _, err := uconE.GetEffectiveRules("unknown"); err == nil {
+		t.Error("Expected an error for an unknown session")
+	}
+}
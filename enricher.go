@@ -0,0 +1,92 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+)
+
+// AttributeEnricher derives attributes of a session being created, e.g. the
+// country from an "ip" attribute, the department from the subject or the
+// device class from a user agent, so callers need not duplicate that logic.
+type AttributeEnricher interface {
+	// Enrich returns the derived attributes. Attributes given by the caller
+	// or set by an earlier enricher are not overwritten.
+	Enrich(request *SessionRequest) (map[string]interface{}, error)
+}
+
+// AttributeEnricherFunc adapts a function to the AttributeEnricher interface.
+type AttributeEnricherFunc func(request *SessionRequest) (map[string]interface{}, error)
+
+// Enrich implements AttributeEnricher.
+func (f AttributeEnricherFunc) Enrich(request *SessionRequest) (map[string]interface{}, error) {
+	return f(request)
+}
+
+type enricherEntry struct {
+	name     string
+	enricher AttributeEnricher
+}
+
+// RegisterAttributeEnricher registers an enricher run by CreateSession and
+// CreateSessionWithPurpose before the session validators. Enrichers run in
+// the order they were first registered; registering a name again replaces
+// the enricher in place. An enricher error is logged and the session is
+// created without its attributes.
+func (u *UconEnforcer) RegisterAttributeEnricher(name string, enricher AttributeEnricher) error {
+	if name == "" {
+		return errors.New("attribute enricher name cannot be empty")
+	}
+	if enricher == nil {
+		return errors.New("attribute enricher cannot be nil")
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i := range u.enrichers {
+		if u.enrichers[i].name == name {
+			u.enrichers[i].enricher = enricher
+			return nil
+		}
+	}
+	u.enrichers = append(u.enrichers, enricherEntry{name: name, enricher: enricher})
+	return nil
+}
+
+// enrichSession adds the derived attributes to a session request.
+func (u *UconEnforcer) enrichSession(request *SessionRequest) {
+	u.mu.RLock()
+	enrichers := make([]enricherEntry, len(u.enrichers))
+	copy(enrichers, u.enrichers)
+	u.mu.RUnlock()
+
+	for _, entry := range enrichers {
+		attributes, err := entry.enricher.Enrich(request)
+		if err != nil {
+			u.logger.Log(LevelWarn, "attribute enricher failed", map[string]interface{}{
+				"enricher": entry.name,
+				"subject":  request.Subject,
+				"object":   request.Object,
+				"error":    err.Error(),
+			})
+			continue
+		}
+		for key, value := range attributes {
+			if _, ok := request.Attributes[key]; !ok {
+				request.Attributes[key] = value
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAttributeEnrichers(t *testing.T) {
+	uconE := GetUconEnforcer()
+	departments := map[string]string{"alice": "finance"}
+	uconE.RegisterAttributeEnricher("department", AttributeEnricherFunc(func(req *SessionRequest) (map[string]interface{}, error) {
+		return map[string]interface{}{"department": departments[req.Subject], "country": "??"}, nil
+	}))
+	uconE.RegisterAttributeEnricher("geo", AttributeEnricherFunc(func(req *SessionRequest) (map[string]interface{}, error) {
+		if req.Attributes["ip"] == nil {
+			return nil, errors.New("no ip")
+		}
+		return map[string]interface{}{"country": "DE", "ip": "overwritten"}, nil
+	}))
+	if err := uconE.RegisterAttributeEnricher("", nil); err == nil {
+		t.Error("Expected an empty enricher name to be rejected")
+	}
+	var seen interface{}
+	uconE.AddSessionValidator(SessionValidatorFunc(func(req *SessionRequest) error {
+		seen = req.Attributes["department"]
+		return nil
+	}))
+
+	attributes := map[string]interface{}{"ip": "10.0.0.1", "country": "FR"}
+	sessionID, err := uconE.CreateSession("alice", "read", "document1", attributes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, _ := uconE.GetSession(sessionID)
+	if session.GetAttribute("department") != "finance" {
+		t.Errorf("Expected department finance, got %v", session.GetAttribute("department"))
+	}
+	if session.GetAttribute("country") != "FR" || session.GetAttribute("ip") != "10.0.0.1" {
+		t.Error("Expected enrichers not to overwrite the caller's attributes")
+	}
+	if seen != "finance" {
+		t.Errorf("Expected validators to see the enriched attributes, got %v", seen)
+	}
+	if len(attributes) != 2 {
+		t.Error("Expected the caller's attributes to be left untouched")
+	}
+
+	// A failing enricher does not prevent the session from being created.
+	sessionID, err = uconE.CreateSession("bob", "read", "document1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, _ = uconE.GetSession(sessionID)
+	if session.GetAttribute("country") != "??" {
+		t.Errorf("Expected the first enricher's country, got %v", session.GetAttribute("country"))
+	}
+
+	// Registering a name again replaces the enricher in place.
+	uconE.RegisterAttributeEnricher("department", AttributeEnricherFunc(func(req *SessionRequest) (map[string]interface{}, error) {
+		return map[string]interface{}{"department": "sales"}, nil
+	}))
+	sessionID, _ = uconE.CreateSession("alice", "write", "document1", map[string]interface{}{"ip": "10.0.0.2"})
+	session, _ = uconE.GetSession(sessionID)
+	if session.GetAttribute("department") != "sales" || session.GetAttribute("country") != "DE" {
+		t.Errorf("Expected department sales and country DE, got %v and %v", session.GetAttribute("department"), session.GetAttribute("country"))
+	}
+}
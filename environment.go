@@ -0,0 +1,99 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// EnvironmentProvider supplies global environment attributes, such as the
+// system load, the threat level or a maintenance mode flag. It is called on
+// every evaluation referencing the environment, so implementations should
+// cache answers.
+type EnvironmentProvider interface {
+	EnvironmentAttributes() (map[string]interface{}, error)
+}
+
+// SetEnvironmentAttributes sets global environment attributes, seen by the
+// evaluation of every session; a nil value removes one. Setting e.g.
+// threat_level to "high" reaches all sessions at their next monitoring tick.
+func (u *UconEnforcer) SetEnvironmentAttributes(attributes map[string]interface{}) {
+	u.mu.Lock()
+	environment := make(map[string]interface{}, len(u.environment)+len(attributes))
+	for k, v := range u.environment {
+		environment[k] = v
+	}
+	for k, v := range attributes {
+		if v == nil {
+			delete(environment, k)
+			continue
+		}
+		environment[k] = v
+	}
+	u.environment = environment
+	u.mu.Unlock()
+	u.InvalidateDecisions()
+}
+
+// RegisterEnvironmentProvider registers a provider of environment attributes.
+func (u *UconEnforcer) RegisterEnvironmentProvider(name string, provider EnvironmentProvider) error {
+	if name == "" {
+		return errors.New("environment provider name cannot be empty")
+	}
+	if provider == nil {
+		return errors.New("environment provider cannot be nil")
+	}
+	u.mu.Lock()
+	if u.environmentProviders == nil {
+		u.environmentProviders = make(map[string]EnvironmentProvider)
+	}
+	u.environmentProviders[name] = provider
+	u.mu.Unlock()
+	u.InvalidateDecisions()
+	return nil
+}
+
+// GetEnvironmentAttributes returns the environment attributes: those of the
+// providers, in name order, overridden by the ones set. A provider error
+// wraps ErrDependencyUnavailable.
+func (u *UconEnforcer) GetEnvironmentAttributes() (map[string]interface{}, error) {
+	u.mu.RLock()
+	names := make([]string, 0, len(u.environmentProviders))
+	providers := make(map[string]EnvironmentProvider, len(u.environmentProviders))
+	for name, provider := range u.environmentProviders {
+		names = append(names, name)
+		providers[name] = provider
+	}
+	set := u.environment
+	u.mu.RUnlock()
+	sort.Strings(names)
+
+	attributes := make(map[string]interface{})
+	for _, name := range names {
+		provided, err := providers[name].EnvironmentAttributes()
+		if err != nil {
+			return nil, fmt.Errorf("environment provider %s: %w: %w", name, ErrDependencyUnavailable, err)
+		}
+		for k, v := range provided {
+			attributes[k] = v
+		}
+	}
+	for k, v := range set {
+		attributes[k] = v
+	}
+	return attributes, nil
+}
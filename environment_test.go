@@ -0,0 +1,91 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeEnvironmentProvider struct {
+	err error
+}
+
+func (p *fakeEnvironmentProvider) EnvironmentAttributes() (map[string]interface{}, error) {
+	return map[string]interface{}{"system_load": 0.4, "maintenance": false}, p.err
+}
+
+func TestEnvironmentRevokesAllSessions(t *testing.T) {
+	uconE := GetUconEnforcer()
+	_ = uconE.RegisterEnvironmentProvider("monitoring", &fakeEnvironmentProvider{})
+	uconE.SetEnvironmentAttributes(map[string]interface{}{"threat_level": "low"})
+	_ = uconE.AddCondition(&Condition{ID: "no_threat", Name: "expression", Kind: "always", Expr: "env.threat_level != 'high' && !env.maintenance"})
+
+	var sessions []*Session
+	for _, subject := range []string{"alice", "bob"} {
+		sessionID, _ := uconE.CreateSession(subject, "read", "document1", nil)
+		session, err := uconE.EnforceWithSession(sessionID)
+		if err != nil || session == nil {
+			t.Fatalf("Expected access for %s, got %v", subject, err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	uconE.SetEnvironmentAttributes(map[string]interface{}{"threat_level": "high"})
+	time.Sleep(500 * time.Millisecond)
+	for _, session := range sessions {
+		if session.IfActive() {
+			t.Errorf("Expected session of %s to be revoked at high threat level", session.GetSubject())
+		}
+	}
+}
+
+func TestEnvironmentStepsDownSessions(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithAnalyzer(AnalyzerFunc(func(features UsageFeatures) (Verdict, error) {
+		if features.Environment["threat_level"] == "elevated" {
+			return VerdictStepDown, nil
+		}
+		return VerdictContinue, nil
+	})))
+
+	sessionID, _ := uconE.CreateSession("alice", "write", "document1", nil)
+	session, err := uconE.EnforceWithSession(sessionID)
+	if err != nil || session == nil {
+		t.Fatalf("Expected access, got %v", err)
+	}
+	defer func() { _ = uconE.StopMonitoring(sessionID) }()
+
+	uconE.SetEnvironmentAttributes(map[string]interface{}{"threat_level": "elevated"})
+	time.Sleep(500 * time.Millisecond)
+	if !session.IfActive() || session.GetAttribute(AttrSteppedDown) != true {
+		t.Errorf("Expected the session to be stepped down, active=%v", session.IfActive())
+	}
+
+	uconE.SetEnvironmentAttributes(map[string]interface{}{"threat_level": nil})
+	environment, _ := uconE.GetEnvironmentAttributes()
+	if _, ok := environment["threat_level"]; ok {
+		t.Error("Expected a nil value to remove the environment attribute")
+	}
+}
+
+func TestEnvironmentProviderError(t *testing.T) {
+	uconE := GetUconEnforcer()
+	_ = uconE.RegisterEnvironmentProvider("monitoring", &fakeEnvironmentProvider{err: errors.New("timeout")})
+	if _, err := uconE.GetEnvironmentAttributes(); !errors.Is(err, ErrDependencyUnavailable) {
+		t.Errorf("Expected ErrDependencyUnavailable, got %v", err)
+	}
+}
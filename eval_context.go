@@ -0,0 +1,158 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrAttributeNotFound is wrapped by EvalContext.ResolveAttribute errors for
+// attributes neither the session nor an attribute provider has.
+var ErrAttributeNotFound = errors.New("attribute not found")
+
+// EvalContext is what a context handler gets to evaluate a condition or
+// execute an obligation, so it can resolve attributes and log without global
+// state.
+type EvalContext struct {
+	// Expr is the expression of the condition or obligation.
+	Expr string
+	// Session is a read-only view of the session. Obligations change the
+	// session through Enforcer, e.g. with UpdateSessionAttributes.
+	Session SessionView
+	// Time is when the evaluation started.
+	Time time.Time
+	// Enforcer is the enforcer evaluating the session.
+	Enforcer IUconEnforcer
+	// Logger logs through the enforcer's Logger, adding the session fields
+	// and the handler name to every entry.
+	Logger Logger
+	// IdempotencyKey is the idempotency key of an obligation execution, see
+	// IdempotentObligationHandler; it is empty for conditions.
+	IdempotencyKey string
+
+	u        *UconEnforcer
+	session  *Session
+	resolved map[string]interface{}
+	fetched  map[string]bool // providers already asked
+}
+
+// ContextConditionHandler evaluates a condition with an EvalContext.
+type ContextConditionHandler func(ctx *EvalContext) (bool, error)
+
+// ContextObligationHandler executes an obligation with an EvalContext.
+type ContextObligationHandler func(ctx *EvalContext) error
+
+// RegisterContextConditionHandler registers a context handler for conditions with the given name.
+func (u *UconEnforcer) RegisterContextConditionHandler(name string, handler ContextConditionHandler) error {
+	if name == "" {
+		return errors.New("condition handler name cannot be empty")
+	}
+	if handler == nil {
+		return errors.New("condition handler cannot be nil")
+	}
+	u.mu.Lock()
+	if u.customConditions == nil {
+		u.customConditions = make(map[string]bool)
+	}
+	u.customConditions[name] = true
+	u.conditionHandlers[name] = func(expr string, session *Session) (bool, error) {
+		return handler(u.newEvalContext(name, expr, session, ""))
+	}
+	u.mu.Unlock()
+	return nil
+}
+
+// RegisterContextObligationHandler registers a context handler for obligations with the given name.
+func (u *UconEnforcer) RegisterContextObligationHandler(name string, handler ContextObligationHandler) error {
+	if handler == nil {
+		return errors.New("obligation handler cannot be nil")
+	}
+	return u.RegisterIdempotentObligationHandler(name, func(expr string, session *Session, key string) error {
+		return handler(u.newEvalContext(name, expr, session, key))
+	})
+}
+
+func (u *UconEnforcer) newEvalContext(name string, expr string, session *Session, key string) *EvalContext {
+	fields := sessionFields(session)
+	fields["handler"] = name
+	return &EvalContext{
+		Expr:           expr,
+		Session:        session.View(),
+		Time:           time.Now(),
+		Enforcer:       u,
+		Logger:         &contextLogger{logger: u.logger, fields: fields},
+		IdempotencyKey: key,
+		u:              u,
+		session:        session,
+	}
+}
+
+// ResolveAttribute returns a session attribute. An attribute the session
+// does not have is fetched from the healthy attribute providers, without
+// storing it in the session; the answer is kept for the rest of the
+// evaluation. A provider error wraps ErrDependencyUnavailable, so
+// WithNegativeCache applies to it, and an attribute nobody has is a
+// MissingAttributeError.
+func (c *EvalContext) ResolveAttribute(key string) (interface{}, error) {
+	if value := c.Session.GetAttribute(key); value != nil {
+		return value, nil
+	}
+	if value, ok := c.resolved[key]; ok {
+		return value, nil
+	}
+	for name, provider := range c.u.providerList() {
+		if c.fetched[name] || !c.u.providerHealthy(name) {
+			continue
+		}
+		attributes, err := provider.FetchAttributes(c.session)
+		if err != nil {
+			return nil, fmt.Errorf("attribute provider %s: %w: %w", name, ErrDependencyUnavailable, err)
+		}
+		if c.resolved == nil {
+			c.resolved = make(map[string]interface{})
+			c.fetched = make(map[string]bool)
+		}
+		c.fetched[name] = true
+		for k, v := range attributes {
+			if _, ok := c.resolved[k]; !ok {
+				c.resolved[k] = v
+			}
+		}
+		if value, ok := c.resolved[key]; ok {
+			return value, nil
+		}
+	}
+	return nil, &MissingAttributeError{Key: key}
+}
+
+// contextLogger adds fixed fields to the entries of a Logger.
+type contextLogger struct {
+	logger Logger
+	fields map[string]interface{}
+}
+
+// Log implements Logger.
+func (l *contextLogger) Log(level LogLevel, msg string, fields map[string]interface{}) {
+	merged := make(map[string]interface{}, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	l.logger.Log(level, msg, merged)
+}
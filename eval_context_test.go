@@ -0,0 +1,74 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEvalContext(t *testing.T) {
+	var logs bytes.Buffer
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithLogger(&DefaultLogger{Level: LevelInfo, Writer: &logs}))
+	provider := &fakeProvider{}
+	_ = uconE.RegisterAttributeProvider("idp", provider)
+
+	_ = uconE.RegisterContextConditionHandler("at", func(ctx *EvalContext) (bool, error) {
+		location, err := ctx.ResolveAttribute("location")
+		if err != nil {
+			return false, err
+		}
+		if _, err := ctx.ResolveAttribute("clearance"); !errors.Is(err, ErrAttributeNotFound) {
+			t.Errorf("Expected ErrAttributeNotFound, got %v", err)
+		}
+		ctx.Logger.Log(LevelInfo, "checked location", map[string]interface{}{"location": location})
+		return location == ctx.Expr, nil
+	})
+	_ = uconE.RegisterContextObligationHandler("stamp", func(ctx *EvalContext) error {
+		if ctx.IdempotencyKey == "" || ctx.Time.IsZero() {
+			t.Error("Expected an idempotency key and an evaluation time")
+		}
+		return ctx.Enforcer.UpdateSessionAttribute(ctx.Session.GetId(), "stamped", ctx.Expr)
+	})
+	_ = uconE.AddCondition(&Condition{ID: "at_office", Name: "at", Kind: "pre", Expr: "office"})
+	_ = uconE.AddObligation(&Obligation{ID: "stamp", Name: "stamp", Kind: "post", Expr: "yes"})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	if ok, err := uconE.EvaluateConditions(sessionID); err != nil || !ok {
+		t.Fatalf("Expected the condition to pass, got %v, %v", ok, err)
+	}
+	session, _ := uconE.GetSession(sessionID)
+	if session.GetAttribute("location") != nil {
+		t.Error("ResolveAttribute stored the provider attribute in the session")
+	}
+	if entry := logs.String(); !strings.Contains(entry, "checked location") || !strings.Contains(entry, "session_id="+sessionID) || !strings.Contains(entry, "handler=at") {
+		t.Errorf("Expected the log entry to carry the session fields, got %q", entry)
+	}
+
+	if err := uconE.ExecuteObligationsByType(sessionID, "post"); err != nil {
+		t.Fatal(err)
+	}
+	if session.GetAttribute("stamped") != "yes" {
+		t.Error("Expected the obligation to update the session through the enforcer")
+	}
+
+	provider.down.Store(true)
+	if _, err := uconE.EvaluateConditions(sessionID); !errors.Is(err, ErrDependencyUnavailable) {
+		t.Errorf("Expected ErrDependencyUnavailable, got %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"fmt"
+	"time"
+)
+
+// EventType identifies the kind of an Event.
+type EventType string
+
+const (
+	// EventHandlerPanic is emitted when a condition or obligation handler panics.
+	EventHandlerPanic EventType = "handler_panic"
+	// EventSessionStopped is emitted when a session stops, whether it ended
+	// normally or was revoked. Data carries the stop "reason".
+	EventSessionStopped EventType = "session_stopped"
+	// EventAccessDecision is emitted for every EnforceWithSession call. Data
+	// carries the "subject", "action", "object", whether access was
+	// "allowed", "degraded" if it was granted while the session store was
+	// unreachable and, if enforcement failed, the "error".
+	EventAccessDecision EventType = "access_decision"
+)
+
+// Event describes something that happened inside the enforcer.
+type Event struct {
+	Type      EventType
+	SessionID string
+	Time      time.Time
+	Message   string
+	Data      map[string]interface{}
+}
+
+// EventListener receives events emitted by the enforcer. Listeners are called
+// synchronously and should return quickly.
+type EventListener func(event Event)
+
+// AddEventListener registers a listener for enforcer events.
+func (u *UconEnforcer) AddEventListener(listener EventListener) {
+	if listener == nil {
+		return
+	}
+	u.mu.Lock()
+	u.listeners = append(u.listeners, listener)
+	u.mu.Unlock()
+}
+
+// emit delivers an event to all registered listeners.
+func (u *UconEnforcer) emit(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	u.mu.RLock()
+	listeners := make([]EventListener, len(u.listeners))
+	copy(listeners, u.listeners)
+	u.mu.RUnlock()
+
+	for i, listener := range listeners {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					u.logger.Log(LevelError, "event listener panicked", map[string]interface{}{
+						"listener": i,
+						"event":    string(event.Type),
+						"panic":    fmt.Sprint(r),
+					})
+				}
+			}()
+			listener(event)
+		}()
+	}
+}
+
+// emitDecision reports the outcome of EnforceWithSession.
+func (u *UconEnforcer) emitDecision(sessionID string, granted *Session, err error) {
+	data := map[string]interface{}{"allowed": granted != nil}
+	message := fmt.Sprintf("access denied for session %s", sessionID)
+	if granted != nil {
+		message = fmt.Sprintf("access granted for session %s", sessionID)
+		if granted.IsStoreDegraded() {
+			data["degraded"] = true
+		}
+	}
+	if err != nil {
+		data["error"] = err.Error()
+	}
+	if session, getErr := u.GetSession(sessionID); getErr == nil {
+		data["subject"] = session.GetSubject()
+		data["action"] = session.GetAction()
+		data["object"] = session.GetObject()
+		data = addLabelFields(data, session)
+	}
+	u.emit(Event{Type: EventAccessDecision, SessionID: sessionID, Message: message, Data: data})
+	u.recordDecision(sessionID, granted, err)
+}
+
+// sessionStopped is the stop hook of the enforcer's sessions.
+func (u *UconEnforcer) sessionStopped(session *Session) {
+	u.releaseMonitoring(session.GetId())
+	u.mu.Lock()
+	u.leaveWaitlistLocked(session.GetId())
+	u.mu.Unlock()
+	u.gauges.remove(session.GetId())
+	u.cancelApproval(session.GetId())
+	u.endLazy(session.GetId())
+	u.recordUsage(session)
+	if u.decisions != nil {
+		u.decisions.remove(session.GetId())
+	}
+	u.emit(Event{
+		Type:      EventSessionStopped,
+		SessionID: session.GetId(),
+		Message:   fmt.Sprintf("session %s stopped", session.GetId()),
+		Data: addLabelFields(map[string]interface{}{
+			"subject": session.GetSubject(),
+			"object":  session.GetObject(),
+			"reason":  session.GetStopReason(),
+		}, session),
+	})
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// EventSchemaVersion is the version of the JSON encoding of events. It
+// changes only when a field is removed or changes meaning; new fields can be
+// added within a version.
+const EventSchemaVersion = 1
+
+// eventJSON is the JSON encoding of an Event:
+//
+//	{"schema_version":1,"type":"session_stopped","session_id":"session_1",
+//	 "time":"2025-01-02T15:04:05.123456789Z","message":"...","data":{"reason":"..."}}
+type eventJSON struct {
+	SchemaVersion int                    `json:"schema_version"`
+	Type          EventType              `json:"type"`
+	SessionID     string                 `json:"session_id,omitempty"`
+	Time          time.Time              `json:"time"`
+	Message       string                 `json:"message,omitempty"`
+	Data          map[string]interface{} `json:"data,omitempty"`
+}
+
+// MarshalJSON encodes the event with the versioned schema described in the
+// README, with the time in UTC.
+func (e Event) MarshalJSON() ([]byte, error) {
+	return json.Marshal(eventJSON{
+		SchemaVersion: EventSchemaVersion,
+		Type:          e.Type,
+		SessionID:     e.SessionID,
+		Time:          e.Time.UTC(),
+		Message:       e.Message,
+		Data:          e.Data,
+	})
+}
+
+// UnmarshalJSON decodes an event encoded by MarshalJSON. Events of a newer
+// schema version are rejected.
+func (e *Event) UnmarshalJSON(data []byte) error {
+	var decoded eventJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	if decoded.SchemaVersion > EventSchemaVersion {
+		return fmt.Errorf("unsupported event schema version %d", decoded.SchemaVersion)
+	}
+	*e = Event{
+		Type:      decoded.Type,
+		SessionID: decoded.SessionID,
+		Time:      decoded.Time,
+		Message:   decoded.Message,
+		Data:      decoded.Data,
+	}
+	return nil
+}
+
+// JSONEventEncoder writes events as JSON lines, e.g. for a log shipper or an
+// audit pipeline.
+type JSONEventEncoder struct {
+	mutex   sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewJSONEventEncoder creates a JSONEventEncoder writing to w.
+func NewJSONEventEncoder(w io.Writer) *JSONEventEncoder {
+	return &JSONEventEncoder{encoder: json.NewEncoder(w)}
+}
+
+// Encode writes one event as a line of JSON. Data values that cannot be
+// encoded as JSON are written as their fmt.Sprint representation.
+func (e *JSONEventEncoder) Encode(event Event) error {
+	if _, err := json.Marshal(event.Data); err != nil {
+		data := make(map[string]interface{}, len(event.Data))
+		for k, v := range event.Data {
+			if _, err := json.Marshal(v); err != nil {
+				v = fmt.Sprint(v)
+			}
+			data[k] = v
+		}
+		event.Data = data
+	}
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e.encoder.Encode(event)
+}
+
+// Listener returns an EventListener encoding every event, to be passed to
+// AddEventListener. Write errors are dropped.
+func (e *JSONEventEncoder) Listener() EventListener {
+	return func(event Event) {
+		_ = e.Encode(event)
+	}
+}
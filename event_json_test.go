@@ -0,0 +1,105 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestEventJSONSchema(t *testing.T) {
+	at := time.Date(2025, 1, 2, 15, 4, 5, 0, time.FixedZone("CET", 3600))
+	event := Event{Type: EventSessionStopped, SessionID: "session_1", Time: at, Message: "stopped", Data: map[string]interface{}{"reason": "done"}}
+	encoded, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"schema_version":1,"type":"session_stopped","session_id":"session_1","time":"2025-01-02T14:04:05Z","message":"stopped","data":{"reason":"done"}}`
+	if string(encoded) != want {
+		t.Errorf("Expected %s, got %s", want, encoded)
+	}
+
+	var decoded Event
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Type != event.Type || !decoded.Time.Equal(at) || decoded.Data["reason"] != "done" {
+		t.Errorf("Expected round trip of %+v, got %+v", event, decoded)
+	}
+	if err := json.Unmarshal([]byte(`{"schema_version":2,"type":"x"}`), &decoded); err == nil {
+		t.Error("Expected a newer schema version to be rejected")
+	}
+}
+
+func TestJSONEventEncoder(t *testing.T) {
+	var out syncBuffer
+	encoder := NewJSONEventEncoder(&out)
+	uconE := GetUconEnforcer()
+	uconE.AddEventListener(encoder.Listener())
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	if _, err := uconE.EnforceWithSession(sessionID); err != nil {
+		t.Fatal(err)
+	}
+	_ = uconE.StopMonitoring(sessionID)
+	deniedID, _ := uconE.CreateSession("bob", "write", "document1", nil)
+	_, _ = uconE.EnforceWithSession(deniedID)
+	_ = encoder.Encode(Event{Type: "custom", Data: map[string]interface{}{"fn": func() {}}})
+
+	var types []EventType
+	var decisions []bool
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Expected a JSON line, got %q: %v", line, err)
+		}
+		if record["schema_version"] != float64(EventSchemaVersion) {
+			t.Errorf("Expected schema version in %s", line)
+		}
+		types = append(types, EventType(record["type"].(string)))
+		if record["type"] == string(EventAccessDecision) {
+			data := record["data"].(map[string]interface{})
+			decisions = append(decisions, data["allowed"].(bool))
+		}
+	}
+	if len(types) != 4 || types[1] != EventSessionStopped || types[3] != "custom" {
+		t.Errorf("Expected decision, stop, decision and custom events, got %v", types)
+	}
+	if len(decisions) != 2 || !decisions[0] || decisions[1] {
+		t.Errorf("Expected a granted then a denied decision, got %v", decisions)
+	}
+}
@@ -0,0 +1,373 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/casbin/govaluate"
+)
+
+// ExpressionFunction is a function callable from condition and obligation
+// expressions. Numeric arguments arrive as float64.
+type ExpressionFunction func(args ...interface{}) (interface{}, error)
+
+// builtinFunctions is the standard library available to every expression:
+//
+//	timeOfDay()           current local time as "HH:MM", comparable as a string
+//	timeOfDay(tz)         current time in the IANA time zone tz
+//	since(t)              seconds elapsed since t
+//	daysUntil(t)          days (fractional) until t, negative once t has passed
+//	ipInCIDR(ip, cidr)    whether ip lies in the CIDR block
+//	matches(s, pattern)   whether s matches the regular expression
+//	remaining(sub, name)  what is left of an entitlement of sub (see Meter)
+//
+// Time arguments may be a time.Time, an RFC 3339 or "2006-01-02" string, or
+// Unix seconds.
+func builtinFunctions(regexes *regexCache) map[string]ExpressionFunction {
+	return map[string]ExpressionFunction{
+		"timeOfDay": exprTimeOfDay,
+		"since":     exprSince,
+		"daysUntil": exprDaysUntil,
+		"ipInCIDR":  exprIPInCIDR,
+		"matches":   regexes.exprMatches,
+	}
+}
+
+// Expression variables holding attributes from outside the session.
+const (
+	// ObjectVariable holds the attributes of the session object, e.g.
+	// obj.classification == 'public'.
+	ObjectVariable = "obj"
+	// EnvironmentVariable holds the environment attributes, e.g.
+	// env.threat_level != 'high'.
+	EnvironmentVariable = "env"
+)
+
+// expressionEngine compiles and caches expressions against the registered functions.
+type expressionEngine struct {
+	functions map[string]govaluate.ExpressionFunction
+	compiled  map[string]*govaluate.EvaluableExpression
+	mutex     sync.RWMutex
+	// regexes caches the patterns of matches and regex_match.
+	regexes *regexCache
+
+	// objects looks up object attributes for the obj variable, if set.
+	objects func(object string) (map[string]interface{}, error)
+	// environment looks up environment attributes for the env variable, if set.
+	environment func() (map[string]interface{}, error)
+}
+
+func newExpressionEngine() *expressionEngine {
+	ee := &expressionEngine{
+		functions: make(map[string]govaluate.ExpressionFunction),
+		compiled:  make(map[string]*govaluate.EvaluableExpression),
+		regexes:   newRegexCache(DefaultRegexCacheSize),
+	}
+	for name, fn := range builtinFunctions(ee.regexes) {
+		ee.functions[name] = govaluate.ExpressionFunction(fn)
+	}
+	return ee
+}
+
+func (ee *expressionEngine) register(name string, fn ExpressionFunction) {
+	ee.mutex.Lock()
+	defer ee.mutex.Unlock()
+	ee.functions[name] = govaluate.ExpressionFunction(fn)
+	// Compiled expressions are bound to the function set they were built with.
+	ee.compiled = make(map[string]*govaluate.EvaluableExpression)
+}
+
+func (ee *expressionEngine) compile(expr string) (*govaluate.EvaluableExpression, error) {
+	ee.mutex.RLock()
+	compiled, ok := ee.compiled[expr]
+	ee.mutex.RUnlock()
+	if ok {
+		return compiled, nil
+	}
+
+	ee.mutex.Lock()
+	defer ee.mutex.Unlock()
+	if compiled, ok = ee.compiled[expr]; ok {
+		return compiled, nil
+	}
+	compiled, err := govaluate.NewEvaluableExpressionWithFunctions(expr, ee.functions)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", expr, err)
+	}
+	ee.compiled[expr] = compiled
+	return compiled, nil
+}
+
+// evaluate evaluates expr with the session's attributes as variables. The
+// session's subject, action, object and start_time are available too, and
+// the object's attributes as obj and the environment attributes as env,
+// unless shadowed by an attribute of the same name. Object and environment
+// attributes are only looked up when expr uses them.
+func (ee *expressionEngine) evaluate(expr string, session *Session) (interface{}, error) {
+	record := session.ToRecord()
+	params := expressionParams{values: map[string]interface{}{
+		"subject":    record.Subject,
+		"action":     record.Action,
+		"object":     record.Object,
+		"purpose":    record.Purpose,
+		"start_time": record.StartTime,
+	}}
+	params.lazy = make(map[string]func() (interface{}, error))
+	if ee.objects != nil {
+		params.lazy[ObjectVariable] = func() (interface{}, error) { return ee.objects(record.Object) }
+	}
+	if ee.environment != nil {
+		params.lazy[EnvironmentVariable] = func() (interface{}, error) { return ee.environment() }
+	}
+	for k, v := range session.GetAttributes() {
+		params.values[k] = v
+	}
+	return ee.eval(expr, params)
+}
+
+// evaluateParams evaluates expr with the given variables.
+func (ee *expressionEngine) evaluateParams(expr string, params map[string]interface{}) (interface{}, error) {
+	return ee.eval(expr, expressionParams{values: params})
+}
+
+func (ee *expressionEngine) eval(expr string, params expressionParams) (interface{}, error) {
+	compiled, err := ee.compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	result, err := compiled.Eval(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate expression %q: %w", expr, err)
+	}
+	return result, nil
+}
+
+// expressionParams are the variables of an expression. Lazy variables are
+// computed when the expression reads them.
+type expressionParams struct {
+	values map[string]interface{}
+	lazy   map[string]func() (interface{}, error)
+}
+
+// Get implements govaluate.Parameters.
+func (p expressionParams) Get(name string) (interface{}, error) {
+	if value, ok := p.values[name]; ok {
+		return value, nil
+	}
+	if compute, ok := p.lazy[name]; ok {
+		return compute()
+	}
+	return nil, fmt.Errorf("no parameter '%s' found", name)
+}
+
+func (ee *expressionEngine) evaluateBool(expr string, session *Session) (bool, error) {
+	result, err := ee.evaluate(expr, session)
+	if err != nil {
+		return false, err
+	}
+	ok, isBool := result.(bool)
+	if !isBool {
+		return false, fmt.Errorf("expression %q returned %v, expected a boolean", expr, result)
+	}
+	return ok, nil
+}
+
+// RegisterFunction makes fn callable as name(...) from condition and obligation
+// expressions. Registering a built-in name replaces the built-in.
+func (u *UconEnforcer) RegisterFunction(name string, fn ExpressionFunction) error {
+	if name == "" {
+		return errors.New("function name cannot be empty")
+	}
+	if fn == nil {
+		return errors.New("function cannot be nil")
+	}
+	u.expressions.register(name, fn)
+	return nil
+}
+
+// checkExpression is the "expression" condition: Expr must evaluate to true.
+func (u *UconEnforcer) checkExpression(expr string, session *Session) (bool, error) {
+	return u.expressions.evaluateBool(expr, session)
+}
+
+// executeExpression is the "expression" obligation: Expr must evaluate to true
+// for the obligation to be fulfilled.
+func (u *UconEnforcer) executeExpression(expr string, session *Session) error {
+	ok, err := u.expressions.evaluateBool(expr, session)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("expression %q is not satisfied for user %s", expr, session.GetSubject())
+	}
+	return nil
+}
+
+func exprTimeOfDay(args ...interface{}) (interface{}, error) {
+	now := time.Now()
+	if len(args) > 1 {
+		return nil, errors.New("timeOfDay expects at most 1 argument")
+	}
+	if len(args) == 1 {
+		name, ok := args[0].(string)
+		if !ok {
+			return nil, errors.New("timeOfDay expects a time zone name")
+		}
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, fmt.Errorf("timeOfDay: %w", err)
+		}
+		now = now.In(loc)
+	}
+	return now.Format("15:04"), nil
+}
+
+func exprSince(args ...interface{}) (interface{}, error) {
+	if len(args) != 1 {
+		return nil, errors.New("since expects 1 argument")
+	}
+	t, err := toTime(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("since: %w", err)
+	}
+	return time.Since(t).Seconds(), nil
+}
+
+func exprDaysUntil(args ...interface{}) (interface{}, error) {
+	if len(args) != 1 {
+		return nil, errors.New("daysUntil expects 1 argument")
+	}
+	t, err := toTime(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("daysUntil: %w", err)
+	}
+	return time.Until(t).Hours() / 24, nil
+}
+
+func exprIPInCIDR(args ...interface{}) (interface{}, error) {
+	if len(args) != 2 {
+		return nil, errors.New("ipInCIDR expects 2 arguments")
+	}
+	ipStr, ok1 := args[0].(string)
+	cidr, ok2 := args[1].(string)
+	if !ok1 || !ok2 {
+		return nil, errors.New("ipInCIDR expects string arguments")
+	}
+	ip := net.ParseIP(ipStr)
+	if ip == nil {
+		return nil, fmt.Errorf("ipInCIDR: invalid IP address %q", ipStr)
+	}
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, fmt.Errorf("ipInCIDR: %w", err)
+	}
+	return network.Contains(ip), nil
+}
+
+// DefaultRegexCacheSize is the number of compiled regular expressions an
+// enforcer keeps for matches and regex_match.
+const DefaultRegexCacheSize = 256
+
+// regexCache holds compiled regular expressions, dropping the least recently
+// used one when full, so patterns taken from attributes cannot grow it
+// without bound.
+type regexCache struct {
+	size    int
+	entries map[string]*list.Element
+	order   *list.List // of *regexEntry, most recently used first
+	mu      sync.Mutex
+}
+
+type regexEntry struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
+func newRegexCache(size int) *regexCache {
+	return &regexCache{size: size, entries: make(map[string]*list.Element), order: list.New()}
+}
+
+func (c *regexCache) exprMatches(args ...interface{}) (interface{}, error) {
+	if len(args) != 2 {
+		return nil, errors.New("matches expects 2 arguments")
+	}
+	s, ok1 := args[0].(string)
+	pattern, ok2 := args[1].(string)
+	if !ok1 || !ok2 {
+		return nil, errors.New("matches expects string arguments")
+	}
+	re, err := c.compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("matches: %w", err)
+	}
+	return re.MatchString(s), nil
+}
+
+func (c *regexCache) compile(pattern string) (*regexp.Regexp, error) {
+	c.mu.Lock()
+	if e, ok := c.entries[pattern]; ok {
+		c.order.MoveToFront(e)
+		c.mu.Unlock()
+		return e.Value.(*regexEntry).re, nil
+	}
+	c.mu.Unlock()
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[pattern]; !ok {
+		c.entries[pattern] = c.order.PushFront(&regexEntry{pattern: pattern, re: re})
+		if c.order.Len() > c.size {
+			oldest := c.order.Back()
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*regexEntry).pattern)
+		}
+	}
+	return re, nil
+}
+
+// toTime converts a time.Time, an RFC 3339 or date-only string, or Unix
+// seconds to a time.Time.
+func toTime(val interface{}) (time.Time, error) {
+	switch v := val.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t, nil
+		}
+		if t, err := time.ParseInLocation("2006-01-02", v, time.Local); err == nil {
+			return t, nil
+		}
+		return time.Time{}, fmt.Errorf("cannot parse time %q", v)
+	case float64:
+		sec := int64(v)
+		return time.Unix(sec, int64((v-float64(sec))*1e9)), nil
+	}
+	if n, ok := toInt64(val); ok {
+		return time.Unix(n, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("cannot convert %v to a time", val)
+}
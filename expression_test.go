@@ -0,0 +1,127 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestExpressionFunctions(t *testing.T) {
+	uconE := GetUconEnforcer()
+	u := uconE.(*UconEnforcer)
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{
+		"ip":        "10.1.2.3",
+		"email":     "alice@example.com",
+		"vip_level": 3,
+		"expires":   time.Now().Add(72 * time.Hour).Format(time.RFC3339),
+		"logged_in": time.Now().Add(-10 * time.Minute),
+	})
+	session, _ := uconE.GetSession(sessionID)
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`ipInCIDR(ip, "10.0.0.0/8")`, true},
+		{`ipInCIDR(ip, "192.168.0.0/16")`, false},
+		{`matches(email, "@example\\.com$")`, true},
+		{`vip_level >= 2 && subject == "alice"`, true},
+		{`daysUntil(expires) > 2 && daysUntil(expires) < 3`, true},
+		{`since(logged_in) > 590 && since(logged_in) < 660`, true},
+		{`since(start_time) < 60`, true},
+		{`timeOfDay() >= "00:00" && timeOfDay("UTC") <= "23:59"`, true},
+	}
+	for _, tt := range tests {
+		got, err := u.checkExpression(tt.expr, session)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.expr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+
+	for _, expr := range []string{`vip_level +`, `missing_attribute == 1`, `vip_level + 1`, `ipInCIDR(ip, "bogus")`} {
+		if _, err := u.checkExpression(expr, session); err == nil {
+			t.Errorf("%s: expected an error", expr)
+		}
+	}
+}
+
+func TestRegisterFunction(t *testing.T) {
+	uconE := GetUconEnforcer()
+
+	_ = uconE.AddCondition(&Condition{ID: "risk", Name: "expression", Kind: "always", Expr: `riskScore(subject) < 50`})
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{})
+
+	if _, err := uconE.EvaluateConditions(sessionID); err == nil {
+		t.Error("Expected unknown function to fail")
+	}
+
+	score := 20.0
+	err := uconE.RegisterFunction("riskScore", func(args ...interface{}) (interface{}, error) {
+		return score, nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to register function: %v", err)
+	}
+	if ok, err := uconE.EvaluateConditions(sessionID); !ok || err != nil {
+		t.Errorf("Expected low risk to pass, got %v, %v", ok, err)
+	}
+	score = 80
+	if ok, _ := uconE.EvaluateConditions(sessionID); ok {
+		t.Error("Expected high risk to fail")
+	}
+
+	_ = uconE.AddObligation(&Obligation{ID: "risk_check", Name: "expression", Kind: "pre", Expr: `riskScore(subject) < 50`})
+	if err := uconE.ExecuteObligationsByType(sessionID, "pre"); err == nil {
+		t.Error("Expected expression obligation to fail")
+	}
+}
+
+func TestRegexCacheBounded(t *testing.T) {
+	cache := newRegexCache(2)
+	for _, pattern := range []string{"a+", "b+", "a+", "c+"} {
+		if _, err := cache.compile(pattern); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := cache.compile("("); err == nil {
+		t.Error("Expected an invalid pattern to fail")
+	}
+	_, hasA := cache.entries["a+"]
+	_, hasB := cache.entries["b+"]
+	if len(cache.entries) != 2 || !hasA || hasB {
+		t.Errorf("Expected the least recently used pattern to be dropped, got %v", cache.entries)
+	}
+
+	// Patterns taken from attributes share the enforcer's bounded cache.
+	uconE := GetUconEnforcer().(*UconEnforcer)
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"email": "alice@example.com"})
+	session, _ := uconE.GetSession(sessionID)
+	for i := 0; i < DefaultRegexCacheSize+10; i++ {
+		_ = session.UpdateAttribute("pattern", fmt.Sprintf("^alice%d", i))
+		if ok, err := uconE.checkExpression(`matches(email, pattern)`, session); ok || err != nil {
+			t.Fatalf("Expected no match, got %v, %v", ok, err)
+		}
+	}
+	if n := len(uconE.expressions.regexes.entries); n != DefaultRegexCacheSize {
+		t.Errorf("Expected the cache to hold %d patterns, got %d", DefaultRegexCacheSize, n)
+	}
+}
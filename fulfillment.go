@@ -0,0 +1,132 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// EventObligationFulfilled is emitted when the caller acknowledges an obligation.
+const EventObligationFulfilled EventType = "obligation_fulfilled"
+
+// ErrObligationNotPending is returned by FulfillObligation when the session
+// has no outstanding request for the obligation.
+var ErrObligationNotPending = errors.New("obligation is not pending")
+
+// ObligationFulfillment tracks an obligation that the caller has to carry out
+// (display a notice, collect a signature) and acknowledge with
+// FulfillObligation before its deadline.
+type ObligationFulfillment struct {
+	ObligationID string                 `json:"obligation_id"`
+	IssuedAt     time.Time              `json:"issued_at"`
+	Deadline     time.Time              `json:"deadline"`
+	FulfilledAt  *time.Time             `json:"fulfilled_at,omitempty"`
+	Evidence     map[string]interface{} `json:"evidence,omitempty"`
+}
+
+// Fulfilled reports whether the obligation has been acknowledged.
+func (f ObligationFulfillment) Fulfilled() bool {
+	return f.FulfilledAt != nil
+}
+
+// executeCallerAction is the "caller_action" obligation. The caller performs
+// the action described by Expr; the enforcer only tracks the acknowledgment,
+// so it is used together with Obligation.Deadline.
+func (u *UconEnforcer) executeCallerAction(expr string, session *Session) error {
+	return nil
+}
+
+// FulfillObligation records that the caller carried out an obligation of a
+// session, with optional evidence such as a signature id or a timestamp of
+// the displayed notice.
+func (u *UconEnforcer) FulfillObligation(sessionID string, obligationID string, evidence map[string]interface{}) error {
+	session, err := u.GetSession(sessionID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	session.mutex.Lock()
+	f, ok := session.fulfillments[obligationID]
+	if !ok || f.Fulfilled() {
+		session.mutex.Unlock()
+		return fmt.Errorf("%w: %s for session %s", ErrObligationNotPending, obligationID, sessionID)
+	}
+	f.FulfilledAt = &now
+	f.Evidence = evidence
+	session.mutex.Unlock()
+
+	u.emit(Event{
+		Type:      EventObligationFulfilled,
+		SessionID: sessionID,
+		Message:   fmt.Sprintf("obligation %s fulfilled for session %s", obligationID, sessionID),
+		Data:      map[string]interface{}{"obligation": obligationID, "evidence": evidence},
+	})
+	return nil
+}
+
+// GetObligationFulfillments returns the caller-fulfilled obligations issued
+// to a session, ordered by issue time.
+func (u *UconEnforcer) GetObligationFulfillments(sessionID string) ([]ObligationFulfillment, error) {
+	session, err := u.GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	session.mutex.RLock()
+	fulfillments := make([]ObligationFulfillment, 0, len(session.fulfillments))
+	for _, f := range session.fulfillments {
+		fulfillments = append(fulfillments, *f)
+	}
+	session.mutex.RUnlock()
+	sort.Slice(fulfillments, func(i, j int) bool {
+		return fulfillments[i].IssuedAt.Before(fulfillments[j].IssuedAt)
+	})
+	return fulfillments, nil
+}
+
+// requireFulfillment issues an obligation with a deadline to the caller. An
+// outstanding request keeps its original deadline; a fulfilled one is only
+// issued again when the obligation is scheduled.
+func (u *UconEnforcer) requireFulfillment(obligation *Obligation, session *Session, now time.Time) {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+	if f, ok := session.fulfillments[obligation.ID]; ok && (!f.Fulfilled() || obligation.Schedule == "") {
+		return
+	}
+	if session.fulfillments == nil {
+		session.fulfillments = make(map[string]*ObligationFulfillment)
+	}
+	session.fulfillments[obligation.ID] = &ObligationFulfillment{
+		ObligationID: obligation.ID,
+		IssuedAt:     now,
+		Deadline:     now.Add(obligation.Deadline),
+	}
+}
+
+// overdueObligation returns the id of an obligation whose deadline passed
+// without fulfillment, or "".
+func (u *UconEnforcer) overdueObligation(session *Session, now time.Time) string {
+	session.mutex.RLock()
+	defer session.mutex.RUnlock()
+	for id, f := range session.fulfillments {
+		if !f.Fulfilled() && now.After(f.Deadline) {
+			return id
+		}
+	}
+	return ""
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFulfillObligation(t *testing.T) {
+	uconE := GetUconEnforcer()
+	_ = uconE.AddObligation(&Obligation{ID: "terms", Name: "caller_action", Kind: "pre", Expr: "accept terms", Deadline: 300 * time.Millisecond})
+
+	var fulfilled []Event
+	uconE.AddEventListener(func(event Event) {
+		if event.Type == EventObligationFulfilled {
+			fulfilled = append(fulfilled, event)
+		}
+	})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	session, _ := uconE.EnforceWithSession(sessionID)
+	if session == nil {
+		t.Fatal("Expected access")
+	}
+	defer uconE.StopMonitoring(sessionID)
+	pending, _ := uconE.GetObligationFulfillments(sessionID)
+	if data, _ := json.Marshal(pending); len(pending) != 1 || strings.Contains(string(data), "fulfilled_at") {
+		t.Errorf("Expected a pending fulfillment without fulfilled_at, got %s", data)
+	}
+
+	if err := uconE.FulfillObligation(sessionID, "terms", map[string]interface{}{"signature": "sig-1"}); err != nil {
+		t.Fatalf("FulfillObligation: %v", err)
+	}
+	if err := uconE.FulfillObligation(sessionID, "terms", nil); !errors.Is(err, ErrObligationNotPending) {
+		t.Errorf("Expected a second fulfillment to fail with ErrObligationNotPending, got %v", err)
+	}
+	if len(fulfilled) != 1 {
+		t.Errorf("Expected one fulfillment event, got %d", len(fulfilled))
+	}
+
+	time.Sleep(600 * time.Millisecond)
+	if !session.IfActive() {
+		t.Fatalf("Expected the session to stay active, stopped: %q", session.GetStopReason())
+	}
+	fulfillments, _ := uconE.GetObligationFulfillments(sessionID)
+	if len(fulfillments) != 1 || !fulfillments[0].Fulfilled() || fulfillments[0].Evidence["signature"] != "sig-1" {
+		t.Errorf("Unexpected fulfillments: %+v", fulfillments)
+	}
+}
+
+func TestUnfulfilledObligationRevokes(t *testing.T) {
+	uconE := GetUconEnforcer()
+	_ = uconE.AddObligation(&Obligation{ID: "notice", Name: "caller_action", Kind: "pre", Expr: "display notice", Deadline: 300 * time.Millisecond})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	session, _ := uconE.EnforceWithSession(sessionID)
+	if session == nil {
+		t.Fatal("Expected access")
+	}
+
+	time.Sleep(700 * time.Millisecond)
+	if session.IfActive() {
+		_ = uconE.StopMonitoring(sessionID)
+		t.Fatal("Expected the session to be revoked after the deadline")
+	}
+	if !strings.Contains(session.GetStopReason(), "Obligation notice not fulfilled") {
+		t.Errorf("Unexpected stop reason: %q", session.GetStopReason())
+	}
+	if err := uconE.FulfillObligation(sessionID, "unknown", nil); !errors.Is(err, ErrObligationNotPending) {
+		t.Errorf("Expected ErrObligationNotPending for an unknown obligation, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import "sync"
+
+// sessionGauges counts the active sessions, by subject and by object, as
+// sessions start and stop, so reading a count does not scan the sessions.
+type sessionGauges struct {
+	mutex     sync.Mutex
+	active    map[string]gaugeKey // active session id -> what it is counted under
+	bySubject map[string]int
+	byObject  map[string]int
+}
+
+type gaugeKey struct {
+	subject string
+	object  string
+}
+
+func newSessionGauges() *sessionGauges {
+	return &sessionGauges{
+		active:    make(map[string]gaugeKey),
+		bySubject: make(map[string]int),
+		byObject:  make(map[string]int),
+	}
+}
+
+// update counts the session if it is active and stops counting it otherwise.
+func (g *sessionGauges) update(session *Session) {
+	if !session.IfActive() {
+		g.remove(session.GetId())
+		return
+	}
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	if _, ok := g.active[session.GetId()]; ok {
+		return
+	}
+	key := gaugeKey{subject: session.GetSubject(), object: session.GetObject()}
+	g.active[session.GetId()] = key
+	g.bySubject[key.subject]++
+	g.byObject[key.object]++
+}
+
+func (g *sessionGauges) remove(sessionID string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	key, ok := g.active[sessionID]
+	if !ok {
+		return
+	}
+	delete(g.active, sessionID)
+	decrement(g.bySubject, key.subject)
+	decrement(g.byObject, key.object)
+}
+
+func decrement(counts map[string]int, key string) {
+	if counts[key] <= 1 {
+		delete(counts, key)
+		return
+	}
+	counts[key]--
+}
+
+// ActiveSessionCount returns the number of active sessions in constant time.
+func (u *UconEnforcer) ActiveSessionCount() int {
+	u.gauges.mutex.Lock()
+	defer u.gauges.mutex.Unlock()
+	return len(u.gauges.active)
+}
+
+// ActiveSessionCountBySubject returns the number of active sessions of a subject.
+func (u *UconEnforcer) ActiveSessionCountBySubject(subject string) int {
+	u.gauges.mutex.Lock()
+	defer u.gauges.mutex.Unlock()
+	return u.gauges.bySubject[subject]
+}
+
+// ActiveSessionCountByObject returns the number of active sessions on an object.
+func (u *UconEnforcer) ActiveSessionCountByObject(object string) int {
+	u.gauges.mutex.Lock()
+	defer u.gauges.mutex.Unlock()
+	return u.gauges.byObject[object]
+}
+
+// MonitoredSessionCount returns the number of sessions holding a monitoring
+// slot, see WithMaxMonitoredSessions.
+func (u *UconEnforcer) MonitoredSessionCount() int {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.monitoredCountLocked()
+}
+
+// MonitoringCapacity returns the maximum number of monitored sessions, 0 if
+// it is unlimited.
+func (u *UconEnforcer) MonitoringCapacity() int {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	if u.maxMonitored < 0 {
+		return 0
+	}
+	return u.maxMonitored
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import "testing"
+
+func TestSessionGauges(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithMaxMonitoredSessions(10, CapacityReject))
+	if uconE.MonitoringCapacity() != 10 {
+		t.Errorf("Expected capacity 10, got %d", uconE.MonitoringCapacity())
+	}
+
+	first, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	second, _ := uconE.CreateSession("alice", "write", "document1", nil)
+	third, _ := uconE.CreateSession("bob", "read", "document2", nil)
+	if _, err := uconE.EnforceWithSession(first); err != nil {
+		t.Fatal(err)
+	}
+	if n := uconE.ActiveSessionCount(); n != 3 {
+		t.Errorf("Expected 3 active sessions, got %d", n)
+	}
+	if n := uconE.ActiveSessionCountBySubject("alice"); n != 2 {
+		t.Errorf("Expected 2 sessions of alice, got %d", n)
+	}
+	if n := uconE.ActiveSessionCountByObject("document2"); n != 1 {
+		t.Errorf("Expected 1 session on document2, got %d", n)
+	}
+	if n := uconE.MonitoredSessionCount(); n != 1 {
+		t.Errorf("Expected 1 monitored session, got %d", n)
+	}
+
+	_ = uconE.StopMonitoring(first)
+	session, _ := uconE.GetSession(third)
+	_ = session.Stop("done")
+	if n := uconE.ActiveSessionCount(); n != 1 {
+		t.Errorf("Expected 1 active session, got %d", n)
+	}
+	if uconE.ActiveSessionCountBySubject("alice") != 1 || uconE.ActiveSessionCountBySubject("bob") != 0 {
+		t.Errorf("Expected counts by subject to follow stops")
+	}
+	if n := uconE.MonitoredSessionCount(); n != 0 {
+		t.Errorf("Expected no monitored session, got %d", n)
+	}
+
+	s, _ := uconE.GetSession(second)
+	standby := NewUconEnforcer(e)
+	_ = standby.ApplyReplicationEvent(ReplicationEvent{Type: ReplicationSave, Session: s.ToRecord()})
+	if standby.ActiveSessionCountByObject("document1") != 1 {
+		t.Errorf("Expected replicated sessions to be counted")
+	}
+	_ = s.Stop("done")
+	_ = standby.ApplyReplicationEvent(ReplicationEvent{Type: ReplicationSave, Session: s.ToRecord()})
+	if standby.ActiveSessionCount() != 0 {
+		t.Errorf("Expected replicated stops to be counted, got %d", standby.ActiveSessionCount())
+	}
+}
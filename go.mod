@@ -2,9 +2,9 @@ module github.com/casbin/casbin-ucon
 
 go 1.21
 
-require github.com/casbin/casbin/v2 v2.120.0
-
 require (
-	github.com/bmatcuk/doublestar/v4 v4.6.1 // indirect
-	github.com/casbin/govaluate v1.3.0 // indirect
+	github.com/casbin/casbin/v2 v2.120.0
+	github.com/casbin/govaluate v1.3.0
 )
+
+require github.com/bmatcuk/doublestar/v4 v4.6.1 // indirect
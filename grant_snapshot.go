@@ -0,0 +1,71 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"slices"
+	"time"
+)
+
+// GrantSnapshot records what was known when a session was first granted:
+// the attributes it was evaluated with and the policy rule that authorized
+// it. It never changes afterwards, so audits can tell it apart from the live
+// attributes, which change while the session is in use.
+type GrantSnapshot struct {
+	GrantedAt time.Time `json:"granted_at"`
+	// Attributes are the session attributes at grant time, including those
+	// of its subject.
+	Attributes map[string]interface{} `json:"attributes"`
+	// Rule is the policy rule that matched, e.g. [alice document1 read].
+	Rule []string `json:"rule"`
+}
+
+func (g *GrantSnapshot) clone() *GrantSnapshot {
+	if g == nil {
+		return nil
+	}
+	attributes := make(map[string]interface{}, len(g.Attributes))
+	for k, v := range g.Attributes {
+		attributes[k] = v
+	}
+	return &GrantSnapshot{GrantedAt: g.GrantedAt, Attributes: attributes, Rule: append([]string(nil), g.Rule...)}
+}
+
+// GetGrantSnapshot returns a copy of the snapshot taken when the session was
+// first granted, or nil if it has not been granted yet.
+func (s *Session) GetGrantSnapshot() *GrantSnapshot {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.grant.clone()
+}
+
+// recordGrant records the policy rule that granted the session and, on its
+// first grant, the grant snapshot.
+func (s *Session) recordGrant(rule []string, now time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.grant != nil && slices.Equal(s.grantSource, rule) {
+		return nil
+	}
+	s.grantSource = append([]string(nil), rule...)
+	if s.grant == nil {
+		s.grant = &GrantSnapshot{
+			GrantedAt:  now,
+			Attributes: s.attributesLocked(),
+			Rule:       append([]string(nil), rule...),
+		}
+	}
+	return s.persistLocked()
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestGrantSnapshot(t *testing.T) {
+	uconE := GetUconEnforcer()
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"location": "office"})
+	session, _ := uconE.GetSession(sessionID)
+	if session.GetGrantSnapshot() != nil {
+		t.Fatal("Expected no snapshot before the grant")
+	}
+	if _, err := uconE.EnforceWithSession(sessionID); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot := session.GetGrantSnapshot()
+	if snapshot == nil || snapshot.GrantedAt.IsZero() || snapshot.Attributes["location"] != "office" || !slices.Equal(snapshot.Rule, []string{"alice", "document1", "read"}) {
+		t.Fatalf("Expected the grant to be recorded, got %+v", snapshot)
+	}
+	snapshot.Attributes["location"] = "tampered"
+
+	_ = uconE.UpdateSessionAttribute(sessionID, "location", "home")
+	_ = uconE.UpdateSessionAttribute(sessionID, "location", "office")
+	if _, err := uconE.EnforceWithSession(sessionID); err != nil {
+		t.Fatal(err)
+	}
+	_ = uconE.UpdateSessionAttribute(sessionID, "location", "cafe")
+	later := session.GetGrantSnapshot()
+	if later.Attributes["location"] != "office" || !later.GrantedAt.Equal(snapshot.GrantedAt) {
+		t.Errorf("Expected the snapshot to stay unchanged, got %+v", later)
+	}
+	if session.GetAttribute("location") != "cafe" {
+		t.Errorf("Expected the live attributes to change, got %v", session.GetAttribute("location"))
+	}
+
+	restored := NewSession(session.ToRecord(), nil, nil)
+	if grant := restored.GetGrantSnapshot(); grant == nil || grant.Attributes["location"] != "office" {
+		t.Errorf("Expected the snapshot to survive the record, got %+v", grant)
+	}
+	_ = uconE.StopMonitoring(sessionID)
+}
+
+func TestGrantSnapshotSurvivesWALReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.wal")
+	store, _ := NewWALSessionStore(path)
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithSessionStore(store))
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"location": "office"})
+	if _, err := uconE.EnforceWithSession(sessionID); err != nil {
+		t.Fatal(err)
+	}
+	_ = uconE.StopMonitoring(sessionID)
+	_ = store.Close()
+
+	reopened, err := NewWALSessionStore(path)
+	if err != nil {
+		t.Fatalf("Failed to replay WAL: %v", err)
+	}
+	defer reopened.Close()
+	records, _ := reopened.LoadSessions()
+	if len(records) != 1 || records[0].Grant == nil || records[0].Grant.Attributes["location"] != "office" ||
+		!slices.Equal(records[0].Grant.Rule, []string{"alice", "document1", "read"}) {
+		t.Errorf("Expected the grant snapshot to be replayed, got %+v", records)
+	}
+}
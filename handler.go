@@ -0,0 +1,145 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+// ConditionHandler evaluates a condition expression against a read-only
+// view of a session.
+type ConditionHandler func(expr string, session SessionView) (bool, error)
+
+// conditionFunc is the form condition handlers are stored in. Builtin
+// conditions that keep per-session state, like time_budget, need the session
+// itself; registered handlers are wrapped to only see its SessionView.
+type conditionFunc func(expr string, session *Session) (bool, error)
+
+// ObligationHandler executes an obligation expression for a session.
+type ObligationHandler func(expr string, session *Session) error
+
+// obligationFunc is the form obligation handlers are stored in, receiving the
+// idempotency key of the execution; see IdempotentObligationHandler.
+type obligationFunc func(expr string, session *Session, key string) error
+
+// FailurePolicy decides how handler errors (including recovered panics) are
+// treated during condition evaluation and obligation execution.
+type FailurePolicy int
+
+const (
+	// FailClosed treats a handler error as a failed condition or obligation,
+	// denying or revoking access. This is the default.
+	FailClosed FailurePolicy = iota
+	// FailOpen ignores handler errors, treating the condition as satisfied
+	// and the obligation as fulfilled.
+	FailOpen
+)
+
+var (
+	// ErrHandlerPanic is wrapped by errors returned when a handler panics.
+	ErrHandlerPanic = errors.New("handler panicked")
+	// ErrUnknownHandler is wrapped by errors for conditions and obligations
+	// whose name has no registered handler.
+	ErrUnknownHandler = errors.New("no handler registered")
+)
+
+// RegisterConditionHandler registers a handler for conditions with the given name.
+func (u *UconEnforcer) RegisterConditionHandler(name string, handler ConditionHandler) error {
+	if name == "" {
+		return errors.New("condition handler name cannot be empty")
+	}
+	if handler == nil {
+		return errors.New("condition handler cannot be nil")
+	}
+	u.mu.Lock()
+	if u.customConditions == nil {
+		u.customConditions = make(map[string]bool)
+	}
+	u.customConditions[name] = true
+	u.conditionHandlers[name] = func(expr string, session *Session) (bool, error) {
+		return handler(expr, session.View())
+	}
+	u.mu.Unlock()
+	return nil
+}
+
+// RegisterObligationHandler registers a handler for obligations with the given name.
+func (u *UconEnforcer) RegisterObligationHandler(name string, handler ObligationHandler) error {
+	if name == "" {
+		return errors.New("obligation handler name cannot be empty")
+	}
+	if handler == nil {
+		return errors.New("obligation handler cannot be nil")
+	}
+	u.mu.Lock()
+	u.obligationHandlers[name] = withoutKey(handler)
+	u.mu.Unlock()
+	return nil
+}
+
+// SetFailurePolicy sets how handler errors are treated.
+func (u *UconEnforcer) SetFailurePolicy(policy FailurePolicy) {
+	u.mu.Lock()
+	u.failurePolicy = policy
+	u.mu.Unlock()
+}
+
+func (u *UconEnforcer) getFailurePolicy() FailurePolicy {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.failurePolicy
+}
+
+// callConditionHandler runs a condition handler, converting a panic into an error.
+func (u *UconEnforcer) callConditionHandler(handler conditionFunc, condition *Condition, session *Session) (result bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = false
+			err = u.recoverHandlerPanic("condition", condition.ID, condition.Name, session, r)
+		}
+	}()
+	return handler(condition.Expr, session)
+}
+
+// callObligationHandler runs an obligation handler, converting a panic into an error.
+func (u *UconEnforcer) callObligationHandler(handler obligationFunc, obligation *Obligation, session *Session, key string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = u.recoverHandlerPanic("obligation", obligation.ID, obligation.Name, session, r)
+		}
+	}()
+	return handler(obligation.Expr, session, key)
+}
+
+func (u *UconEnforcer) recoverHandlerPanic(kind string, id string, name string, session *Session, r interface{}) error {
+	if session.dryRun {
+		return fmt.Errorf("%s %s handler %s: %w: %v", kind, id, name, ErrHandlerPanic, r)
+	}
+	u.emit(Event{
+		Type:      EventHandlerPanic,
+		SessionID: session.GetId(),
+		Message:   fmt.Sprintf("%s %s handler %s panicked: %v", kind, id, name, r),
+		Data: map[string]interface{}{
+			"kind":  kind,
+			"id":    id,
+			"name":  name,
+			"panic": r,
+			"stack": string(debug.Stack()),
+		},
+	})
+	return fmt.Errorf("%s %s handler %s: %w: %v", kind, id, name, ErrHandlerPanic, r)
+}
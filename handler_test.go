@@ -0,0 +1,153 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestConditionHandlerPanic(t *testing.T) {
+	uconE := GetUconEnforcer()
+
+	var mu sync.Mutex
+	var events []Event
+	uconE.AddEventListener(func(event Event) {
+		mu.Lock()
+		events = append(events, event)
+		mu.Unlock()
+	})
+
+	_ = uconE.RegisterConditionHandler("buggy", func(expr string, session SessionView) (bool, error) {
+		var m map[string]int
+		m[expr]++
+		return true, nil
+	})
+	_ = uconE.AddCondition(&Condition{ID: "buggy_condition", Name: "buggy", Kind: "always", Expr: "x"})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{})
+
+	ok, err := uconE.EvaluateConditions(sessionID)
+	if ok {
+		t.Error("Expected panicking condition to fail closed")
+	}
+	if !errors.Is(err, ErrHandlerPanic) {
+		t.Errorf("Expected ErrHandlerPanic, got %v", err)
+	}
+
+	mu.Lock()
+	if len(events) != 1 || events[0].Type != EventHandlerPanic || events[0].SessionID != sessionID {
+		t.Errorf("Expected one handler panic event for %s, got %+v", sessionID, events)
+	}
+	mu.Unlock()
+
+	uconE.SetFailurePolicy(FailOpen)
+	ok, err = uconE.EvaluateConditions(sessionID)
+	if !ok || err != nil {
+		t.Errorf("Expected panicking condition to fail open, got %v, %v", ok, err)
+	}
+}
+
+func TestObligationHandlerPanicDuringMonitoring(t *testing.T) {
+	uconE := GetUconEnforcer()
+
+	_ = uconE.RegisterObligationHandler("buggy", func(expr string, session *Session) error {
+		panic("boom")
+	})
+	_ = uconE.AddObligation(&Obligation{ID: "buggy_obligation", Name: "buggy", Kind: "ongoing"})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{})
+	session, err := uconE.EnforceWithSession(sessionID)
+	if session == nil {
+		t.Fatalf("Failed to enforce: %v", err)
+	}
+
+	time.Sleep(500 * time.Millisecond)
+	if session.IfActive() {
+		t.Fatal("Expected session to be stopped after the obligation panicked")
+	}
+	if session.GetStopReason() == NormalStopReason {
+		t.Error("Expected a stop reason describing the panic")
+	}
+}
+
+func TestRegisterHandlerValidation(t *testing.T) {
+	uconE := GetUconEnforcer()
+
+	if err := uconE.RegisterConditionHandler("", func(string, SessionView) (bool, error) { return true, nil }); err == nil {
+		t.Error("Expected empty condition handler name to be rejected")
+	}
+	if err := uconE.RegisterConditionHandler("nil", nil); err == nil {
+		t.Error("Expected nil condition handler to be rejected")
+	}
+	if err := uconE.RegisterObligationHandler("nil", nil); err == nil {
+		t.Error("Expected nil obligation handler to be rejected")
+	}
+}
+
+func TestWrappedErrors(t *testing.T) {
+	uconE := GetUconEnforcer()
+	errDown := errors.New("backend down")
+	_ = uconE.RegisterConditionHandler("backend", func(expr string, session SessionView) (bool, error) {
+		return false, errDown
+	})
+	_ = uconE.AddCondition(&Condition{ID: "backend-check", Name: "backend", Kind: "one"})
+	_ = uconE.AddObligation(&Obligation{ID: "unknown-obl", Name: "no_such_obligation", Kind: "post"})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	_, err := uconE.EvaluateConditions(sessionID)
+	if !errors.Is(err, errDown) {
+		t.Fatalf("Expected the handler error to be wrapped, got %v", err)
+	}
+	if msg := err.Error(); msg != "session "+sessionID+": condition backend-check: backend down" {
+		t.Errorf("Expected session and condition ids in the error, got %q", msg)
+	}
+
+	err = uconE.ExecuteObligationsByType(sessionID, "post")
+	if !errors.Is(err, ErrUnknownHandler) {
+		t.Errorf("Expected ErrUnknownHandler, got %v", err)
+	}
+
+	if _, err := uconE.EnforceWithSession("missing"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+	if err := uconE.StartMonitoring("missing"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+}
+
+func TestEventListenerPanic(t *testing.T) {
+	logger := &countingLogger{}
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithLogger(logger))
+	var delivered int32
+	uconE.AddEventListener(func(event Event) { panic("listener bug") })
+	uconE.AddEventListener(func(event Event) { atomic.AddInt32(&delivered, 1) })
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	if _, err := uconE.EnforceWithSession(sessionID); err != nil {
+		t.Fatal(err)
+	}
+	_ = uconE.StopMonitoring(sessionID)
+	if atomic.LoadInt32(&delivered) == 0 {
+		t.Error("Expected the other listeners to receive events")
+	}
+	if logger.count("event listener panicked") == 0 {
+		t.Error("Expected the panic to be logged")
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"time"
+)
+
+// WithHeartbeat requires clients to call Heartbeat for monitored sessions at
+// least every interval. A session that misses maxMissed consecutive heartbeats
+// is revoked, so clients that vanish do not keep their grants forever.
+func WithHeartbeat(interval time.Duration, maxMissed int) Option {
+	return func(u *UconEnforcer) {
+		u.heartbeatInterval = interval
+		u.heartbeatMaxMissed = maxMissed
+	}
+}
+
+// Heartbeat records that the client of a session is still alive.
+func (u *UconEnforcer) Heartbeat(sessionID string) error {
+	session, err := u.GetSession(sessionID)
+	if err != nil {
+		return err
+	}
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+	if !session.IfActive() {
+		return ErrSessionNotActive
+	}
+	session.lastHeartbeat = time.Now()
+	return nil
+}
+
+// heartbeatExpired reports whether the session has missed too many heartbeats.
+// The heartbeat clock starts when monitoring of the session starts.
+func (u *UconEnforcer) heartbeatExpired(session *Session, now time.Time) bool {
+	if u.heartbeatInterval <= 0 {
+		return false
+	}
+	maxMissed := u.heartbeatMaxMissed
+	if maxMissed < 1 {
+		maxMissed = 1
+	}
+
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+	if session.lastHeartbeat.IsZero() {
+		session.lastHeartbeat = now
+	}
+	return now.Sub(session.lastHeartbeat) > time.Duration(maxMissed)*u.heartbeatInterval
+}
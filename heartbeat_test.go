@@ -0,0 +1,55 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHeartbeat(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithHeartbeat(200*time.Millisecond, 2))
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	session, _ := uconE.EnforceWithSession(sessionID)
+	if session == nil {
+		t.Fatal("Expected access")
+	}
+
+	// Regular heartbeats keep the session alive.
+	for i := 0; i < 6; i++ {
+		time.Sleep(150 * time.Millisecond)
+		if err := uconE.Heartbeat(sessionID); err != nil {
+			t.Fatalf("Heartbeat: %v", err)
+		}
+	}
+	if !session.IfActive() {
+		t.Fatalf("Expected session to stay active, stopped: %q", session.GetStopReason())
+	}
+
+	// The client vanishes.
+	time.Sleep(800 * time.Millisecond)
+	if session.IfActive() {
+		t.Fatal("Expected session to be revoked after missed heartbeats")
+	}
+	if !strings.Contains(session.GetStopReason(), "Missed heartbeats") {
+		t.Errorf("Unexpected stop reason: %q", session.GetStopReason())
+	}
+	if err := uconE.Heartbeat(sessionID); err == nil {
+		t.Error("Expected heartbeat on a revoked session to fail")
+	}
+}
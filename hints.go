@@ -0,0 +1,147 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MissingAttributeError is returned by conditions that need an attribute the
+// session does not have. It wraps ErrAttributeNotFound, and denied decisions
+// list its Key in MissingAttributes, so custom handlers should return it too.
+type MissingAttributeError struct {
+	Key string
+}
+
+func (e *MissingAttributeError) Error() string {
+	return fmt.Sprintf("%s attribute not found", e.Key)
+}
+
+// Is reports whether target is ErrAttributeNotFound.
+func (e *MissingAttributeError) Is(target error) bool {
+	return target == ErrAttributeNotFound
+}
+
+// denialHints tell the client of a session denied by a condition how to
+// get access.
+type denialHints struct {
+	retryAfter time.Time
+	missing    []string
+}
+
+// retryHints return when the condition with the given expression may pass
+// again by waiting alone, for the built-in conditions that depend on time.
+var retryHints = map[string]func(expr string, now time.Time) (time.Time, bool){
+	"weekday_in":  nextWeekdayIn,
+	"time_budget": nextTimeBudget,
+}
+
+// nextWeekdayIn returns the start of the next day a weekday_in condition
+// allows.
+func nextWeekdayIn(expr string, now time.Time) (time.Time, bool) {
+	days := expr
+	if i := strings.Index(expr, "@"); i >= 0 {
+		loc, err := time.LoadLocation(strings.TrimSpace(expr[i+1:]))
+		if err != nil {
+			return time.Time{}, false
+		}
+		now, days = now.In(loc), expr[:i]
+	}
+	allowed, err := parseWeekdays(days)
+	if err != nil {
+		return time.Time{}, false
+	}
+	year, month, day := now.Date()
+	for i := 1; i <= 7; i++ {
+		next := time.Date(year, month, day+i, 0, 0, 0, 0, now.Location())
+		if allowed[next.Weekday()] {
+			return next, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// nextTimeBudget returns the start of the next period of a time_budget
+// condition, when its budget is renewed.
+func nextTimeBudget(expr string, now time.Time) (time.Time, bool) {
+	fields := strings.Fields(expr)
+	if len(fields) < 3 {
+		return time.Time{}, false
+	}
+	start, err := periodStart(fields[2], now)
+	if err != nil {
+		return time.Time{}, false
+	}
+	switch fields[2] {
+	case "day":
+		return start.AddDate(0, 0, 1), true
+	case "week":
+		return start.AddDate(0, 0, 7), true
+	default:
+		return start.AddDate(0, 1, 0), true
+	}
+}
+
+// conditionHints returns the hints for a session denied by a condition,
+// given the error of its evaluation.
+func conditionHints(condition *Condition, err error, now time.Time) denialHints {
+	var hints denialHints
+	var missing *MissingAttributeError
+	if errors.As(err, &missing) {
+		hints.missing = []string{missing.Key}
+		return hints
+	}
+	if err != nil {
+		return hints
+	}
+	if next, ok := retryHints[condition.Name]; ok {
+		hints.retryAfter, _ = next(condition.Expr, now)
+	}
+	return hints
+}
+
+func (s *Session) setDenialHints(hints denialHints) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.denial = hints
+}
+
+func (s *Session) getDenialHints() denialHints {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.denial
+}
+
+// addHints fills in the hints of a decision denying a session.
+func (u *UconEnforcer) addHints(decision *Decision, sessionID string, err error) {
+	session, getErr := u.GetSession(sessionID)
+	if getErr != nil {
+		return
+	}
+	if errors.Is(err, ErrUnderMaintenance) {
+		if w, ok := u.activeMaintenance(session.GetObject(), time.Now()); ok {
+			decision.RetryAfter = &w.End
+		}
+		return
+	}
+	hints := session.getDenialHints()
+	if !hints.retryAfter.IsZero() {
+		decision.RetryAfter = &hints.retryAfter
+	}
+	decision.MissingAttributes = hints.missing
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMissingAttributeError(t *testing.T) {
+	var err error = &MissingAttributeError{Key: "department"}
+	if !errors.Is(err, ErrAttributeNotFound) || err.Error() != "department attribute not found" {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+func TestRetryHints(t *testing.T) {
+	monday := time.Date(2026, 3, 2, 15, 4, 0, 0, time.UTC)
+	tests := []struct {
+		next func(string, time.Time) (time.Time, bool)
+		expr string
+		want time.Time
+	}{
+		{nextWeekdayIn, "sat,sun", time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC)},
+		{nextWeekdayIn, "mon", time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)},
+		{nextTimeBudget, "1h per day", time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)},
+		{nextTimeBudget, "1h per week", time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)},
+		{nextTimeBudget, "10h per month by content_type", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got, ok := tt.next(tt.expr, monday); !ok || !got.Equal(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+
+	loc, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip("time zone database unavailable")
+	}
+	got, _ := nextWeekdayIn("tue@Asia/Tokyo", monday)
+	if want := time.Date(2026, 3, 10, 0, 0, 0, 0, loc); !got.Equal(want) {
+		t.Errorf("Expected %v in Tokyo, got %v", want, got)
+	}
+}
+
+func TestDecisionHints(t *testing.T) {
+	uconE := GetUconEnforcer().(*UconEnforcer)
+
+	var otherDays []string
+	for name, day := range weekdays {
+		if day != time.Now().Weekday() {
+			otherDays = append(otherDays, name)
+		}
+	}
+	_ = uconE.AddCondition(&Condition{ID: "weekdays", Name: "weekday_in", Kind: "always", Expr: strings.Join(otherDays, ",")})
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{})
+	decision, err := uconE.Decide(sessionID)
+	if err != nil || decision.Allowed {
+		t.Fatalf("Expected a denial, got %+v %v", decision, err)
+	}
+	if decision.RetryAfter == nil || !decision.RetryAfter.After(time.Now()) || decision.RetryAfter.After(time.Now().Add(24*time.Hour)) {
+		t.Errorf("Expected a retry within a day, got %v", decision.RetryAfter)
+	}
+	_ = uconE.RemoveCondition("weekdays")
+
+	_ = uconE.AddCondition(&Condition{ID: "department", Name: "attribute_equals", Kind: "always", Expr: "department:engineering"})
+	decision, _ = uconE.Decide(sessionID)
+	if !reflect.DeepEqual(decision.MissingAttributes, []string{"department"}) || decision.RetryAfter != nil {
+		t.Errorf("Expected department to be missing, got %+v", decision)
+	}
+	_ = uconE.RemoveCondition("department")
+
+	end := time.Now().Add(time.Hour).Truncate(time.Second)
+	_ = uconE.AddMaintenanceWindow(&MaintenanceWindow{ID: "upgrade", Start: time.Now(), End: end})
+	decision, err = uconE.Decide(sessionID)
+	if !errors.Is(err, ErrUnderMaintenance) || decision.RetryAfter == nil || !decision.RetryAfter.Equal(end) || decision.MissingAttributes != nil {
+		t.Errorf("Expected a retry after the maintenance window, got %+v %v", decision, err)
+	}
+
+	// Decisions without a hint leave it out of their JSON.
+	if data, _ := json.Marshal(&Decision{Allowed: true}); strings.Contains(string(data), "retry_after") {
+		t.Errorf("Expected no retry_after, got %s", data)
+	}
+}
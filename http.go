@@ -0,0 +1,118 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// DefaultSessionHeader is the request header carrying the UCON session id.
+const DefaultSessionHeader = "X-Session-ID"
+
+var (
+	// ErrNoSession is returned when a request carries no session id.
+	ErrNoSession = errors.New("no session id in request")
+	// ErrSessionDenied is returned when the session is refused or has been revoked.
+	ErrSessionDenied = errors.New("session denied")
+)
+
+type sessionContextKey struct{}
+
+// ContextWithSession returns a copy of ctx carrying session.
+func ContextWithSession(ctx context.Context, session *Session) context.Context {
+	return context.WithValue(ctx, sessionContextKey{}, session)
+}
+
+// SessionFromContext returns the session stored by ContextWithSession.
+func SessionFromContext(ctx context.Context) (*Session, bool) {
+	session, ok := ctx.Value(sessionContextKey{}).(*Session)
+	return session, ok
+}
+
+// SessionIDExtractor returns the UCON session id carried by a request, or "".
+type SessionIDExtractor func(r *http.Request) string
+
+// HeaderSessionID extracts the session id from a request header.
+func HeaderSessionID(header string) SessionIDExtractor {
+	return func(r *http.Request) string {
+		return r.Header.Get(header)
+	}
+}
+
+// CookieSessionID extracts the session id from a cookie.
+func CookieSessionID(name string) SessionIDExtractor {
+	return func(r *http.Request) string {
+		cookie, err := r.Cookie(name)
+		if err != nil {
+			return ""
+		}
+		return cookie.Value
+	}
+}
+
+// AuthorizeRequest enforces the session behind a request and maps the outcome
+// to an HTTP status: 200 when access is granted, 401 when the session id is
+// missing or unknown, and 403 when the session is refused or has been revoked.
+// A granted session is monitored continuously, so once its conditions stop
+// holding, later requests are answered with 403. ReasonFor returns the reason
+// code of a returned error.
+func AuthorizeRequest(e IUconEnforcer, sessionID string) (*Session, int, error) {
+	if sessionID == "" {
+		return nil, http.StatusUnauthorized, ErrNoSession
+	}
+	session, err := e.GetSession(sessionID)
+	if err != nil {
+		return nil, http.StatusUnauthorized, err
+	}
+	if !session.IfActive() {
+		stopReason := session.GetStopReason()
+		return nil, http.StatusForbidden, &ReasonError{
+			Code: ReasonForStop(stopReason),
+			Err:  fmt.Errorf("%w: session %s was stopped: %s", ErrSessionDenied, sessionID, stopReason),
+		}
+	}
+
+	granted, err := e.EnforceWithSession(sessionID)
+	if err != nil {
+		return nil, http.StatusForbidden, fmt.Errorf("%w: %w", ErrSessionDenied, err)
+	}
+	if granted == nil {
+		return nil, http.StatusForbidden, fmt.Errorf("%w: session %s is not authorized", ErrSessionDenied, sessionID)
+	}
+	return granted, http.StatusOK, nil
+}
+
+// NewHTTPMiddleware returns net/http middleware that authorizes every request
+// with AuthorizeHTTPRequest and stores the granted session in the request
+// context. Denials are answered with a Problem carrying the reason code. If
+// extract is nil, the session id is read from DefaultSessionHeader.
+func NewHTTPMiddleware(e IUconEnforcer, extract SessionIDExtractor) func(http.Handler) http.Handler {
+	if extract == nil {
+		extract = HeaderSessionID(DefaultSessionHeader)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			session, status, err := AuthorizeHTTPRequest(e, extract(r), r)
+			if err != nil {
+				WriteProblem(w, status, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(ContextWithSession(r.Context(), session)))
+		})
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	uconE := GetUconEnforcer()
+	_ = uconE.AddCondition(&Condition{ID: "location_always", Name: "location", Kind: "always", Expr: "office"})
+
+	handler := NewHTTPMiddleware(uconE, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session, ok := SessionFromContext(r.Context())
+		if !ok {
+			t.Error("Expected session in request context")
+			return
+		}
+		_, _ = w.Write([]byte(session.GetSubject()))
+	}))
+
+	do := func(sessionID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/document1", nil)
+		if sessionID != "" {
+			req.Header.Set(DefaultSessionHeader, sessionID)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	aliceID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"location": "office"})
+	carolID, _ := uconE.CreateSession("carol", "read", "document1", map[string]interface{}{"location": "office"})
+
+	if rec := do(""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without session id, got %d", rec.Code)
+	}
+	if rec := do("session_unknown"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for unknown session, got %d", rec.Code)
+	}
+	if rec := do(carolID); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for unauthorized session, got %d", rec.Code)
+	}
+	if rec := do(aliceID); rec.Code != http.StatusOK || rec.Body.String() != "alice" {
+		t.Errorf("Expected 200 for alice, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	// Ongoing monitoring revokes the session; later requests are refused.
+	_ = uconE.UpdateSessionAttribute(aliceID, "location", "home")
+	time.Sleep(500 * time.Millisecond)
+	if rec := do(aliceID); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 after revocation, got %d", rec.Code)
+	}
+	_ = uconE.StopMonitoring(carolID)
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// IdempotencyKeyHeader is the HTTP header carrying the idempotency key of a
+// webhook obligation.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentObligationHandler is an ObligationHandler that also receives the
+// idempotency key of the execution, to pass on to webhooks, billing or
+// notification services so they can drop duplicates.
+//
+// The key identifies the (session, obligation, phase, attempt) being
+// executed. The attempt only advances once an execution succeeds, so when an
+// execution fails with a transient error and is retried, by the monitor on
+// its next tick or by the caller, the retry carries the same key, while the
+// next execution of an ongoing obligation gets a new one.
+type IdempotentObligationHandler func(expr string, session *Session, key string) error
+
+// RegisterIdempotentObligationHandler registers a handler, receiving
+// idempotency keys, for obligations with the given name.
+func (u *UconEnforcer) RegisterIdempotentObligationHandler(name string, handler IdempotentObligationHandler) error {
+	if name == "" {
+		return errors.New("obligation handler name cannot be empty")
+	}
+	if handler == nil {
+		return errors.New("obligation handler cannot be nil")
+	}
+	u.mu.Lock()
+	u.obligationHandlers[name] = obligationFunc(handler)
+	u.mu.Unlock()
+	return nil
+}
+
+// IdempotencyKey returns the idempotency key of an execution of an obligation.
+func IdempotencyKey(sessionID string, obligationID string, phase string, attempt int) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%s\x00%d", sessionID, obligationID, phase, attempt)))
+	return hex.EncodeToString(sum[:16])
+}
+
+func withoutKey(handler ObligationHandler) obligationFunc {
+	return func(expr string, session *Session, _ string) error {
+		return handler(expr, session)
+	}
+}
+
+func attemptKey(obligation *Obligation) string {
+	return obligation.Kind + "/" + obligation.ID
+}
+
+// obligationAttempt returns the number of the next execution of obligation.
+func (s *Session) obligationAttempt(obligation *Obligation) int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.attempts[attemptKey(obligation)]
+}
+
+// completeObligation records that an execution succeeded, so the next one
+// gets a new idempotency key. Executions of pre and post obligations are
+// saved at once; those of ongoing obligations, which run on every monitor
+// tick, are saved with the next change of the session.
+func (s *Session) completeObligation(obligation *Obligation, attempt int) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	key := attemptKey(obligation)
+	if s.attempts[key] != attempt {
+		return nil
+	}
+	if s.attempts == nil {
+		s.attempts = make(map[string]int)
+	}
+	s.attempts[key] = attempt + 1
+	if obligation.Kind == "ongoing" {
+		return nil
+	}
+	return s.persistLocked()
+}
+
+func copyAttempts(attempts map[string]int) map[string]int {
+	if len(attempts) == 0 {
+		return nil
+	}
+	copied := make(map[string]int, len(attempts))
+	for k, v := range attempts {
+		copied[k] = v
+	}
+	return copied
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIdempotencyKeys(t *testing.T) {
+	uconE := GetUconEnforcer()
+	var keys []string
+	fail := true
+	_ = uconE.RegisterIdempotentObligationHandler("charge", func(expr string, session *Session, key string) error {
+		keys = append(keys, key)
+		if fail {
+			fail = false
+			return errors.New("billing service unavailable")
+		}
+		return nil
+	})
+	_ = uconE.AddObligation(&Obligation{ID: "charge", Name: "charge", Kind: "post", Expr: "1"})
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+
+	if err := uconE.ExecuteObligationsByType(sessionID, "post"); err == nil {
+		t.Fatal("expected the first execution to fail")
+	}
+	for i := 0; i < 2; i++ {
+		if err := uconE.ExecuteObligationsByType(sessionID, "post"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(keys) != 3 {
+		t.Fatalf("expected 3 executions, got %d", len(keys))
+	}
+	if keys[0] != keys[1] {
+		t.Error("retry of a failed execution got a new idempotency key")
+	}
+	if keys[1] == keys[2] {
+		t.Error("next execution reused the idempotency key")
+	}
+	if keys[0] != IdempotencyKey(sessionID, "charge", "post", 0) {
+		t.Errorf("unexpected key %s", keys[0])
+	}
+
+	session, _ := uconE.GetSession(sessionID)
+	if attempts := session.ToRecord().ObligationAttempts["post/charge"]; attempts != 2 {
+		t.Errorf("expected 2 recorded attempts, got %d", attempts)
+	}
+}
+
+func TestWebhookIdempotencyKey(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get(IdempotencyKeyHeader))
+	}))
+	defer server.Close()
+
+	uconE := GetUconEnforcer()
+	_ = uconE.AddObligation(&Obligation{ID: "notify", Name: "webhook", Kind: "post", Expr: server.URL})
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	if err := uconE.ExecuteObligationsByType(sessionID, "post"); err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 1 || received[0] != IdempotencyKey(sessionID, "notify", "post", 0) {
+		t.Errorf("unexpected Idempotency-Key headers %v", received)
+	}
+}
+
+func TestOngoingObligationAttemptsNotSavedPerExecution(t *testing.T) {
+	store := &countingStore{}
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithSessionStore(store))
+	_ = uconE.RegisterIdempotentObligationHandler("meter", func(expr string, session *Session, key string) error { return nil })
+	_ = uconE.AddObligation(&Obligation{ID: "meter", Name: "meter", Kind: "ongoing", Expr: "1"})
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+
+	saves := store.saves
+	for i := 0; i < 3; i++ {
+		if err := uconE.ExecuteObligationsByType(sessionID, "ongoing"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if store.saves != saves {
+		t.Errorf("Expected ongoing executions not to save the session, got %d saves", store.saves-saves)
+	}
+
+	session, _ := uconE.GetSession(sessionID)
+	_ = session.Stop("done")
+	if attempts := session.ToRecord().ObligationAttempts["ongoing/meter"]; attempts != 3 || store.saves != saves+1 {
+		t.Errorf("Expected the 3 attempts to be saved when the session stops, got %d attempts and %d saves", attempts, store.saves-saves)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestImpersonationCondition(t *testing.T) {
+	session := NewSession(&SessionRecord{ID: "s1", Subject: "alice", StartTime: time.Now(), Attributes: map[string]interface{}{}}, nil, nil)
+	if _, err := checkImpersonation("", session); !errors.Is(err, ErrAttributeNotFound) {
+		t.Errorf("Expected a missing impersonator, got %v", err)
+	}
+	_ = session.UpdateAttribute(AttrImpersonator, "alice")
+	if ok, _ := checkImpersonation("", session); ok {
+		t.Error("Expected subjects not to impersonate themselves")
+	}
+	_ = session.UpdateAttribute(AttrImpersonator, "root")
+	if ok, err := checkImpersonation("1h", session); !ok || err != nil {
+		t.Errorf("Expected a fresh impersonated session to pass, got %v %v", ok, err)
+	}
+	if ok, _ := checkImpersonation("0s", session); ok {
+		t.Error("Expected the duration cap to apply")
+	}
+}
+
+func TestImpersonationAudit(t *testing.T) {
+	uconE := GetUconEnforcer().(*UconEnforcer)
+	var mu sync.Mutex
+	events := make(map[EventType][]Event)
+	uconE.AddEventListener(func(event Event) {
+		mu.Lock()
+		events[event.Type] = append(events[event.Type], event)
+		mu.Unlock()
+	})
+	purposes := []string{"impersonation"}
+	_ = uconE.AddCondition(&Condition{ID: "impersonation", Name: "impersonation", Kind: "always", Expr: "30m", Purposes: purposes})
+	_ = uconE.AddObligation(&Obligation{ID: "impersonation_audit", Name: "impersonation_audit", Kind: "pre", Expr: "TICKET-42", Purposes: purposes})
+
+	own, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{})
+	if granted, err := uconE.EnforceWithSession(own); granted == nil || err != nil {
+		t.Fatalf("Expected alice's own session to be unaffected, got %v", err)
+	}
+	unnamed, _ := uconE.CreateSessionWithPurpose("alice", "read", "document1", "impersonation", map[string]interface{}{})
+	if granted, err := uconE.EnforceWithSession(unnamed); granted != nil || !errors.Is(err, ErrAttributeNotFound) {
+		t.Errorf("Expected an impersonation without impersonator to be denied, got %v", err)
+	}
+
+	sessionID, _ := uconE.CreateSessionWithPurpose("alice", "read", "document1", "impersonation", map[string]interface{}{AttrImpersonator: "root"})
+	granted, err := uconE.EnforceWithSession(sessionID)
+	if granted == nil || err != nil {
+		t.Fatalf("Expected the impersonated session to be granted, got %v", err)
+	}
+	if label := granted.GetAttribute(ImpersonatorLabel); label != "root" {
+		t.Errorf("Expected the session to be labelled, got %v", label)
+	}
+	if mine := uconE.GetMySessions("alice"); mine[len(mine)-1].ExpiresAt.IsZero() {
+		t.Error("Expected the duration cap to expire the session")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if audit := events[EventImpersonation]; len(audit) != 1 || audit[0].Data["impersonator"] != "root" || audit[0].Data["note"] != "TICKET-42" {
+		t.Errorf("Expected one impersonation event, got %+v", audit)
+	}
+	decisions := events[EventAccessDecision]
+	if last := decisions[len(decisions)-1]; last.SessionID != sessionID || last.Data[ImpersonatorLabel] != "root" {
+		t.Errorf("Expected the decision event to carry the impersonator, got %+v", last)
+	}
+}
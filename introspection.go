@@ -0,0 +1,137 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultTokenAttribute is the session attribute holding the OAuth2 access
+// token that IntrospectionProvider introspects.
+const DefaultTokenAttribute = "access_token"
+
+// IntrospectionProvider is an AttributeProvider backed by an OAuth2 token
+// introspection endpoint (RFC 7662). It introspects the access token stored
+// in the session and maps the returned claims into session attributes. When
+// the upstream token is no longer active the session is revoked.
+type IntrospectionProvider struct {
+	// Endpoint is the introspection endpoint URL.
+	Endpoint string
+	// ClientID and ClientSecret authenticate the request with HTTP basic auth.
+	ClientID     string
+	ClientSecret string
+	// TokenAttribute is the session attribute holding the token;
+	// DefaultTokenAttribute if empty.
+	TokenAttribute string
+	// Claims maps introspection claims to session attribute names. If nil,
+	// every claim is stored as "token_<claim>".
+	Claims map[string]string
+	// Client sends the requests; a client with a 5s timeout if nil.
+	Client *http.Client
+}
+
+// NewIntrospectionProvider creates an IntrospectionProvider for endpoint.
+func NewIntrospectionProvider(endpoint string, clientID string, clientSecret string) *IntrospectionProvider {
+	return &IntrospectionProvider{
+		Endpoint:     endpoint,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Client:       &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// FetchAttributes implements AttributeProvider. The "token_active" attribute
+// is set to true; an inactive token returns an error wrapping ErrRevokedByProvider.
+func (p *IntrospectionProvider) FetchAttributes(session *Session) (map[string]interface{}, error) {
+	tokenAttribute := p.TokenAttribute
+	if tokenAttribute == "" {
+		tokenAttribute = DefaultTokenAttribute
+	}
+	token, ok := session.GetAttribute(tokenAttribute).(string)
+	if !ok || token == "" {
+		return nil, fmt.Errorf("%w: session has no %s attribute", ErrRevokedByProvider, tokenAttribute)
+	}
+
+	resp, err := p.introspect(token)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("introspection endpoint returned %s", resp.Status)
+	}
+
+	claims := make(map[string]interface{})
+	if err := json.NewDecoder(resp.Body).Decode(&claims); err != nil {
+		return nil, fmt.Errorf("invalid introspection response: %w", err)
+	}
+	if active, _ := claims["active"].(bool); !active {
+		return nil, fmt.Errorf("%w: token is no longer active", ErrRevokedByProvider)
+	}
+
+	attributes := map[string]interface{}{"token_active": true}
+	if p.Claims == nil {
+		for claim, val := range claims {
+			if claim != "active" {
+				attributes["token_"+claim] = val
+			}
+		}
+		return attributes, nil
+	}
+	for claim, attribute := range p.Claims {
+		if val, ok := claims[claim]; ok {
+			attributes[attribute] = val
+		}
+	}
+	return attributes, nil
+}
+
+// HealthCheck implements AttributeProvider. The endpoint is healthy if it
+// answers an empty introspection request without a server error.
+func (p *IntrospectionProvider) HealthCheck() error {
+	resp, err := p.introspect("")
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("introspection endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+func (p *IntrospectionProvider) introspect(token string) (*http.Response, error) {
+	form := url.Values{"token": {token}, "token_type_hint": {"access_token"}}
+	req, err := http.NewRequest(http.MethodPost, p.Endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.ClientID != "" {
+		req.SetBasicAuth(url.QueryEscape(p.ClientID), url.QueryEscape(p.ClientSecret))
+	}
+
+	client := p.Client
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	return client.Do(req)
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestIntrospectionProvider(t *testing.T) {
+	var revoked atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "ucon" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.FormValue("token") != "token-123" || revoked.Load() {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"active": false})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"active": true, "scope": "read write", "username": "alice",
+		})
+	}))
+	defer server.Close()
+
+	provider := NewIntrospectionProvider(server.URL, "ucon", "secret")
+	provider.Claims = map[string]string{"scope": "scope", "username": "idp_user"}
+	if err := provider.HealthCheck(); err != nil {
+		t.Fatalf("HealthCheck: %v", err)
+	}
+
+	uconE := GetUconEnforcer()
+	_ = uconE.RegisterAttributeProvider("introspection", provider)
+	_ = uconE.AddCondition(&Condition{ID: "can_read", Name: "regex_match", Kind: "always", Expr: "scope:\\bread\\b"})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"access_token": "token-123"})
+	session, err := uconE.EnforceWithSession(sessionID)
+	if err != nil || session == nil {
+		t.Fatalf("Expected access, got %v", err)
+	}
+	if session.GetAttribute("idp_user") != "alice" || session.GetAttribute("token_active") != true {
+		t.Errorf("Expected mapped claims, got %v", session.ToRecord().Attributes)
+	}
+
+	// The token is revoked upstream.
+	revoked.Store(true)
+	time.Sleep(500 * time.Millisecond)
+	if session.IfActive() {
+		t.Fatal("Expected session to be revoked with the upstream token")
+	}
+	if !strings.Contains(session.GetStopReason(), "token is no longer active") {
+		t.Errorf("Unexpected stop reason: %q", session.GetStopReason())
+	}
+	if health := uconE.GetProviderHealth(); !health[0].Healthy {
+		t.Error("Expected an inactive token not to mark the provider unhealthy")
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LabelPrefix marks session attributes that are labels, e.g.
+// "label.customer": "acme". Labels are added as fields to audit log entries
+// and events, and to metrics selected with WithMetricLabels.
+const LabelPrefix = "label."
+
+// MaxMetricLabelValues is the number of distinct values kept per metric
+// label; further values are reported as OtherLabelValue.
+const MaxMetricLabelValues = 100
+
+// OtherLabelValue replaces label values beyond MaxMetricLabelValues.
+const OtherLabelValue = "other"
+
+// LabeledMetrics is implemented by Metrics that accept labels on latency
+// observations, such as customer or environment.
+type LabeledMetrics interface {
+	ObserveLatencyWithLabels(kind string, id string, d time.Duration, labels map[string]string)
+}
+
+// metricLabels bounds the cardinality of the labels reported to metrics.
+type metricLabels struct {
+	keys   []string
+	values map[string]map[string]bool
+	mu     sync.Mutex
+}
+
+// WithMetricLabels reports the given session labels, e.g. "customer" for the
+// label.customer attribute, with every latency observation to Metrics that
+// implement LabeledMetrics, such as the default InMemoryMetrics. Sessions
+// without a label report it as empty; values beyond the first
+// MaxMetricLabelValues of a label are reported as OtherLabelValue, so a label
+// cannot blow up the number of series.
+func WithMetricLabels(keys ...string) Option {
+	return func(u *UconEnforcer) {
+		u.metricLabels = &metricLabels{keys: keys, values: make(map[string]map[string]bool)}
+	}
+}
+
+// GetLabels returns the session labels, without LabelPrefix.
+func (s *Session) GetLabels() map[string]string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	labels := make(map[string]string)
+	for key, val := range s.attributes {
+		if name, ok := strings.CutPrefix(key, LabelPrefix); ok && name != "" {
+			labels[name] = fmt.Sprint(val)
+		}
+	}
+	return labels
+}
+
+// addLabelFields adds the session labels to structured log or event fields.
+func addLabelFields(fields map[string]interface{}, session *Session) map[string]interface{} {
+	for name, val := range session.GetLabels() {
+		fields[LabelPrefix+name] = val
+	}
+	return fields
+}
+
+// bounded returns the metric labels of a session.
+func (m *metricLabels) bounded(session *Session) map[string]string {
+	labels := session.GetLabels()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bounded := make(map[string]string, len(m.keys))
+	for _, key := range m.keys {
+		val := labels[key]
+		seen := m.values[key]
+		if seen == nil {
+			seen = make(map[string]bool)
+			m.values[key] = seen
+		}
+		if !seen[val] && len(seen) >= MaxMetricLabelValues {
+			val = OtherLabelValue
+		} else {
+			seen[val] = true
+		}
+		bounded[key] = val
+	}
+	return bounded
+}
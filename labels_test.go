@@ -0,0 +1,118 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type labeledMetrics struct {
+	*InMemoryMetrics
+	labels []map[string]string
+	mu     sync.Mutex
+}
+
+func (m *labeledMetrics) ObserveLatencyWithLabels(kind string, id string, d time.Duration, labels map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.labels = append(m.labels, labels)
+}
+
+func TestSessionLabels(t *testing.T) {
+	var logs bytes.Buffer
+	metrics := &labeledMetrics{InMemoryMetrics: NewInMemoryMetrics()}
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e,
+		WithLogger(&DefaultLogger{Level: LevelInfo, Writer: &logs}),
+		WithMetrics(metrics),
+		WithMetricLabels("customer"))
+	_ = uconE.AddObligation(&Obligation{ID: "log", Name: "access_logging", Kind: "pre", Expr: "access"})
+
+	var stopped Event
+	uconE.AddEventListener(func(event Event) {
+		if event.Type == EventSessionStopped {
+			stopped = event
+		}
+	})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{
+		"label.customer": "acme",
+		"label.env":      "prod",
+		"role":           "user",
+	})
+	session, _ := uconE.GetSession(sessionID)
+	if labels := session.GetLabels(); len(labels) != 2 || labels["customer"] != "acme" {
+		t.Fatalf("Unexpected labels %v", labels)
+	}
+	if err := uconE.ExecuteObligationsByType(sessionID, "pre"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(logs.String(), "label.customer=acme") || !strings.Contains(logs.String(), "label.env=prod") {
+		t.Errorf("Expected the labels in the audit log, got %s", logs.String())
+	}
+	_ = session.Stop("done")
+	if stopped.Data["label.customer"] != "acme" {
+		t.Errorf("Expected the labels in the stop event, got %v", stopped.Data)
+	}
+
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	if len(metrics.labels) != 1 || len(metrics.labels[0]) != 1 || metrics.labels[0]["customer"] != "acme" {
+		t.Errorf("Expected only the selected label on metrics, got %v", metrics.labels)
+	}
+}
+
+func TestMetricLabelCardinality(t *testing.T) {
+	labels := &metricLabels{keys: []string{"customer"}, values: make(map[string]map[string]bool)}
+	uconE := GetUconEnforcer()
+	var last map[string]string
+	for i := 0; i <= MaxMetricLabelValues; i++ {
+		sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"label.customer": fmt.Sprint("c", i)})
+		session, _ := uconE.GetSession(sessionID)
+		last = labels.bounded(session)
+	}
+	if last["customer"] != OtherLabelValue {
+		t.Errorf("Expected values beyond the limit to be reported as %s, got %v", OtherLabelValue, last)
+	}
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"label.customer": "c1"})
+	session, _ := uconE.GetSession(sessionID)
+	if got := labels.bounded(session); got["customer"] != "c1" {
+		t.Errorf("Expected known values to be kept, got %v", got)
+	}
+}
+
+func TestInMemoryMetricsLabels(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithMetricLabels("customer", "env"))
+	_ = uconE.AddObligation(&Obligation{ID: "log", Name: "access_logging", Kind: "pre", Expr: "access"})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"label.customer": "acme"})
+	if err := uconE.ExecuteObligationsByType(sessionID, "pre"); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot := uconE.GetMetrics().(*InMemoryMetrics).Snapshot()
+	if h := snapshot.LabeledLatency[MetricObligation]["log"]["customer=acme,env="]; h == nil || h.Count != 1 {
+		t.Errorf("Expected the observation under its labels, got %+v", snapshot.LabeledLatency)
+	}
+	if h := snapshot.Latency[MetricObligation]["log"]; h == nil || h.Count != 1 {
+		t.Errorf("Expected the observation to be kept unlabeled too, got %+v", snapshot.Latency)
+	}
+}
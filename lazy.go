@@ -0,0 +1,152 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SessionExpiredReason is the stop reason of lazily governed sessions that
+// outlived their TTL, see WithLazyMonitoring.
+const SessionExpiredReason = "session expired"
+
+// ErrSessionExpired is returned when enforcing a lazily governed session that
+// outlived its TTL.
+var ErrSessionExpired = errors.New("session expired")
+
+// WithLazyMonitoring skips monitoring for sessions nothing can revoke while
+// they are in use: no condition and no ongoing obligation applies to them
+// after purpose and break-glass targeting, and the enforcer has no
+// heartbeats, analyzer or attribute providers. Such sessions take no
+// monitoring slot or goroutine; they are stopped with SessionExpiredReason
+// once ttl has passed since they started. Every EnforceWithSession still
+// evaluates them in full, so a session that rules added later apply to is
+// monitored from its next grant on. A ttl <= 0 monitors every session.
+func WithLazyMonitoring(ttl time.Duration) Option {
+	return func(u *UconEnforcer) {
+		u.lazyTTL = ttl
+	}
+}
+
+// needsMonitoring reports whether anything checked at monitoring ticks
+// applies to a session.
+func (u *UconEnforcer) needsMonitoring(session *Session) bool {
+	if u.heartbeatInterval > 0 || u.analyzer != nil || session.IsBreakGlass() || len(u.providerList()) > 0 {
+		return true
+	}
+	if u.hasPendingFulfillment(session) {
+		return true
+	}
+	for _, condition := range u.conditionList() {
+		if conditionApplies(&condition, session) {
+			return true
+		}
+	}
+	for _, obligation := range u.obligationList() {
+		if obligation.Kind == "ongoing" && obligationApplies(&obligation, session) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPendingFulfillment reports whether the caller still has to acknowledge
+// an obligation of the session.
+func (u *UconEnforcer) hasPendingFulfillment(session *Session) bool {
+	session.mutex.RLock()
+	defer session.mutex.RUnlock()
+	for _, f := range session.fulfillments {
+		if !f.Fulfilled() {
+			return true
+		}
+	}
+	return false
+}
+
+// governLazily arms the TTL of a session instead of monitoring it, if lazy
+// monitoring is on and the session needs no monitoring. It returns whether
+// the session is governed lazily.
+func (u *UconEnforcer) governLazily(session *Session) bool {
+	if u.lazyTTL <= 0 {
+		return false
+	}
+	id := session.GetId()
+	u.mu.RLock()
+	monitored := u.monitoringActive[id]
+	u.mu.RUnlock()
+	if monitored {
+		return false
+	}
+	if u.needsMonitoring(session) {
+		u.endLazy(id)
+		return false
+	}
+
+	expires := session.GetStartTime().Add(u.lazyTTL)
+	u.mu.Lock()
+	if _, ok := u.lazySessions[id]; ok {
+		u.mu.Unlock()
+		return true
+	}
+	if u.lazySessions == nil {
+		u.lazySessions = make(map[string]*time.Timer)
+	}
+	u.lazySessions[id] = time.AfterFunc(time.Until(expires), func() { u.expireLazy(session) })
+	u.mu.Unlock()
+
+	fields := sessionFields(session)
+	fields["expires"] = expires
+	u.logger.Log(LevelDebug, "monitoring skipped", fields)
+	return true
+}
+
+// expireLazy stops a lazily governed session at the end of its TTL.
+func (u *UconEnforcer) expireLazy(session *Session) {
+	u.mu.Lock()
+	_, lazy := u.lazySessions[session.GetId()]
+	delete(u.lazySessions, session.GetId())
+	u.mu.Unlock()
+	if lazy {
+		_ = session.Stop(SessionExpiredReason)
+	}
+}
+
+// endLazy disarms the TTL of a session that stopped or is monitored now.
+func (u *UconEnforcer) endLazy(sessionID string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if timer, ok := u.lazySessions[sessionID]; ok {
+		timer.Stop()
+		delete(u.lazySessions, sessionID)
+	}
+}
+
+// checkLazyExpiry stops a session that outlived its TTL without being
+// monitored, e.g. after a restart dropped its timer.
+func (u *UconEnforcer) checkLazyExpiry(session *Session, now time.Time) error {
+	if u.lazyTTL <= 0 || now.Before(session.GetStartTime().Add(u.lazyTTL)) {
+		return nil
+	}
+	u.mu.RLock()
+	monitored := u.monitoringActive[session.GetId()]
+	u.mu.RUnlock()
+	if monitored || u.needsMonitoring(session) {
+		return nil
+	}
+	_ = session.Stop(SessionExpiredReason)
+	return fmt.Errorf("session %s: %w", session.GetId(), ErrSessionExpired)
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func isMonitored(u *UconEnforcer, sessionID string) bool {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.monitoringActive[sessionID]
+}
+
+func TestLazyMonitoring(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	u := NewUconEnforcer(e, WithLazyMonitoring(300*time.Millisecond)).(*UconEnforcer)
+	// Targeted at other purposes, the condition does not apply.
+	_ = u.AddCondition(&Condition{ID: "dept", Name: "attribute_equals", Kind: "always", Expr: "department:billing", Purposes: []string{"billing"}})
+
+	sessionID, _ := u.CreateSession("alice", "read", "document1", nil)
+	session, err := u.EnforceWithSession(sessionID)
+	if session == nil || err != nil {
+		t.Fatalf("Expected access, got %v", err)
+	}
+	if isMonitored(u, sessionID) {
+		t.Error("Expected a session no rule applies to not to be monitored")
+	}
+	waitFor(t, func() bool { return !session.IfActive() })
+	if session.GetStopReason() != SessionExpiredReason {
+		t.Errorf("Expected the session to expire, got %q", session.GetStopReason())
+	}
+
+	monitoredID, _ := u.CreateSessionWithPurpose("alice", "read", "document1", "billing", map[string]interface{}{"department": "billing"})
+	monitored, _ := u.EnforceWithSession(monitoredID)
+	if monitored == nil || !isMonitored(u, monitoredID) {
+		t.Fatal("Expected a session a condition applies to to be monitored")
+	}
+	time.Sleep(400 * time.Millisecond)
+	if !monitored.IfActive() {
+		t.Error("Expected the TTL not to apply to monitored sessions")
+	}
+	_ = u.StopMonitoring(monitoredID)
+}
+
+func TestLazyMonitoringUpgrade(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	u := NewUconEnforcer(e, WithLazyMonitoring(300*time.Millisecond)).(*UconEnforcer)
+	sessionID, _ := u.CreateSession("alice", "read", "document1", map[string]interface{}{"department": "eng"})
+	session, _ := u.EnforceWithSession(sessionID)
+	if session == nil || isMonitored(u, sessionID) {
+		t.Fatal("Expected an unmonitored grant")
+	}
+
+	// A rule that applies now is monitored from the next grant on.
+	_ = u.AddCondition(&Condition{ID: "dept", Name: "attribute_equals", Kind: "always", Expr: "department:eng"})
+	if session, _ = u.EnforceWithSession(sessionID); session == nil || !isMonitored(u, sessionID) {
+		t.Fatal("Expected the session to be monitored once a condition applies")
+	}
+	time.Sleep(400 * time.Millisecond)
+	if !session.IfActive() {
+		t.Errorf("Expected the TTL to be disarmed, got %q", session.GetStopReason())
+	}
+	_ = u.StopMonitoring(sessionID)
+}
+
+func TestLazyMonitoringExpiredOnEnforce(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	u := NewUconEnforcer(e, WithLazyMonitoring(50*time.Millisecond))
+	sessionID, _ := u.CreateSession("alice", "read", "document1", nil)
+	time.Sleep(100 * time.Millisecond)
+
+	session, err := u.EnforceWithSession(sessionID)
+	if session != nil || !errors.Is(err, ErrSessionExpired) {
+		t.Fatalf("Expected ErrSessionExpired, got %v", err)
+	}
+	if ReasonFor(err) != ReasonSessionRevoked {
+		t.Errorf("Expected %s, got %s", ReasonSessionRevoked, ReasonFor(err))
+	}
+	if stopped, _ := u.GetSession(sessionID); stopped.IfActive() {
+		t.Error("Expected the expired session to be stopped")
+	}
+}
+
+func TestLazyMonitoringNeedsMonitoring(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	tests := []struct {
+		name       string
+		opts       []Option
+		obligation *Obligation
+		want       bool
+	}{
+		{"no rules", nil, nil, false},
+		{"pre obligation", nil, &Obligation{ID: "mark", Name: "watermark", Kind: "pre", Expr: "alice"}, false},
+		{"ongoing obligation", nil, &Obligation{ID: "count", Name: "increment_counter", Kind: "ongoing", Expr: "ticks"}, true},
+		{"break-glass obligation", nil, &Obligation{ID: "alert", Name: "webhook", Kind: "ongoing", BreakGlass: true}, false},
+		{"heartbeats", []Option{WithHeartbeat(time.Second, 1)}, nil, true},
+	}
+	for _, tt := range tests {
+		u := NewUconEnforcer(e, append(tt.opts, WithLazyMonitoring(time.Minute))...).(*UconEnforcer)
+		if tt.obligation != nil {
+			if err := u.AddObligation(tt.obligation); err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+		}
+		sessionID, _ := u.CreateSession("alice", "read", "document1", nil)
+		session, _ := u.GetSession(sessionID)
+		if got := u.needsMonitoring(session); got != tt.want {
+			t.Errorf("%s: needsMonitoring = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,332 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultLeaseTTL is how long a monitoring lease lasts without renewal.
+const DefaultLeaseTTL = 10 * time.Second
+
+// Monitoring lease event types.
+const (
+	// EventMonitoringTakeover is emitted when an enforcer takes over the
+	// monitoring of a session whose lease expired, e.g. because the node
+	// monitoring it died.
+	EventMonitoringTakeover EventType = "monitoring_takeover"
+	// EventMonitoringLost is emitted when an enforcer stops monitoring a
+	// session because another node took over its lease.
+	EventMonitoringLost EventType = "monitoring_lost"
+)
+
+// LeaseStore is implemented by session stores shared by several enforcers
+// that can grant expiring, exclusive leases on sessions. With
+// WithMonitoringLeases, only the holder of a session's lease monitors it.
+type LeaseStore interface {
+	// AcquireLease grants the lease on a session to owner for ttl, or renews
+	// it if owner already holds it. It returns false if another owner holds
+	// an unexpired lease.
+	AcquireLease(id string, owner string, ttl time.Duration) (bool, error)
+	// ReleaseLease gives up the lease of owner on a session. Releasing a
+	// lease held by another owner, or none, is not an error.
+	ReleaseLease(id string, owner string) error
+}
+
+// WithMonitoringLeases makes enforcers sharing a session store that
+// implements LeaseStore agree on which of them monitors each session: node
+// identifies this enforcer, and a node that stops renewing its leases for
+// ttl (DefaultLeaseTTL if 0) loses them to the others, see
+// WatchMonitoringLeases. Without a LeaseStore the option has no effect.
+func WithMonitoringLeases(node string, ttl time.Duration) Option {
+	return func(u *UconEnforcer) {
+		if ttl <= 0 {
+			ttl = DefaultLeaseTTL
+		}
+		u.leaseNode, u.leaseTTL = node, ttl
+	}
+}
+
+// leases returns the lease store if monitoring leases are enabled.
+func (u *UconEnforcer) leases() (LeaseStore, bool) {
+	if u.leaseNode == "" || u.heartbeatCoordination {
+		return nil, false
+	}
+	leases, ok := u.store.(LeaseStore)
+	return leases, ok
+}
+
+// acquireLease takes or renews the monitoring lease of a session. Store
+// errors are logged and count as success, so an unreachable store does not
+// leave sessions unmonitored.
+func (u *UconEnforcer) acquireLease(session *Session) bool {
+	acquired, err := u.claim(session.GetId())
+	if err != nil {
+		fields := sessionFields(session)
+		fields["node"] = u.leaseNode
+		fields["error"] = err.Error()
+		u.logger.Log(LevelWarn, "failed to acquire monitoring lease", fields)
+		return true
+	}
+	return acquired
+}
+
+// claim acquires the lease of a session, or claims it under heartbeat
+// coordination, in which case the session takes the fencing token. Without
+// coordination it always succeeds.
+func (u *UconEnforcer) claim(sessionID string) (bool, error) {
+	if heartbeats, ok := u.heartbeats(); ok {
+		fence, claimed, err := heartbeats.ClaimSession(sessionID, u.leaseNode)
+		if err != nil || !claimed {
+			return false, err
+		}
+		if session, err := u.GetSession(sessionID); err == nil {
+			session.setFence(fence)
+		}
+		return true, nil
+	}
+	if leases, ok := u.leases(); ok {
+		return leases.AcquireLease(sessionID, u.leaseNode, u.leaseTTL)
+	}
+	return true, nil
+}
+
+func (u *UconEnforcer) releaseLease(sessionID string) {
+	var err error
+	if heartbeats, ok := u.heartbeats(); ok {
+		err = heartbeats.ReleaseSession(sessionID, u.leaseNode)
+		if session, getErr := u.GetSession(sessionID); getErr == nil {
+			session.setFence(0)
+		}
+	} else if leases, ok := u.leases(); ok {
+		err = leases.ReleaseLease(sessionID, u.leaseNode)
+	}
+	if err != nil {
+		u.logger.Log(LevelWarn, "failed to release monitoring lease", map[string]interface{}{"session": sessionID, "node": u.leaseNode, "error": err.Error()})
+	}
+}
+
+// renewLease renews the lease of a monitored session once a third of its TTL
+// has passed since the last renewal. It returns false if another node took
+// the lease over. Under heartbeat coordination there is nothing to renew.
+func (u *UconEnforcer) renewLease(session *Session, now time.Time, state *monitorState) bool {
+	if _, ok := u.leases(); !ok || now.Sub(state.leaseRenewed) < u.leaseTTL/3 {
+		return true
+	}
+	if !u.acquireLease(session) {
+		return false
+	}
+	state.leaseRenewed = now
+	return true
+}
+
+// loseLease stops monitoring a session leased by another node. The session
+// itself goes on, monitored by the new owner.
+func (u *UconEnforcer) loseLease(session *Session) {
+	u.releaseMonitoring(session.GetId())
+	fields := sessionFields(session)
+	fields["node"] = u.leaseNode
+	u.logger.Log(LevelWarn, "monitoring lease lost", fields)
+	u.emit(Event{
+		Type:      EventMonitoringLost,
+		SessionID: session.GetId(),
+		Message:   fmt.Sprintf("monitoring of session %s taken over by another node", session.GetId()),
+		Data:      map[string]interface{}{"node": u.leaseNode},
+	})
+}
+
+// WatchMonitoringLeases takes over the monitoring of sessions whose lease
+// expired, checking the store every half lease TTL, until ctx is done. Under
+// heartbeat coordination it also sends the node's heartbeats, every third of
+// the TTL, and must run on every node. It returns ctx.Err(), or an error if
+// neither monitoring leases nor heartbeat coordination are enabled.
+func (u *UconEnforcer) WatchMonitoringLeases(ctx context.Context) error {
+	_, leases := u.leases()
+	_, heartbeats := u.heartbeats()
+	if !leases && !heartbeats {
+		return errors.New("monitoring leases require WithMonitoringLeases and a session store implementing LeaseStore, or WithHeartbeatCoordination and a HeartbeatStore")
+	}
+	period := u.leaseTTL / 2
+	if heartbeats {
+		period = u.leaseTTL / 3
+	}
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		if heartbeats {
+			u.heartbeat(time.Now())
+		}
+		if err := u.claimExpiredLeases(); err != nil {
+			u.logger.Log(LevelWarn, "failed to claim monitoring leases", map[string]interface{}{"node": u.leaseNode, "error": err.Error()})
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// claimExpiredLeases starts monitoring the stored sessions that should be
+// monitored and whose lease this node can acquire.
+func (u *UconEnforcer) claimExpiredLeases() error {
+	records, err := u.store.LoadSessions()
+	if err != nil {
+		return fmt.Errorf("failed to load sessions: %w", err)
+	}
+	for _, record := range records {
+		if !record.Active || !record.Monitored || record.SuspendedUntil != nil {
+			continue
+		}
+		u.mu.RLock()
+		monitored := u.monitoringActive[record.ID]
+		u.mu.RUnlock()
+		if monitored {
+			continue
+		}
+		acquired, err := u.claim(record.ID)
+		if err != nil {
+			return fmt.Errorf("failed to acquire lease of session %s: %w", record.ID, err)
+		}
+		if !acquired {
+			continue
+		}
+		u.takeOverSession(record)
+	}
+	return nil
+}
+
+// takeOverSession starts monitoring a session whose lease was just acquired,
+// from its latest stored state.
+func (u *UconEnforcer) takeOverSession(record *SessionRecord) {
+	session, restored := u.restoreSession(record)
+	if !restored {
+		session.applyRecord(record)
+		u.gauges.update(session)
+	}
+	interval, err := u.reserveMonitoring(session)
+	if err != nil || interval == 0 {
+		u.releaseLease(record.ID)
+		return
+	}
+	u.startMonitoring(session, interval)
+	u.emit(Event{
+		Type:      EventMonitoringTakeover,
+		SessionID: record.ID,
+		Message:   fmt.Sprintf("monitoring of session %s taken over by %s", record.ID, u.leaseNode),
+		Data:      map[string]interface{}{"node": u.leaseNode},
+	})
+}
+
+// fileLease is the content of a FileSessionStore lease file.
+type fileLease struct {
+	Owner   string    `json:"owner"`
+	Expires time.Time `json:"expires"`
+}
+
+// fileLeaseLockTimeout is how old a lock file must be to be considered left
+// behind by a crashed process.
+const fileLeaseLockTimeout = 5 * time.Second
+
+// AcquireLease implements LeaseStore. Leases are kept in "<id>.lease" files
+// next to the sessions; a lock file makes acquisition atomic across
+// processes sharing the directory.
+func (fs *FileSessionStore) AcquireLease(id string, owner string, ttl time.Duration) (bool, error) {
+	acquired := false
+	err := fs.withLock(id, ".lease", func(path string) error {
+		lease := fileLease{}
+		if data, err := os.ReadFile(path); err == nil {
+			if err := json.Unmarshal(data, &lease); err != nil {
+				return fmt.Errorf("failed to decode lease of session %s: %w", id, err)
+			}
+		} else if !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read lease of session %s: %w", id, err)
+		}
+		now := time.Now()
+		if lease.Owner != "" && lease.Owner != owner && now.Before(lease.Expires) {
+			return nil
+		}
+		data, err := json.Marshal(fileLease{Owner: owner, Expires: now.Add(ttl)})
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			return fmt.Errorf("failed to write lease of session %s: %w", id, err)
+		}
+		acquired = true
+		return nil
+	})
+	return acquired, err
+}
+
+// ReleaseLease implements LeaseStore.
+func (fs *FileSessionStore) ReleaseLease(id string, owner string) error {
+	return fs.withLock(id, ".lease", func(path string) error {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read lease of session %s: %w", id, err)
+		}
+		lease := fileLease{}
+		if err := json.Unmarshal(data, &lease); err != nil || lease.Owner != owner {
+			return nil
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to release lease of session %s: %w", id, err)
+		}
+		return nil
+	})
+}
+
+// withLock runs fn with the path of the lease or owner file of a session,
+// named after the session with the given suffix, while holding its lock file.
+func (fs *FileSessionStore) withLock(id string, suffix string, fn func(path string) error) error {
+	path, err := fs.path(id)
+	if err != nil {
+		return err
+	}
+	path = strings.TrimSuffix(path, "."+fs.codec.Name()) + suffix
+	lock := path + ".lock"
+
+	deadline := time.Now().Add(fileLeaseLockTimeout)
+	for {
+		f, err := os.OpenFile(lock, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			f.Close()
+			break
+		}
+		if !os.IsExist(err) {
+			return fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if info, err := os.Stat(lock); err == nil && time.Since(info.ModTime()) > fileLeaseLockTimeout {
+			_ = os.Remove(lock)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out locking %s", path)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	defer os.Remove(lock)
+	return fn(path)
+}
@@ -0,0 +1,158 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileSessionStoreLeases(t *testing.T) {
+	for _, codec := range []Codec{JSONCodec{}, ProtobufCodec{}} {
+		dir := t.TempDir()
+		store, _ := NewFileSessionStoreWithCodec(dir, codec)
+		acquire := func(owner string, ttl time.Duration, want bool) {
+			t.Helper()
+			acquired, err := store.AcquireLease("s1", owner, ttl)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if acquired != want {
+				t.Errorf("%s: expected %s acquiring the lease to be %v", codec.Name(), owner, want)
+			}
+		}
+		acquire("node1", 50*time.Millisecond, true)
+		acquire("node2", time.Minute, false)
+		acquire("node1", 50*time.Millisecond, true)
+		time.Sleep(60 * time.Millisecond)
+		acquire("node2", time.Minute, true)
+		acquire("node1", time.Minute, false)
+
+		if err := store.ReleaseLease("s1", "node1"); err != nil {
+			t.Fatal(err)
+		}
+		acquire("node1", time.Minute, false)
+		if err := store.ReleaseLease("s1", "node2"); err != nil {
+			t.Fatal(err)
+		}
+		acquire("node1", time.Minute, true)
+		if _, err := os.Stat(filepath.Join(dir, "s1.lease")); err != nil {
+			t.Errorf("%s: expected the lease file to be named after the session: %v", codec.Name(), err)
+		}
+
+		records, err := store.LoadSessions()
+		if err != nil || len(records) != 0 {
+			t.Errorf("%s: expected lease files not to be loaded as sessions, got %d, %v", codec.Name(), len(records), err)
+		}
+	}
+}
+
+func TestMonitoringLeaseTakeover(t *testing.T) {
+	dir := t.TempDir()
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	ttl := 300 * time.Millisecond
+	newNode := func(node string) (*UconEnforcer, chan Event) {
+		store, _ := NewFileSessionStore(dir)
+		u := NewUconEnforcer(e, WithSessionStore(store), WithMonitoringLeases(node, ttl)).(*UconEnforcer)
+		events := make(chan Event, 16)
+		u.AddEventListener(func(event Event) {
+			if event.Type == EventMonitoringTakeover || event.Type == EventMonitoringLost {
+				events <- event
+			}
+		})
+		return u, events
+	}
+	nodeA, eventsA := newNode("a")
+	nodeB, eventsB := newNode("b")
+
+	sessionID, _ := nodeA.CreateSession("alice", "read", "document1", nil)
+	if _, err := nodeA.EnforceWithSession(sessionID); err != nil {
+		t.Fatal(err)
+	}
+	if err := nodeB.claimExpiredLeases(); err != nil {
+		t.Fatal(err)
+	}
+	if nodeB.MonitoredSessionCount() != 0 {
+		t.Fatal("Expected node b to leave the session to node a")
+	}
+
+	// Node a dies: its monitoring stops without giving up the lease.
+	nodeA.mu.Lock()
+	delete(nodeA.monitoringActive, sessionID)
+	nodeA.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- nodeB.WatchMonitoringLeases(ctx) }()
+	select {
+	case event := <-eventsB:
+		if event.Type != EventMonitoringTakeover || event.SessionID != sessionID || event.Data["node"] != "b" {
+			t.Errorf("Unexpected event %+v", event)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected node b to take over the session")
+	}
+	if nodeB.MonitoredSessionCount() != 1 {
+		t.Error("Expected node b to monitor the session")
+	}
+
+	// Node a comes back but cannot monitor the session any more.
+	if err := nodeA.StartMonitoring(sessionID); err != nil {
+		t.Fatal(err)
+	}
+	if nodeA.MonitoredSessionCount() != 0 {
+		t.Error("Expected node a to leave the session to node b")
+	}
+
+	// A node whose lease is taken over stops monitoring.
+	store, _ := NewFileSessionStore(dir)
+	store.ReleaseLease(sessionID, "b")
+	store.AcquireLease(sessionID, "c", time.Minute)
+	select {
+	case event := <-eventsB:
+		if event.Type != EventMonitoringLost {
+			t.Errorf("Expected EventMonitoringLost, got %s", event.Type)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected node b to lose the session")
+	}
+	if nodeB.MonitoredSessionCount() != 0 {
+		t.Error("Expected node b to stop monitoring")
+	}
+	session, _ := nodeB.GetSession(sessionID)
+	if !session.IfActive() {
+		t.Error("Expected the session to stay active")
+	}
+	if len(eventsA) != 0 {
+		t.Errorf("Expected no events on node a, got %d", len(eventsA))
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestWatchMonitoringLeasesRequiresLeaseStore(t *testing.T) {
+	uconE := GetUconEnforcer()
+	if err := uconE.WatchMonitoringLeases(context.Background()); err == nil {
+		t.Error("Expected an error without monitoring leases")
+	}
+}
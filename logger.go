@@ -0,0 +1,156 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogLevel is the severity of a log entry.
+type LogLevel int
+
+const (
+	LevelDebug LogLevel = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	default:
+		return fmt.Sprintf("LEVEL(%d)", int(l))
+	}
+}
+
+// Logger receives structured log entries from the enforcer and its handlers.
+type Logger interface {
+	Log(level LogLevel, msg string, fields map[string]interface{})
+}
+
+// DefaultLogger writes entries at or above Level as single text lines:
+//
+//	2025-01-02T15:04:05Z INFO access granted object=document1 subject=alice
+type DefaultLogger struct {
+	Level  LogLevel
+	Writer io.Writer // os.Stdout if nil
+
+	mutex sync.Mutex
+}
+
+// NewDefaultLogger creates a DefaultLogger writing to stdout.
+func NewDefaultLogger(level LogLevel) *DefaultLogger {
+	return &DefaultLogger{Level: level}
+}
+
+// Log writes the entry if its level is enabled.
+func (l *DefaultLogger) Log(level LogLevel, msg string, fields map[string]interface{}) {
+	if level < l.Level {
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString(time.Now().UTC().Format(time.RFC3339))
+	b.WriteByte(' ')
+	b.WriteString(level.String())
+	b.WriteByte(' ')
+	b.WriteString(msg)
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%v", k, fields[k])
+	}
+	b.WriteByte('\n')
+
+	w := l.Writer
+	if w == nil {
+		w = os.Stdout
+	}
+	l.mutex.Lock()
+	_, _ = io.WriteString(w, b.String())
+	l.mutex.Unlock()
+}
+
+// WithLogger sets the logger used by the enforcer and the built-in handlers.
+func WithLogger(logger Logger) Option {
+	return func(u *UconEnforcer) {
+		if logger != nil {
+			u.logger = logger
+		}
+	}
+}
+
+// DefaultMonitorLogInterval is how often the status of a monitored session is
+// logged unless WithMonitorLogInterval sets another interval.
+const DefaultMonitorLogInterval = time.Minute
+
+// WithMonitorLogInterval sets how often the monitor logs, at debug level, that
+// a session is still valid: at most once per interval per session, with the
+// number of evaluations since the previous entry. 0 logs every evaluation and
+// a negative interval turns the status entries off.
+func WithMonitorLogInterval(interval time.Duration) Option {
+	return func(u *UconEnforcer) {
+		u.monitorLogInterval = interval
+	}
+}
+
+// logMonitorStatus logs that a session passed an evaluation, rate-limited
+// by the monitor log interval.
+func (u *UconEnforcer) logMonitorStatus(session *Session, state *monitorState, now time.Time) {
+	if u.monitorLogInterval < 0 {
+		return
+	}
+	state.evaluations++
+	if !state.loggedAt.IsZero() && now.Sub(state.loggedAt) < u.monitorLogInterval {
+		return
+	}
+	fields := sessionFields(session)
+	fields["evaluations"] = state.evaluations
+	u.logger.Log(LevelDebug, "session still valid", fields)
+	state.loggedAt = now
+	state.evaluations = 0
+}
+
+// sessionFields returns the standard log fields identifying a session.
+func sessionFields(session *Session) map[string]interface{} {
+	fields := map[string]interface{}{
+		"session_id": session.GetId(),
+		"subject":    session.GetSubject(),
+		"action":     session.GetAction(),
+		"object":     session.GetObject(),
+	}
+	if purpose := session.GetPurpose(); purpose != "" {
+		fields["purpose"] = purpose
+	}
+	return addLabelFields(fields, session)
+}
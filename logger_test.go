@@ -0,0 +1,93 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingLogger counts entries by message.
+type countingLogger struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (l *countingLogger) Log(level LogLevel, msg string, fields map[string]interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.counts == nil {
+		l.counts = make(map[string]int)
+	}
+	l.counts[msg]++
+}
+
+func (l *countingLogger) count(msg string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.counts[msg]
+}
+
+func TestMonitorLogInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		min, max int
+	}{
+		{"default", DefaultMonitorLogInterval, 1, 1},
+		{"every evaluation", 0, 3, 6},
+		{"off", -1, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := GetUconEnforcer().(*UconEnforcer).Enforcer
+			logger := &countingLogger{}
+			uconE := NewUconEnforcer(e, WithLogger(logger), WithMonitorLogInterval(tt.interval))
+			sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+			if _, err := uconE.EnforceWithSession(sessionID); err != nil {
+				t.Fatal(err)
+			}
+			time.Sleep(5*DefaultMonitorInterval - DefaultMonitorInterval/2)
+			_ = uconE.StopMonitoring(sessionID)
+
+			if got := logger.count("session still valid"); got < tt.min || got > tt.max {
+				t.Errorf("Expected %d to %d status entries, got %d", tt.min, tt.max, got)
+			}
+			if logger.count("monitoring started") != 1 || logger.count("monitoring stopped") != 1 {
+				t.Errorf("Expected monitoring start and stop to be logged, got %v", logger.counts)
+			}
+		})
+	}
+}
+
+func TestPreObligationFailureIsLogged(t *testing.T) {
+	logger := &countingLogger{}
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithLogger(logger))
+	_ = uconE.RegisterObligationHandler("unreachable", func(expr string, session *Session) error {
+		return errors.New("connection refused")
+	})
+	_ = uconE.AddObligation(&Obligation{ID: "notify", Name: "unreachable", Kind: "pre"})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	if session, err := uconE.EnforceWithSession(sessionID); session != nil || err == nil {
+		t.Fatalf("Expected a failed pre obligation to deny access, got %v, %v", session, err)
+	}
+	if n := logger.count("failed to execute pre-access obligations"); n != 1 {
+		t.Errorf("Expected the failure to be logged once, got %d", n)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"time"
+)
+
+// MaintenanceReason is the stop reason of sessions ended by a maintenance window.
+const MaintenanceReason = "object under maintenance"
+
+// EventMaintenanceWarning is emitted once per session and window when a
+// maintenance window of the session's object is about to start.
+const EventMaintenanceWarning EventType = "maintenance_warning"
+
+// ErrUnderMaintenance is wrapped by EnforceWithSession errors for sessions
+// whose object is in a maintenance window.
+var ErrUnderMaintenance = errors.New("object under maintenance")
+
+// MaintenanceWindow is a period during which objects are unavailable: new
+// sessions on them are denied and ongoing ones are ended when it starts.
+type MaintenanceWindow struct {
+	ID string `json:"id"`
+	// Objects are path.Match patterns of the objects under maintenance,
+	// e.g. "reports/*"; if empty the window covers every object.
+	Objects []string  `json:"objects,omitempty"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	// Warning is how long before Start the sessions on the objects receive
+	// an EventMaintenanceWarning.
+	Warning time.Duration `json:"warning,omitempty"`
+	Message string        `json:"message,omitempty"`
+}
+
+// covers reports whether the window applies to object.
+func (w *MaintenanceWindow) covers(object string) bool {
+	if len(w.Objects) == 0 {
+		return true
+	}
+	for _, pattern := range w.Objects {
+		if ok, _ := path.Match(pattern, object); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *MaintenanceWindow) activeAt(t time.Time) bool {
+	return !t.Before(w.Start) && t.Before(w.End)
+}
+
+// AddMaintenanceWindow adds or replaces a maintenance window.
+func (u *UconEnforcer) AddMaintenanceWindow(window *MaintenanceWindow) error {
+	if window == nil {
+		return errors.New("maintenance window cannot be nil")
+	}
+	if window.ID == "" {
+		return errors.New("maintenance window ID cannot be empty")
+	}
+	if !window.End.After(window.Start) {
+		return fmt.Errorf("maintenance window %s must end after it starts", window.ID)
+	}
+	for _, pattern := range window.Objects {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("maintenance window %s: invalid object pattern %q: %w", window.ID, pattern, err)
+		}
+	}
+	w := *window
+	w.Objects = append([]string(nil), window.Objects...)
+	u.mu.Lock()
+	if u.maintenance == nil {
+		u.maintenance = make(map[string]MaintenanceWindow)
+	}
+	u.maintenance[w.ID] = w
+	u.mu.Unlock()
+	u.InvalidateDecisions()
+	return nil
+}
+
+// RemoveMaintenanceWindow removes a maintenance window, e.g. to end it early.
+func (u *UconEnforcer) RemoveMaintenanceWindow(id string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if _, ok := u.maintenance[id]; !ok {
+		return fmt.Errorf("maintenance window %s not found", id)
+	}
+	delete(u.maintenance, id)
+	return nil
+}
+
+// GetMaintenanceWindows returns the maintenance windows, ordered by start.
+func (u *UconEnforcer) GetMaintenanceWindows() []MaintenanceWindow {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	windows := make([]MaintenanceWindow, 0, len(u.maintenance))
+	for _, w := range u.maintenance {
+		windows = append(windows, w)
+	}
+	sort.Slice(windows, func(i, j int) bool {
+		if !windows[i].Start.Equal(windows[j].Start) {
+			return windows[i].Start.Before(windows[j].Start)
+		}
+		return windows[i].ID < windows[j].ID
+	})
+	return windows
+}
+
+// activeMaintenance returns the maintenance window covering object at t.
+func (u *UconEnforcer) activeMaintenance(object string, t time.Time) (MaintenanceWindow, bool) {
+	for _, w := range u.GetMaintenanceWindows() {
+		if w.activeAt(t) && w.covers(object) {
+			return w, true
+		}
+	}
+	return MaintenanceWindow{}, false
+}
+
+// checkMaintenance warns a monitored session of upcoming maintenance of its
+// object and reports the window that has started, if any. Windows that are
+// over are dropped.
+func (u *UconEnforcer) checkMaintenance(session *Session, now time.Time) (MaintenanceWindow, bool) {
+	u.mu.RLock()
+	scheduled := len(u.maintenance) > 0
+	u.mu.RUnlock()
+	if !scheduled {
+		return MaintenanceWindow{}, false
+	}
+	object := session.GetObject()
+	for _, w := range u.GetMaintenanceWindows() {
+		if !now.Before(w.End) {
+			u.mu.Lock()
+			if current, ok := u.maintenance[w.ID]; ok && !now.Before(current.End) {
+				delete(u.maintenance, w.ID)
+			}
+			u.mu.Unlock()
+			continue
+		}
+		if !w.covers(object) {
+			continue
+		}
+		if w.activeAt(now) {
+			return w, true
+		}
+		if now.Before(w.Start.Add(-w.Warning)) || !session.markOnce(string(EventMaintenanceWarning)+":"+w.ID) {
+			continue
+		}
+		message := fmt.Sprintf("%s is under maintenance from %s to %s", object, w.Start.Format(time.RFC3339), w.End.Format(time.RFC3339))
+		if w.Message != "" {
+			message += ": " + w.Message
+		}
+		u.emit(Event{
+			Type:      EventMaintenanceWarning,
+			SessionID: session.GetId(),
+			Time:      now,
+			Message:   message,
+			Data:      map[string]interface{}{"window": w.ID, "object": object, "start": w.Start, "end": w.End},
+		})
+	}
+	return MaintenanceWindow{}, false
+}
+
+// endForMaintenance runs the post obligations of a monitored session and
+// stops it with MaintenanceReason.
+func (u *UconEnforcer) endForMaintenance(session *Session, trace *TraceEntry, window MaintenanceWindow) {
+	if err := u.ExecuteObligationsByType(session.GetId(), "post"); err != nil {
+		u.logger.Log(LevelWarn, "post obligations failed before maintenance", map[string]interface{}{"session": session.GetId(), "window": window.ID, "error": err.Error()})
+	}
+	u.stopMonitored(session, trace, MaintenanceReason)
+}
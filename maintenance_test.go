@@ -0,0 +1,111 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMaintenanceWindow(t *testing.T) {
+	uconE := GetUconEnforcer().(*UconEnforcer)
+	var mu sync.Mutex
+	var warnings []Event
+	uconE.AddEventListener(func(event Event) {
+		if event.Type == EventMaintenanceWarning {
+			mu.Lock()
+			warnings = append(warnings, event)
+			mu.Unlock()
+		}
+	})
+	var ended []string
+	_ = uconE.RegisterObligationHandler("goodbye", func(expr string, session *Session) error {
+		mu.Lock()
+		ended = append(ended, session.GetId())
+		mu.Unlock()
+		return nil
+	})
+	_ = uconE.AddObligation(&Obligation{ID: "goodbye", Name: "goodbye", Kind: "post"})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	session, err := uconE.EnforceWithSession(sessionID)
+	if err != nil || session == nil {
+		t.Fatalf("Expected access, got %v", err)
+	}
+
+	// The window is an hour ahead, so the monitoring loop never reaches it;
+	// the test drives the monitoring checks with explicit times instead.
+	start := time.Now().Add(time.Hour)
+	if err := uconE.AddMaintenanceWindow(&MaintenanceWindow{ID: "upgrade", Objects: []string{"document*"}, Start: start, End: start.Add(time.Hour), Warning: time.Minute}); err != nil {
+		t.Fatal(err)
+	}
+	_ = uconE.AddMaintenanceWindow(&MaintenanceWindow{ID: "other", Objects: []string{"photos/*"}, Start: time.Now(), End: time.Now().Add(time.Hour)})
+
+	state := &monitorState{}
+	for _, now := range []time.Time{start.Add(-2 * time.Minute), start.Add(-30 * time.Second), start.Add(-10 * time.Second)} {
+		if !uconE.checkSession(session, now, state) {
+			t.Fatalf("Expected the session to last until the window starts, stopped at %s", now)
+		}
+	}
+	mu.Lock()
+	if len(warnings) != 1 || warnings[0].SessionID != sessionID || warnings[0].Data["window"] != "upgrade" || !warnings[0].Time.Equal(start.Add(-30*time.Second)) {
+		t.Errorf("Expected one maintenance warning once the warning period started, got %+v", warnings)
+	}
+	mu.Unlock()
+
+	if uconE.checkSession(session, start, state) {
+		t.Fatal("Expected monitoring to end when the window starts")
+	}
+	if session.IfActive() || session.GetStopReason() != MaintenanceReason {
+		t.Errorf("Expected the session to end for maintenance, active=%v reason=%q", session.IfActive(), session.GetStopReason())
+	}
+	mu.Lock()
+	if len(ended) != 1 || len(warnings) != 1 {
+		t.Errorf("Expected post obligations once and a single warning, got %v and %d warnings", ended, len(warnings))
+	}
+	mu.Unlock()
+
+	// Admission is checked against the current time.
+	_ = uconE.AddMaintenanceWindow(&MaintenanceWindow{ID: "hotfix", Objects: []string{"document*"}, Start: time.Now(), End: time.Now().Add(time.Hour)})
+	newID, _ := uconE.CreateSession("bob", "read", "document1", nil)
+	if _, err := uconE.EnforceWithSession(newID); !errors.Is(err, ErrUnderMaintenance) {
+		t.Errorf("Expected new sessions to be denied, got %v", err)
+	}
+	if err := uconE.RemoveMaintenanceWindow("hotfix"); err != nil {
+		t.Fatal(err)
+	}
+	if granted, err := uconE.EnforceWithSession(newID); err != nil || granted == nil {
+		t.Errorf("Expected access once the window was removed, got %v", err)
+	}
+	_ = uconE.StopMonitoring(newID)
+}
+
+func TestInvalidMaintenanceWindow(t *testing.T) {
+	uconE := GetUconEnforcer()
+	now := time.Now()
+	invalid := []*MaintenanceWindow{
+		nil,
+		{Start: now, End: now.Add(time.Hour)},
+		{ID: "backwards", Start: now, End: now.Add(-time.Hour)},
+		{ID: "pattern", Objects: []string{"["}, Start: now, End: now.Add(time.Hour)},
+	}
+	for _, window := range invalid {
+		if err := uconE.AddMaintenanceWindow(window); err == nil {
+			t.Errorf("Expected %+v to be rejected", window)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultUsageHistory is how many usage records a Meter keeps.
+const DefaultUsageHistory = 10000
+
+// ErrEntitlementExhausted is returned when an entitlement has too little left
+// for a consumption.
+var ErrEntitlementExhausted = errors.New("entitlement exhausted")
+
+// UsageRecord records one consumption of an entitlement.
+type UsageRecord struct {
+	Subject     string    `json:"subject"`
+	Entitlement string    `json:"entitlement"`
+	Amount      int64     `json:"amount"`
+	Remaining   int64     `json:"remaining"`
+	SessionID   string    `json:"session_id,omitempty"`
+	Time        time.Time `json:"time"`
+}
+
+// Meter tracks consumable entitlements (seats, credits, minutes, ...) per
+// subject, shared by all of the subject's sessions.
+type Meter struct {
+	balances map[string]map[string]int64
+	records  []UsageRecord
+	mu       sync.Mutex
+}
+
+// NewMeter creates an empty Meter.
+func NewMeter() *Meter {
+	return &Meter{balances: make(map[string]map[string]int64)}
+}
+
+// Grant adds amount to an entitlement of subject and returns the new balance.
+func (m *Meter) Grant(subject string, entitlement string, amount int64) int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	balances, ok := m.balances[subject]
+	if !ok {
+		balances = make(map[string]int64)
+		m.balances[subject] = balances
+	}
+	balances[entitlement] += amount
+	return balances[entitlement]
+}
+
+// Remaining returns what is left of an entitlement of subject.
+func (m *Meter) Remaining(subject string, entitlement string) int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.balances[subject][entitlement]
+}
+
+// Consume deducts amount from an entitlement of subject and records the
+// usage. It fails with ErrEntitlementExhausted, deducting nothing, if less
+// than amount is left. Negative amounts are refused; use Grant to add to an
+// entitlement.
+func (m *Meter) Consume(subject string, entitlement string, amount int64, sessionID string) (int64, error) {
+	if amount < 0 {
+		return 0, fmt.Errorf("invalid amount %d, expected a non-negative amount", amount)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	remaining := m.balances[subject][entitlement]
+	if remaining < amount {
+		return remaining, fmt.Errorf("%w: %s of %s has %d left, %d needed", ErrEntitlementExhausted, entitlement, subject, remaining, amount)
+	}
+	if amount == 0 {
+		return remaining, nil
+	}
+	remaining -= amount
+	m.balances[subject][entitlement] = remaining
+
+	m.records = append(m.records, UsageRecord{
+		Subject:     subject,
+		Entitlement: entitlement,
+		Amount:      amount,
+		Remaining:   remaining,
+		SessionID:   sessionID,
+		Time:        time.Now(),
+	})
+	if len(m.records) > DefaultUsageHistory {
+		m.records = append([]UsageRecord(nil), m.records[len(m.records)-DefaultUsageHistory:]...)
+	}
+	return remaining, nil
+}
+
+// Usage returns the recent usage records of subject, oldest first.
+func (m *Meter) Usage(subject string) []UsageRecord {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var records []UsageRecord
+	for _, record := range m.records {
+		if record.Subject == subject {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// WithMeter replaces the enforcer's default in-memory Meter, e.g. to share
+// one between enforcers.
+func WithMeter(meter *Meter) Option {
+	return func(u *UconEnforcer) {
+		u.meter = meter
+	}
+}
+
+// GetMeter returns the Meter of the enforcer.
+func (u *UconEnforcer) GetMeter() *Meter {
+	return u.meter
+}
+
+// checkEntitlementRemaining compares what is left of an entitlement of the
+// session's subject with a threshold.
+// Expr: "entitlement op number", e.g. "credits > 0".
+func (u *UconEnforcer) checkEntitlementRemaining(expr string, session *Session) (bool, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 3 {
+		return false, fmt.Errorf("invalid expression format: %s, expected 'entitlement op number'", expr)
+	}
+	threshold, err := strconv.ParseFloat(fields[2], 64)
+	if err != nil {
+		return false, fmt.Errorf("invalid threshold in %s: %w", expr, err)
+	}
+	remaining := float64(u.meter.Remaining(session.GetSubject(), fields[0]))
+	return compareNumbers(remaining, fields[1], threshold, expr)
+}
+
+// executeConsumeEntitlement deducts from an entitlement of the session's
+// subject and fails once it is exhausted.
+// Expr: "entitlement" or "entitlement:amount", e.g. "minutes:1".
+func (u *UconEnforcer) executeConsumeEntitlement(expr string, session *Session) error {
+	entitlement, amount := strings.TrimSpace(expr), int64(1)
+	if strings.Contains(expr, ":") {
+		e, a, err := splitKeyValue(expr)
+		if err != nil {
+			return err
+		}
+		if amount, err = strconv.ParseInt(a, 10, 64); err != nil || amount < 0 {
+			return fmt.Errorf("invalid amount in %s", expr)
+		}
+		entitlement = e
+	}
+	if session.IsDryRun() {
+		if remaining := u.meter.Remaining(session.GetSubject(), entitlement); remaining < amount {
+			return fmt.Errorf("%w: %s of %s has %d left, %d needed", ErrEntitlementExhausted, entitlement, session.GetSubject(), remaining, amount)
+		}
+		return nil
+	}
+	_, err := u.meter.Consume(session.GetSubject(), entitlement, amount, session.GetId())
+	return err
+}
+
+// exprRemaining is the "remaining(subject, entitlement)" expression function.
+func (u *UconEnforcer) exprRemaining(args ...interface{}) (interface{}, error) {
+	if len(args) != 2 {
+		return nil, errors.New("remaining expects (subject, entitlement)")
+	}
+	return float64(u.meter.Remaining(fmt.Sprint(args[0]), fmt.Sprint(args[1]))), nil
+}
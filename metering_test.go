@@ -0,0 +1,80 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMeter(t *testing.T) {
+	m := NewMeter()
+	if got := m.Grant("alice", "credits", 3); got != 3 {
+		t.Errorf("Expected balance 3, got %d", got)
+	}
+	if remaining, err := m.Consume("alice", "credits", 2, "s1"); err != nil || remaining != 1 {
+		t.Errorf("Expected 1 remaining, got %d, %v", remaining, err)
+	}
+	if _, err := m.Consume("alice", "credits", 2, "s1"); !errors.Is(err, ErrEntitlementExhausted) {
+		t.Errorf("Expected ErrEntitlementExhausted, got %v", err)
+	}
+	if _, err := m.Consume("carol", "credits", -5, "s3"); err == nil || m.Remaining("carol", "credits") != 0 {
+		t.Error("Expected a negative amount to be refused")
+	}
+	if _, err := m.Consume("bob", "credits", 1, "s2"); !errors.Is(err, ErrEntitlementExhausted) {
+		t.Errorf("Expected ErrEntitlementExhausted for unknown subject, got %v", err)
+	}
+	if m.Remaining("alice", "credits") != 1 {
+		t.Errorf("Failed consumption should not deduct, got %d", m.Remaining("alice", "credits"))
+	}
+	if usage := m.Usage("alice"); len(usage) != 1 || usage[0].Amount != 2 || usage[0].SessionID != "s1" {
+		t.Errorf("Unexpected usage: %+v", usage)
+	}
+}
+
+func TestEntitlementEnforcement(t *testing.T) {
+	uconE := GetUconEnforcer()
+	uconE.GetMeter().Grant("alice", "credits", 3)
+	_ = uconE.AddCondition(&Condition{ID: "has_credits", Name: "entitlement_remaining", Kind: "always", Expr: "credits > 0"})
+	_ = uconE.AddObligation(&Obligation{ID: "charge", Name: "consume_entitlement", Kind: "ongoing", Expr: "credits:1"})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	session, _ := uconE.EnforceWithSession(sessionID)
+	if session == nil {
+		t.Fatal("Expected access while credits remain")
+	}
+
+	time.Sleep(1 * time.Second)
+	if session.IfActive() {
+		t.Fatal("Expected session to be revoked once credits are used up")
+	}
+	if remaining := uconE.GetMeter().Remaining("alice", "credits"); remaining != 0 {
+		t.Errorf("Expected all credits consumed, got %d", remaining)
+	}
+	if !strings.Contains(session.GetStopReason(), "Conditions no longer met") {
+		t.Errorf("Unexpected stop reason: %q", session.GetStopReason())
+	}
+
+	otherID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	if granted, _ := uconE.EnforceWithSession(otherID); granted != nil {
+		t.Error("Expected new sessions to be denied without credits")
+	}
+	u := uconE.(*UconEnforcer)
+	if ok, err := u.checkExpression("remaining(subject, 'credits') == 0", session); err != nil || !ok {
+		t.Errorf("Expected remaining() expression function, got %v, %v", ok, err)
+	}
+}
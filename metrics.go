@@ -0,0 +1,273 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kinds of latency measurements reported to Metrics.
+const (
+	MetricCondition  = "condition"
+	MetricObligation = "obligation"
+)
+
+// Metrics receives runtime measurements from the enforcer. Implement it to
+// export them to a monitoring system such as Prometheus.
+type Metrics interface {
+	// ObserveLatency records how long the condition or obligation with the
+	// given ID took to evaluate. kind is MetricCondition or MetricObligation.
+	ObserveLatency(kind string, id string, d time.Duration)
+}
+
+// CounterMetrics is implemented by Metrics that also count events, such as
+// evaluations exceeding their deadline.
+type CounterMetrics interface {
+	IncCounter(name string, id string)
+}
+
+// DefaultLatencyBuckets are the histogram upper bounds used by NewInMemoryMetrics.
+var DefaultLatencyBuckets = []time.Duration{
+	time.Millisecond, 5 * time.Millisecond, 10 * time.Millisecond, 25 * time.Millisecond,
+	50 * time.Millisecond, 100 * time.Millisecond, 250 * time.Millisecond, 500 * time.Millisecond,
+	time.Second, 2500 * time.Millisecond, 5 * time.Second,
+}
+
+// HistogramBucket counts the observations less than or equal to UpperBound.
+type HistogramBucket struct {
+	UpperBound float64 `json:"le"`
+	Count      uint64  `json:"count"`
+}
+
+// Histogram is a snapshot of a latency histogram. Values are in seconds and
+// bucket counts are cumulative; observations above the last bound are only
+// included in Count.
+type Histogram struct {
+	Buckets []HistogramBucket `json:"buckets"`
+	Count   uint64            `json:"count"`
+	Sum     float64           `json:"sum"`
+	Max     float64           `json:"max"`
+}
+
+// MetricsSnapshot holds the latency histograms by kind and ID, the counters
+// by name and ID, and the health of the attribute providers. LabeledLatency
+// holds the histograms of observations with metric labels by kind, ID and
+// label set, formatted as "customer=acme,env=prod".
+type MetricsSnapshot struct {
+	Latency        map[string]map[string]*Histogram            `json:"latency"`
+	LabeledLatency map[string]map[string]map[string]*Histogram `json:"labeled_latency,omitempty"`
+	Counters       map[string]map[string]uint64                `json:"counters,omitempty"`
+	ProviderHealth map[string]bool                             `json:"provider_health,omitempty"`
+}
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    time.Duration
+	max    time.Duration
+}
+
+// InMemoryMetrics keeps latency histograms in memory. It is the default
+// Metrics of the enforcer.
+type InMemoryMetrics struct {
+	buckets  []time.Duration
+	latency  map[string]map[string]*histogram
+	labeled  map[string]map[string]map[string]*histogram
+	counters map[string]map[string]uint64
+	health   map[string]bool
+	mu       sync.Mutex
+}
+
+// NewInMemoryMetrics creates an InMemoryMetrics with the given histogram upper
+// bounds, or DefaultLatencyBuckets if none are given.
+func NewInMemoryMetrics(buckets ...time.Duration) *InMemoryMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	sorted := append([]time.Duration(nil), buckets...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return &InMemoryMetrics{
+		buckets:  sorted,
+		latency:  make(map[string]map[string]*histogram),
+		labeled:  make(map[string]map[string]map[string]*histogram),
+		counters: make(map[string]map[string]uint64),
+		health:   make(map[string]bool),
+	}
+}
+
+// ObserveLatency implements Metrics.
+func (m *InMemoryMetrics) ObserveLatency(kind string, id string, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.histogram(m.latency, kind, id).observe(m.buckets, d)
+}
+
+// ObserveLatencyWithLabels implements LabeledMetrics. The observation is also
+// included in the unlabeled histogram of the kind and ID.
+func (m *InMemoryMetrics) ObserveLatencyWithLabels(kind string, id string, d time.Duration, labels map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.histogram(m.latency, kind, id).observe(m.buckets, d)
+
+	byID, ok := m.labeled[kind]
+	if !ok {
+		byID = make(map[string]map[string]*histogram)
+		m.labeled[kind] = byID
+	}
+	m.histogram(byID, id, formatLabels(labels)).observe(m.buckets, d)
+}
+
+// histogram returns the histogram under key and id, creating it if needed.
+func (m *InMemoryMetrics) histogram(series map[string]map[string]*histogram, key string, id string) *histogram {
+	byID, ok := series[key]
+	if !ok {
+		byID = make(map[string]*histogram)
+		series[key] = byID
+	}
+	h, ok := byID[id]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(m.buckets))}
+		byID[id] = h
+	}
+	return h
+}
+
+func (h *histogram) observe(buckets []time.Duration, d time.Duration) {
+	if i := sort.Search(len(buckets), func(i int) bool { return d <= buckets[i] }); i < len(buckets) {
+		h.counts[i]++
+	}
+	h.count++
+	h.sum += d
+	if d > h.max {
+		h.max = d
+	}
+}
+
+// formatLabels formats a label set as "customer=acme,env=prod", ordered by
+// label name.
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for name, val := range labels {
+		pairs = append(pairs, name+"="+val)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// IncCounter implements CounterMetrics.
+func (m *InMemoryMetrics) IncCounter(name string, id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	byID, ok := m.counters[name]
+	if !ok {
+		byID = make(map[string]uint64)
+		m.counters[name] = byID
+	}
+	byID[id]++
+}
+
+// SetProviderHealth implements ProviderHealthMetrics.
+func (m *InMemoryMetrics) SetProviderHealth(name string, healthy bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.health[name] = healthy
+}
+
+// Snapshot returns a copy of the current histograms, counters and provider health.
+func (m *InMemoryMetrics) Snapshot() *MetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := &MetricsSnapshot{Latency: make(map[string]map[string]*Histogram, len(m.latency))}
+	for kind, byID := range m.latency {
+		snapshot.Latency[kind] = m.snapshotHistograms(byID)
+	}
+	if len(m.labeled) > 0 {
+		snapshot.LabeledLatency = make(map[string]map[string]map[string]*Histogram, len(m.labeled))
+		for kind, byID := range m.labeled {
+			snapshot.LabeledLatency[kind] = make(map[string]map[string]*Histogram, len(byID))
+			for id, bySet := range byID {
+				snapshot.LabeledLatency[kind][id] = m.snapshotHistograms(bySet)
+			}
+		}
+	}
+	if len(m.counters) > 0 {
+		snapshot.Counters = make(map[string]map[string]uint64, len(m.counters))
+		for name, byID := range m.counters {
+			snapshot.Counters[name] = make(map[string]uint64, len(byID))
+			for id, count := range byID {
+				snapshot.Counters[name][id] = count
+			}
+		}
+	}
+	if len(m.health) > 0 {
+		snapshot.ProviderHealth = make(map[string]bool, len(m.health))
+		for name, healthy := range m.health {
+			snapshot.ProviderHealth[name] = healthy
+		}
+	}
+	return snapshot
+}
+
+func (m *InMemoryMetrics) snapshotHistograms(byKey map[string]*histogram) map[string]*Histogram {
+	snapshot := make(map[string]*Histogram, len(byKey))
+	for key, h := range byKey {
+		out := &Histogram{
+			Buckets: make([]HistogramBucket, len(m.buckets)),
+			Count:   h.count,
+			Sum:     h.sum.Seconds(),
+			Max:     h.max.Seconds(),
+		}
+		var cumulative uint64
+		for i, bound := range m.buckets {
+			cumulative += h.counts[i]
+			out.Buckets[i] = HistogramBucket{UpperBound: bound.Seconds(), Count: cumulative}
+		}
+		snapshot[key] = out
+	}
+	return snapshot
+}
+
+// WithMetrics replaces the default InMemoryMetrics.
+func WithMetrics(metrics Metrics) Option {
+	return func(u *UconEnforcer) {
+		u.metrics = metrics
+	}
+}
+
+// GetMetrics returns the metrics the enforcer reports to.
+func (u *UconEnforcer) GetMetrics() Metrics {
+	return u.metrics
+}
+
+func (u *UconEnforcer) observeLatency(kind string, id string, session *Session, start time.Time) {
+	if u.metrics == nil {
+		return
+	}
+	if m, ok := u.metrics.(LabeledMetrics); ok && u.metricLabels != nil {
+		m.ObserveLatencyWithLabels(kind, id, time.Since(start), u.metricLabels.bounded(session))
+		return
+	}
+	u.metrics.ObserveLatency(kind, id, time.Since(start))
+}
+
+func (u *UconEnforcer) incCounter(name string, id string) {
+	if m, ok := u.metrics.(CounterMetrics); ok {
+		m.IncCounter(name, id)
+	}
+}
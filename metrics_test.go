@@ -0,0 +1,62 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInMemoryMetrics(t *testing.T) {
+	m := NewInMemoryMetrics(10*time.Millisecond, time.Millisecond)
+	m.ObserveLatency(MetricCondition, "c1", 500*time.Microsecond)
+	m.ObserveLatency(MetricCondition, "c1", 5*time.Millisecond)
+	m.ObserveLatency(MetricCondition, "c1", time.Second)
+
+	h := m.Snapshot().Latency[MetricCondition]["c1"]
+	if h == nil || h.Count != 3 || h.Max != 1 {
+		t.Fatalf("Unexpected histogram: %+v", h)
+	}
+	if h.Buckets[0].UpperBound != 0.001 || h.Buckets[0].Count != 1 || h.Buckets[1].Count != 2 {
+		t.Errorf("Unexpected buckets: %+v", h.Buckets)
+	}
+}
+
+func TestEvaluationLatencyMetrics(t *testing.T) {
+	uconE := GetUconEnforcer()
+	_ = uconE.RegisterConditionHandler("slow_lookup", func(expr string, session SessionView) (bool, error) {
+		time.Sleep(20 * time.Millisecond)
+		return true, nil
+	})
+	_ = uconE.AddCondition(&Condition{ID: "slow", Name: "slow_lookup", Kind: "always"})
+	_ = uconE.AddObligation(&Obligation{ID: "audit", Name: "access_logging", Kind: "pre", Expr: "audit"})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	if _, err := uconE.EvaluateConditions(sessionID); err != nil {
+		t.Fatalf("EvaluateConditions: %v", err)
+	}
+	if err := uconE.ExecuteObligationsByType(sessionID, "pre"); err != nil {
+		t.Fatalf("ExecuteObligationsByType: %v", err)
+	}
+
+	snapshot := uconE.GetMetrics().(*InMemoryMetrics).Snapshot()
+	slow := snapshot.Latency[MetricCondition]["slow"]
+	if slow == nil || slow.Count != 1 || slow.Sum < 0.02 {
+		t.Errorf("Unexpected condition latency: %+v", slow)
+	}
+	if audit := snapshot.Latency[MetricObligation]["audit"]; audit == nil || audit.Count != 1 {
+		t.Errorf("Unexpected obligation latency: %+v", audit)
+	}
+}
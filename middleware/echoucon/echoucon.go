@@ -0,0 +1,70 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package echoucon provides Echo middleware for casbin-ucon.
+package echoucon
+
+import (
+	"github.com/casbin/casbin-ucon"
+	"github.com/labstack/echo/v4"
+)
+
+// SessionKey is the echo.Context key under which the granted session is stored.
+const SessionKey = "ucon_session"
+
+type config struct {
+	extract ucon.SessionIDExtractor
+}
+
+// Option configures the middleware.
+type Option func(c *config)
+
+// WithSessionIDExtractor sets how the session id is read from the request.
+// By default it is taken from the ucon.DefaultSessionHeader header.
+func WithSessionIDExtractor(extract ucon.SessionIDExtractor) Option {
+	return func(c *config) {
+		c.extract = extract
+	}
+}
+
+// Middleware authorizes every request with ucon.AuthorizeHTTPRequest. A granted
+// session is stored in the echo.Context and the request context; missing or
+// unknown sessions are rejected with 401, refused or revoked ones with 403, both
+// with a ucon.Problem body carrying the reason code.
+func Middleware(e ucon.IUconEnforcer, opts ...Option) echo.MiddlewareFunc {
+	c := &config{extract: ucon.HeaderSessionID(ucon.DefaultSessionHeader)}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			req := ctx.Request()
+			session, status, err := ucon.AuthorizeHTTPRequest(e, c.extract(req), req)
+			if err != nil {
+				ucon.WriteProblem(ctx.Response(), status, err)
+				return nil
+			}
+			ctx.Set(SessionKey, session)
+			ctx.SetRequest(req.WithContext(ucon.ContextWithSession(req.Context(), session)))
+			return next(ctx)
+		}
+	}
+}
+
+// GetSession returns the session granted by Middleware.
+func GetSession(ctx echo.Context) (*ucon.Session, bool) {
+	session, ok := ctx.Get(SessionKey).(*ucon.Session)
+	return session, ok
+}
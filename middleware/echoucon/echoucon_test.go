@@ -0,0 +1,102 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echoucon
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/casbin/casbin-ucon"
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/model"
+	"github.com/labstack/echo/v4"
+)
+
+func newEnforcer(t *testing.T) ucon.IUconEnforcer {
+	m, err := model.NewModelFromString(`
+[request_definition]
+r = sub, obj, act
+
+[policy_definition]
+p = sub, obj, act
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = r.sub == p.sub && r.obj == p.obj && r.act == p.act
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, _ := casbin.NewEnforcer(m)
+	_, _ = e.AddPolicy("alice", "document1", "read")
+	return ucon.NewUconEnforcer(e)
+}
+
+func TestMiddleware(t *testing.T) {
+	uconE := newEnforcer(t)
+
+	router := echo.New()
+	router.Use(Middleware(uconE))
+	router.GET("/document1", func(ctx echo.Context) error {
+		session, ok := GetSession(ctx)
+		if !ok {
+			t.Error("Expected session in echo context")
+		}
+		if _, ok := ucon.SessionFromContext(ctx.Request().Context()); !ok {
+			t.Error("Expected session in request context")
+		}
+		return ctx.String(http.StatusOK, session.GetSubject())
+	})
+
+	do := func(sessionID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/document1", nil)
+		if sessionID != "" {
+			req.Header.Set(ucon.DefaultSessionHeader, sessionID)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	aliceID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{})
+	bobID, _ := uconE.CreateSession("bob", "read", "document1", map[string]interface{}{})
+
+	if rec := do(""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without session, got %d", rec.Code)
+	}
+	rec := do("")
+	var problem ucon.Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil || problem.Code != ucon.ReasonSessionRequired {
+		t.Errorf("Expected a %s problem, got %q", ucon.ReasonSessionRequired, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != ucon.ProblemContentType {
+		t.Errorf("Expected content type %s, got %s", ucon.ProblemContentType, ct)
+	}
+	if rec := do(bobID); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for bob, got %d", rec.Code)
+	}
+	if rec := do(aliceID); rec.Code != http.StatusOK || rec.Body.String() != "alice" {
+		t.Errorf("Expected 200 for alice, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	_ = uconE.StopMonitoring(aliceID)
+	if rec := do(aliceID); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 after the session stopped, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ginucon provides Gin middleware for casbin-ucon.
+package ginucon
+
+import (
+	"github.com/casbin/casbin-ucon"
+	"github.com/gin-gonic/gin"
+)
+
+// SessionKey is the gin.Context key under which the granted session is stored.
+const SessionKey = "ucon_session"
+
+type config struct {
+	extract ucon.SessionIDExtractor
+}
+
+// Option configures the middleware.
+type Option func(c *config)
+
+// WithSessionIDExtractor sets how the session id is read from the request.
+// By default it is taken from the ucon.DefaultSessionHeader header.
+func WithSessionIDExtractor(extract ucon.SessionIDExtractor) Option {
+	return func(c *config) {
+		c.extract = extract
+	}
+}
+
+// Middleware authorizes every request with ucon.AuthorizeHTTPRequest. A granted
+// session is stored in the gin.Context and the request context; missing or
+// unknown sessions are aborted with 401, refused or revoked ones with 403, both
+// with a ucon.Problem body carrying the reason code.
+func Middleware(e ucon.IUconEnforcer, opts ...Option) gin.HandlerFunc {
+	c := &config{extract: ucon.HeaderSessionID(ucon.DefaultSessionHeader)}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return func(ctx *gin.Context) {
+		session, status, err := ucon.AuthorizeHTTPRequest(e, c.extract(ctx.Request), ctx.Request)
+		if err != nil {
+			ucon.WriteProblem(ctx.Writer, status, err)
+			ctx.Abort()
+			return
+		}
+		ctx.Set(SessionKey, session)
+		ctx.Request = ctx.Request.WithContext(ucon.ContextWithSession(ctx.Request.Context(), session))
+		ctx.Next()
+	}
+}
+
+// GetSession returns the session granted by Middleware.
+func GetSession(ctx *gin.Context) (*ucon.Session, bool) {
+	val, ok := ctx.Get(SessionKey)
+	if !ok {
+		return nil, false
+	}
+	session, ok := val.(*ucon.Session)
+	return session, ok
+}
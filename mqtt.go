@@ -0,0 +1,176 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultMQTTTopicPrefix is the topic prefix of device topics:
+	// <prefix>/<device>/attributes and <prefix>/<device>/control.
+	DefaultMQTTTopicPrefix = "ucon/devices"
+	// DefaultDeviceAttribute is the session attribute binding a session to a device.
+	DefaultDeviceAttribute = "device_id"
+)
+
+// MQTTClient is the part of an MQTT client used by MQTTBridge. Adapt a
+// client library such as Eclipse Paho to it, choosing the QoS there.
+type MQTTClient interface {
+	Subscribe(topic string, handler func(topic string, payload []byte)) error
+	Publish(topic string, payload []byte) error
+}
+
+// ControlMessage is published on a device's control topic when one of its
+// sessions stops.
+type ControlMessage struct {
+	Type      string    `json:"type"`
+	SessionID string    `json:"session_id"`
+	Object    string    `json:"object"`
+	Reason    string    `json:"reason,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+// MQTTBridge connects device-bound sessions to an MQTT broker. A JSON object
+// published on <prefix>/<device>/attributes updates the attributes of the
+// device's active sessions listed in Attributes, and when a device's session stops a
+// ControlMessage is published on <prefix>/<device>/control, so constrained
+// devices learn at once that their usage grant ended.
+type MQTTBridge struct {
+	// TopicPrefix defaults to DefaultMQTTTopicPrefix.
+	TopicPrefix string
+	// DeviceAttribute defaults to DefaultDeviceAttribute.
+	DeviceAttribute string
+	// Attributes are the session attributes devices may set. Other keys of
+	// attribute messages are dropped, so devices cannot change attributes
+	// that grant them access, such as priority or the channel binding.
+	Attributes []string
+	// Logger defaults to the logger of the enforcer.
+	Logger Logger
+
+	e      IUconEnforcer
+	client MQTTClient
+}
+
+// NewMQTTBridge creates a bridge between e and the broker client is connected
+// to, accepting the given device attributes.
+func NewMQTTBridge(e IUconEnforcer, client MQTTClient, attributes ...string) *MQTTBridge {
+	logger := Logger(NewDefaultLogger(LevelInfo))
+	if u, ok := e.(*UconEnforcer); ok {
+		logger = u.logger
+	}
+	return &MQTTBridge{
+		TopicPrefix:     DefaultMQTTTopicPrefix,
+		DeviceAttribute: DefaultDeviceAttribute,
+		Attributes:      attributes,
+		Logger:          logger,
+		e:               e,
+		client:          client,
+	}
+}
+
+// Start subscribes to the attribute topics and starts publishing revocations.
+func (b *MQTTBridge) Start() error {
+	if err := b.client.Subscribe(b.TopicPrefix+"/+/attributes", b.handleAttributes); err != nil {
+		return fmt.Errorf("failed to subscribe to device attributes: %w", err)
+	}
+	b.e.AddEventListener(b.handleEvent)
+	return nil
+}
+
+func (b *MQTTBridge) handleAttributes(topic string, payload []byte) {
+	device, ok := b.device(topic, "attributes")
+	if !ok {
+		return
+	}
+	message := make(map[string]interface{})
+	if err := json.Unmarshal(payload, &message); err != nil {
+		b.Logger.Log(LevelWarn, "invalid attribute message", map[string]interface{}{"topic": topic, "error": err.Error()})
+		return
+	}
+	attributes := make(map[string]interface{}, len(message))
+	var dropped []string
+	for key, val := range message {
+		if slices.Contains(b.Attributes, key) {
+			attributes[key] = val
+		} else {
+			dropped = append(dropped, key)
+		}
+	}
+	if len(dropped) > 0 {
+		sort.Strings(dropped)
+		b.Logger.Log(LevelWarn, "dropped attributes devices may not set", map[string]interface{}{"device": device, "attributes": strings.Join(dropped, ",")})
+	}
+	if len(attributes) == 0 {
+		return
+	}
+	for _, session := range b.e.GetSessions() {
+		if !session.IfActive() || fmt.Sprint(session.GetAttribute(b.DeviceAttribute)) != device {
+			continue
+		}
+		if err := b.e.UpdateSessionAttributes(session.GetId(), attributes); err != nil {
+			b.Logger.Log(LevelWarn, "failed to update device attributes", map[string]interface{}{"session": session.GetId(), "device": device, "error": err.Error()})
+		}
+	}
+}
+
+func (b *MQTTBridge) handleEvent(event Event) {
+	if event.Type != EventSessionStopped {
+		return
+	}
+	session, err := b.e.GetSession(event.SessionID)
+	if err != nil {
+		return
+	}
+	device := session.GetAttribute(b.DeviceAttribute)
+	if device == nil {
+		return
+	}
+
+	reason := strings.TrimSpace(session.GetStopReason())
+	msgType := "revoked"
+	if reason == NormalStopReason {
+		msgType = "ended"
+	}
+	payload, _ := json.Marshal(&ControlMessage{
+		Type:      msgType,
+		SessionID: session.GetId(),
+		Object:    session.GetObject(),
+		Reason:    reason,
+		Time:      event.Time,
+	})
+	topic := fmt.Sprintf("%s/%v/control", b.TopicPrefix, device)
+	if err := b.client.Publish(topic, payload); err != nil {
+		b.Logger.Log(LevelWarn, "failed to publish revocation", map[string]interface{}{"session": session.GetId(), "topic": topic, "error": err.Error()})
+	}
+}
+
+// device extracts the device from <prefix>/<device>/<kind>.
+func (b *MQTTBridge) device(topic string, kind string) (string, bool) {
+	rest, ok := strings.CutPrefix(topic, b.TopicPrefix+"/")
+	if !ok {
+		return "", false
+	}
+	device, ok := strings.CutSuffix(rest, "/"+kind)
+	if !ok || device == "" || strings.Contains(device, "/") {
+		return "", false
+	}
+	return device, true
+}
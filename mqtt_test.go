@@ -0,0 +1,96 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeMQTTClient struct {
+	handlers  map[string]func(topic string, payload []byte)
+	published map[string][][]byte
+	mu        sync.Mutex
+}
+
+func (c *fakeMQTTClient) Subscribe(topic string, handler func(topic string, payload []byte)) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers[topic] = handler
+	return nil
+}
+
+func (c *fakeMQTTClient) Publish(topic string, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.published[topic] = append(c.published[topic], payload)
+	return nil
+}
+
+func (c *fakeMQTTClient) deliver(filter string, topic string, payload string) {
+	c.mu.Lock()
+	handler := c.handlers[filter]
+	c.mu.Unlock()
+	handler(topic, []byte(payload))
+}
+
+func (c *fakeMQTTClient) messages(topic string) [][]byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.published[topic]
+}
+
+func TestMQTTBridge(t *testing.T) {
+	uconE := GetUconEnforcer()
+	_ = uconE.AddCondition(&Condition{ID: "battery", Name: "numeric_threshold", Kind: "always", Expr: "battery >= 20"})
+	client := &fakeMQTTClient{handlers: map[string]func(string, []byte){}, published: map[string][][]byte{}}
+	if err := NewMQTTBridge(uconE, client, "battery").Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"device_id": "sensor-7", "battery": 80})
+	session, _ := uconE.EnforceWithSession(sessionID)
+	if session == nil {
+		t.Fatal("Expected access")
+	}
+	otherID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"device_id": "sensor-8", "battery": 80})
+
+	client.deliver("ucon/devices/+/attributes", "ucon/devices/sensor-7/attributes", `{"battery": 10, "priority": "critical"}`)
+	other, _ := uconE.GetSession(otherID)
+	if other.GetAttribute("battery") != 80 {
+		t.Error("Expected other devices' sessions to be left untouched")
+	}
+	if session.GetAttribute("priority") != nil {
+		t.Error("Expected attributes outside the allow-list to be dropped")
+	}
+
+	time.Sleep(400 * time.Millisecond)
+	if session.IfActive() {
+		_ = uconE.StopMonitoring(sessionID)
+		t.Fatal("Expected the session to be revoked after the attribute update")
+	}
+	messages := client.messages("ucon/devices/sensor-7/control")
+	if len(messages) != 1 {
+		t.Fatalf("Expected one control message, got %d", len(messages))
+	}
+	msg := &ControlMessage{}
+	_ = json.Unmarshal(messages[0], msg)
+	if msg.Type != "revoked" || msg.SessionID != sessionID || !strings.Contains(msg.Reason, "Conditions no longer met") {
+		t.Errorf("Unexpected control message: %+v", msg)
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SubjectSession is the view of a session its subject may see, e.g. to show
+// "you are signed in on 3 devices". Unlike SessionRecord it leaves out the
+// session id, which grants access, and the attributes.
+type SubjectSession struct {
+	Action    string    `json:"action"`
+	Object    string    `json:"object"`
+	Purpose   string    `json:"purpose,omitempty"`
+	StartTime time.Time `json:"start_time"`
+	// ExpiresAt is when the session's conditions revoke it at the latest:
+	// the earliest deadline of its session_age_below, expiry, svid_valid and
+	// impersonation conditions and, for break-glass sessions, the
+	// break-glass TTL. It is nil if none applies.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Suspended bool       `json:"suspended,omitempty"`
+	// Restrictions are the types of the content transformations the
+	// session's obligations require, e.g. "mask" or "watermark".
+	Restrictions []string `json:"restrictions,omitempty"`
+}
+
+// expiryDeadlines return when a condition revokes a session at the latest,
+// for the built-in conditions that expire.
+var expiryDeadlines = map[string]func(expr string, session *Session) (time.Time, bool){
+	"session_age_below": sessionAgeDeadline,
+	"expiry":            expiryDeadline,
+	"svid_valid":        svidDeadline,
+	"impersonation":     impersonationDeadline,
+}
+
+func sessionAgeDeadline(expr string, session *Session) (time.Time, bool) {
+	maxAge, err := time.ParseDuration(strings.TrimSpace(expr))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return session.GetStartTime().Add(maxAge), true
+}
+
+func expiryDeadline(expr string, session *Session) (time.Time, bool) {
+	key, grace, _, err := parseExpiry(expr)
+	if err != nil {
+		return time.Time{}, false
+	}
+	expiry, err := toTime(session.GetAttribute(key))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return expiry.Add(grace), true
+}
+
+func svidDeadline(expr string, session *Session) (time.Time, bool) {
+	expiry, err := toTime(session.GetAttribute(AttrSVIDExpiry))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return expiry, true
+}
+
+// GetMySessions returns the active sessions of subject, oldest first, in a
+// view that is safe to show the subject itself. Use GetSessions for the
+// administrative listing.
+func (u *UconEnforcer) GetMySessions(subject string) []SubjectSession {
+	conditions := u.conditionList()
+	var sessions []*Session
+	for _, session := range u.GetSessions() {
+		if session.GetSubject() == subject && session.IfActive() {
+			sessions = append(sessions, session)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].GetStartTime().Before(sessions[j].GetStartTime())
+	})
+
+	views := make([]SubjectSession, 0, len(sessions))
+	for _, session := range sessions {
+		view := SubjectSession{
+			Action:    session.GetAction(),
+			Object:    session.GetObject(),
+			Purpose:   session.GetPurpose(),
+			StartTime: session.GetStartTime(),
+			Suspended: session.IsSuspended(),
+		}
+		for _, condition := range conditions {
+			deadline, ok := expiryDeadlines[condition.Name]
+			if !ok || !conditionApplies(&condition, session) {
+				continue
+			}
+			if t, ok := deadline(condition.Expr, session); ok && (view.ExpiresAt == nil || t.Before(*view.ExpiresAt)) {
+				view.ExpiresAt = &t
+			}
+		}
+		if t, ok := u.breakGlassDeadline(session); ok && (view.ExpiresAt == nil || t.Before(*view.ExpiresAt)) {
+			view.ExpiresAt = &t
+		}
+		seen := make(map[string]bool)
+		for _, directive := range session.GetDirectives() {
+			if !seen[directive.Type] {
+				seen[directive.Type] = true
+				view.Restrictions = append(view.Restrictions, directive.Type)
+			}
+		}
+		views = append(views, view)
+	}
+	return views
+}
+
+// UserInitiatedReason is the stop reason of sessions stopped by their own
+// subject, see RevokeMySessions.
+const UserInitiatedReason = "user initiated"
+
+// RevokeMySessions stops sessions on behalf of their subject, e.g. for "sign
+// out everywhere": the given sessions, or every active session of subject if
+// none are given. The stop reason is UserInitiatedReason, followed by the
+// reason the subject gave, if any. Sessions of other subjects are refused
+// with ErrSessionNotFound, so that subjects cannot probe each other's session
+// ids, and nothing is stopped then. Sessions that already stopped are
+// skipped. It returns the number of sessions stopped.
+func (u *UconEnforcer) RevokeMySessions(subject string, reason string, sessionIDs ...string) (int, error) {
+	stopReason := UserInitiatedReason
+	if reason = strings.TrimSpace(reason); reason != "" {
+		stopReason += ": " + reason
+	}
+
+	var sessions []*Session
+	if len(sessionIDs) == 0 {
+		for _, session := range u.GetSessions() {
+			if session.GetSubject() == subject {
+				sessions = append(sessions, session)
+			}
+		}
+	}
+	for _, id := range sessionIDs {
+		session, err := u.GetSession(id)
+		if err == nil && session.GetSubject() != subject {
+			err = fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+		}
+		if err != nil {
+			return 0, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	revoked := 0
+	for _, session := range sessions {
+		if session.IfActive() && session.Stop(stopReason) == nil {
+			revoked++
+		}
+	}
+	if revoked > 0 {
+		u.logger.Log(LevelInfo, "sessions revoked by subject", map[string]interface{}{"subject": subject, "count": revoked, "reason": stopReason})
+	}
+	return revoked, nil
+}
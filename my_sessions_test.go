@@ -0,0 +1,117 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGetMySessions(t *testing.T) {
+	uconE := GetUconEnforcer().(*UconEnforcer)
+	_ = uconE.AddCondition(&Condition{ID: "max_age", Name: "session_age_below", Kind: "always", Expr: "8h"})
+	_ = uconE.AddCondition(&Condition{ID: "license", Name: "expiry", Kind: "always", Expr: "license_expiry grace=1h"})
+	_ = uconE.AddObligation(&Obligation{ID: "mark", Name: "watermark", Kind: "pre"})
+
+	license := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+	first, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"license_expiry": license.Format(time.RFC3339), "ip": "10.0.0.1"})
+	if _, err := uconE.EnforceWithSession(first); err != nil {
+		t.Fatal(err)
+	}
+	second, _ := uconE.CreateSession("alice", "write", "document1", map[string]interface{}{})
+	stopped, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{})
+	session, _ := uconE.GetSession(stopped)
+	_ = session.Stop("done")
+	_, _ = uconE.CreateSession("bob", "read", "document1", map[string]interface{}{})
+
+	sessions := uconE.GetMySessions("alice")
+	if len(sessions) != 2 || sessions[0].Action != "read" || sessions[1].Action != "write" {
+		t.Fatalf("Expected alice's two active sessions, oldest first, got %+v", sessions)
+	}
+	if sessions[0].ExpiresAt == nil || !sessions[0].ExpiresAt.Equal(license.Add(time.Hour)) {
+		t.Errorf("Expected the license to expire the session at %v, got %v", license.Add(time.Hour), sessions[0].ExpiresAt)
+	}
+	if len(sessions[0].Restrictions) != 1 || sessions[0].Restrictions[0] != DirectiveWatermark {
+		t.Errorf("Expected a watermark restriction, got %v", sessions[0].Restrictions)
+	}
+	if want := sessions[1].StartTime.Add(8 * time.Hour); sessions[1].ExpiresAt == nil || !sessions[1].ExpiresAt.Equal(want) {
+		t.Errorf("Expected the session age limit at %v, got %v", want, sessions[1].ExpiresAt)
+	}
+
+	data, _ := json.Marshal(sessions)
+	for _, secret := range []string{first, second, "10.0.0.1"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Expected %s to be redacted from %s", secret, data)
+		}
+	}
+
+	// Without an expiring condition there is no expiry to report.
+	_ = uconE.RemoveCondition("max_age")
+	_ = uconE.RemoveCondition("license")
+	if data, _ := json.Marshal(uconE.GetMySessions("bob")); strings.Contains(string(data), "expires_at") {
+		t.Errorf("Expected no expires_at, got %s", data)
+	}
+}
+
+func TestRevokeMySessions(t *testing.T) {
+	uconE := GetUconEnforcer().(*UconEnforcer)
+	var mu sync.Mutex
+	stopped := 0
+	uconE.AddEventListener(func(event Event) {
+		if event.Type == EventSessionStopped {
+			mu.Lock()
+			stopped++
+			mu.Unlock()
+		}
+	})
+	phone, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{})
+	laptop, _ := uconE.CreateSession("alice", "write", "document1", map[string]interface{}{})
+	bobs, _ := uconE.CreateSession("bob", "read", "document1", map[string]interface{}{})
+
+	if _, err := uconE.RevokeMySessions("alice", "", phone, bobs); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Expected ErrSessionNotFound for bob's session, got %v", err)
+	}
+	if session, _ := uconE.GetSession(phone); !session.IfActive() {
+		t.Error("Expected no session to be stopped after a refused request")
+	}
+
+	if n, err := uconE.RevokeMySessions("alice", "lost my phone", phone); n != 1 || err != nil {
+		t.Fatalf("Expected one session revoked, got %d %v", n, err)
+	}
+	session, _ := uconE.GetSession(phone)
+	if reason := session.GetStopReason(); reason != UserInitiatedReason+": lost my phone" {
+		t.Errorf("Unexpected stop reason %q", reason)
+	}
+
+	if n, _ := uconE.RevokeMySessions("alice", ""); n != 1 {
+		t.Errorf("Expected the remaining session to be revoked, got %d", n)
+	}
+	session, _ = uconE.GetSession(laptop)
+	if session.IfActive() || session.GetStopReason() != UserInitiatedReason {
+		t.Errorf("Expected the laptop session to be signed out, got %q", session.GetStopReason())
+	}
+	if session, _ := uconE.GetSession(bobs); !session.IfActive() {
+		t.Error("Expected bob's session to stay active")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if stopped != 2 {
+		t.Errorf("Expected two session_stopped events, got %d", stopped)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrDependencyUnavailable should be wrapped by condition handlers whose
+// external dependency (a remote service, a database) cannot be reached, as
+// opposed to failures specific to the session. With WithNegativeCache such
+// failures are remembered per condition instead of retried by every session.
+var ErrDependencyUnavailable = errors.New("external dependency unavailable")
+
+// Counters reported to CounterMetrics by the negative cache, by condition ID.
+const (
+	MetricNegativeCacheHit  = "negative_cache_hit"
+	MetricNegativeCacheMiss = "negative_cache_miss"
+)
+
+// negativeCache remembers recent ErrDependencyUnavailable failures by
+// condition ID.
+type negativeCache struct {
+	ttl     time.Duration
+	entries map[string]cachedFailure
+	mu      sync.Mutex
+}
+
+type cachedFailure struct {
+	err     error
+	expires time.Time
+}
+
+// WithNegativeCache remembers a condition's ErrDependencyUnavailable failure
+// for ttl. Until it expires, evaluations of the condition fail with the
+// cached error without calling the handler, so a dependency that is hard down
+// is probed once per ttl rather than by every session on every tick, the way
+// an unhealthy attribute provider is skipped until its health check passes.
+// Hits and misses are counted in CounterMetrics.
+func WithNegativeCache(ttl time.Duration) Option {
+	return func(u *UconEnforcer) {
+		if ttl > 0 {
+			u.negativeCache = &negativeCache{ttl: ttl, entries: make(map[string]cachedFailure)}
+		}
+	}
+}
+
+func (c *negativeCache) get(conditionID string, now time.Time) (cachedFailure, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[conditionID]
+	if !ok {
+		return cachedFailure{}, false
+	}
+	if !now.Before(entry.expires) {
+		delete(c.entries, conditionID)
+		return cachedFailure{}, false
+	}
+	return entry, true
+}
+
+func (c *negativeCache) put(conditionID string, err error, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[conditionID] = cachedFailure{err: err, expires: now.Add(c.ttl)}
+}
+
+// cachedConditionFailure returns the cached failure of a condition, if any,
+// and counts the lookup.
+func (u *UconEnforcer) cachedConditionFailure(condition *Condition, now time.Time) error {
+	if u.negativeCache == nil {
+		return nil
+	}
+	if entry, ok := u.negativeCache.get(condition.ID, now); ok {
+		u.incCounter(MetricNegativeCacheHit, condition.ID)
+		return fmt.Errorf("cached failure: %w", entry.err)
+	}
+	u.incCounter(MetricNegativeCacheMiss, condition.ID)
+	return nil
+}
+
+// rememberConditionFailure caches err if it reports an unavailable dependency.
+func (u *UconEnforcer) rememberConditionFailure(condition *Condition, err error, now time.Time) {
+	if u.negativeCache != nil && errors.Is(err, ErrDependencyUnavailable) {
+		u.negativeCache.put(condition.ID, err, now)
+	}
+}
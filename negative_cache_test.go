@@ -0,0 +1,78 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNegativeCache(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithNegativeCache(200*time.Millisecond))
+
+	var calls int32
+	down := int32(1)
+	_ = uconE.RegisterConditionHandler("risk_score", func(expr string, session SessionView) (bool, error) {
+		atomic.AddInt32(&calls, 1)
+		if atomic.LoadInt32(&down) == 1 {
+			return false, fmt.Errorf("risk service: %w", ErrDependencyUnavailable)
+		}
+		return true, nil
+	})
+	_ = uconE.RegisterConditionHandler("needs_role", func(expr string, session SessionView) (bool, error) {
+		return false, errors.New("role attribute not found")
+	})
+	_ = uconE.AddCondition(&Condition{ID: "risk", Name: "risk_score", Kind: "one"})
+
+	var sessions []string
+	for i := 0; i < 5; i++ {
+		sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+		sessions = append(sessions, sessionID)
+	}
+	for _, sessionID := range sessions {
+		_, err := uconE.EvaluateConditions(sessionID)
+		if !errors.Is(err, ErrDependencyUnavailable) {
+			t.Fatalf("Expected the dependency failure, got %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Expected the dependency to be called once, got %d calls", calls)
+	}
+	counters := uconE.GetMetrics().(*InMemoryMetrics).Snapshot().Counters
+	if counters[MetricNegativeCacheHit]["risk"] != 4 || counters[MetricNegativeCacheMiss]["risk"] != 1 {
+		t.Errorf("Unexpected negative cache counters %v", counters)
+	}
+
+	// Once the failure expires, the recovered dependency is called again.
+	atomic.StoreInt32(&down, 0)
+	time.Sleep(250 * time.Millisecond)
+	if ok, err := uconE.EvaluateConditions(sessions[0]); !ok || err != nil {
+		t.Fatalf("Expected the condition to pass, got %v", err)
+	}
+
+	// Session-specific failures are not cached.
+	_ = uconE.RemoveCondition("risk")
+	_ = uconE.AddCondition(&Condition{ID: "role", Name: "needs_role", Kind: "one"})
+	_, _ = uconE.EvaluateConditions(sessions[0])
+	_, _ = uconE.EvaluateConditions(sessions[1])
+	counters = uconE.GetMetrics().(*InMemoryMetrics).Snapshot().Counters
+	if counters[MetricNegativeCacheHit]["role"] != 0 {
+		t.Errorf("Expected session-specific failures not to be cached, got %v", counters)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ObjectAttributeProvider supplies the attributes of objects, such as their
+// classification, owner or embargo date, e.g. from a document management
+// system. It is called on every evaluation referencing object attributes, so
+// implementations should cache answers.
+type ObjectAttributeProvider interface {
+	ObjectAttributes(object string) (map[string]interface{}, error)
+}
+
+// SetObjectAttributes replaces the attributes registered for an object.
+// Sessions on the object see them at their next evaluation.
+func (u *UconEnforcer) SetObjectAttributes(object string, attributes map[string]interface{}) error {
+	if object == "" {
+		return errors.New("object cannot be empty")
+	}
+	copied := make(map[string]interface{}, len(attributes))
+	for k, v := range attributes {
+		copied[k] = v
+	}
+	u.mu.Lock()
+	if u.objectAttributes == nil {
+		u.objectAttributes = make(map[string]map[string]interface{})
+	}
+	u.objectAttributes[object] = copied
+	u.mu.Unlock()
+	u.InvalidateDecisions()
+	return nil
+}
+
+// RemoveObjectAttributes removes the attributes registered for an object.
+func (u *UconEnforcer) RemoveObjectAttributes(object string) {
+	u.mu.Lock()
+	delete(u.objectAttributes, object)
+	u.mu.Unlock()
+	u.InvalidateDecisions()
+}
+
+// RegisterObjectAttributeProvider registers a provider of object attributes.
+func (u *UconEnforcer) RegisterObjectAttributeProvider(name string, provider ObjectAttributeProvider) error {
+	if name == "" {
+		return errors.New("object attribute provider name cannot be empty")
+	}
+	if provider == nil {
+		return errors.New("object attribute provider cannot be nil")
+	}
+	u.mu.Lock()
+	if u.objectProviders == nil {
+		u.objectProviders = make(map[string]ObjectAttributeProvider)
+	}
+	u.objectProviders[name] = provider
+	u.mu.Unlock()
+	u.InvalidateDecisions()
+	return nil
+}
+
+// GetObjectAttributes returns the attributes of an object: those of the
+// providers, in name order, overridden by the registered ones. A provider
+// error wraps ErrDependencyUnavailable.
+func (u *UconEnforcer) GetObjectAttributes(object string) (map[string]interface{}, error) {
+	u.mu.RLock()
+	names := make([]string, 0, len(u.objectProviders))
+	providers := make(map[string]ObjectAttributeProvider, len(u.objectProviders))
+	for name, provider := range u.objectProviders {
+		names = append(names, name)
+		providers[name] = provider
+	}
+	registered := u.objectAttributes[object]
+	u.mu.RUnlock()
+	sort.Strings(names)
+
+	attributes := make(map[string]interface{})
+	for _, name := range names {
+		provided, err := providers[name].ObjectAttributes(object)
+		if err != nil {
+			return nil, fmt.Errorf("object attribute provider %s: %w: %w", name, ErrDependencyUnavailable, err)
+		}
+		for k, v := range provided {
+			attributes[k] = v
+		}
+	}
+	for k, v := range registered {
+		attributes[k] = v
+	}
+	return attributes, nil
+}
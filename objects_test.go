@@ -0,0 +1,81 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+type fakeObjectProvider struct {
+	calls atomic.Int32
+	down  atomic.Bool
+}
+
+func (p *fakeObjectProvider) ObjectAttributes(object string) (map[string]interface{}, error) {
+	p.calls.Add(1)
+	if p.down.Load() {
+		return nil, errors.New("connection refused")
+	}
+	return map[string]interface{}{"owner": "alice", "classification": "internal"}, nil
+}
+
+func TestObjectAttributes(t *testing.T) {
+	uconE := GetUconEnforcer()
+	provider := &fakeObjectProvider{}
+	_ = uconE.RegisterObjectAttributeProvider("dms", provider)
+	_ = uconE.SetObjectAttributes("document1", map[string]interface{}{"classification": "public"})
+
+	attributes, err := uconE.GetObjectAttributes("document1")
+	if err != nil || attributes["owner"] != "alice" || attributes["classification"] != "public" {
+		t.Fatalf("Expected provided attributes overridden by registered ones, got %v, %v", attributes, err)
+	}
+
+	_ = uconE.AddCondition(&Condition{ID: "owner_or_public", Name: "expression", Kind: "always", Expr: "obj.owner == subject || obj.classification == 'public'"})
+	bobID, _ := uconE.CreateSession("bob", "read", "document1", nil)
+	if ok, err := uconE.EvaluateConditions(bobID); err != nil || !ok {
+		t.Fatalf("Expected bob to read the public document, got %v, %v", ok, err)
+	}
+
+	_ = uconE.SetObjectAttributes("document1", map[string]interface{}{"classification": "secret"})
+	if ok, err := uconE.EvaluateConditions(bobID); err != nil || ok {
+		t.Errorf("Expected the reclassified document to be denied, got %v, %v", ok, err)
+	}
+	aliceID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	if ok, err := uconE.EvaluateConditions(aliceID); err != nil || !ok {
+		t.Errorf("Expected the owner to keep access, got %v, %v", ok, err)
+	}
+
+	provider.down.Store(true)
+	if _, err := uconE.EvaluateConditions(aliceID); !errors.Is(err, ErrDependencyUnavailable) {
+		t.Errorf("Expected ErrDependencyUnavailable, got %v", err)
+	}
+}
+
+func TestObjectAttributesLookedUpOnDemand(t *testing.T) {
+	uconE := GetUconEnforcer()
+	provider := &fakeObjectProvider{}
+	_ = uconE.RegisterObjectAttributeProvider("dms", provider)
+	_ = uconE.AddCondition(&Condition{ID: "subject_only", Name: "expression", Kind: "always", Expr: "subject == 'alice'"})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	if ok, err := uconE.EvaluateConditions(sessionID); err != nil || !ok {
+		t.Fatalf("Expected the condition to pass, got %v, %v", ok, err)
+	}
+	if calls := provider.calls.Load(); calls != 0 {
+		t.Errorf("Expected no object attribute lookups, got %d", calls)
+	}
+}
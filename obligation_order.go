@@ -0,0 +1,81 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrDependencyCycle is wrapped by AddObligation when the obligation's After
+// dependencies form a cycle.
+var ErrDependencyCycle = errors.New("obligation dependency cycle")
+
+// orderObligations sorts obligations so that each runs after the obligations
+// listed in its After, and otherwise by ID. Dependencies on obligations that
+// do not exist are ignored.
+func orderObligations(obligations map[string]Obligation) ([]Obligation, error) {
+	ids := make([]string, 0, len(obligations))
+	for id := range obligations {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int, len(obligations))
+	ordered := make([]Obligation, 0, len(obligations))
+	var path []string
+	var visit func(id string) error
+	visit = func(id string) error {
+		switch state[id] {
+		case done:
+			return nil
+		case visiting:
+			start := 0
+			for path[start] != id {
+				start++
+			}
+			cycle := append(append([]string(nil), path[start:]...), id)
+			return fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(cycle, " -> "))
+		}
+		state[id] = visiting
+		path = append(path, id)
+		after := append([]string(nil), obligations[id].After...)
+		sort.Strings(after)
+		for _, dep := range after {
+			if _, exists := obligations[dep]; !exists {
+				continue
+			}
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = done
+		ordered = append(ordered, obligations[id])
+		return nil
+	}
+	for _, id := range ids {
+		if err := visit(id); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
@@ -0,0 +1,276 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EventObligation is emitted by the emit_event obligation.
+const EventObligation EventType = "obligation"
+
+// builtinObligations returns the ready-made obligation handlers, keyed by the
+// Obligation.Name that selects them.
+func (u *UconEnforcer) builtinObligations() map[string]ObligationHandler {
+	return map[string]ObligationHandler{
+		"access_logging":    u.executeAccessLogging,
+		"set_attribute":     u.executeSetAttribute,
+		"increment_counter": u.executeIncrementCounter,
+		"emit_event":        u.executeEmitEvent,
+		"attribute_update":  u.executeAttributeUpdate,
+		"caller_action":     u.executeCallerAction,
+	}
+}
+
+// executeAccessLogging writes a structured access log entry through the
+// enforcer's Logger. Expr is the log message; "log_level:detailed" also logs
+// the session attributes.
+func (u *UconEnforcer) executeAccessLogging(expr string, session *Session) error {
+	if session.IsDryRun() {
+		return nil
+	}
+	record := session.ToRecord()
+	fields := sessionFields(session)
+	fields["active"] = record.Active
+	fields["duration"] = session.GetDuration().Round(time.Millisecond)
+	if strings.TrimSpace(expr) == "log_level:detailed" {
+		fields["attributes"] = record.Attributes
+	}
+	u.logger.Log(LevelInfo, "[ACCESS LOG] "+expr, fields)
+	return nil
+}
+
+// executeSetAttribute sets a session attribute. Expr: "key:value"; the value
+// is stored as an int, float or bool when it parses as one, else as a string.
+func (u *UconEnforcer) executeSetAttribute(expr string, session *Session) error {
+	key, value, err := splitKeyValue(expr)
+	if err != nil {
+		return err
+	}
+	return u.setAttributes(session, map[string]interface{}{key: parseLiteral(value)}, false)
+}
+
+// executeIncrementCounter atomically increments an integer attribute.
+// Expr: "key" (increment by one) or "key:delta", e.g. "downloads_remaining:-1".
+func (u *UconEnforcer) executeIncrementCounter(expr string, session *Session) error {
+	key, delta := strings.TrimSpace(expr), int64(1)
+	if strings.Contains(expr, ":") {
+		k, d, err := splitKeyValue(expr)
+		if err != nil {
+			return err
+		}
+		if delta, err = strconv.ParseInt(d, 10, 64); err != nil {
+			return fmt.Errorf("invalid delta in %s: %w", expr, err)
+		}
+		key = k
+	}
+	_, err := u.incrementAttribute(session, key, delta)
+	return err
+}
+
+// executeAttributeUpdate applies UCON attribute mutations, typically as a
+// "pre" or "post" update. Expr: one or more ";" separated assignments of the
+// form "key = expr", "key += expr" or "key -= expr", where expr is an
+// expression evaluated against the session, e.g.
+// "usage_count += 1; last_object = object". Integer increments are atomic.
+// Keys prefixed with "subject." update the subject attributes shared by all
+// of the subject's sessions, e.g. "subject.risk_score += 5".
+func (u *UconEnforcer) executeAttributeUpdate(expr string, session *Session) error {
+	for _, stmt := range strings.Split(expr, ";") {
+		if strings.TrimSpace(stmt) == "" {
+			continue
+		}
+		if err := u.applyAttributeUpdate(stmt, session); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *UconEnforcer) applyAttributeUpdate(stmt string, session *Session) error {
+	i := strings.Index(stmt, "=")
+	if i <= 0 {
+		return fmt.Errorf("invalid attribute update %q, expected 'key = expr'", stmt)
+	}
+	op := "="
+	if stmt[i-1] == '+' || stmt[i-1] == '-' {
+		op = stmt[i-1 : i+1]
+	}
+	key := strings.TrimSpace(stmt[:i+1-len(op)])
+	key = strings.TrimPrefix(key, "session.")
+	key, onSubject := strings.CutPrefix(key, "subject.")
+	if key == "" || strings.ContainsAny(key, " \t") {
+		return fmt.Errorf("invalid attribute name in %q", stmt)
+	}
+
+	value, err := u.expressions.evaluate(strings.TrimSpace(stmt[i+1:]), session)
+	if err != nil {
+		return err
+	}
+	if onSubject {
+		if session.IsDryRun() {
+			return nil // subject attributes are shared with other sessions
+		}
+		return u.applySubjectUpdate(stmt, session.GetSubject(), key, op, value)
+	}
+
+	if op == "=" {
+		if f, ok := value.(float64); ok && f == float64(int(f)) {
+			value = int(f)
+		}
+		return u.setAttributes(session, map[string]interface{}{key: value}, false)
+	}
+
+	delta, ok := value.(float64)
+	if !ok {
+		return fmt.Errorf("attribute update %q: %v is not a number", stmt, value)
+	}
+	if op == "-=" {
+		delta = -delta
+	}
+	current := session.GetAttribute(key)
+	if _, isInt := toInt64(current); (isInt || current == nil) && delta == float64(int64(delta)) {
+		_, err = u.incrementAttribute(session, key, int64(delta))
+		return err
+	}
+	f, ok := toFloat64(current)
+	if current == nil {
+		f, ok = 0, true
+	}
+	if !ok {
+		return fmt.Errorf("attribute update %q: %s is not a number", stmt, key)
+	}
+	return u.setAttributes(session, map[string]interface{}{key: f + delta}, false)
+}
+
+func (u *UconEnforcer) applySubjectUpdate(stmt string, subject string, key string, op string, value interface{}) error {
+	if op == "=" {
+		if f, ok := value.(float64); ok && f == float64(int(f)) {
+			value = int(f)
+		}
+		return u.SetSubjectAttributes(subject, map[string]interface{}{key: value})
+	}
+	delta, ok := value.(float64)
+	if !ok {
+		return fmt.Errorf("attribute update %q: %v is not a number", stmt, value)
+	}
+	if op == "-=" {
+		delta = -delta
+	}
+	if err := u.subjects.add(subject, key, delta); err != nil {
+		return fmt.Errorf("attribute update %q: %w", stmt, err)
+	}
+	u.InvalidateDecisions()
+	return nil
+}
+
+// executeEmitEvent emits an EventObligation event whose message is Expr.
+func (u *UconEnforcer) executeEmitEvent(expr string, session *Session) error {
+	if session.IsDryRun() {
+		return nil
+	}
+	u.emit(Event{
+		Type:      EventObligation,
+		SessionID: session.GetId(),
+		Message:   expr,
+		Data:      sessionFields(session),
+	})
+	return nil
+}
+
+// WebhookPayload is the JSON body posted by the webhook obligation.
+type WebhookPayload struct {
+	SessionID string    `json:"session_id"`
+	Subject   string    `json:"subject"`
+	Action    string    `json:"action"`
+	Object    string    `json:"object"`
+	Active    bool      `json:"active"`
+	StartTime time.Time `json:"start_time"`
+	Time      time.Time `json:"time"`
+}
+
+// executeWebhook posts the session to a URL, with the idempotency key in the
+// Idempotency-Key header. Expr: the URL. Any response other than 2xx fails
+// the obligation.
+func (u *UconEnforcer) executeWebhook(expr string, session *Session, key string) error {
+	url := strings.TrimSpace(expr)
+	body, err := json.Marshal(&WebhookPayload{
+		SessionID: session.GetId(),
+		Subject:   session.GetSubject(),
+		Action:    session.GetAction(),
+		Object:    session.GetObject(),
+		Active:    session.IfActive(),
+		StartTime: session.GetStartTime(),
+		Time:      time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook %s failed: %w", url, err)
+	}
+	if session.IsDryRun() {
+		return nil
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(IdempotencyKeyHeader, key)
+	resp, err := u.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook %s failed: %w", url, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook %s returned %s", url, resp.Status)
+	}
+	return nil
+}
+
+// WithHTTPClient sets the HTTP client used by the webhook obligation. The
+// default client times out after 5 seconds.
+func WithHTTPClient(client *http.Client) Option {
+	return func(u *UconEnforcer) {
+		if client != nil {
+			u.httpClient = client
+		}
+	}
+}
+
+// parseLiteral converts a textual value to an int, float64 or bool ("true"
+// or "false") when possible, falling back to the string itself.
+func parseLiteral(s string) interface{} {
+	if i, err := strconv.Atoi(s); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return s
+}
@@ -0,0 +1,164 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBuiltinObligations(t *testing.T) {
+	var logs bytes.Buffer
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithLogger(&DefaultLogger{Level: LevelInfo, Writer: &logs}))
+
+	var events []Event
+	uconE.AddEventListener(func(event Event) {
+		events = append(events, event)
+	})
+
+	var received WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		if strings.HasSuffix(r.URL.Path, "/fail") {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{
+		"downloads_remaining": 3,
+	})
+	session, _ := uconE.GetSession(sessionID)
+
+	obligations := []*Obligation{
+		{ID: "log", Name: "access_logging", Kind: "post", Expr: "log_level:detailed"},
+		{ID: "set_tier", Name: "set_attribute", Kind: "post", Expr: "tier:gold"},
+		{ID: "set_limit", Name: "set_attribute", Kind: "post", Expr: "limit:10"},
+		{ID: "count_grants", Name: "increment_counter", Kind: "post", Expr: "grants"},
+		{ID: "use_download", Name: "increment_counter", Kind: "post", Expr: "downloads_remaining:-1"},
+		{ID: "notify", Name: "emit_event", Kind: "post", Expr: "document downloaded"},
+		{ID: "hook", Name: "webhook", Kind: "post", Expr: server.URL + "/usage"},
+	}
+	for _, obligation := range obligations {
+		_ = uconE.AddObligation(obligation)
+	}
+	if err := uconE.ExecuteObligationsByType(sessionID, "post"); err != nil {
+		t.Fatalf("Failed to execute obligations: %v", err)
+	}
+
+	if !strings.Contains(logs.String(), "[ACCESS LOG] log_level:detailed") || !strings.Contains(logs.String(), "subject=alice") {
+		t.Errorf("Expected structured access log entry, got %q", logs.String())
+	}
+	if session.GetAttribute("tier") != "gold" || session.GetAttribute("limit") != 10 {
+		t.Errorf("Expected set_attribute to set typed values, got %v and %v", session.GetAttribute("tier"), session.GetAttribute("limit"))
+	}
+	if session.GetAttribute("grants") != 1 || session.GetAttribute("downloads_remaining") != 2 {
+		t.Errorf("Expected counters 1 and 2, got %v and %v", session.GetAttribute("grants"), session.GetAttribute("downloads_remaining"))
+	}
+	if len(events) != 1 || events[0].Type != EventObligation || events[0].Message != "document downloaded" {
+		t.Errorf("Expected one obligation event, got %+v", events)
+	}
+	if received.SessionID != sessionID || received.Subject != "alice" {
+		t.Errorf("Expected webhook payload for %s, got %+v", sessionID, received)
+	}
+
+	_ = uconE.AddObligation(&Obligation{ID: "hook", Name: "webhook", Kind: "post", Expr: server.URL + "/fail"})
+	if err := uconE.ExecuteObligationsByType(sessionID, "post"); err == nil {
+		t.Error("Expected failing webhook to fail the obligation")
+	}
+}
+
+func TestAttributeUpdateObligation(t *testing.T) {
+	uconE := GetUconEnforcer()
+
+	_ = uconE.AddCondition(&Condition{ID: "quota", Name: "numeric_threshold", Kind: "always", Expr: "usage_count < 2"})
+	_ = uconE.AddObligation(&Obligation{
+		ID:   "post_update",
+		Name: "attribute_update",
+		Kind: "post",
+		Expr: `usage_count += 1; credits -= 0.5; last_object = object; session.flagged = usage_count >= 2`,
+	})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{
+		"usage_count": 0,
+		"credits":     2.0,
+	})
+	session, _ := uconE.GetSession(sessionID)
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := uconE.EvaluateConditions(sessionID); !ok {
+			t.Fatalf("Grant %d should be allowed", i+1)
+		}
+		if err := uconE.ExecuteObligationsByType(sessionID, "post"); err != nil {
+			t.Fatalf("Failed to execute post-update: %v", err)
+		}
+	}
+
+	if session.GetAttribute("usage_count") != 2 {
+		t.Errorf("Expected usage_count 2, got %v", session.GetAttribute("usage_count"))
+	}
+	if session.GetAttribute("credits") != 1.0 {
+		t.Errorf("Expected credits 1.0, got %v", session.GetAttribute("credits"))
+	}
+	if session.GetAttribute("last_object") != "document1" || session.GetAttribute("flagged") != true {
+		t.Errorf("Expected last_object and flagged to be set, got %v, %v", session.GetAttribute("last_object"), session.GetAttribute("flagged"))
+	}
+	if ok, _ := uconE.EvaluateConditions(sessionID); ok {
+		t.Error("Expected the post-update to exhaust the quota")
+	}
+
+	u := uconE.(*UconEnforcer)
+	for _, expr := range []string{"usage_count", "= 1", "last_object += 1", "usage_count += object"} {
+		if err := u.executeAttributeUpdate(expr, session); err == nil {
+			t.Errorf("%q: expected an error", expr)
+		}
+	}
+}
+
+func TestObligationDependencies(t *testing.T) {
+	uconE := GetUconEnforcer()
+	var order []string
+	_ = uconE.RegisterObligationHandler("record", func(expr string, session *Session) error {
+		order = append(order, expr)
+		return nil
+	})
+	// Without dependencies "audit" would run first.
+	_ = uconE.AddObligation(&Obligation{ID: "audit", Name: "record", Kind: "pre", Expr: "audit", After: []string{"notice"}})
+	_ = uconE.AddObligation(&Obligation{ID: "notice", Name: "record", Kind: "pre", Expr: "notice", After: []string{"login"}})
+	_ = uconE.AddObligation(&Obligation{ID: "login", Name: "record", Kind: "pre", Expr: "login"})
+	_ = uconE.AddObligation(&Obligation{ID: "banner", Name: "record", Kind: "pre", Expr: "banner", After: []string{"missing"}})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	if err := uconE.ExecuteObligationsByType(sessionID, "pre"); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(order, ","); got != "login,notice,audit,banner" {
+		t.Errorf("Unexpected execution order %s", got)
+	}
+
+	err := uconE.AddObligation(&Obligation{ID: "login", Name: "record", Kind: "pre", Expr: "login", After: []string{"audit"}})
+	if !errors.Is(err, ErrDependencyCycle) || !strings.Contains(err.Error(), "audit -> notice -> login -> audit") {
+		t.Errorf("Expected a dependency cycle error, got %v", err)
+	}
+	if obligations := uconE.GetObligations(); obligations[0].ID != "login" || len(obligations[0].After) != 0 {
+		t.Errorf("Expected the rejected obligation not to replace the existing one, got %+v", obligations[0])
+	}
+}
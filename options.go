@@ -0,0 +1,46 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+// Option configures a UconEnforcer at construction time.
+type Option func(u *UconEnforcer)
+
+// WithSessionStore persists sessions to store. Active, monitored sessions
+// found in the store are reloaded and monitored again by NewUconEnforcer.
+func WithSessionStore(store SessionStore) Option {
+	return func(u *UconEnforcer) {
+		u.store = store
+	}
+}
+
+// WithSessionManager replaces the built-in SessionManager with a custom
+// session registry. A nil manager is ignored.
+func WithSessionManager(manager ISessionManager) Option {
+	return func(u *UconEnforcer) {
+		if manager != nil {
+			u.sessions = manager
+		}
+	}
+}
+
+// WithStoppedSessionUpdates lets UpdateSessionAttribute and
+// UpdateSessionAttributes modify sessions that have already stopped. By
+// default they are refused, so late updates cannot alter the audit record of
+// a revoked session.
+func WithStoppedSessionUpdates() Option {
+	return func(u *UconEnforcer) {
+		u.stoppedSessionUpdates = true
+	}
+}
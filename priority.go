@@ -0,0 +1,130 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"time"
+)
+
+// Priority is the priority class of a session, set by WithPriorities.
+type Priority string
+
+const (
+	// PriorityCritical sessions are evaluated most often and evict lower
+	// priority sessions when the monitoring capacity is reached.
+	PriorityCritical Priority = "critical"
+	// PriorityStandard is the priority of sessions no PriorityRule matches.
+	PriorityStandard Priority = "standard"
+	// PriorityBackground sessions, such as batch jobs, are evaluated least
+	// often and are the first to be evicted.
+	PriorityBackground Priority = "background"
+)
+
+// PriorityRule gives the sessions of a subject on an object a priority
+// class. An empty Subject or Object matches every subject or object.
+type PriorityRule struct {
+	Subject  string   `json:"subject,omitempty"`
+	Object   string   `json:"object,omitempty"`
+	Priority Priority `json:"priority"`
+}
+
+// WithPriorities sets the priority classes of sessions. The first matching
+// rule applies; sessions no rule matches are PriorityStandard. Priorities are
+// part of the enforcer configuration rather than session attributes, since
+// critical sessions evict others.
+func WithPriorities(rules ...PriorityRule) Option {
+	return func(u *UconEnforcer) {
+		u.priorities = append([]PriorityRule(nil), rules...)
+	}
+}
+
+// priorityFor returns the priority class of the sessions of sub on obj.
+func (u *UconEnforcer) priorityFor(sub string, obj string) Priority {
+	for _, rule := range u.priorities {
+		if (rule.Subject == "" || rule.Subject == sub) && (rule.Object == "" || rule.Object == obj) {
+			switch rule.Priority {
+			case PriorityCritical, PriorityBackground:
+				return rule.Priority
+			default:
+				return PriorityStandard
+			}
+		}
+	}
+	return PriorityStandard
+}
+
+// EvictedReason is the stop reason of sessions evicted to make room for a
+// higher priority session.
+const EvictedReason = "evicted for a higher priority session"
+
+const (
+	// CriticalMonitorInterval is how often critical sessions are evaluated.
+	CriticalMonitorInterval = 100 * time.Millisecond
+	// BackgroundMonitorInterval is how often background sessions are evaluated.
+	BackgroundMonitorInterval = time.Second
+)
+
+// GetPriority returns the priority class of the session.
+func (s *Session) GetPriority() Priority {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if s.priority == "" {
+		return PriorityStandard
+	}
+	return s.priority
+}
+
+func (p Priority) rank() int {
+	switch p {
+	case PriorityCritical:
+		return 2
+	case PriorityBackground:
+		return 0
+	default:
+		return 1
+	}
+}
+
+func (p Priority) monitorInterval() time.Duration {
+	switch p {
+	case PriorityCritical:
+		return CriticalMonitorInterval
+	case PriorityBackground:
+		return BackgroundMonitorInterval
+	default:
+		return DefaultMonitorInterval
+	}
+}
+
+// evictionCandidateLocked returns the monitored session to evict for a
+// session of priority p: the lowest priority one below p, most recently
+// started first. The caller must hold u.mu.
+func (u *UconEnforcer) evictionCandidateLocked(p Priority) *Session {
+	var victim *Session
+	for id, monitored := range u.monitoringActive {
+		if !monitored {
+			continue
+		}
+		session, err := u.sessions.GetSessionById(id)
+		if err != nil || !session.IfActive() || session.GetPriority().rank() >= p.rank() {
+			continue
+		}
+		if victim == nil || session.GetPriority().rank() < victim.GetPriority().rank() ||
+			session.GetPriority() == victim.GetPriority() && session.GetStartTime().After(victim.GetStartTime()) {
+			victim = session
+		}
+	}
+	return victim
+}
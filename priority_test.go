@@ -0,0 +1,98 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPriorityIntervals(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithPriorities(
+		PriorityRule{Subject: "surgeon", Priority: PriorityCritical},
+		PriorityRule{Subject: "batch", Object: "record", Priority: PriorityBackground},
+		PriorityRule{Subject: "alice", Priority: "urgent"},
+	))
+	critical, _ := uconE.CreateSession("surgeon", "read", "record", nil)
+	background, _ := uconE.CreateSession("batch", "read", "record", nil)
+	_ = uconE.StartMonitoring(critical)
+	_ = uconE.StartMonitoring(background)
+	time.Sleep(700 * time.Millisecond)
+	_ = uconE.StopMonitoring(critical)
+	_ = uconE.StopMonitoring(background)
+
+	criticalTrace, _ := uconE.GetSessionTrace(critical)
+	backgroundTrace, _ := uconE.GetSessionTrace(background)
+	if len(criticalTrace) < 5 || len(backgroundTrace) != 0 {
+		t.Errorf("Expected critical sessions to be evaluated more often, got %d and %d evaluations", len(criticalTrace), len(backgroundTrace))
+	}
+
+	session, _ := uconE.GetSession(critical)
+	if session.GetPriority() != PriorityCritical {
+		t.Errorf("Expected critical priority, got %s", session.GetPriority())
+	}
+	other, _ := uconE.CreateSession("alice", "read", "record", nil)
+	session, _ = uconE.GetSession(other)
+	if session.GetPriority() != PriorityStandard {
+		t.Errorf("Expected unknown priorities to be standard, got %s", session.GetPriority())
+	}
+	claimed, _ := uconE.CreateSession("bob", "read", "record", map[string]interface{}{"priority": "critical"})
+	session, _ = uconE.GetSession(claimed)
+	if session.GetPriority() != PriorityStandard {
+		t.Errorf("Expected a priority attribute to be ignored, got %s", session.GetPriority())
+	}
+}
+
+func TestPriorityEviction(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithMaxMonitoredSessions(2, CapacityReject), WithPriorities(
+		PriorityRule{Subject: "batch", Priority: PriorityBackground},
+		PriorityRule{Subject: "alice", Object: "document1", Priority: PriorityCritical},
+	))
+	_ = uconE.AddObligation(&Obligation{ID: "release", Name: "set_attribute", Kind: "post", Expr: "released:true"})
+
+	batch1, _ := uconE.CreateSession("batch", "read", "document1", nil)
+	batch2, _ := uconE.CreateSession("batch", "read", "document1", nil)
+	_ = uconE.StartMonitoring(batch1)
+	_ = uconE.StartMonitoring(batch2)
+
+	batch3, _ := uconE.CreateSession("batch", "read", "document1", nil)
+	if err := uconE.StartMonitoring(batch3); !errors.Is(err, ErrCapacityExceeded) {
+		t.Fatalf("Expected background sessions not to evict each other, got %v", err)
+	}
+
+	surgeon, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	if session, err := uconE.EnforceWithSession(surgeon); session == nil {
+		t.Fatalf("Expected the critical session to evict a background session, got %v", err)
+	}
+	evicted, _ := uconE.GetSession(batch2)
+	kept, _ := uconE.GetSession(batch1)
+	if evicted.IfActive() || evicted.GetStopReason() != EvictedReason {
+		t.Errorf("Expected the most recent background session to be evicted, got %q", evicted.GetStopReason())
+	}
+	if evicted.GetAttribute("released") != true {
+		t.Error("Expected the post obligations of the evicted session to run")
+	}
+	if trace, _ := uconE.GetSessionTrace(batch2); len(trace) == 0 || trace[len(trace)-1].StopReason != EvictedReason {
+		t.Errorf("Expected the eviction to be traced, got %+v", trace)
+	}
+	if !kept.IfActive() {
+		t.Error("Expected only one session to be evicted")
+	}
+	_ = uconE.StopMonitoring(batch1)
+	_ = uconE.StopMonitoring(surgeon)
+}
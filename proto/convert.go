@@ -0,0 +1,260 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package uconpb holds the Go types generated from the protobuf definitions
+// of sessions, rules, decisions and events, and converters from and to the
+// types of package ucon. The encoding of SessionRecord is the one written by
+// ucon.ProtobufCodec.
+package uconpb
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	ucon "github.com/casbin/casbin-ucon"
+	"google.golang.org/protobuf/proto"
+)
+
+// FromCondition converts a condition.
+func FromCondition(c ucon.Condition) *Condition {
+	return &Condition{
+		Id:               c.ID,
+		Name:             c.Name,
+		Kind:             c.Kind,
+		Expr:             c.Expr,
+		Purposes:         c.Purposes,
+		Dwell:            int64(c.Dwell),
+		Attributes:       c.Attributes,
+		Shadow:           c.Shadow,
+		Rollout:          int32(c.Rollout),
+		BreakGlassBypass: c.BreakGlassBypass,
+	}
+}
+
+// ToCondition converts a condition back.
+func ToCondition(c *Condition) ucon.Condition {
+	return ucon.Condition{
+		ID:               c.GetId(),
+		Name:             c.GetName(),
+		Kind:             c.GetKind(),
+		Expr:             c.GetExpr(),
+		Purposes:         c.GetPurposes(),
+		Dwell:            time.Duration(c.GetDwell()),
+		Attributes:       c.GetAttributes(),
+		Shadow:           c.GetShadow(),
+		Rollout:          int(c.GetRollout()),
+		BreakGlassBypass: c.GetBreakGlassBypass(),
+	}
+}
+
+// FromObligation converts an obligation.
+func FromObligation(o ucon.Obligation) *Obligation {
+	return &Obligation{
+		Id:         o.ID,
+		Name:       o.Name,
+		Kind:       o.Kind,
+		Expr:       o.Expr,
+		Purposes:   o.Purposes,
+		Schedule:   o.Schedule,
+		After:      o.After,
+		Deadline:   int64(o.Deadline),
+		Shadow:     o.Shadow,
+		Rollout:    int32(o.Rollout),
+		BreakGlass: o.BreakGlass,
+	}
+}
+
+// ToObligation converts an obligation back.
+func ToObligation(o *Obligation) ucon.Obligation {
+	return ucon.Obligation{
+		ID:         o.GetId(),
+		Name:       o.GetName(),
+		Kind:       o.GetKind(),
+		Expr:       o.GetExpr(),
+		Purposes:   o.GetPurposes(),
+		Schedule:   o.GetSchedule(),
+		After:      o.GetAfter(),
+		Deadline:   time.Duration(o.GetDeadline()),
+		Shadow:     o.GetShadow(),
+		Rollout:    int(o.GetRollout()),
+		BreakGlass: o.GetBreakGlass(),
+	}
+}
+
+// FromSessionRecord converts a session record through its
+// ucon.ProtobufCodec encoding.
+func FromSessionRecord(record *ucon.SessionRecord) (*SessionRecord, error) {
+	data, err := ucon.ProtobufCodec{}.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	converted := &SessionRecord{}
+	if err := proto.Unmarshal(data, converted); err != nil {
+		return nil, err
+	}
+	return converted, nil
+}
+
+// ToSessionRecord converts a session record back.
+func ToSessionRecord(record *SessionRecord) (*ucon.SessionRecord, error) {
+	data, err := proto.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	converted := &ucon.SessionRecord{}
+	if err := (ucon.ProtobufCodec{}).Unmarshal(data, converted); err != nil {
+		return nil, err
+	}
+	return converted, nil
+}
+
+// FromDecision converts a decision and the error Decide returned with it,
+// which sets the reason code of a denial.
+func FromDecision(d *ucon.Decision, decideErr error) (*Decision, error) {
+	converted := &Decision{
+		Allowed:           d.Allowed,
+		Degraded:          d.Degraded,
+		MissingAttributes: d.MissingAttributes,
+		Code:              string(ucon.ReasonFor(decideErr)),
+	}
+	if d.RetryAfter != nil {
+		converted.RetryAfter = d.RetryAfter.UnixNano()
+	}
+	for _, directive := range d.Directives {
+		converted.Directives = append(converted.Directives, &Directive{Type: directive.Type, Fields: directive.Fields, Params: directive.Params})
+	}
+	if d.Session != nil {
+		record, err := FromSessionRecord(d.Session.ToRecord())
+		if err != nil {
+			return nil, err
+		}
+		converted.Session = record
+	}
+	return converted, nil
+}
+
+// FromEvent converts an event.
+func FromEvent(e ucon.Event) (*Event, error) {
+	converted := &Event{
+		SchemaVersion: ucon.EventSchemaVersion,
+		Type:          string(e.Type),
+		SessionId:     e.SessionID,
+		Message:       e.Message,
+	}
+	if !e.Time.IsZero() {
+		converted.Time = e.Time.UnixNano()
+	}
+	if len(e.Data) > 0 {
+		converted.Data = make(map[string]*AttributeValue, len(e.Data))
+		for k, v := range e.Data {
+			value, err := NewAttributeValue(v)
+			if err != nil {
+				return nil, fmt.Errorf("event data %q: %w", k, err)
+			}
+			converted.Data[k] = value
+		}
+	}
+	return converted, nil
+}
+
+// ToEvent converts an event back. Events of a newer schema version are
+// rejected.
+func ToEvent(e *Event) (ucon.Event, error) {
+	if e.GetSchemaVersion() > ucon.EventSchemaVersion {
+		return ucon.Event{}, fmt.Errorf("unsupported event schema version %d", e.GetSchemaVersion())
+	}
+	converted := ucon.Event{
+		Type:      ucon.EventType(e.GetType()),
+		SessionID: e.GetSessionId(),
+		Message:   e.GetMessage(),
+	}
+	if e.GetTime() != 0 {
+		converted.Time = time.Unix(0, e.GetTime())
+	}
+	if len(e.GetData()) > 0 {
+		converted.Data = make(map[string]interface{}, len(e.GetData()))
+		for k, v := range e.GetData() {
+			value, err := v.AsInterface()
+			if err != nil {
+				return ucon.Event{}, fmt.Errorf("event data %q: %w", k, err)
+			}
+			converted.Data[k] = value
+		}
+	}
+	return converted, nil
+}
+
+// NewAttributeValue converts an attribute value the way ucon.ProtobufCodec
+// does: integers as int_value, lists, objects and values of other types as
+// JSON.
+func NewAttributeValue(value interface{}) (*AttributeValue, error) {
+	var i int64
+	switch v := value.(type) {
+	case nil:
+		return &AttributeValue{Kind: &AttributeValue_NullValue{NullValue: true}}, nil
+	case bool:
+		return &AttributeValue{Kind: &AttributeValue_BoolValue{BoolValue: v}}, nil
+	case int:
+		i = int64(v)
+	case int8:
+		i = int64(v)
+	case int16:
+		i = int64(v)
+	case int32:
+		i = int64(v)
+	case int64:
+		i = v
+	case uint8:
+		i = int64(v)
+	case uint16:
+		i = int64(v)
+	case uint32:
+		i = int64(v)
+	case float32:
+		return &AttributeValue{Kind: &AttributeValue_DoubleValue{DoubleValue: float64(v)}}, nil
+	case float64:
+		return &AttributeValue{Kind: &AttributeValue_DoubleValue{DoubleValue: v}}, nil
+	case string:
+		return &AttributeValue{Kind: &AttributeValue_StringValue{StringValue: v}}, nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return &AttributeValue{Kind: &AttributeValue_JsonValue{JsonValue: data}}, nil
+	}
+	return &AttributeValue{Kind: &AttributeValue_IntValue{IntValue: i}}, nil
+}
+
+// AsInterface converts an attribute value back; integers are int64.
+func (x *AttributeValue) AsInterface() (interface{}, error) {
+	switch kind := x.GetKind().(type) {
+	case *AttributeValue_BoolValue:
+		return kind.BoolValue, nil
+	case *AttributeValue_IntValue:
+		return kind.IntValue, nil
+	case *AttributeValue_DoubleValue:
+		return kind.DoubleValue, nil
+	case *AttributeValue_StringValue:
+		return kind.StringValue, nil
+	case *AttributeValue_JsonValue:
+		var value interface{}
+		if err := json.Unmarshal(kind.JsonValue, &value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+	return nil, nil
+}
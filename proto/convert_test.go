@@ -0,0 +1,159 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package uconpb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	ucon "github.com/casbin/casbin-ucon"
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/model"
+	"google.golang.org/protobuf/proto"
+)
+
+func newEnforcer(t *testing.T) ucon.IUconEnforcer {
+	m, err := model.NewModelFromString(`
+[request_definition]
+r = sub, obj, act
+
+[policy_definition]
+p = sub, obj, act
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = r.sub == p.sub && r.obj == p.obj && r.act == p.act
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, _ := casbin.NewEnforcer(m)
+	_, _ = e.AddPolicy("alice", "document1", "read")
+	return ucon.NewUconEnforcer(e)
+}
+
+func TestSessionRecord(t *testing.T) {
+	now := time.Unix(0, time.Now().UnixNano())
+	record := &ucon.SessionRecord{
+		ID:                 "session_1",
+		Subject:            "alice",
+		Action:             "read",
+		Object:             "document1",
+		Attributes:         map[string]interface{}{"count": int64(3), "ratio": 0.5, "tags": []interface{}{"a"}},
+		Active:             true,
+		StartTime:          now,
+		Grant:              &ucon.GrantSnapshot{GrantedAt: now, Attributes: map[string]interface{}{"location": "office"}, Rule: []string{"alice", "document1", "read"}},
+		ObligationAttempts: map[string]int{"pre/notify": 2},
+		BreakGlass:         "outage",
+		Review:             &ucon.BreakGlassOutcome{Reviewer: "bob", ReviewedAt: now},
+	}
+	converted, err := FromSessionRecord(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if converted.GetId() != "session_1" || converted.GetGrant().GetRule()[2] != "read" || converted.GetAttributes()["count"].GetIntValue() != 3 ||
+		converted.GetReview().GetReviewer() != "bob" {
+		t.Errorf("Expected the generated type to decode the codec encoding, got %v", converted)
+	}
+
+	// The generated encoding decodes with ucon.ProtobufCodec.
+	data, err := proto.Marshal(converted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := &ucon.SessionRecord{}
+	if err := (ucon.ProtobufCodec{}).Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, record) {
+		t.Errorf("Expected the record to round-trip:\n got %+v\nwant %+v", decoded, record)
+	}
+	if back, err := ToSessionRecord(converted); err != nil || !reflect.DeepEqual(back, record) {
+		t.Errorf("Expected ToSessionRecord to round-trip, got %+v, %v", back, err)
+	}
+}
+
+func TestRules(t *testing.T) {
+	condition := ucon.Condition{ID: "c1", Name: "office", Kind: "always", Expr: "location == 'office'", Purposes: []string{"billing"}, Dwell: time.Second, Attributes: []string{"location"}, Shadow: true, Rollout: 25, BreakGlassBypass: true}
+	if got := ToCondition(FromCondition(condition)); !reflect.DeepEqual(got, condition) {
+		t.Errorf("Expected the condition to round-trip, got %+v", got)
+	}
+	obligation := ucon.Obligation{ID: "o1", Name: "notify", Kind: "ongoing", Expr: "log", Schedule: "@hourly", After: []string{"o0"}, Deadline: time.Minute, Rollout: 50, BreakGlass: true}
+	if got := ToObligation(FromObligation(obligation)); !reflect.DeepEqual(got, obligation) {
+		t.Errorf("Expected the obligation to round-trip, got %+v", got)
+	}
+}
+
+func TestEvent(t *testing.T) {
+	event := ucon.Event{
+		Type:      ucon.EventSessionStopped,
+		SessionID: "session_1",
+		Time:      time.Unix(0, time.Now().UnixNano()),
+		Message:   "stopped",
+		Data:      map[string]interface{}{"reason": "expired", "count": int64(2), "none": nil, "list": []interface{}{"a"}},
+	}
+	converted, err := FromEvent(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if converted.GetSchemaVersion() != ucon.EventSchemaVersion {
+		t.Errorf("Expected schema version %d, got %d", ucon.EventSchemaVersion, converted.GetSchemaVersion())
+	}
+	data, _ := proto.Marshal(converted)
+	decoded := &Event{}
+	if err := proto.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ToEvent(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Time.Equal(event.Time) {
+		t.Errorf("Expected time %v, got %v", event.Time, got.Time)
+	}
+	got.Time = event.Time
+	if !reflect.DeepEqual(got, event) {
+		t.Errorf("Expected the event to round-trip:\n got %+v\nwant %+v", got, event)
+	}
+
+	decoded.SchemaVersion = ucon.EventSchemaVersion + 1
+	if _, err := ToEvent(decoded); err == nil {
+		t.Error("Expected an event of a newer schema version to be rejected")
+	}
+}
+
+func TestDecision(t *testing.T) {
+	u := newEnforcer(t)
+	sessionID, err := u.CreateSession("alice", "read", "document1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decision, err := u.Decide(sessionID)
+	converted, err := FromDecision(decision, err)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !converted.GetAllowed() || converted.GetCode() != "" || converted.GetSession().GetId() != sessionID {
+		t.Errorf("Expected an allowed decision with its session, got %v", converted)
+	}
+
+	decision, err = u.Decide("missing")
+	if converted, _ = FromDecision(decision, err); converted.GetAllowed() || converted.GetCode() != string(ucon.ReasonSessionNotFound) {
+		t.Errorf("Expected a denial with code %s, got %v", ucon.ReasonSessionNotFound, converted)
+	}
+}
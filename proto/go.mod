@@ -0,0 +1,16 @@
+module github.com/casbin/casbin-ucon/proto
+
+go 1.21
+
+require (
+	github.com/casbin/casbin-ucon v0.0.0
+	github.com/casbin/casbin/v2 v2.120.0
+	google.golang.org/protobuf v1.30.0
+)
+
+require (
+	github.com/bmatcuk/doublestar/v4 v4.6.1 // indirect
+	github.com/casbin/govaluate v1.3.0 // indirect
+)
+
+replace github.com/casbin/casbin-ucon => ../
@@ -0,0 +1,634 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// ProtobufCodec is a Codec writing the protocol buffers messages described
+// in proto/session_record.proto, without depending on a protobuf runtime. It
+// encodes a *SessionRecord and the entries of WALSessionStore. Integer
+// attributes are decoded as int64 rather than float64; lists, objects and
+// values of other types are stored as JSON.
+type ProtobufCodec struct{}
+
+// Name implements Codec.
+func (ProtobufCodec) Name() string { return "pb" }
+
+// Marshal implements Codec.
+func (ProtobufCodec) Marshal(v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case *SessionRecord:
+		return appendProtoRecord(nil, v)
+	case *walEntry:
+		return appendProtoWALEntry(nil, v)
+	}
+	return nil, fmt.Errorf("protobuf codec cannot encode %T", v)
+}
+
+// Unmarshal implements Codec.
+func (ProtobufCodec) Unmarshal(data []byte, v interface{}) error {
+	switch v := v.(type) {
+	case *SessionRecord:
+		return decodeProtoRecord(data, v)
+	case *walEntry:
+		return decodeProtoWALEntry(data, v)
+	}
+	return fmt.Errorf("protobuf codec cannot decode %T", v)
+}
+
+const (
+	protoVarint  = 0
+	protoFixed64 = 1
+	protoBytes   = 2
+	protoFixed32 = 5
+)
+
+var errProtoTruncated = errors.New("truncated protobuf message")
+
+func appendProtoTag(b []byte, field int, wire int) []byte {
+	return binary.AppendUvarint(b, uint64(field)<<3|uint64(wire))
+}
+
+func appendProtoVarint(b []byte, field int, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	return binary.AppendUvarint(appendProtoTag(b, field, protoVarint), v)
+}
+
+func appendProtoBool(b []byte, field int, v bool) []byte {
+	if !v {
+		return b
+	}
+	return appendProtoVarint(b, field, 1)
+}
+
+func appendProtoTime(b []byte, field int, t time.Time) []byte {
+	if t.IsZero() {
+		return b
+	}
+	return appendProtoVarint(b, field, uint64(t.UnixNano()))
+}
+
+// appendProtoLen appends a length-delimited field, even if data is empty.
+func appendProtoLen(b []byte, field int, data []byte) []byte {
+	b = binary.AppendUvarint(appendProtoTag(b, field, protoBytes), uint64(len(data)))
+	return append(b, data...)
+}
+
+func appendProtoString(b []byte, field int, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = binary.AppendUvarint(appendProtoTag(b, field, protoBytes), uint64(len(s)))
+	return append(b, s...)
+}
+
+func appendProtoStrings(b []byte, field int, values []string) []byte {
+	for _, s := range values {
+		b = binary.AppendUvarint(appendProtoTag(b, field, protoBytes), uint64(len(s)))
+		b = append(b, s...)
+	}
+	return b
+}
+
+func zigzag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+// appendProtoAttributeValue appends the fields of an AttributeValue.
+func appendProtoAttributeValue(b []byte, value interface{}) ([]byte, error) {
+	var i int64
+	switch v := value.(type) {
+	case nil:
+		return binary.AppendUvarint(appendProtoTag(b, 1, protoVarint), 1), nil
+	case bool:
+		flag := uint64(0)
+		if v {
+			flag = 1
+		}
+		return binary.AppendUvarint(appendProtoTag(b, 2, protoVarint), flag), nil
+	case int:
+		i = int64(v)
+	case int8:
+		i = int64(v)
+	case int16:
+		i = int64(v)
+	case int32:
+		i = int64(v)
+	case int64:
+		i = v
+	case uint8:
+		i = int64(v)
+	case uint16:
+		i = int64(v)
+	case uint32:
+		i = int64(v)
+	case float32:
+		return binary.LittleEndian.AppendUint64(appendProtoTag(b, 4, protoFixed64), math.Float64bits(float64(v))), nil
+	case float64:
+		return binary.LittleEndian.AppendUint64(appendProtoTag(b, 4, protoFixed64), math.Float64bits(v)), nil
+	case string:
+		b = binary.AppendUvarint(appendProtoTag(b, 5, protoBytes), uint64(len(v)))
+		return append(b, v...), nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return appendProtoLen(b, 6, data), nil
+	}
+	return binary.AppendUvarint(appendProtoTag(b, 3, protoVarint), zigzag(i)), nil
+}
+
+func appendProtoAttributes(b []byte, field int, attributes map[string]interface{}) ([]byte, error) {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value, err := appendProtoAttributeValue(nil, attributes[key])
+		if err != nil {
+			return nil, fmt.Errorf("attribute %s: %w", key, err)
+		}
+		b = appendProtoLen(b, field, appendProtoLen(appendProtoString(nil, 1, key), 2, value))
+	}
+	return b, nil
+}
+
+func appendProtoRecord(b []byte, record *SessionRecord) ([]byte, error) {
+	b = appendProtoString(b, 1, record.ID)
+	b = appendProtoString(b, 2, record.Subject)
+	b = appendProtoString(b, 3, record.Action)
+	b = appendProtoString(b, 4, record.Object)
+	b = appendProtoString(b, 5, record.Purpose)
+	b, err := appendProtoAttributes(b, 6, record.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	b = appendProtoBool(b, 7, record.Active)
+	b = appendProtoBool(b, 8, record.Monitored)
+	b = appendProtoTime(b, 9, record.StartTime)
+	b = appendProtoTime(b, 10, record.EndTime)
+	b = appendProtoString(b, 11, record.StopReason)
+	b = appendProtoStrings(b, 12, record.GrantSource)
+	if record.Grant != nil {
+		grant := appendProtoTime(nil, 1, record.Grant.GrantedAt)
+		if grant, err = appendProtoAttributes(grant, 2, record.Grant.Attributes); err != nil {
+			return nil, err
+		}
+		b = appendProtoLen(b, 13, appendProtoStrings(grant, 3, record.Grant.Rule))
+	}
+	keys := make([]string, 0, len(record.ObligationAttempts))
+	for key := range record.ObligationAttempts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		b = appendProtoLen(b, 14, appendProtoVarint(appendProtoString(nil, 1, key), 2, uint64(record.ObligationAttempts[key])))
+	}
+	b = appendProtoTime(b, 15, record.suspendedUntil())
+	b = appendProtoString(b, 16, record.ResumeHash)
+	b = appendProtoString(b, 17, record.BreakGlass)
+	b = appendProtoVarint(b, 18, record.Fence)
+	if record.Review != nil {
+		review := appendProtoString(nil, 1, record.Review.Reviewer)
+		review = appendProtoTime(review, 2, record.Review.ReviewedAt)
+		b = appendProtoLen(b, 19, appendProtoString(review, 3, record.Review.Notes))
+	}
+	b = appendProtoString(b, 20, record.ChannelBinding)
+	return b, nil
+}
+
+func appendProtoWALEntry(b []byte, entry *walEntry) ([]byte, error) {
+	b = appendProtoVarint(b, 1, entry.Seq)
+	b = appendProtoString(b, 2, string(entry.Op))
+	b = appendProtoString(b, 3, entry.SessionID)
+	b = appendProtoTime(b, 4, entry.Time)
+	if entry.Record != nil {
+		record, err := appendProtoRecord(nil, entry.Record)
+		if err != nil {
+			return nil, err
+		}
+		b = appendProtoLen(b, 5, record)
+	}
+	b = appendProtoString(b, 6, entry.Key)
+	if entry.Value != nil {
+		value, err := appendProtoAttributeValue(nil, entry.Value)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %s: %w", entry.Key, err)
+		}
+		b = appendProtoLen(b, 7, value)
+	}
+	if entry.State != nil {
+		state := appendProtoBool(nil, 1, entry.State.Active)
+		state = appendProtoBool(state, 2, entry.State.Monitored)
+		state = appendProtoTime(state, 3, entry.State.EndTime)
+		b = appendProtoLen(b, 8, appendProtoString(state, 4, entry.State.StopReason))
+	}
+	return b, nil
+}
+
+// protoReader reads the fields of a protobuf message.
+type protoReader struct {
+	data []byte
+}
+
+func (r *protoReader) more() bool {
+	return len(r.data) > 0
+}
+
+func (r *protoReader) next() (int, int, error) {
+	tag, err := r.varint()
+	return int(tag >> 3), int(tag & 7), err
+}
+
+func (r *protoReader) varint() (uint64, error) {
+	v, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		return 0, errProtoTruncated
+	}
+	r.data = r.data[n:]
+	return v, nil
+}
+
+func (r *protoReader) bytes() ([]byte, error) {
+	n, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(r.data)) {
+		return nil, errProtoTruncated
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b, nil
+}
+
+func (r *protoReader) string() (string, error) {
+	b, err := r.bytes()
+	return string(b), err
+}
+
+func (r *protoReader) bool() (bool, error) {
+	v, err := r.varint()
+	return v != 0, err
+}
+
+func (r *protoReader) time() (time.Time, error) {
+	v, err := r.varint()
+	if err != nil || v == 0 {
+		return time.Time{}, err
+	}
+	return time.Unix(0, int64(v)), nil
+}
+
+func (r *protoReader) fixed64() (uint64, error) {
+	if len(r.data) < 8 {
+		return 0, errProtoTruncated
+	}
+	v := binary.LittleEndian.Uint64(r.data)
+	r.data = r.data[8:]
+	return v, nil
+}
+
+// skip skips a field of an unknown number, e.g. one added in a later
+// version of the schema.
+func (r *protoReader) skip(wire int) error {
+	var err error
+	switch wire {
+	case protoVarint:
+		_, err = r.varint()
+	case protoFixed64:
+		_, err = r.fixed64()
+	case protoBytes:
+		_, err = r.bytes()
+	case protoFixed32:
+		if len(r.data) < 4 {
+			return errProtoTruncated
+		}
+		r.data = r.data[4:]
+	default:
+		return fmt.Errorf("unsupported protobuf wire type %d", wire)
+	}
+	return err
+}
+
+func decodeProtoAttributeValue(data []byte) (interface{}, error) {
+	r := &protoReader{data: data}
+	var value interface{}
+	for r.more() {
+		field, wire, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case field == 1 && wire == protoVarint:
+			value, err = nil, r.skip(wire)
+		case field == 2 && wire == protoVarint:
+			value, err = r.bool()
+		case field == 3 && wire == protoVarint:
+			var v uint64
+			v, err = r.varint()
+			value = int64(v>>1) ^ -int64(v&1)
+		case field == 4 && wire == protoFixed64:
+			var v uint64
+			v, err = r.fixed64()
+			value = math.Float64frombits(v)
+		case field == 5 && wire == protoBytes:
+			value, err = r.string()
+		case field == 6 && wire == protoBytes:
+			var data []byte
+			if data, err = r.bytes(); err == nil {
+				err = json.Unmarshal(data, &value)
+			}
+		default:
+			err = r.skip(wire)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
+
+// decodeProtoAttribute adds a map entry of attributes.
+func decodeProtoAttribute(data []byte, attributes map[string]interface{}) error {
+	r := &protoReader{data: data}
+	var key string
+	var value interface{}
+	for r.more() {
+		field, wire, err := r.next()
+		if err != nil {
+			return err
+		}
+		switch {
+		case field == 1 && wire == protoBytes:
+			key, err = r.string()
+		case field == 2 && wire == protoBytes:
+			var data []byte
+			if data, err = r.bytes(); err == nil {
+				value, err = decodeProtoAttributeValue(data)
+			}
+		default:
+			err = r.skip(wire)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	attributes[key] = value
+	return nil
+}
+
+func decodeProtoGrant(data []byte) (*GrantSnapshot, error) {
+	r := &protoReader{data: data}
+	grant := &GrantSnapshot{Attributes: make(map[string]interface{})}
+	for r.more() {
+		field, wire, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case field == 1 && wire == protoVarint:
+			grant.GrantedAt, err = r.time()
+		case field == 2 && wire == protoBytes:
+			var entry []byte
+			if entry, err = r.bytes(); err == nil {
+				err = decodeProtoAttribute(entry, grant.Attributes)
+			}
+		case field == 3 && wire == protoBytes:
+			var rule string
+			rule, err = r.string()
+			grant.Rule = append(grant.Rule, rule)
+		default:
+			err = r.skip(wire)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return grant, nil
+}
+
+func decodeProtoReview(data []byte) (*BreakGlassOutcome, error) {
+	r := &protoReader{data: data}
+	review := &BreakGlassOutcome{}
+	for r.more() {
+		field, wire, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case field == 1 && wire == protoBytes:
+			review.Reviewer, err = r.string()
+		case field == 2 && wire == protoVarint:
+			review.ReviewedAt, err = r.time()
+		case field == 3 && wire == protoBytes:
+			review.Notes, err = r.string()
+		default:
+			err = r.skip(wire)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return review, nil
+}
+
+func decodeProtoAttempt(data []byte, attempts map[string]int) error {
+	r := &protoReader{data: data}
+	var key string
+	var count uint64
+	for r.more() {
+		field, wire, err := r.next()
+		if err != nil {
+			return err
+		}
+		switch {
+		case field == 1 && wire == protoBytes:
+			key, err = r.string()
+		case field == 2 && wire == protoVarint:
+			count, err = r.varint()
+		default:
+			err = r.skip(wire)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	attempts[key] = int(count)
+	return nil
+}
+
+func decodeProtoRecord(data []byte, record *SessionRecord) error {
+	r := &protoReader{data: data}
+	*record = SessionRecord{Attributes: make(map[string]interface{})}
+	for r.more() {
+		field, wire, err := r.next()
+		if err != nil {
+			return err
+		}
+		switch {
+		case field == 1 && wire == protoBytes:
+			record.ID, err = r.string()
+		case field == 2 && wire == protoBytes:
+			record.Subject, err = r.string()
+		case field == 3 && wire == protoBytes:
+			record.Action, err = r.string()
+		case field == 4 && wire == protoBytes:
+			record.Object, err = r.string()
+		case field == 5 && wire == protoBytes:
+			record.Purpose, err = r.string()
+		case field == 6 && wire == protoBytes:
+			var entry []byte
+			if entry, err = r.bytes(); err == nil {
+				err = decodeProtoAttribute(entry, record.Attributes)
+			}
+		case field == 7 && wire == protoVarint:
+			record.Active, err = r.bool()
+		case field == 8 && wire == protoVarint:
+			record.Monitored, err = r.bool()
+		case field == 9 && wire == protoVarint:
+			record.StartTime, err = r.time()
+		case field == 10 && wire == protoVarint:
+			record.EndTime, err = r.time()
+		case field == 11 && wire == protoBytes:
+			record.StopReason, err = r.string()
+		case field == 12 && wire == protoBytes:
+			var rule string
+			rule, err = r.string()
+			record.GrantSource = append(record.GrantSource, rule)
+		case field == 13 && wire == protoBytes:
+			var grant []byte
+			if grant, err = r.bytes(); err == nil {
+				record.Grant, err = decodeProtoGrant(grant)
+			}
+		case field == 14 && wire == protoBytes:
+			var entry []byte
+			if entry, err = r.bytes(); err == nil {
+				if record.ObligationAttempts == nil {
+					record.ObligationAttempts = make(map[string]int)
+				}
+				err = decodeProtoAttempt(entry, record.ObligationAttempts)
+			}
+		case field == 15 && wire == protoVarint:
+			var until time.Time
+			if until, err = r.time(); err == nil && !until.IsZero() {
+				record.SuspendedUntil = &until
+			}
+		case field == 16 && wire == protoBytes:
+			record.ResumeHash, err = r.string()
+		case field == 17 && wire == protoBytes:
+			record.BreakGlass, err = r.string()
+		case field == 18 && wire == protoVarint:
+			record.Fence, err = r.varint()
+		case field == 19 && wire == protoBytes:
+			var review []byte
+			if review, err = r.bytes(); err == nil {
+				record.Review, err = decodeProtoReview(review)
+			}
+		case field == 20 && wire == protoBytes:
+			record.ChannelBinding, err = r.string()
+		default:
+			err = r.skip(wire)
+		}
+		if err != nil {
+			return fmt.Errorf("session record field %d: %w", field, err)
+		}
+	}
+	return nil
+}
+
+func decodeProtoWALState(data []byte) (*walSessionState, error) {
+	r := &protoReader{data: data}
+	state := &walSessionState{}
+	for r.more() {
+		field, wire, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case field == 1 && wire == protoVarint:
+			state.Active, err = r.bool()
+		case field == 2 && wire == protoVarint:
+			state.Monitored, err = r.bool()
+		case field == 3 && wire == protoVarint:
+			state.EndTime, err = r.time()
+		case field == 4 && wire == protoBytes:
+			state.StopReason, err = r.string()
+		default:
+			err = r.skip(wire)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return state, nil
+}
+
+func decodeProtoWALEntry(data []byte, entry *walEntry) error {
+	r := &protoReader{data: data}
+	*entry = walEntry{}
+	for r.more() {
+		field, wire, err := r.next()
+		if err != nil {
+			return err
+		}
+		var b []byte
+		switch {
+		case field == 1 && wire == protoVarint:
+			entry.Seq, err = r.varint()
+		case field == 2 && wire == protoBytes:
+			var op string
+			op, err = r.string()
+			entry.Op = walOp(op)
+		case field == 3 && wire == protoBytes:
+			entry.SessionID, err = r.string()
+		case field == 4 && wire == protoVarint:
+			entry.Time, err = r.time()
+		case field == 5 && wire == protoBytes:
+			if b, err = r.bytes(); err == nil {
+				entry.Record = &SessionRecord{}
+				err = decodeProtoRecord(b, entry.Record)
+			}
+		case field == 6 && wire == protoBytes:
+			entry.Key, err = r.string()
+		case field == 7 && wire == protoBytes:
+			if b, err = r.bytes(); err == nil {
+				entry.Value, err = decodeProtoAttributeValue(b)
+			}
+		case field == 8 && wire == protoBytes:
+			if b, err = r.bytes(); err == nil {
+				entry.State, err = decodeProtoWALState(b)
+			}
+		default:
+			err = r.skip(wire)
+		}
+		if err != nil {
+			return fmt.Errorf("write-ahead log entry field %d: %w", field, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestProtobufCodecRecord(t *testing.T) {
+	now := time.Unix(0, time.Now().UnixNano())
+	until := now.Add(time.Minute)
+	record := &SessionRecord{
+		ID:      "session_1",
+		Subject: "alice",
+		Action:  "read",
+		Object:  "document1",
+		Purpose: "billing",
+		Attributes: map[string]interface{}{
+			"none":   nil,
+			"flag":   false,
+			"count":  int64(-42),
+			"small":  7,
+			"ratio":  0.5,
+			"name":   "",
+			"tags":   []interface{}{"a", "b"},
+			"nested": map[string]interface{}{"x": 1.5},
+		},
+		Active:             true,
+		Monitored:          true,
+		StartTime:          now,
+		StopReason:         "done",
+		GrantSource:        []string{"alice", "document1", "read"},
+		Grant:              &GrantSnapshot{GrantedAt: now, Attributes: map[string]interface{}{"location": "office"}, Rule: []string{"alice", "document1", "read"}},
+		ObligationAttempts: map[string]int{"pre/notify": 2},
+		SuspendedUntil:     &until,
+		ResumeHash:         "abc",
+		BreakGlass:         "outage",
+		Fence:              7,
+		Review:             &BreakGlassOutcome{Reviewer: "bob", ReviewedAt: now, Notes: "justified"},
+		ChannelBinding:     "cafe",
+	}
+	if n := reflect.TypeOf(SessionRecord{}).NumField(); n != 20 {
+		t.Fatalf("SessionRecord has %d fields; add the new ones to ProtobufCodec and proto/session_record.proto", n)
+	}
+
+	codec := ProtobufCodec{}
+	data, err := codec.Marshal(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := &SessionRecord{}
+	if err := codec.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+	record.Attributes["small"] = int64(7)
+	if !reflect.DeepEqual(decoded, record) {
+		t.Errorf("Expected the record to round-trip:\n got %+v\nwant %+v", decoded, record)
+	}
+
+	if again, _ := codec.Marshal(record); string(again) != string(data) {
+		t.Error("Expected the encoding to be deterministic")
+	}
+	// Fields added by a later version of the schema are skipped.
+	extended := appendProtoString(append([]byte(nil), data...), 99, "future")
+	if err := codec.Unmarshal(extended, decoded); err != nil || decoded.ID != "session_1" {
+		t.Errorf("Expected unknown fields to be skipped, got %v", err)
+	}
+	if err := codec.Unmarshal(data[:len(data)-1], decoded); !errors.Is(err, errProtoTruncated) {
+		t.Errorf("Expected a truncated record to fail, got %v", err)
+	}
+	if _, err := codec.Marshal(struct{}{}); err == nil {
+		t.Error("Expected unsupported types to be refused")
+	}
+}
+
+func TestProtobufCodecWALEntry(t *testing.T) {
+	now := time.Unix(0, time.Now().UnixNano())
+	codec := ProtobufCodec{}
+	for _, entry := range []*walEntry{
+		{Seq: 1, Op: walCreate, SessionID: "s", Time: now, Record: &SessionRecord{ID: "s", Attributes: map[string]interface{}{}}},
+		{Seq: 2, Op: walSet, SessionID: "s", Time: now, Key: "count", Value: int64(3)},
+		{Seq: 3, Op: walUnset, SessionID: "s", Time: now, Key: "count"},
+		{Seq: 4, Op: walState, SessionID: "s", Time: now, State: &walSessionState{EndTime: now, StopReason: "done"}},
+	} {
+		data, err := codec.Marshal(entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded := &walEntry{}
+		if err := codec.Unmarshal(data, decoded); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(decoded, entry) {
+			t.Errorf("Expected the %s entry to round-trip:\n got %+v\nwant %+v", entry.Op, decoded, entry)
+		}
+	}
+}
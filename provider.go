@@ -0,0 +1,246 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DefaultHealthCheckInterval is how often attribute providers are health checked.
+const DefaultHealthCheckInterval = 30 * time.Second
+
+// EventProviderHealth is emitted when an attribute provider becomes healthy or unhealthy.
+const EventProviderHealth EventType = "provider_health"
+
+// ErrRevokedByProvider is wrapped by FetchAttributes errors that mean the
+// session must end, e.g. because the upstream token was revoked. Such errors
+// revoke the session at once and do not affect the provider's health.
+var ErrRevokedByProvider = errors.New("revoked by attribute provider")
+
+// AttributeProvider supplies session attributes from an external system such
+// as an IdP or an HR database.
+type AttributeProvider interface {
+	// FetchAttributes returns the current attributes for the session. They are
+	// merged into the session before its conditions are evaluated.
+	FetchAttributes(session *Session) (map[string]interface{}, error)
+	// HealthCheck returns an error if the provider is unreachable.
+	HealthCheck() error
+}
+
+// ProviderHealth is the health of a registered attribute provider.
+type ProviderHealth struct {
+	Name      string     `json:"name"`
+	Healthy   bool       `json:"healthy"`
+	Since     time.Time  `json:"since"`
+	LastCheck *time.Time `json:"last_check,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+}
+
+// ProviderHealthMetrics is implemented by Metrics that also record the health
+// of attribute providers.
+type ProviderHealthMetrics interface {
+	SetProviderHealth(name string, healthy bool)
+}
+
+type providerEntry struct {
+	provider AttributeProvider
+	health   ProviderHealth
+}
+
+// WithDegradedMode keeps sessions alive while an attribute provider is down,
+// using the attributes last fetched from it for up to maxAge. Without it (or
+// with maxAge 0) sessions that depend on an unavailable provider are revoked.
+func WithDegradedMode(maxAge time.Duration) Option {
+	return func(u *UconEnforcer) {
+		u.degradedMaxAge = maxAge
+	}
+}
+
+// WithHealthCheckInterval sets how often attribute providers are health checked.
+func WithHealthCheckInterval(interval time.Duration) Option {
+	return func(u *UconEnforcer) {
+		u.healthCheckInterval = interval
+	}
+}
+
+// RegisterAttributeProvider registers an attribute provider under name. Its
+// attributes are fetched when a session is enforced and on every monitoring
+// tick, and it is health checked periodically in the background.
+func (u *UconEnforcer) RegisterAttributeProvider(name string, provider AttributeProvider) error {
+	if name == "" {
+		return errors.New("attribute provider name cannot be empty")
+	}
+	if provider == nil {
+		return errors.New("attribute provider cannot be nil")
+	}
+
+	u.mu.Lock()
+	u.providers[name] = &providerEntry{
+		provider: provider,
+		health:   ProviderHealth{Name: name, Healthy: true, Since: time.Now()},
+	}
+	u.mu.Unlock()
+
+	u.healthCheckOnce.Do(func() {
+		go u.healthCheckLoop()
+	})
+	return nil
+}
+
+// GetProviderHealth returns the health of all attribute providers, ordered by name.
+func (u *UconEnforcer) GetProviderHealth() []ProviderHealth {
+	u.mu.RLock()
+	health := make([]ProviderHealth, 0, len(u.providers))
+	for _, entry := range u.providers {
+		health = append(health, entry.health)
+	}
+	u.mu.RUnlock()
+
+	sort.Slice(health, func(i, j int) bool {
+		return health[i].Name < health[j].Name
+	})
+	return health
+}
+
+// CheckProviders health checks all attribute providers now.
+func (u *UconEnforcer) CheckProviders() {
+	for name, provider := range u.providerList() {
+		u.setProviderHealth(name, provider.HealthCheck(), true)
+	}
+}
+
+func (u *UconEnforcer) healthCheckLoop() {
+	ticker := time.NewTicker(u.healthCheckInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		u.CheckProviders()
+	}
+}
+
+func (u *UconEnforcer) providerList() map[string]AttributeProvider {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	if len(u.providers) == 0 {
+		return nil
+	}
+	providers := make(map[string]AttributeProvider, len(u.providers))
+	for name, entry := range u.providers {
+		providers[name] = entry.provider
+	}
+	return providers
+}
+
+func (u *UconEnforcer) providerHealthy(name string) bool {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	entry, ok := u.providers[name]
+	return ok && entry.health.Healthy
+}
+
+// setProviderHealth records the outcome of a health check or fetch, emitting
+// an event and updating metrics when the provider changes state.
+func (u *UconEnforcer) setProviderHealth(name string, err error, checked bool) {
+	now := time.Now()
+	healthy := err == nil
+
+	u.mu.Lock()
+	entry, ok := u.providers[name]
+	if !ok {
+		u.mu.Unlock()
+		return
+	}
+	changed := entry.health.Healthy != healthy
+	if changed {
+		entry.health.Healthy = healthy
+		entry.health.Since = now
+	}
+	if checked {
+		entry.health.LastCheck = &now
+	}
+	if err != nil {
+		entry.health.LastError = err.Error()
+	}
+	u.mu.Unlock()
+
+	if m, ok := u.metrics.(ProviderHealthMetrics); ok {
+		m.SetProviderHealth(name, healthy)
+	}
+	if changed {
+		data := map[string]interface{}{"provider": name, "healthy": healthy}
+		message := fmt.Sprintf("attribute provider %s is healthy", name)
+		if err != nil {
+			data["error"] = err.Error()
+			message = fmt.Sprintf("attribute provider %s is unhealthy: %v", name, err)
+		}
+		u.emit(Event{Type: EventProviderHealth, Time: now, Message: message, Data: data})
+	}
+}
+
+// refreshAttributes updates the roles of the session and merges the
+// attributes of every provider into it.
+// A provider that is unhealthy or fails to respond is tolerated in degraded
+// mode as long as the session's attributes from it are younger than the
+// configured maximum age; otherwise an error is returned.
+func (u *UconEnforcer) refreshAttributes(session *Session) error {
+	if err := u.refreshRoles(session); err != nil {
+		return err
+	}
+	for name, provider := range u.providerList() {
+		var err error
+		if u.providerHealthy(name) {
+			var attributes map[string]interface{}
+			attributes, err = provider.FetchAttributes(session)
+			if err == nil {
+				if err := u.setAttributes(session, attributes, false); err != nil {
+					return err
+				}
+				session.setFetchedAt(name, time.Now())
+				continue
+			}
+			if errors.Is(err, ErrRevokedByProvider) {
+				return fmt.Errorf("attribute provider %s: %w", name, err)
+			}
+			u.setProviderHealth(name, err, false)
+		} else {
+			err = errors.New("provider is unhealthy")
+		}
+
+		fetchedAt, ok := session.getFetchedAt(name)
+		if u.degradedMaxAge > 0 && ok && time.Since(fetchedAt) <= u.degradedMaxAge {
+			continue
+		}
+		return fmt.Errorf("attribute provider %s unavailable: %w", name, err)
+	}
+	return nil
+}
+
+func (s *Session) setFetchedAt(provider string, t time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.fetchedAt == nil {
+		s.fetchedAt = make(map[string]time.Time)
+	}
+	s.fetchedAt[provider] = t
+}
+
+func (s *Session) getFetchedAt(provider string) (time.Time, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	t, ok := s.fetchedAt[provider]
+	return t, ok
+}
@@ -0,0 +1,128 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakeProvider struct {
+	down atomic.Bool
+}
+
+func (p *fakeProvider) FetchAttributes(session *Session) (map[string]interface{}, error) {
+	if p.down.Load() {
+		return nil, errors.New("connection refused")
+	}
+	return map[string]interface{}{"location": "office"}, nil
+}
+
+func (p *fakeProvider) HealthCheck() error {
+	if p.down.Load() {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func newProviderEnforcer(t *testing.T, opts ...Option) (IUconEnforcer, *fakeProvider) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, opts...)
+	provider := &fakeProvider{}
+	if err := uconE.RegisterAttributeProvider("idp", provider); err != nil {
+		t.Fatalf("RegisterAttributeProvider: %v", err)
+	}
+	_ = uconE.AddCondition(&Condition{ID: "location_always", Name: "location", Kind: "always", Expr: "office"})
+	return uconE, provider
+}
+
+func TestAttributeProviderRevokes(t *testing.T) {
+	uconE, provider := newProviderEnforcer(t)
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	session, err := uconE.EnforceWithSession(sessionID)
+	if err != nil || session == nil {
+		t.Fatalf("Expected access with provider attributes, got %v", err)
+	}
+	if session.GetAttribute("location") != "office" {
+		t.Errorf("Expected provider attribute to be merged, got %v", session.GetAttribute("location"))
+	}
+
+	provider.down.Store(true)
+	time.Sleep(500 * time.Millisecond)
+	if session.IfActive() {
+		t.Fatal("Expected session to be revoked when the provider is down")
+	}
+	if !strings.Contains(session.GetStopReason(), "attribute provider idp unavailable") {
+		t.Errorf("Unexpected stop reason: %q", session.GetStopReason())
+	}
+}
+
+func TestAttributeProviderDegradedMode(t *testing.T) {
+	uconE, provider := newProviderEnforcer(t, WithDegradedMode(time.Hour))
+	var mu sync.Mutex
+	var events []Event
+	uconE.AddEventListener(func(event Event) {
+		if event.Type == EventProviderHealth {
+			mu.Lock()
+			events = append(events, event)
+			mu.Unlock()
+		}
+	})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	session, _ := uconE.EnforceWithSession(sessionID)
+	if session == nil {
+		t.Fatal("Expected access")
+	}
+
+	provider.down.Store(true)
+	time.Sleep(500 * time.Millisecond)
+	if !session.IfActive() {
+		t.Fatalf("Expected session to survive in degraded mode, stopped: %q", session.GetStopReason())
+	}
+	if health := uconE.GetProviderHealth(); len(health) != 1 || health[0].Healthy {
+		t.Errorf("Expected unhealthy provider, got %+v", health)
+	}
+	if snapshot := uconE.GetMetrics().(*InMemoryMetrics).Snapshot(); snapshot.ProviderHealth["idp"] {
+		t.Error("Expected provider health metric to be false")
+	}
+
+	// A new session has no cached attributes to fall back to.
+	otherID, _ := uconE.CreateSession("bob", "read", "document1", nil)
+	if _, err := uconE.EnforceWithSession(otherID); err == nil {
+		t.Error("Expected a new session to be denied while the provider is down")
+	}
+
+	provider.down.Store(false)
+	uconE.CheckProviders()
+	if health := uconE.GetProviderHealth(); !health[0].Healthy || health[0].LastCheck == nil {
+		t.Errorf("Expected provider to recover, got %+v", health)
+	}
+	if encoded, _ := json.Marshal(ProviderHealth{Name: "idp", Healthy: true}); strings.Contains(string(encoded), "last_check") {
+		t.Errorf("Expected an unchecked provider to omit last_check, got %s", encoded)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) != 2 || events[0].Data["healthy"] != false || events[1].Data["healthy"] != true {
+		t.Errorf("Expected unhealthy and healthy events, got %+v", events)
+	}
+	_ = uconE.StopMonitoring(sessionID)
+}
@@ -0,0 +1,51 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"strings"
+)
+
+// CreateSessionWithPurpose creates a session for accessing obj for a declared
+// purpose, e.g. "support" or "marketing". Conditions and obligations can be
+// restricted to purposes, and the purpose is included in audit logs.
+func (u *UconEnforcer) CreateSessionWithPurpose(sub string, act string, obj string, purpose string, attributes map[string]interface{}) (string, error) {
+	return u.createSession(sub, act, obj, purpose, attributes)
+}
+
+// matchesPurpose reports whether a rule restricted to purposes applies to a
+// session. Rules without purposes apply to every session.
+func matchesPurpose(purposes []string, session *Session) bool {
+	if len(purposes) == 0 {
+		return true
+	}
+	for _, purpose := range purposes {
+		if purpose == session.GetPurpose() {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPurposeIn passes when the session was created for one of the purposes.
+// Expr: "p1,p2,...", e.g. "support,billing".
+func checkPurposeIn(expr string, session *Session) (bool, error) {
+	for _, purpose := range strings.Split(expr, ",") {
+		if strings.TrimSpace(purpose) == session.GetPurpose() && session.GetPurpose() != "" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
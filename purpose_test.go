@@ -0,0 +1,62 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPurposeBasedUsageControl(t *testing.T) {
+	var logs bytes.Buffer
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithLogger(&DefaultLogger{Level: LevelInfo, Writer: &logs}))
+
+	// Customer data may only be read for support or billing.
+	_ = uconE.AddCondition(&Condition{ID: "purpose_limitation", Name: "purpose_in", Kind: "always", Expr: "support,billing"})
+	// Billing access additionally requires a billing role.
+	_ = uconE.AddCondition(&Condition{ID: "billing_role", Name: "attribute_equals", Kind: "always", Expr: "role:billing", Purposes: []string{"billing"}})
+	// Support access is audited.
+	_ = uconE.AddObligation(&Obligation{ID: "support_audit", Name: "access_logging", Kind: "pre", Expr: "support access", Purposes: []string{"support"}})
+
+	enforce := func(purpose string, attributes map[string]interface{}) *Session {
+		sessionID, _ := uconE.CreateSessionWithPurpose("alice", "read", "document1", purpose, attributes)
+		session, _ := uconE.EnforceWithSession(sessionID)
+		if session != nil {
+			_ = uconE.StopMonitoring(sessionID)
+		}
+		return session
+	}
+
+	if session := enforce("support", map[string]interface{}{"role": "agent"}); session == nil || session.GetPurpose() != "support" {
+		t.Error("Expected support access to be granted")
+	}
+	if !strings.Contains(logs.String(), "support access") || !strings.Contains(logs.String(), "purpose=support") {
+		t.Errorf("Expected a support audit entry with the purpose, got %q", logs.String())
+	}
+	if enforce("marketing", map[string]interface{}{"role": "billing"}) != nil {
+		t.Error("Expected marketing access to be denied")
+	}
+	if enforce("billing", map[string]interface{}{"role": "agent"}) != nil {
+		t.Error("Expected billing access without the billing role to be denied")
+	}
+	if enforce("billing", map[string]interface{}{"role": "billing"}) == nil {
+		t.Error("Expected billing access with the billing role to be granted")
+	}
+	if enforce("", nil) != nil {
+		t.Error("Expected access without a purpose to be denied")
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// AttrRoles is the session attribute listing the roles of the session
+// subject, direct and inherited, when the model has a role definition.
+// Expressions test it with in, e.g. "'admin' in roles".
+const AttrRoles = "roles"
+
+// usesRBAC reports whether the model defines roles with a role_definition g.
+func (u *UconEnforcer) usesRBAC() bool {
+	m := u.GetModel()
+	if m == nil {
+		return false
+	}
+	_, ok := m["g"]["g"]
+	return ok
+}
+
+// refreshRoles sets the roles attribute of a session to the current roles of
+// its subject. It is called when the session is created and whenever its
+// attributes are refreshed, so removing a role from the subject reaches its
+// sessions at their next evaluation. The session is only updated when the
+// roles changed.
+func (u *UconEnforcer) refreshRoles(session *Session) error {
+	if !u.usesRBAC() {
+		return nil
+	}
+	names, err := u.GetImplicitRolesForUser(session.GetSubject())
+	if err != nil {
+		return fmt.Errorf("failed to resolve roles of %s: %w", session.GetSubject(), err)
+	}
+	sort.Strings(names)
+	roles := make([]interface{}, len(names))
+	for i, name := range names {
+		roles[i] = name
+	}
+	current, _ := session.GetAttribute(AttrRoles).([]interface{})
+	if current != nil && reflect.DeepEqual(current, roles) {
+		return nil
+	}
+	return session.UpdateAttribute(AttrRoles, roles)
+}
+
+// sessionCreated attaches a session just created to the subject attributes
+// and sets its roles.
+func (u *UconEnforcer) sessionCreated(sessionID string, err error) (string, error) {
+	if err != nil {
+		return sessionID, err
+	}
+	session, err := u.sessions.GetSessionById(sessionID)
+	if err != nil {
+		return "", err
+	}
+	u.attachSession(session)
+	u.gauges.update(session)
+	if err := u.refreshRoles(session); err != nil {
+		return "", err
+	}
+	return sessionID, nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/model"
+)
+
+func getRBACEnforcer(t *testing.T) IUconEnforcer {
+	m, err := model.NewModelFromString(`
+[request_definition]
+r = sub, obj, act
+
+[policy_definition]
+p = sub, obj, act
+
+[role_definition]
+g = _, _
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = g(r.sub, p.sub) && r.obj == p.obj && r.act == p.act
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := casbin.NewEnforcer(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = e.AddPolicy("editor", "document1", "read")
+	_, _ = e.AddGroupingPolicy("alice", "admin")
+	_, _ = e.AddGroupingPolicy("admin", "editor")
+	return NewUconEnforcer(e)
+}
+
+func TestSessionRoles(t *testing.T) {
+	uconE := getRBACEnforcer(t)
+	_ = uconE.AddCondition(&Condition{ID: "admins_only", Name: "expression", Kind: "always", Expr: "'admin' in roles"})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	session, _ := uconE.GetSession(sessionID)
+	if roles := session.GetAttribute(AttrRoles); !reflect.DeepEqual(roles, []interface{}{"admin", "editor"}) {
+		t.Fatalf("Expected direct and inherited roles, got %v", roles)
+	}
+	if granted, err := uconE.EnforceWithSession(sessionID); err != nil || granted == nil {
+		t.Fatalf("Expected access, got %v", err)
+	}
+
+	_, _ = uconE.DeleteRoleForUser("alice", "admin")
+	time.Sleep(500 * time.Millisecond)
+	if session.IfActive() {
+		t.Fatal("Expected the session to be revoked once alice lost the admin role")
+	}
+	if roles := session.GetAttribute(AttrRoles); len(roles.([]interface{})) != 0 {
+		t.Errorf("Expected the roles to be refreshed, got %v", roles)
+	}
+}
+
+func TestSessionRolesWithoutRBAC(t *testing.T) {
+	uconE := GetUconEnforcer()
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	session, _ := uconE.GetSession(sessionID)
+	if roles := session.GetAttribute(AttrRoles); roles != nil {
+		t.Errorf("Expected no roles attribute without a role definition, got %v", roles)
+	}
+}
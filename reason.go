@@ -0,0 +1,215 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ReasonCode is a stable, machine-readable reason for a denial or
+// revocation, for clients to react to programmatically, e.g. by prompting
+// the user to authenticate again on ReasonReauthRequired. Codes are never
+// renamed; new ones may be added.
+type ReasonCode string
+
+// Reason codes, see ReasonFor.
+const (
+	ReasonSessionRequired      ReasonCode = "SESSION_REQUIRED"
+	ReasonSessionNotFound      ReasonCode = "SESSION_NOT_FOUND"
+	ReasonSessionRevoked       ReasonCode = "SESSION_REVOKED"
+	ReasonSessionSuspended     ReasonCode = "SESSION_SUSPENDED"
+	ReasonSessionReserved      ReasonCode = "SESSION_RESERVED"
+	ReasonAccessDenied         ReasonCode = "ACCESS_DENIED"
+	ReasonReauthRequired       ReasonCode = "REAUTH_REQUIRED"
+	ReasonChannelMismatch      ReasonCode = "CHANNEL_MISMATCH"
+	ReasonConsentRequired      ReasonCode = "CONSENT_REQUIRED"
+	ReasonEntitlementExhausted ReasonCode = "ENTITLEMENT_EXHAUSTED"
+	ReasonUnderMaintenance     ReasonCode = "UNDER_MAINTENANCE"
+	ReasonCapacityExceeded     ReasonCode = "CAPACITY_EXCEEDED"
+	ReasonServiceUnavailable   ReasonCode = "SERVICE_UNAVAILABLE"
+	ReasonApprovalPending      ReasonCode = "APPROVAL_PENDING"
+)
+
+// ReasonDomain is the domain of the reason codes in gRPC ErrorInfo details.
+const ReasonDomain = "ucon.casbin.org"
+
+// ProblemContentType is the media type of Problem bodies (RFC 9457).
+const ProblemContentType = "application/problem+json"
+
+// gRPC status codes, as defined by google.golang.org/grpc/codes.
+const (
+	grpcPermissionDenied  uint32 = 7
+	grpcResourceExhausted uint32 = 8
+	grpcUnavailable       uint32 = 14
+	grpcUnauthenticated   uint32 = 16
+)
+
+type reasonInfo struct {
+	title string
+	grpc  uint32
+}
+
+var reasons = map[ReasonCode]reasonInfo{
+	ReasonSessionRequired:      {"No session", grpcUnauthenticated},
+	ReasonSessionNotFound:      {"Unknown session", grpcUnauthenticated},
+	ReasonSessionRevoked:       {"Session revoked", grpcPermissionDenied},
+	ReasonSessionSuspended:     {"Session suspended", grpcPermissionDenied},
+	ReasonSessionReserved:      {"Session awaiting reservation", grpcPermissionDenied},
+	ReasonAccessDenied:         {"Access denied", grpcPermissionDenied},
+	ReasonReauthRequired:       {"Authentication required again", grpcUnauthenticated},
+	ReasonChannelMismatch:      {"Session used over another TLS channel", grpcPermissionDenied},
+	ReasonConsentRequired:      {"Consent required", grpcPermissionDenied},
+	ReasonEntitlementExhausted: {"Entitlement exhausted", grpcResourceExhausted},
+	ReasonUnderMaintenance:     {"Object under maintenance", grpcUnavailable},
+	ReasonCapacityExceeded:     {"Monitoring capacity exceeded", grpcUnavailable},
+	ReasonServiceUnavailable:   {"Service unavailable", grpcUnavailable},
+	ReasonApprovalPending:      {"Approval pending", grpcPermissionDenied},
+}
+
+// reasonErrors maps the errors behind denials to their reason code, most
+// specific first.
+var reasonErrors = []struct {
+	err  error
+	code ReasonCode
+}{
+	{ErrNoSession, ReasonSessionRequired},
+	{ErrSessionNotFound, ReasonSessionNotFound},
+	{ErrRevokedByProvider, ReasonReauthRequired},
+	{ErrInvalidToken, ReasonReauthRequired},
+	{ErrTokenExpired, ReasonReauthRequired},
+	{ErrChannelMismatch, ReasonChannelMismatch},
+	{ErrNoConsent, ReasonConsentRequired},
+	{ErrEntitlementExhausted, ReasonEntitlementExhausted},
+	{ErrSessionSuspended, ReasonSessionSuspended},
+	{ErrSessionReserved, ReasonSessionReserved},
+	{ErrPendingApproval, ReasonApprovalPending},
+	{ErrApprovalRejected, ReasonSessionRevoked},
+	{ErrUnderMaintenance, ReasonUnderMaintenance},
+	{ErrCapacityExceeded, ReasonCapacityExceeded},
+	{ErrWaitlisted, ReasonCapacityExceeded},
+	{ErrStoreUnavailable, ReasonServiceUnavailable},
+	{ErrDependencyUnavailable, ReasonServiceUnavailable},
+	{ErrBreakGlassExpired, ReasonSessionRevoked},
+	{ErrSessionExpired, ReasonSessionRevoked},
+	{ErrSessionNotActive, ReasonSessionRevoked},
+}
+
+// ReasonError is a denial with its reason code, as returned by
+// AuthorizeRequest.
+type ReasonError struct {
+	Code ReasonCode
+	Err  error
+}
+
+func (e *ReasonError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ReasonError) Unwrap() error {
+	return e.Err
+}
+
+// ReasonFor returns the reason code of a denial: the code of a ReasonError,
+// else the code of the first known error err wraps, else
+// ReasonAccessDenied. It returns "" for a nil error.
+func ReasonFor(err error) ReasonCode {
+	if err == nil {
+		return ""
+	}
+	var reasonErr *ReasonError
+	if errors.As(err, &reasonErr) {
+		return reasonErr.Code
+	}
+	for _, r := range reasonErrors {
+		if errors.Is(err, r.err) {
+			return r.code
+		}
+	}
+	return ReasonAccessDenied
+}
+
+// ReasonForStop returns the reason code of a session stopped with the given
+// stop reason: the code of a known error the reason mentions, e.g.
+// ReasonReauthRequired for sessions revoked by an attribute provider, else
+// ReasonSessionRevoked.
+func ReasonForStop(stopReason string) ReasonCode {
+	for _, r := range reasonErrors {
+		if r.code != ReasonSessionNotFound && strings.Contains(stopReason, r.err.Error()) {
+			return r.code
+		}
+	}
+	return ReasonSessionRevoked
+}
+
+// HTTPStatus returns the HTTP status of the code, as used by
+// AuthorizeRequest: 401 without a valid session, 403 otherwise.
+func (c ReasonCode) HTTPStatus() int {
+	if c == ReasonSessionRequired || c == ReasonSessionNotFound {
+		return http.StatusUnauthorized
+	}
+	return http.StatusForbidden
+}
+
+// GRPCCode returns the gRPC status code of the code, as a
+// google.golang.org/grpc/codes value. Interceptors attach the code itself as
+// an ErrorInfo detail with ReasonDomain.
+func (c ReasonCode) GRPCCode() uint32 {
+	if info, ok := reasons[c]; ok {
+		return info.grpc
+	}
+	return grpcPermissionDenied
+}
+
+// Problem is an RFC 9457 problem details body describing a denial, with the
+// reason code as an extension member.
+type Problem struct {
+	Type   string     `json:"type"`
+	Title  string     `json:"title"`
+	Status int        `json:"status"`
+	Detail string     `json:"detail,omitempty"`
+	Code   ReasonCode `json:"code"`
+}
+
+// NewProblem describes a denial as a Problem.
+func NewProblem(err error) *Problem {
+	code := ReasonFor(err)
+	title := reasons[code].title
+	if title == "" {
+		title = reasons[ReasonAccessDenied].title
+	}
+	problem := &Problem{
+		Type:   "urn:casbin-ucon:reason:" + strings.ToLower(string(code)),
+		Title:  title,
+		Status: code.HTTPStatus(),
+		Code:   code,
+	}
+	if err != nil {
+		problem.Detail = err.Error()
+	}
+	return problem
+}
+
+// WriteProblem answers a request with the given status and the Problem
+// describing a denial, like http.Error.
+func WriteProblem(w http.ResponseWriter, status int, err error) {
+	problem := NewProblem(err)
+	problem.Status = status
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(problem.Status)
+	_ = json.NewEncoder(w).Encode(problem)
+}
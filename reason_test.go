@@ -0,0 +1,107 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReasonFor(t *testing.T) {
+	tests := []struct {
+		err  error
+		want ReasonCode
+	}{
+		{nil, ""},
+		{ErrNoSession, ReasonSessionRequired},
+		{fmt.Errorf("%w: s1", ErrSessionNotFound), ReasonSessionNotFound},
+		{fmt.Errorf("%w: %w", ErrSessionDenied, ErrRevokedByProvider), ReasonReauthRequired},
+		{fmt.Errorf("%w: %w", ErrSessionDenied, ErrTokenExpired), ReasonReauthRequired},
+		{fmt.Errorf("%w: %w", ErrSessionDenied, ErrUnderMaintenance), ReasonUnderMaintenance},
+		{fmt.Errorf("%w: %w", ErrSessionDenied, ErrChannelMismatch), ReasonChannelMismatch},
+		{&ReasonError{Code: ReasonConsentRequired, Err: ErrSessionDenied}, ReasonConsentRequired},
+		{ErrSessionDenied, ReasonAccessDenied},
+		{errors.New("boom"), ReasonAccessDenied},
+	}
+	for _, tt := range tests {
+		if got := ReasonFor(tt.err); got != tt.want {
+			t.Errorf("ReasonFor(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestReasonForStop(t *testing.T) {
+	reason := fmt.Sprintf("Attribute provider check failed for session s1: %v", ErrRevokedByProvider)
+	if got := ReasonForStop(reason); got != ReasonReauthRequired {
+		t.Errorf("Expected %s for a provider revocation, got %s", ReasonReauthRequired, got)
+	}
+	if got := ReasonForStop("Conditions no longer met"); got != ReasonSessionRevoked {
+		t.Errorf("Expected %s, got %s", ReasonSessionRevoked, got)
+	}
+}
+
+func TestReasonCodeMappings(t *testing.T) {
+	if ReasonSessionRequired.HTTPStatus() != http.StatusUnauthorized || ReasonReauthRequired.HTTPStatus() != http.StatusForbidden {
+		t.Errorf("Unexpected HTTP statuses")
+	}
+	if ReasonReauthRequired.GRPCCode() != 16 || ReasonAccessDenied.GRPCCode() != 7 || ReasonUnderMaintenance.GRPCCode() != 14 {
+		t.Errorf("Unexpected gRPC codes")
+	}
+	for code := range reasons {
+		if NewProblem(&ReasonError{Code: code, Err: ErrSessionDenied}).Title == "" {
+			t.Errorf("Expected a title for %s", code)
+		}
+	}
+}
+
+func TestAuthorizeRequestReasonCode(t *testing.T) {
+	e := GetUconEnforcer()
+	sessionID, _ := e.CreateSession("alice", "read", "document1", map[string]interface{}{})
+	session, _ := e.GetSession(sessionID)
+	_ = session.Stop(fmt.Sprintf("Attribute provider check failed for session %s: %v", sessionID, ErrRevokedByProvider))
+
+	_, status, err := AuthorizeRequest(e, sessionID)
+	if status != http.StatusForbidden || !errors.Is(err, ErrSessionDenied) {
+		t.Fatalf("Expected a 403 denial, got %d %v", status, err)
+	}
+	if code := ReasonFor(err); code != ReasonReauthRequired {
+		t.Errorf("Expected %s, got %s", ReasonReauthRequired, code)
+	}
+}
+
+func TestHTTPMiddlewareProblem(t *testing.T) {
+	e := GetUconEnforcer()
+	handler := NewHTTPMiddleware(e, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(DefaultSessionHeader, "missing")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if ct := rec.Header().Get("Content-Type"); ct != ProblemContentType {
+		t.Errorf("Expected content type %s, got %s", ProblemContentType, ct)
+	}
+	var problem Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+		t.Fatal(err)
+	}
+	if problem.Status != http.StatusUnauthorized || problem.Code != ReasonSessionNotFound || problem.Type != "urn:casbin-ucon:reason:session_not_found" {
+		t.Errorf("Unexpected problem %+v", problem)
+	}
+}
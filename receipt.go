@@ -0,0 +1,211 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInvalidReceipt is returned by VerifyUsageReceipt for receipts whose
+// signature does not match their content.
+var ErrInvalidReceipt = errors.New("invalid usage receipt signature")
+
+// UsageReceipt is a signed record of one usage, written by the
+// "usage_receipt" obligation for billing and non-repudiation.
+type UsageReceipt struct {
+	// ID is the idempotency key of the obligation execution, so a retried
+	// obligation produces the same ID.
+	ID              string           `json:"id"`
+	SessionID       string           `json:"session_id"`
+	Subject         string           `json:"subject"`
+	Action          string           `json:"action"`
+	Object          string           `json:"object"`
+	Purpose         string           `json:"purpose,omitempty"`
+	GrantSource     []string         `json:"grant_source,omitempty"`
+	StartTime       time.Time        `json:"start_time"`
+	EndTime         time.Time        `json:"end_time"`
+	DurationSeconds float64          `json:"duration_seconds"`
+	Counters        map[string]int64 `json:"counters,omitempty"`
+	// Consumed sums the entitlements the session consumed from the Meter.
+	Consumed map[string]int64 `json:"consumed,omitempty"`
+	// TrailHash is the hex SHA-256 of the session's evaluation trace as
+	// returned by GetSessionTrace, encoded as JSON.
+	TrailHash string `json:"trail_hash"`
+	// Signature is the base64 Ed25519 signature of the receipt encoded as
+	// JSON without its signature.
+	Signature string `json:"signature"`
+}
+
+// ReceiptStore receives the usage receipts.
+type ReceiptStore interface {
+	SaveReceipt(receipt *UsageReceipt) error
+}
+
+// InMemoryReceiptStore is a ReceiptStore keeping receipts in memory, e.g. for tests.
+type InMemoryReceiptStore struct {
+	receipts map[string]*UsageReceipt
+	order    []string
+	mu       sync.Mutex
+}
+
+// NewInMemoryReceiptStore creates an empty InMemoryReceiptStore.
+func NewInMemoryReceiptStore() *InMemoryReceiptStore {
+	return &InMemoryReceiptStore{receipts: make(map[string]*UsageReceipt)}
+}
+
+// SaveReceipt implements ReceiptStore. Saving a receipt with a known ID
+// replaces it.
+func (s *InMemoryReceiptStore) SaveReceipt(receipt *UsageReceipt) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.receipts[receipt.ID]; !ok {
+		s.order = append(s.order, receipt.ID)
+	}
+	s.receipts[receipt.ID] = receipt
+	return nil
+}
+
+// Receipts returns the saved receipts, oldest first.
+func (s *InMemoryReceiptStore) Receipts() []*UsageReceipt {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	receipts := make([]*UsageReceipt, 0, len(s.order))
+	for _, id := range s.order {
+		receipts = append(receipts, s.receipts[id])
+	}
+	return receipts
+}
+
+// WithUsageReceipts enables the "usage_receipt" obligation: receipts are
+// signed with key and saved to store.
+func WithUsageReceipts(store ReceiptStore, key ed25519.PrivateKey) Option {
+	return func(u *UconEnforcer) {
+		u.receipts = store
+		u.receiptKey = key
+	}
+}
+
+// VerifyUsageReceipt checks the signature of a receipt with the public key
+// of the enforcer that issued it.
+func VerifyUsageReceipt(receipt *UsageReceipt, key ed25519.PublicKey) error {
+	signature, err := base64.StdEncoding.DecodeString(receipt.Signature)
+	if err != nil {
+		return ErrInvalidReceipt
+	}
+	payload, err := receiptPayload(receipt)
+	if err != nil {
+		return err
+	}
+	if len(key) != ed25519.PublicKeySize || !ed25519.Verify(key, payload, signature) {
+		return ErrInvalidReceipt
+	}
+	return nil
+}
+
+// receiptPayload is the signed content of a receipt.
+func receiptPayload(receipt *UsageReceipt) ([]byte, error) {
+	unsigned := *receipt
+	unsigned.Signature = ""
+	return json.Marshal(&unsigned)
+}
+
+// executeUsageReceipt is the "usage_receipt" obligation. Expr lists the
+// integer attributes to include as usage counters, comma separated; if
+// empty, every integer attribute of the session is included.
+func (u *UconEnforcer) executeUsageReceipt(expr string, session *Session, key string) error {
+	if u.receipts == nil || len(u.receiptKey) != ed25519.PrivateKeySize {
+		return errors.New("usage receipts are not configured, see WithUsageReceipts")
+	}
+	if session.IsDryRun() {
+		return nil
+	}
+	trace, err := u.GetSessionTrace(session.GetId())
+	if err != nil {
+		return err
+	}
+	encodedTrace, err := json.Marshal(trace)
+	if err != nil {
+		return err
+	}
+	trailHash := sha256.Sum256(encodedTrace)
+
+	now := time.Now()
+	receipt := &UsageReceipt{
+		ID:              key,
+		SessionID:       session.GetId(),
+		Subject:         session.GetSubject(),
+		Action:          session.GetAction(),
+		Object:          session.GetObject(),
+		Purpose:         session.GetPurpose(),
+		GrantSource:     session.GetGrantSource(),
+		StartTime:       session.GetStartTime(),
+		EndTime:         now,
+		DurationSeconds: now.Sub(session.GetStartTime()).Seconds(),
+		Counters:        receiptCounters(expr, session),
+		Consumed:        u.consumedBy(session),
+		TrailHash:       hex.EncodeToString(trailHash[:]),
+	}
+	payload, err := receiptPayload(receipt)
+	if err != nil {
+		return err
+	}
+	receipt.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(u.receiptKey, payload))
+	if err := u.receipts.SaveReceipt(receipt); err != nil {
+		return fmt.Errorf("failed to save usage receipt of session %s: %w", session.GetId(), err)
+	}
+	return nil
+}
+
+func receiptCounters(expr string, session *Session) map[string]int64 {
+	attributes := session.GetAttributes()
+	var keys []string
+	for _, key := range strings.Split(expr, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		for key := range attributes {
+			keys = append(keys, key)
+		}
+	}
+	counters := make(map[string]int64)
+	for _, key := range keys {
+		if n, ok := toInt64(attributes[key]); ok {
+			counters[key] = n
+		}
+	}
+	return counters
+}
+
+// consumedBy sums the Meter consumptions of a session by entitlement.
+func (u *UconEnforcer) consumedBy(session *Session) map[string]int64 {
+	consumed := make(map[string]int64)
+	for _, record := range u.meter.Usage(session.GetSubject()) {
+		if record.SessionID == session.GetId() {
+			consumed[record.Entitlement] += record.Amount
+		}
+	}
+	return consumed
+}
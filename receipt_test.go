@@ -0,0 +1,71 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"testing"
+)
+
+func TestUsageReceipt(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := NewInMemoryReceiptStore()
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithUsageReceipts(store, private))
+	uconE.GetMeter().Grant("alice", "credits", 10)
+	_ = uconE.AddObligation(&Obligation{ID: "charge", Name: "consume_entitlement", Kind: "pre", Expr: "credits:3"})
+	_ = uconE.AddObligation(&Obligation{ID: "receipt", Name: "usage_receipt", Kind: "post", Expr: "pages, label"})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"pages": 12, "label": "x"})
+	if _, err := uconE.EnforceWithSession(sessionID); err != nil {
+		t.Fatal(err)
+	}
+	if err := uconE.StopMonitoring(sessionID); err != nil {
+		t.Fatal(err)
+	}
+
+	receipts := store.Receipts()
+	if len(receipts) != 1 {
+		t.Fatalf("Expected one receipt, got %d", len(receipts))
+	}
+	receipt := receipts[0]
+	if receipt.SessionID != sessionID || receipt.Subject != "alice" || receipt.Counters["pages"] != 12 || len(receipt.Counters) != 1 {
+		t.Errorf("Expected the session and its pages counter, got %+v", receipt)
+	}
+	if receipt.Consumed["credits"] != 3 || len(receipt.TrailHash) != 64 || len(receipt.GrantSource) == 0 {
+		t.Errorf("Expected consumed credits, trail hash and grant source, got %+v", receipt)
+	}
+	if err := VerifyUsageReceipt(receipt, public); err != nil {
+		t.Errorf("Expected a valid signature, got %v", err)
+	}
+	tampered := *receipt
+	tampered.Counters = map[string]int64{"pages": 1}
+	if err := VerifyUsageReceipt(&tampered, public); !errors.Is(err, ErrInvalidReceipt) {
+		t.Errorf("Expected a tampered receipt to be rejected, got %v", err)
+	}
+}
+
+func TestUsageReceiptNotConfigured(t *testing.T) {
+	uconE := GetUconEnforcer()
+	_ = uconE.AddObligation(&Obligation{ID: "receipt", Name: "usage_receipt", Kind: "post"})
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	if err := uconE.ExecuteObligationsByType(sessionID, "post"); err == nil {
+		t.Error("Expected the obligation to fail without WithUsageReceipts")
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// RecordedRequest is an outgoing request captured by a RecordingTransport.
+type RecordedRequest struct {
+	Method string
+	URL    string
+	Header http.Header
+	Body   []byte
+}
+
+// Decode unmarshals the JSON body of the request, e.g. into a WebhookPayload.
+func (r *RecordedRequest) Decode(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// RecordingTransport is an http.RoundTripper that captures the requests of
+// obligations such as webhook instead of sending them, so tests can assert
+// on their side effects. It is safe for concurrent use.
+type RecordingTransport struct {
+	status   int
+	requests []RecordedRequest
+	mu       sync.Mutex
+}
+
+// NewRecordingTransport creates a RecordingTransport answering 200 OK.
+func NewRecordingTransport() *RecordingTransport {
+	return &RecordingTransport{status: http.StatusOK}
+}
+
+// SetStatusCode sets the status every request is answered with from now on,
+// e.g. http.StatusServiceUnavailable to test how failing webhooks are handled.
+func (t *RecordingTransport) SetStatusCode(status int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.status = status
+}
+
+// WithRecordingTransport makes the webhook obligation send its requests to
+// transport instead of the network.
+func WithRecordingTransport(transport *RecordingTransport) Option {
+	return WithHTTPClient(&http.Client{Transport: transport})
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	t.mu.Lock()
+	t.requests = append(t.requests, RecordedRequest{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: req.Header.Clone(),
+		Body:   body,
+	})
+	status := t.status
+	t.mu.Unlock()
+
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode: status,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(bytes.NewReader(nil)),
+		Request:    req,
+	}, nil
+}
+
+// Requests returns the captured requests, oldest first.
+func (t *RecordingTransport) Requests() []RecordedRequest {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]RecordedRequest(nil), t.requests...)
+}
+
+// Reset forgets the captured requests.
+func (t *RecordingTransport) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.requests = nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRecordingTransport(t *testing.T) {
+	transport := NewRecordingTransport()
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithRecordingTransport(transport))
+	_ = uconE.AddObligation(&Obligation{ID: "notify", Name: "webhook", Kind: "pre", Expr: "https://hooks.example.com/access"})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	if err := uconE.ExecuteObligationsByType(sessionID, "pre"); err != nil {
+		t.Fatal(err)
+	}
+	requests := transport.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("Expected one captured request, got %d", len(requests))
+	}
+	request := requests[0]
+	if request.Method != http.MethodPost || request.URL != "https://hooks.example.com/access" || request.Header.Get(IdempotencyKeyHeader) == "" {
+		t.Errorf("Unexpected request %+v", request)
+	}
+	var payload WebhookPayload
+	if err := request.Decode(&payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.SessionID != sessionID || payload.Subject != "alice" || payload.Object != "document1" {
+		t.Errorf("Unexpected payload %+v", payload)
+	}
+
+	transport.Reset()
+	transport.SetStatusCode(http.StatusServiceUnavailable)
+	err := uconE.ExecuteObligationsByType(sessionID, "pre")
+	if err == nil || !strings.Contains(err.Error(), "503 Service Unavailable") {
+		t.Errorf("Expected the webhook to fail with the configured status, got %v", err)
+	}
+	if len(transport.Requests()) != 1 {
+		t.Errorf("Expected the failed request to be captured too, got %d", len(transport.Requests()))
+	}
+}
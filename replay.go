@@ -0,0 +1,200 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// DecisionRecord is an access decision of EnforceWithSession as written to a
+// decision log, with the attributes the session had at the time. The records
+// of a session form the timeline of its attributes.
+type DecisionRecord struct {
+	Time       time.Time              `json:"time"`
+	SessionID  string                 `json:"session_id"`
+	Subject    string                 `json:"subject"`
+	Action     string                 `json:"action"`
+	Object     string                 `json:"object"`
+	Purpose    string                 `json:"purpose,omitempty"`
+	StartTime  time.Time              `json:"start_time"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	Allowed    bool                   `json:"allowed"`
+	Error      string                 `json:"error,omitempty"`
+}
+
+// DecisionChange is a recorded decision that a replay decided differently.
+type DecisionChange struct {
+	Record DecisionRecord `json:"record"`
+	// Allowed is the replayed decision and Reason, for denials, why access
+	// was denied.
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// ReplayReport is the outcome of ReplayDecisions.
+type ReplayReport struct {
+	Total   int              `json:"total"`
+	Changed []DecisionChange `json:"changed"`
+}
+
+// decisionLog writes DecisionRecords as JSON lines.
+type decisionLog struct {
+	w  io.Writer
+	mu sync.Mutex
+}
+
+// WithDecisionLog writes every access decision to w as a JSON line, for
+// ReplayDecisions. Denials for reasons other than the rules, such as a
+// stopped or suspended session, maintenance, an unreachable store or the
+// monitoring capacity, are not written.
+func WithDecisionLog(w io.Writer) Option {
+	return func(u *UconEnforcer) {
+		u.decisionLog = &decisionLog{w: w}
+	}
+}
+
+// isAdmissionError reports whether a decision was made before the rules
+// were evaluated, see checkAdmission.
+func isAdmissionError(err error) bool {
+	for _, target := range []error{ErrSessionNotFound, ErrSessionNotActive, ErrSessionSuspended, ErrSessionReserved, ErrUnderMaintenance, ErrStoreUnavailable, ErrCapacityExceeded} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordDecision writes a decision to the decision log.
+func (u *UconEnforcer) recordDecision(sessionID string, granted *Session, err error) {
+	if u.decisionLog == nil || isAdmissionError(err) {
+		return
+	}
+	session, getErr := u.GetSession(sessionID)
+	if getErr != nil {
+		return
+	}
+	record := session.ToRecord()
+	decision := DecisionRecord{
+		Time:       time.Now(),
+		SessionID:  sessionID,
+		Subject:    record.Subject,
+		Action:     record.Action,
+		Object:     record.Object,
+		Purpose:    record.Purpose,
+		StartTime:  record.StartTime,
+		Attributes: session.GetAttributes(),
+		Allowed:    granted != nil,
+	}
+	if err != nil {
+		decision.Error = err.Error()
+	}
+	data, marshalErr := json.Marshal(decision)
+	if marshalErr != nil {
+		u.logger.Log(LevelWarn, "failed to encode decision", map[string]interface{}{"session": sessionID, "error": marshalErr.Error()})
+		return
+	}
+	u.decisionLog.mu.Lock()
+	defer u.decisionLog.mu.Unlock()
+	if _, err := u.decisionLog.w.Write(append(data, '\n')); err != nil {
+		u.logger.Log(LevelWarn, "failed to write decision log", map[string]interface{}{"session": sessionID, "error": err.Error()})
+	}
+}
+
+// ReadDecisionLog reads the records written by WithDecisionLog.
+func ReadDecisionLog(r io.Reader) ([]DecisionRecord, error) {
+	var records []DecisionRecord
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		record := DecisionRecord{}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("decision log line %d: %w", line, err)
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+// ReplayDecisions decides the recorded decisions again with the enforcer's
+// current policy and conditions, e.g. those of a candidate rule set, and
+// reports the decisions that would change. Each record is evaluated on a
+// detached copy of its session with the recorded attributes, so replaying
+// creates no sessions and runs no obligations; pre obligations that denied
+// access when recorded are therefore not reproduced. Time-dependent
+// conditions are evaluated at the current time.
+func (u *UconEnforcer) ReplayDecisions(records []DecisionRecord) (*ReplayReport, error) {
+	report := &ReplayReport{Total: len(records), Changed: []DecisionChange{}}
+	for _, record := range records {
+		allowed, reason, err := u.replayDecision(&record)
+		if err != nil {
+			return nil, fmt.Errorf("session %s at %s: %w", record.SessionID, record.Time.Format(time.RFC3339), err)
+		}
+		if allowed != record.Allowed {
+			report.Changed = append(report.Changed, DecisionChange{Record: record, Allowed: allowed, Reason: reason})
+		}
+	}
+	return report, nil
+}
+
+// replayDecision evaluates the conditions and the policy for a recorded
+// decision. The conditions are dry-run, so replaying emits no events and
+// records no metrics. Condition errors deny access, as they do in
+// EnforceWithSession; the returned error is for policy evaluation failures.
+func (u *UconEnforcer) replayDecision(record *DecisionRecord) (bool, string, error) {
+	attributes := make(map[string]interface{}, len(record.Attributes))
+	for k, v := range record.Attributes {
+		attributes[k] = v
+	}
+	session := NewSession(&SessionRecord{
+		ID:         record.SessionID,
+		Subject:    record.Subject,
+		Action:     record.Action,
+		Object:     record.Object,
+		Purpose:    record.Purpose,
+		Attributes: attributes,
+		Active:     true,
+		StartTime:  record.StartTime,
+	}, nil, nil)
+	session.dryRun = true
+	session.origin, _ = u.GetSession(record.SessionID)
+
+	for _, condition := range u.conditionList() {
+		cond := condition // Create a copy to avoid memory aliasing
+		passed, err := u.dryRunCondition(&cond, session, u.getFailurePolicy())
+		if err != nil {
+			return false, err.Error(), nil
+		}
+		if !passed {
+			return false, fmt.Sprintf("condition %s not met", cond.ID), nil
+		}
+	}
+	allowed, _, err := u.EnforceEx(record.Subject, record.Object, record.Action)
+	if err != nil {
+		return false, "", err
+	}
+	if !allowed {
+		return false, "denied by policy", nil
+	}
+	return true, "", nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDecisionReplay(t *testing.T) {
+	var decisionLog bytes.Buffer
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithDecisionLog(&decisionLog))
+	uconE.AddCondition(&Condition{ID: "office", Name: "location", Kind: "always", Expr: "office"})
+
+	atOffice, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"location": "office"})
+	atHome, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"location": "home"})
+	bob, _ := uconE.CreateSession("bob", "read", "document1", map[string]interface{}{"location": "office"})
+	for _, id := range []string{atOffice, atHome, bob} {
+		uconE.EnforceWithSession(id)
+	}
+	// Moving home is part of the session's attribute timeline.
+	uconE.UpdateSessionAttribute(atOffice, "location", "home")
+	uconE.EnforceWithSession(atOffice)
+	// Denials that do not depend on the rules are not recorded.
+	uconE.StopMonitoring(bob)
+	uconE.EnforceWithSession(bob)
+
+	records, err := ReadDecisionLog(&decisionLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("Expected 4 recorded decisions, got %d", len(records))
+	}
+	if !records[0].Allowed || records[1].Allowed || records[3].Allowed || records[3].Attributes["location"] != "home" {
+		t.Errorf("Unexpected records %+v", records)
+	}
+
+	// The same rules decide the same way, and replaying them records nothing.
+	uconE.StopMonitoring(atOffice)
+	uconE.StopMonitoring(atHome)
+	evaluations := uconE.GetMetrics().(*InMemoryMetrics).Snapshot().Latency[MetricCondition]["office"].Count
+	events := 0
+	uconE.AddEventListener(func(event Event) { events++ })
+	report, err := uconE.ReplayDecisions(records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Total != 4 || len(report.Changed) != 0 {
+		t.Errorf("Expected no changes with the same rules, got %+v", report)
+	}
+	if count := uconE.GetMetrics().(*InMemoryMetrics).Snapshot().Latency[MetricCondition]["office"].Count; count != evaluations || events != 0 {
+		t.Errorf("Expected replaying to record no evaluations and emit no events, got %d evaluations and %d events", count-evaluations, events)
+	}
+
+	// The candidate allows working from home but no longer lets bob read.
+	candidateCasbin := GetUconEnforcer().(*UconEnforcer).Enforcer
+	candidateCasbin.RemovePolicy("bob", "document1", "read")
+	candidate := NewUconEnforcer(candidateCasbin)
+	candidate.AddCondition(&Condition{ID: "on_site", Name: "attribute_in", Kind: "always", Expr: "location:office,home"})
+	report, err = candidate.ReplayDecisions(records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Changed) != 3 {
+		t.Fatalf("Expected 3 changed decisions, got %+v", report.Changed)
+	}
+	for _, change := range report.Changed {
+		switch change.Record.SessionID {
+		case bob:
+			if change.Allowed || change.Reason != "denied by policy" {
+				t.Errorf("Expected bob to be denied by policy, got %+v", change)
+			}
+		case atHome, atOffice:
+			if !change.Allowed {
+				t.Errorf("Expected alice at home to be allowed, got %+v", change)
+			}
+		}
+	}
+	if len(candidate.GetSessions()) != 0 {
+		t.Error("Expected replaying not to create sessions")
+	}
+}
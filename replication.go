@@ -0,0 +1,353 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Replication event types.
+const (
+	ReplicationSave      = "save"
+	ReplicationDelete    = "delete"
+	ReplicationKeepalive = "keepalive"
+)
+
+// DefaultReplicationKeepalive is how often a Replicator stream sends a
+// keepalive event when no session changes.
+const DefaultReplicationKeepalive = time.Second
+
+// replicationBuffer is the number of events buffered per standby. A standby
+// that falls further behind is disconnected and resynchronizes on reconnect.
+const replicationBuffer = 1024
+
+// ReplicationEvent is a session change streamed from a primary enforcer to
+// its standbys.
+type ReplicationEvent struct {
+	Seq     uint64         `json:"seq"`
+	Type    string         `json:"type"`
+	Session *SessionRecord `json:"session,omitempty"`
+	ID      string         `json:"id,omitempty"`
+}
+
+// Replicator is a SessionStore for a primary enforcer that streams every
+// session change to hot-standby enforcers. It keeps the latest record of each
+// session in memory and also writes through to inner, if not nil.
+//
+// Install it with WithSessionStore on the primary and serve it over HTTP;
+// standbys follow it with FollowPrimary.
+type Replicator struct {
+	inner       SessionStore
+	records     map[string]*SessionRecord
+	seq         uint64
+	subscribers map[chan ReplicationEvent]struct{}
+	mu          sync.Mutex
+}
+
+// NewReplicator creates a Replicator writing through to inner, which may be nil.
+func NewReplicator(inner SessionStore) *Replicator {
+	return &Replicator{
+		inner:       inner,
+		records:     make(map[string]*SessionRecord),
+		subscribers: make(map[chan ReplicationEvent]struct{}),
+	}
+}
+
+// SaveSession implements SessionStore.
+func (r *Replicator) SaveSession(record *SessionRecord) error {
+	if r.inner != nil {
+		if err := r.inner.SaveSession(record); err != nil {
+			return err
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records[record.ID] = record
+	r.publishLocked(ReplicationEvent{Type: ReplicationSave, Session: record})
+	return nil
+}
+
+// LoadSessions implements SessionStore.
+func (r *Replicator) LoadSessions() ([]*SessionRecord, error) {
+	if r.inner != nil {
+		records, err := r.inner.LoadSessions()
+		if err != nil {
+			return nil, err
+		}
+		r.mu.Lock()
+		for _, record := range records {
+			r.records[record.ID] = record
+		}
+		r.mu.Unlock()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.recordsLocked(), nil
+}
+
+// DeleteSession implements SessionStore.
+func (r *Replicator) DeleteSession(id string) error {
+	if r.inner != nil {
+		if err := r.inner.DeleteSession(id); err != nil {
+			return err
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.records, id)
+	r.publishLocked(ReplicationEvent{Type: ReplicationDelete, ID: id})
+	return nil
+}
+
+func (r *Replicator) recordsLocked() []*SessionRecord {
+	records := make([]*SessionRecord, 0, len(r.records))
+	for _, record := range r.records {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].StartTime.Before(records[j].StartTime)
+	})
+	return records
+}
+
+func (r *Replicator) publishLocked(event ReplicationEvent) {
+	r.seq++
+	event.Seq = r.seq
+	for ch := range r.subscribers {
+		select {
+		case ch <- event:
+		default:
+			// Too far behind: drop the standby, it resynchronizes on reconnect.
+			delete(r.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Subscribe returns the current sessions as save events followed by a
+// channel of later changes. The channel is closed if the subscriber falls
+// behind or cancel is called.
+func (r *Replicator) Subscribe() ([]ReplicationEvent, <-chan ReplicationEvent, func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	snapshot := make([]ReplicationEvent, 0, len(r.records))
+	for _, record := range r.recordsLocked() {
+		snapshot = append(snapshot, ReplicationEvent{Seq: r.seq, Type: ReplicationSave, Session: record})
+	}
+	ch := make(chan ReplicationEvent, replicationBuffer)
+	r.subscribers[ch] = struct{}{}
+	cancel := func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if _, ok := r.subscribers[ch]; ok {
+			delete(r.subscribers, ch)
+			close(ch)
+		}
+	}
+	return snapshot, ch, cancel
+}
+
+// ServeHTTP streams the replication events to a standby as newline-delimited
+// JSON: the current sessions first, then every change, with keepalives in
+// between.
+func (r *Replicator) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	snapshot, events, cancel := r.Subscribe()
+	defer cancel()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(w)
+	send := func(event ReplicationEvent) bool {
+		if err := enc.Encode(event); err != nil {
+			return false
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return true
+	}
+
+	for _, event := range snapshot {
+		if !send(event) {
+			return
+		}
+	}
+	keepalive := time.NewTicker(DefaultReplicationKeepalive)
+	defer keepalive.Stop()
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case event, ok := <-events:
+			if !ok || !send(event) {
+				return
+			}
+		case <-keepalive.C:
+			if !send(ReplicationEvent{Type: ReplicationKeepalive}) {
+				return
+			}
+		}
+	}
+}
+
+// ApplyReplicationEvent applies a session change received from a primary.
+// Sessions are replicated without being monitored until TakeOver is called.
+func (u *UconEnforcer) ApplyReplicationEvent(event ReplicationEvent) error {
+	switch event.Type {
+	case ReplicationSave:
+		if event.Session == nil {
+			return fmt.Errorf("replication event %d has no session", event.Seq)
+		}
+		record := *event.Session
+		record.Attributes = make(map[string]interface{}, len(event.Session.Attributes))
+		for k, v := range event.Session.Attributes {
+			record.Attributes[k] = v
+		}
+		if session, restored := u.restoreSession(&record); !restored {
+			session.applyRecord(event.Session)
+			u.gauges.update(session)
+		}
+	case ReplicationDelete:
+		_ = u.sessions.DeleteSession(event.ID)
+		u.gauges.remove(event.ID)
+	case ReplicationKeepalive:
+	default:
+		return fmt.Errorf("unknown replication event type %q", event.Type)
+	}
+	return nil
+}
+
+// FollowPrimary keeps the enforcer a hot standby of the primary whose
+// Replicator is served at url, reconnecting when the stream breaks. If
+// nothing is received from the primary for failoverAfter, the standby takes
+// over monitoring and FollowPrimary returns nil. It returns ctx.Err() when
+// ctx is done first.
+func (u *UconEnforcer) FollowPrimary(ctx context.Context, url string, failoverAfter time.Duration) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	received := make(chan ReplicationEvent)
+	go func() {
+		for ctx.Err() == nil {
+			if err := u.readReplicationStream(ctx, url, received); err != nil && ctx.Err() == nil {
+				u.logger.Log(LevelWarn, "replication stream failed", map[string]interface{}{"primary": url, "error": err.Error()})
+			}
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+		}
+	}()
+
+	timer := time.NewTimer(failoverAfter)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event := <-received:
+			if err := u.ApplyReplicationEvent(event); err != nil {
+				u.logger.Log(LevelWarn, "invalid replication event", map[string]interface{}{"primary": url, "error": err.Error()})
+			}
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(failoverAfter)
+		case <-timer.C:
+			u.logger.Log(LevelWarn, "primary unreachable, taking over", map[string]interface{}{"primary": url, "silence": failoverAfter.String()})
+			return u.TakeOver()
+		}
+	}
+}
+
+func (u *UconEnforcer) readReplicationStream(ctx context.Context, url string, received chan<- ReplicationEvent) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	// The stream is long-lived, so it must not use a client with a timeout.
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		event := ReplicationEvent{}
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return fmt.Errorf("invalid replication event: %w", err)
+		}
+		select {
+		case received <- event:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("stream closed by primary")
+}
+
+// TakeOver makes a standby the primary: it starts monitoring every active
+// session that the former primary was monitoring.
+func (u *UconEnforcer) TakeOver() error {
+	for _, session := range u.GetSessions() {
+		record := session.ToRecord()
+		if !record.Active || !record.Monitored {
+			continue
+		}
+		if err := u.StartMonitoring(record.ID); err != nil {
+			return fmt.Errorf("failed to take over monitoring of session %s: %w", record.ID, err)
+		}
+	}
+	return nil
+}
+
+// applyRecord overwrites the mutable state of the session with a newer record.
+func (s *Session) applyRecord(record *SessionRecord) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.attributes = make(map[string]interface{}, len(record.Attributes))
+	for k, v := range record.Attributes {
+		s.attributes[k] = v
+	}
+	s.attrVersion++
+	s.setFlag(flagActive, record.Active)
+	s.setFlag(flagMonitored, record.Monitored)
+	s.endTime = record.EndTime
+	s.stopReason = record.StopReason
+	s.grantSource = append([]string(nil), record.GrantSource...)
+	s.grant = record.Grant.clone()
+	s.attempts = copyAttempts(record.ObligationAttempts)
+	s.suspendedUntil = record.suspendedUntil()
+	s.resumeHash = record.ResumeHash
+	s.breakGlass = record.BreakGlass
+	s.review = record.Review.clone()
+	s.binding = record.ChannelBinding
+	s.version++
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHotStandbyReplication(t *testing.T) {
+	replicator := NewReplicator(nil)
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	primary := NewUconEnforcer(e, WithSessionStore(replicator))
+	server := httptest.NewServer(replicator)
+	defer server.Close()
+
+	standby := GetUconEnforcer().(*UconEnforcer)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	followed := make(chan error, 1)
+	go func() {
+		followed <- standby.FollowPrimary(ctx, server.URL, 500*time.Millisecond)
+	}()
+
+	sessionID, _ := primary.CreateSession("alice", "read", "document1", map[string]interface{}{"location": "office"})
+	if session, _ := primary.EnforceWithSession(sessionID); session == nil {
+		t.Fatal("Expected access")
+	}
+	_ = primary.UpdateSessionAttribute(sessionID, "location", "home")
+	stoppedID, _ := primary.CreateSession("bob", "read", "document1", nil)
+	stopped, _ := primary.GetSession(stoppedID)
+	_ = stopped.Stop("done")
+	time.Sleep(300 * time.Millisecond)
+
+	replica, err := standby.GetSession(sessionID)
+	if err != nil {
+		t.Fatalf("Expected the session to be replicated: %v", err)
+	}
+	if replica.GetAttribute("location") != "home" || !replica.IfActive() {
+		t.Errorf("Unexpected replica: %+v", replica.ToRecord())
+	}
+	if replica, _ := standby.GetSession(stoppedID); replica == nil || replica.IfActive() {
+		t.Error("Expected the stopped session to be replicated as stopped")
+	}
+	standby.mu.RLock()
+	monitored := standby.monitoringActive[sessionID]
+	standby.mu.RUnlock()
+	if monitored {
+		t.Error("Expected the standby not to monitor replicated sessions")
+	}
+
+	// The primary fails.
+	server.CloseClientConnections()
+	server.Config.SetKeepAlivesEnabled(false)
+	server.Listener.Close()
+	primary.(*UconEnforcer).mu.Lock()
+	primary.(*UconEnforcer).monitoringActive[sessionID] = false
+	primary.(*UconEnforcer).mu.Unlock()
+
+	select {
+	case err := <-followed:
+		if err != nil {
+			t.Fatalf("FollowPrimary: %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("Expected the standby to take over")
+	}
+	defer standby.StopMonitoring(sessionID)
+	standby.mu.RLock()
+	monitored = standby.monitoringActive[sessionID]
+	_, stoppedMonitored := standby.monitoringActive[stoppedID]
+	standby.mu.RUnlock()
+	if !monitored || stoppedMonitored {
+		t.Error("Expected the standby to monitor only the sessions the primary was monitoring")
+	}
+}
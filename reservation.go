@@ -0,0 +1,207 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultReservationTTL is how long a reservation is held unless
+// ReserveAccess is given another TTL.
+const DefaultReservationTTL = 30 * time.Second
+
+// Events of reservations.
+const (
+	// EventAccessReserved is emitted when ReserveAccess grants a reservation.
+	EventAccessReserved EventType = "access_reserved"
+	// EventReservationReleased is emitted when a reservation is cancelled or
+	// expires. Data carries the "reservation" and the "reason".
+	EventReservationReleased EventType = "reservation_released"
+)
+
+var (
+	// ErrReservationDenied is returned by ReserveAccess when the session's
+	// conditions or the policy deny access.
+	ErrReservationDenied = errors.New("reservation denied")
+	// ErrReservationNotFound is returned for reservations that do not exist,
+	// were committed or cancelled, or have expired.
+	ErrReservationNotFound = errors.New("reservation not found")
+	// ErrSessionReserved is returned when a session with a pending
+	// reservation is enforced or reserved again.
+	ErrSessionReserved = errors.New("session has a pending reservation")
+)
+
+// reservation is a grant held by ReserveAccess until it is committed.
+type reservation struct {
+	id       string
+	session  *Session
+	rule     []string
+	interval time.Duration // of the monitoring slot it holds
+	timer    *time.Timer
+}
+
+// ReserveAccess is the first phase of a two-phase grant: it checks the
+// session's conditions and the policy, and holds a monitoring slot for the
+// session for ttl (DefaultReservationTTL if 0), so that resources such as
+// licenses or bandwidth can be allocated before usage starts. CommitAccess
+// turns the reservation into a monitored session; CancelReservation or the
+// end of the TTL releases it. Pre obligations run on commit.
+func (u *UconEnforcer) ReserveAccess(sessionID string, ttl time.Duration) (string, error) {
+	session, err := u.GetSession(sessionID)
+	if err != nil {
+		return "", err
+	}
+	if err := u.checkAdmission(session); err != nil {
+		return "", err
+	}
+	if ttl <= 0 {
+		ttl = DefaultReservationTTL
+	}
+
+	if err := u.refreshAttributes(session); err != nil {
+		return "", err
+	}
+	conditionsOk, err := u.EvaluateConditions(sessionID)
+	if err != nil {
+		return "", err
+	}
+	if !conditionsOk {
+		return "", fmt.Errorf("%w: conditions of session %s not met", ErrReservationDenied, sessionID)
+	}
+	ok, rule, err := u.EnforceEx(session.GetSubject(), session.GetObject(), session.GetAction())
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("%w: no policy grants session %s", ErrReservationDenied, sessionID)
+	}
+
+	interval, err := u.reserveMonitoring(session)
+	if err != nil {
+		return "", fmt.Errorf("cannot reserve access for session %s: %w", sessionID, err)
+	}
+	if interval == 0 {
+		return "", fmt.Errorf("session %s is already monitored", sessionID)
+	}
+
+	r := &reservation{
+		id:       fmt.Sprintf("reservation_%d", time.Now().UnixNano()),
+		session:  session,
+		rule:     rule,
+		interval: interval,
+	}
+	u.mu.Lock()
+	if u.reservations == nil {
+		u.reservations = make(map[string]*reservation)
+		u.reservedSessions = make(map[string]string)
+	}
+	if existing, ok := u.reservedSessions[sessionID]; ok {
+		u.mu.Unlock()
+		u.releaseMonitoring(sessionID)
+		return "", fmt.Errorf("session %s: %w by %s", sessionID, ErrSessionReserved, existing)
+	}
+	u.reservations[r.id] = r
+	u.reservedSessions[sessionID] = r.id
+	r.timer = time.AfterFunc(ttl, func() { u.releaseReservation(r.id, "expired") })
+	u.mu.Unlock()
+
+	expires := time.Now().Add(ttl)
+	u.emit(Event{
+		Type:      EventAccessReserved,
+		SessionID: sessionID,
+		Message:   fmt.Sprintf("access reserved for session %s until %s", sessionID, expires.Format(time.RFC3339)),
+		Data:      map[string]interface{}{"reservation": r.id, "expires": expires},
+	})
+	return r.id, nil
+}
+
+// CommitAccess is the second phase of a two-phase grant: it runs the pre
+// obligations of the reserved session and starts monitoring it in the slot
+// the reservation held. If the session can no longer be enforced, e.g.
+// because it was stopped, the reservation is released with the error.
+func (u *UconEnforcer) CommitAccess(reservationID string) (*Session, error) {
+	r := u.takeReservation(reservationID)
+	if r == nil {
+		return nil, fmt.Errorf("%w: %s", ErrReservationNotFound, reservationID)
+	}
+	session := r.session
+	sessionID := session.GetId()
+	if err := u.checkAdmission(session); err != nil {
+		u.releaseMonitoring(sessionID)
+		u.emitDecision(sessionID, nil, err)
+		return nil, err
+	}
+	if err := u.ExecuteObligationsByType(sessionID, "pre"); err != nil {
+		u.releaseMonitoring(sessionID)
+		u.emitDecision(sessionID, nil, err)
+		return nil, err
+	}
+	if err := session.recordGrant(r.rule, time.Now()); err != nil {
+		u.logger.Log(LevelWarn, "failed to persist grant source", map[string]interface{}{"session": sessionID, "error": err.Error()})
+	}
+	u.startMonitoring(session, r.interval)
+	u.emitDecision(sessionID, session, nil)
+	return session, nil
+}
+
+// CancelReservation releases a reservation that will not be committed, e.g.
+// because allocating the resources failed.
+func (u *UconEnforcer) CancelReservation(reservationID string) error {
+	if !u.releaseReservation(reservationID, "cancelled") {
+		return fmt.Errorf("%w: %s", ErrReservationNotFound, reservationID)
+	}
+	return nil
+}
+
+// takeReservation removes a pending reservation and returns it, or nil if
+// there is none.
+func (u *UconEnforcer) takeReservation(reservationID string) *reservation {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	r, ok := u.reservations[reservationID]
+	if !ok {
+		return nil
+	}
+	r.timer.Stop()
+	delete(u.reservations, reservationID)
+	delete(u.reservedSessions, r.session.GetId())
+	return r
+}
+
+// releaseReservation removes a pending reservation and frees its monitoring
+// slot. It reports whether the reservation was pending.
+func (u *UconEnforcer) releaseReservation(reservationID string, reason string) bool {
+	r := u.takeReservation(reservationID)
+	if r == nil {
+		return false
+	}
+	u.releaseMonitoring(r.session.GetId())
+	u.emit(Event{
+		Type:      EventReservationReleased,
+		SessionID: r.session.GetId(),
+		Message:   fmt.Sprintf("reservation %s %s", reservationID, reason),
+		Data:      map[string]interface{}{"reservation": reservationID, "reason": reason},
+	})
+	return true
+}
+
+// reservationOf returns the pending reservation of a session, if any.
+func (u *UconEnforcer) reservationOf(sessionID string) string {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.reservedSessions[sessionID]
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReserveAndCommitAccess(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithMaxMonitoredSessions(1, CapacityReject))
+	var mu sync.Mutex
+	var pre int
+	_ = uconE.RegisterObligationHandler("allocate", func(expr string, session *Session) error {
+		if session.GetSubject() == "alice" {
+			mu.Lock()
+			pre++
+			mu.Unlock()
+		}
+		return nil
+	})
+	_ = uconE.AddObligation(&Obligation{ID: "allocate", Name: "allocate", Kind: "pre"})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	reservationID, err := uconE.ReserveAccess(sessionID, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uconE.MonitoredSessionCount() != 1 {
+		t.Errorf("Expected the reservation to hold a monitoring slot")
+	}
+	other, _ := uconE.CreateSession("bob", "read", "document1", nil)
+	if _, err := uconE.EnforceWithSession(other); !errors.Is(err, ErrCapacityExceeded) {
+		t.Errorf("Expected the held slot to count against capacity, got %v", err)
+	}
+	if _, err := uconE.EnforceWithSession(sessionID); !errors.Is(err, ErrSessionReserved) {
+		t.Errorf("Expected ErrSessionReserved, got %v", err)
+	}
+	mu.Lock()
+	if pre != 0 {
+		t.Errorf("Expected pre obligations to wait for the commit")
+	}
+	mu.Unlock()
+
+	session, err := uconE.CommitAccess(reservationID)
+	if err != nil || session == nil {
+		t.Fatalf("Expected the commit to grant access, got %v", err)
+	}
+	mu.Lock()
+	if pre != 1 {
+		t.Errorf("Expected pre obligations to run once on commit, got %d", pre)
+	}
+	mu.Unlock()
+	if len(session.GetGrantSource()) == 0 || !session.ToRecord().Monitored {
+		t.Errorf("Expected a monitored session with its grant source")
+	}
+	if _, err := uconE.CommitAccess(reservationID); !errors.Is(err, ErrReservationNotFound) {
+		t.Errorf("Expected a committed reservation to be gone, got %v", err)
+	}
+	_ = uconE.StopMonitoring(sessionID)
+}
+
+func TestReservationReleased(t *testing.T) {
+	uconE := GetUconEnforcer()
+	var mu sync.Mutex
+	var reasons []interface{}
+	uconE.AddEventListener(func(event Event) {
+		if event.Type == EventReservationReleased {
+			mu.Lock()
+			reasons = append(reasons, event.Data["reason"])
+			mu.Unlock()
+		}
+	})
+
+	denied, _ := uconE.CreateSession("bob", "write", "document1", nil)
+	if _, err := uconE.ReserveAccess(denied, 0); !errors.Is(err, ErrReservationDenied) {
+		t.Errorf("Expected ErrReservationDenied, got %v", err)
+	}
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	cancelled, _ := uconE.ReserveAccess(sessionID, time.Minute)
+	if err := uconE.CancelReservation(cancelled); err != nil {
+		t.Fatal(err)
+	}
+	expiring, _ := uconE.ReserveAccess(sessionID, 50*time.Millisecond)
+	time.Sleep(150 * time.Millisecond)
+	if _, err := uconE.CommitAccess(expiring); !errors.Is(err, ErrReservationNotFound) {
+		t.Errorf("Expected an expired reservation to be gone, got %v", err)
+	}
+	mu.Lock()
+	if len(reasons) != 2 || reasons[0] != "cancelled" || reasons[1] != "expired" {
+		t.Errorf("Expected cancelled and expired releases, got %v", reasons)
+	}
+	mu.Unlock()
+	if session, err := uconE.EnforceWithSession(sessionID); err != nil || session == nil {
+		t.Errorf("Expected a released session to be enforceable, got %v", err)
+	}
+	_ = uconE.StopMonitoring(sessionID)
+}
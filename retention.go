@@ -0,0 +1,235 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"sort"
+	"time"
+)
+
+// DefaultRetentionInterval is how often WithRetention prunes by default.
+const DefaultRetentionInterval = time.Hour
+
+// RetentionPolicy bounds a history by age and size. A zero MaxAge or
+// MaxEntries leaves that bound unlimited.
+type RetentionPolicy struct {
+	MaxAge     time.Duration `json:"max_age,omitempty"`
+	MaxEntries int           `json:"max_entries,omitempty"`
+}
+
+// excess returns how many of the oldest items to remove, given their times
+// oldest first.
+func (p RetentionPolicy) excess(times []time.Time, now time.Time) int {
+	n := 0
+	if p.MaxEntries > 0 && len(times) > p.MaxEntries {
+		n = len(times) - p.MaxEntries
+	}
+	if p.MaxAge > 0 {
+		cutoff := now.Add(-p.MaxAge)
+		for n < len(times) && times[n].Before(cutoff) {
+			n++
+		}
+	}
+	return n
+}
+
+// Retention configures WithRetention.
+type Retention struct {
+	// Interval is the time between prunings, DefaultRetentionInterval if 0.
+	Interval time.Duration
+	// AuditLog, if set, is pruned with Audit.
+	AuditLog *AuditLog
+	Audit    RetentionPolicy
+	// Sessions bounds the stopped sessions kept, by end time. Pruned
+	// sessions are deleted, from the session store as well.
+	Sessions RetentionPolicy
+	// Traces bounds the evaluation trace of each session, see
+	// GetSessionTrace. WithTraceSize remains the upper bound between
+	// prunings.
+	Traces RetentionPolicy
+	// BreakGlassReviews bounds the reviewed break-glass reviews, by review
+	// time. Pending reviews are never pruned.
+	BreakGlassReviews RetentionPolicy
+	// Usage bounds the usage ledger entries of each object, by end time, if
+	// the ledger implements UsageLedgerPruner like InMemoryUsageLedger.
+	Usage RetentionPolicy
+}
+
+// RetentionReport counts what a pruning removed.
+type RetentionReport struct {
+	AuditEntries      int `json:"audit_entries"`
+	Sessions          int `json:"sessions"`
+	TraceEntries      int `json:"trace_entries"`
+	BreakGlassReviews int `json:"break_glass_reviews"`
+	UsageEntries      int `json:"usage_entries"`
+	Approvals         int `json:"approvals"`
+}
+
+// WithRetention prunes the audit log, the stopped sessions, the evaluation
+// traces, the break-glass reviews and the usage ledger in the background, so
+// long-running services do not grow without bound, until StopRetention.
+func WithRetention(retention Retention) Option {
+	return func(u *UconEnforcer) {
+		u.retention = &retention
+	}
+}
+
+// PruneHistory applies the WithRetention policies now and reports what it
+// removed. It also drops the approvals of sessions that are gone or no longer
+// active. Without WithRetention it removes nothing.
+func (u *UconEnforcer) PruneHistory() RetentionReport {
+	report := RetentionReport{}
+	retention := u.retention
+	if retention == nil {
+		return report
+	}
+	now := time.Now()
+	if retention.AuditLog != nil {
+		report.AuditEntries = retention.AuditLog.Prune(retention.Audit, now)
+	}
+
+	var stopped []*Session
+	for _, session := range u.GetSessions() {
+		report.TraceEntries += session.pruneTrace(retention.Traces, now)
+		if !session.IfActive() {
+			stopped = append(stopped, session)
+		}
+	}
+	sort.Slice(stopped, func(i, j int) bool { return stopped[i].GetEndTime().Before(stopped[j].GetEndTime()) })
+	ended := make([]time.Time, len(stopped))
+	for i, session := range stopped {
+		ended[i] = session.GetEndTime()
+	}
+	for _, session := range stopped[:retention.Sessions.excess(ended, now)] {
+		if err := u.sessions.DeleteSession(session.GetId()); err != nil {
+			u.logger.Log(LevelWarn, "failed to prune session", map[string]interface{}{"session": session.GetId(), "error": err.Error()})
+			continue
+		}
+		report.Sessions++
+	}
+
+	report.BreakGlassReviews = u.pruneBreakGlassReviews(retention.BreakGlassReviews, now)
+	if pruner, ok := u.usageLedger.(UsageLedgerPruner); ok {
+		report.UsageEntries = pruner.PruneUsage(retention.Usage, now)
+	}
+	report.Approvals = u.pruneApprovals()
+
+	if report != (RetentionReport{}) {
+		u.logger.Log(LevelDebug, "history pruned", map[string]interface{}{
+			"audit_entries":       report.AuditEntries,
+			"sessions":            report.Sessions,
+			"trace_entries":       report.TraceEntries,
+			"break_glass_reviews": report.BreakGlassReviews,
+			"usage_entries":       report.UsageEntries,
+			"approvals":           report.Approvals,
+		})
+	}
+	return report
+}
+
+// StopRetention stops the background pruning of WithRetention and waits for
+// it to return. PruneHistory keeps applying the policies when called.
+func (u *UconEnforcer) StopRetention() {
+	u.mu.Lock()
+	stop, done := u.retentionStop, u.retentionDone
+	u.retentionStop = nil
+	u.mu.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+func (u *UconEnforcer) retentionLoop(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	interval := u.retention.Interval
+	if interval <= 0 {
+		interval = DefaultRetentionInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			u.PruneHistory()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// pruneBreakGlassReviews removes the reviewed break-glass reviews exceeding
+// policy and returns how many it removed.
+func (u *UconEnforcer) pruneBreakGlassReviews(policy RetentionPolicy, now time.Time) int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	var reviewed []*BreakGlassReview
+	for _, review := range u.breakGlassReviews {
+		if !review.Pending() {
+			reviewed = append(reviewed, review)
+		}
+	}
+	sort.Slice(reviewed, func(i, j int) bool { return reviewed[i].ReviewedAt.Before(*reviewed[j].ReviewedAt) })
+	times := make([]time.Time, len(reviewed))
+	for i, review := range reviewed {
+		times[i] = *review.ReviewedAt
+	}
+	n := policy.excess(times, now)
+	for _, review := range reviewed[:n] {
+		delete(u.breakGlassReviews, review.SessionID)
+	}
+	return n
+}
+
+// pruneApprovals drops the approvals of sessions that were deleted or
+// stopped without going through the stop hook, and returns how many.
+func (u *UconEnforcer) pruneApprovals() int {
+	u.mu.RLock()
+	ids := make([]string, 0, len(u.approvals))
+	for id := range u.approvals {
+		ids = append(ids, id)
+	}
+	u.mu.RUnlock()
+
+	n := 0
+	for _, id := range ids {
+		if session, err := u.GetSession(id); err == nil && session.IfActive() {
+			continue
+		}
+		u.cancelApproval(id)
+		n++
+	}
+	return n
+}
+
+// pruneTrace removes the trace entries exceeding policy and returns how many
+// it removed.
+func (s *Session) pruneTrace(policy RetentionPolicy, now time.Time) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	trace := append(append([]TraceEntry(nil), s.trace[s.traceNext:]...), s.trace[:s.traceNext]...)
+	times := make([]time.Time, len(trace))
+	for i := range trace {
+		times[i] = trace[i].Time
+	}
+	n := policy.excess(times, now)
+	if n == 0 {
+		return 0
+	}
+	s.trace = trace[n:]
+	s.traceNext = 0
+	return n
+}
@@ -0,0 +1,177 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAuditLogPrune(t *testing.T) {
+	auditLog := NewAuditLog(nil)
+	for i := 0; i < 5; i++ {
+		if _, err := auditLog.Append(Event{Type: EventSessionStopped, SessionID: "s"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	head := auditLog.Head()
+
+	if pruned := auditLog.Prune(RetentionPolicy{MaxAge: time.Hour}, time.Now()); pruned != 0 {
+		t.Errorf("Expected recent entries to be kept, pruned %d", pruned)
+	}
+	if pruned := auditLog.Prune(RetentionPolicy{MaxEntries: 2}, time.Now()); pruned != 3 {
+		t.Fatalf("Expected 3 entries to be pruned, pruned %d", pruned)
+	}
+	entries := auditLog.Entries()
+	if len(entries) != 2 || entries[0].Seq != 4 || auditLog.Head() != head {
+		t.Fatalf("Expected the last two entries and the same head, got %+v", entries)
+	}
+	if err := auditLog.Verify(); err != nil {
+		t.Errorf("Expected the pruned log to verify, got %v", err)
+	}
+	if _, err := auditLog.Proof(3); err == nil {
+		t.Error("Expected no proof for a pruned entry")
+	}
+	proof, err := auditLog.Proof(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyAuditProof(proof, head); err != nil {
+		t.Errorf("Expected the proof of a remaining entry to verify, got %v", err)
+	}
+
+	entry, _ := auditLog.Append(Event{Type: EventSessionStopped, SessionID: "s"})
+	if entry.Seq != 6 || entry.PrevHash != head {
+		t.Errorf("Expected the chain to continue, got %+v", entry)
+	}
+	if pruned := auditLog.Prune(RetentionPolicy{MaxAge: time.Minute}, time.Now().Add(time.Hour)); pruned != 3 || len(auditLog.Entries()) != 0 {
+		t.Errorf("Expected old entries to be pruned, pruned %d", pruned)
+	}
+	if err := auditLog.Verify(); err != nil {
+		t.Errorf("Expected the empty log to verify, got %v", err)
+	}
+}
+
+func TestPruneHistory(t *testing.T) {
+	auditLog := NewAuditLog(nil)
+	uconE := NewUconEnforcer(GetUconEnforcer().(*UconEnforcer).Enforcer, WithRetention(Retention{
+		AuditLog: auditLog,
+		Audit:    RetentionPolicy{MaxEntries: 1},
+		Sessions: RetentionPolicy{MaxEntries: 1},
+		Traces:   RetentionPolicy{MaxAge: time.Minute, MaxEntries: 2},
+	})).(*UconEnforcer)
+	uconE.AddEventListener(auditLog.Listener())
+
+	var stopped []string
+	for i := 0; i < 3; i++ {
+		id, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{})
+		_ = uconE.StopMonitoring(id)
+		stopped = append(stopped, id)
+	}
+	activeID, _ := uconE.CreateSession("bob", "read", "document1", map[string]interface{}{})
+	active, _ := uconE.GetSession(activeID)
+	now := time.Now()
+	for _, at := range []time.Time{now.Add(-time.Hour), now.Add(-3 * time.Second), now.Add(-2 * time.Second), now.Add(-time.Second)} {
+		uconE.recordTrace(active, &TraceEntry{Time: at})
+	}
+
+	report := uconE.PruneHistory()
+	if report.Sessions != 2 || report.TraceEntries != 2 || report.AuditEntries != 2 {
+		t.Fatalf("Unexpected report %+v", report)
+	}
+	for i, id := range stopped {
+		if _, err := uconE.GetSession(id); (err == nil) != (i == 2) {
+			t.Errorf("Expected only the last stopped session to be kept, session %d: %v", i, err)
+		}
+	}
+	if _, err := uconE.GetSession(activeID); err != nil {
+		t.Errorf("Expected the active session to be kept, got %v", err)
+	}
+	trace, _ := uconE.GetSessionTrace(activeID)
+	if len(trace) != 2 || !trace[0].Time.Equal(now.Add(-2*time.Second)) {
+		t.Errorf("Expected the two most recent trace entries, got %+v", trace)
+	}
+	uconE.recordTrace(active, &TraceEntry{Time: now})
+	if trace, _ := uconE.GetSessionTrace(activeID); len(trace) != 3 || !trace[2].Time.Equal(now) {
+		t.Errorf("Expected tracing to continue after pruning, got %+v", trace)
+	}
+	if report := uconE.PruneHistory(); report.Sessions != 0 || report.TraceEntries != 1 {
+		t.Errorf("Unexpected second report %+v", report)
+	}
+	_ = uconE.StopMonitoring(activeID)
+}
+
+func TestPruneReviewsUsageAndApprovals(t *testing.T) {
+	uconE := NewUconEnforcer(GetUconEnforcer().(*UconEnforcer).Enforcer, WithRetention(Retention{
+		BreakGlassReviews: RetentionPolicy{MaxEntries: 1},
+		Usage:             RetentionPolicy{MaxEntries: 1},
+	})).(*UconEnforcer)
+
+	var reviewed []string
+	for i := 0; i < 2; i++ {
+		id, _ := uconE.CreateBreakGlassSession("alice", "read", "document1", "outage", nil)
+		_ = uconE.ReviewBreakGlass(id, "bob", "justified")
+		reviewed = append(reviewed, id)
+	}
+	pendingID, _ := uconE.CreateBreakGlassSession("alice", "read", "document1", "outage", nil)
+
+	for i := 0; i < 2; i++ {
+		id, _ := uconE.CreateSession("bob", "read", "document1", nil)
+		if granted, _ := uconE.EnforceWithSession(id); granted == nil {
+			t.Fatal("Expected access")
+		}
+		_ = uconE.StopMonitoring(id)
+	}
+
+	_ = uconE.AddObligation(&Obligation{ID: "approval", Name: "four_eyes_approval", Kind: "pre", Expr: "1h"})
+	waitingID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	_, _ = uconE.EnforceWithSession(waitingID)
+	_ = uconE.sessions.DeleteSession(waitingID)
+
+	report := uconE.PruneHistory()
+	if report.BreakGlassReviews != 1 || report.UsageEntries != 1 || report.Approvals != 1 {
+		t.Fatalf("Unexpected report %+v", report)
+	}
+	reviews := uconE.GetBreakGlassReviews(false)
+	if len(reviews) != 2 || reviews[0].SessionID != reviewed[1] || reviews[1].SessionID != pendingID {
+		t.Errorf("Expected the latest reviewed and the pending review to be kept, got %+v", reviews)
+	}
+	if history, _ := uconE.GetObjectUsageHistory("document1", TimeRange{}); len(history) != 1 {
+		t.Errorf("Expected one usage entry to be kept, got %+v", history)
+	}
+	if _, ok := uconE.GetApproval(waitingID); ok {
+		t.Error("Expected the approval of the deleted session to be dropped")
+	}
+}
+
+func TestStopRetention(t *testing.T) {
+	uconE := NewUconEnforcer(GetUconEnforcer().(*UconEnforcer).Enforcer, WithRetention(Retention{
+		Interval: time.Millisecond,
+		Sessions: RetentionPolicy{MaxEntries: 1},
+	})).(*UconEnforcer)
+	for i := 0; i < 2; i++ {
+		id, _ := uconE.CreateSession("alice", "read", "document1", nil)
+		_ = uconE.StopMonitoring(id)
+	}
+	waitFor(t, func() bool { return len(uconE.GetSessions()) == 1 })
+
+	uconE.StopRetention()
+	select {
+	case <-uconE.retentionDone:
+	default:
+		t.Fatal("Expected the retention loop to return")
+	}
+	uconE.StopRetention()
+}
@@ -0,0 +1,160 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// ErrEmptySelector is returned for a SessionSelector without any criterion,
+// which would select every session.
+var ErrEmptySelector = errors.New("session selector has no criteria")
+
+// SessionSelector selects the active sessions of a mass revocation. Every
+// criterion that is set must match.
+type SessionSelector struct {
+	Subject string `json:"subject,omitempty"`
+	Object  string `json:"object,omitempty"`
+	// Labels are matched against the session labels, e.g. {"customer":
+	// "acme"} for the label.customer attribute.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+func (s SessionSelector) empty() bool {
+	return s.Subject == "" && s.Object == "" && len(s.Labels) == 0
+}
+
+func (s SessionSelector) matches(session *Session) bool {
+	if s.Subject != "" && session.GetSubject() != s.Subject {
+		return false
+	}
+	if s.Object != "" && session.GetObject() != s.Object {
+		return false
+	}
+	if len(s.Labels) > 0 {
+		labels := session.GetLabels()
+		for name, val := range s.Labels {
+			if labels[name] != val {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// RevokedSessionUsage is a session that a revocation would stop, with its
+// usage so far.
+type RevokedSessionUsage struct {
+	SessionID       string    `json:"session_id"`
+	Subject         string    `json:"subject"`
+	Action          string    `json:"action"`
+	Object          string    `json:"object"`
+	Purpose         string    `json:"purpose,omitempty"`
+	StartTime       time.Time `json:"start_time"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	// Monitored is true for sessions in use, i.e. granted and monitored.
+	Monitored bool `json:"monitored"`
+	// Consumed sums the entitlements the session consumed from the Meter.
+	Consumed map[string]int64 `json:"consumed,omitempty"`
+}
+
+// RevocationImpact is what a revocation would affect, see PreviewRevocation.
+type RevocationImpact struct {
+	Sessions  int                   `json:"sessions"`
+	Monitored int                   `json:"monitored"`
+	Subjects  []string              `json:"subjects"`
+	Objects   []string              `json:"objects"`
+	Affected  []RevokedSessionUsage `json:"affected"`
+}
+
+// selectSessions returns the active sessions matched by selector.
+func (u *UconEnforcer) selectSessions(selector SessionSelector) ([]*Session, error) {
+	if selector.empty() {
+		return nil, ErrEmptySelector
+	}
+	var sessions []*Session
+	for _, session := range u.GetSessions() {
+		if session.IfActive() && selector.matches(session) {
+			sessions = append(sessions, session)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].GetId() < sessions[j].GetId() })
+	return sessions, nil
+}
+
+// PreviewRevocation reports which sessions RevokeSessions would stop for the
+// same selector, which subjects and objects they belong to and how far their
+// usage has gone, without stopping anything.
+func (u *UconEnforcer) PreviewRevocation(selector SessionSelector) (*RevocationImpact, error) {
+	sessions, err := u.selectSessions(selector)
+	if err != nil {
+		return nil, err
+	}
+	impact := &RevocationImpact{Subjects: []string{}, Objects: []string{}, Affected: []RevokedSessionUsage{}}
+	subjects := make(map[string]bool)
+	objects := make(map[string]bool)
+	now := time.Now()
+	for _, session := range sessions {
+		record := session.ToRecord()
+		impact.Affected = append(impact.Affected, RevokedSessionUsage{
+			SessionID:       record.ID,
+			Subject:         record.Subject,
+			Action:          record.Action,
+			Object:          record.Object,
+			Purpose:         record.Purpose,
+			StartTime:       record.StartTime,
+			DurationSeconds: now.Sub(record.StartTime).Seconds(),
+			Monitored:       record.Monitored,
+			Consumed:        u.consumedBy(session),
+		})
+		if record.Monitored {
+			impact.Monitored++
+		}
+		if !subjects[record.Subject] {
+			subjects[record.Subject] = true
+			impact.Subjects = append(impact.Subjects, record.Subject)
+		}
+		if !objects[record.Object] {
+			objects[record.Object] = true
+			impact.Objects = append(impact.Objects, record.Object)
+		}
+	}
+	impact.Sessions = len(impact.Affected)
+	sort.Strings(impact.Subjects)
+	sort.Strings(impact.Objects)
+	return impact, nil
+}
+
+// RevokeSessions stops every active session matched by selector with reason
+// and returns the number of sessions stopped. Use PreviewRevocation first to
+// see what it would affect.
+func (u *UconEnforcer) RevokeSessions(selector SessionSelector, reason string) (int, error) {
+	sessions, err := u.selectSessions(selector)
+	if err != nil {
+		return 0, err
+	}
+	revoked := 0
+	for _, session := range sessions {
+		if session.Stop(reason) == nil {
+			revoked++
+		}
+	}
+	if revoked > 0 {
+		u.logger.Log(LevelInfo, "sessions revoked", map[string]interface{}{"subject": selector.Subject, "object": selector.Object, "labels": selector.Labels, "count": revoked, "reason": reason})
+	}
+	return revoked, nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPreviewRevocation(t *testing.T) {
+	uconE := GetUconEnforcer()
+	aliceRead, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"label.region": "eu"})
+	aliceWrite, _ := uconE.CreateSession("alice", "write", "document1", map[string]interface{}{"label.region": "us"})
+	bobRead, _ := uconE.CreateSession("bob", "read", "document1", map[string]interface{}{"label.region": "eu"})
+	if _, err := uconE.EnforceWithSession(aliceRead); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uconE.PreviewRevocation(SessionSelector{}); !errors.Is(err, ErrEmptySelector) {
+		t.Errorf("Expected an empty selector to be refused, got %v", err)
+	}
+	if _, err := uconE.RevokeSessions(SessionSelector{}, "oops"); !errors.Is(err, ErrEmptySelector) {
+		t.Errorf("Expected an empty selector to be refused, got %v", err)
+	}
+
+	selector := SessionSelector{Object: "document1", Labels: map[string]string{"region": "eu"}}
+	impact, err := uconE.PreviewRevocation(selector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if impact.Sessions != 2 || impact.Monitored != 1 || !reflect.DeepEqual(impact.Subjects, []string{"alice", "bob"}) || !reflect.DeepEqual(impact.Objects, []string{"document1"}) {
+		t.Fatalf("Expected two eu sessions of alice and bob, got %+v", impact)
+	}
+	for _, affected := range impact.Affected {
+		if affected.SessionID == aliceWrite || affected.StartTime.IsZero() {
+			t.Errorf("Unexpected affected session %+v", affected)
+		}
+	}
+	for _, id := range []string{aliceRead, aliceWrite, bobRead} {
+		if session, _ := uconE.GetSession(id); !session.IfActive() {
+			t.Fatalf("Expected the preview to stop nothing, but %s stopped", id)
+		}
+	}
+
+	revoked, err := uconE.RevokeSessions(selector, "region offboarding")
+	if err != nil || revoked != impact.Sessions {
+		t.Fatalf("Expected the revocation to match the preview, got %d, %v", revoked, err)
+	}
+	if session, _ := uconE.GetSession(bobRead); session.IfActive() || session.GetStopReason() != "region offboarding" {
+		t.Errorf("Expected bob's session to be revoked, got %q", session.GetStopReason())
+	}
+	if session, _ := uconE.GetSession(aliceWrite); !session.IfActive() {
+		t.Error("Expected the us session to stay active")
+	}
+	if impact, _ := uconE.PreviewRevocation(SessionSelector{Subject: "alice"}); impact.Sessions != 1 || impact.Affected[0].SessionID != aliceWrite {
+		t.Errorf("Expected only alice's us session to remain, got %+v", impact)
+	}
+	_ = uconE.StopMonitoring(aliceWrite)
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestRollout(t *testing.T) {
+	uconE := GetUconEnforcer()
+	_ = uconE.AddCondition(&Condition{ID: "geofence", Name: "location", Kind: "always", Expr: "office", Shadow: true})
+
+	enforced := func() map[string]bool {
+		subjects := make(map[string]bool)
+		for i := 0; i < 200; i++ {
+			subject := fmt.Sprintf("user%d", i)
+			sessionID, _ := uconE.CreateSession(subject, "read", "document1", map[string]interface{}{"location": "home"})
+			if ok, _ := uconE.EvaluateConditions(sessionID); !ok {
+				subjects[subject] = true
+			}
+		}
+		return subjects
+	}
+
+	if n := len(enforced()); n != 0 {
+		t.Errorf("Expected a shadow condition to deny nobody, denied %d subjects", n)
+	}
+	if err := uconE.SetConditionRollout("geofence", 25); err != nil {
+		t.Fatal(err)
+	}
+	quarter := enforced()
+	if len(quarter) < 20 || len(quarter) > 80 {
+		t.Errorf("Expected about a quarter of 200 subjects to be denied, got %d", len(quarter))
+	}
+	if err := uconE.SetConditionRollout("geofence", 50); err != nil {
+		t.Fatal(err)
+	}
+	half := enforced()
+	for subject := range quarter {
+		if !half[subject] {
+			t.Errorf("Expected %s to stay enforced while ramping up", subject)
+		}
+	}
+	if len(half) <= len(quarter) {
+		t.Errorf("Expected more subjects to be enforced at 50%%, got %d after %d", len(half), len(quarter))
+	}
+	if err := uconE.SetConditionRollout("geofence", 100); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(enforced()); n != 200 {
+		t.Errorf("Expected everyone to be enforced at 100%%, got %d", n)
+	}
+	if conditions := uconE.GetConditions(); conditions[0].Shadow || conditions[0].Rollout != 0 {
+		t.Errorf("Expected a fully rolled out condition to no longer be a shadow condition, got %+v", conditions[0])
+	}
+
+	if err := uconE.SetConditionRollout("geofence", 120); err == nil {
+		t.Error("Expected an error for a rollout above 100")
+	}
+	if err := uconE.SetConditionRollout("unknown", 10); err == nil {
+		t.Error("Expected an error for an unknown condition")
+	}
+	if err := uconE.AddObligation(&Obligation{ID: "notice", Name: "notify", Kind: "pre", Rollout: -1}); err == nil {
+		t.Error("Expected an error for a negative rollout")
+	}
+}
+
+func TestObligationRollout(t *testing.T) {
+	uconE := GetUconEnforcer()
+	_ = uconE.RegisterObligationHandler("notify", func(expr string, session *Session) error {
+		return errors.New("notification service unavailable")
+	})
+	_ = uconE.AddObligation(&Obligation{ID: "notice", Name: "notify", Kind: "pre", Shadow: true})
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	if err := uconE.ExecuteObligationsByType(sessionID, "pre"); err != nil {
+		t.Errorf("Expected the shadow obligation's failure to be ignored, got %v", err)
+	}
+	_ = uconE.SetObligationRollout("notice", 100)
+	if err := uconE.ExecuteObligationsByType(sessionID, "pre"); err == nil {
+		t.Error("Expected the enforced obligation to fail")
+	}
+}
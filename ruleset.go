@@ -0,0 +1,202 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// MaxRuleSetHistory is the number of activations RollbackRuleSet can undo.
+const MaxRuleSetHistory = 16
+
+// RuleSet is a versioned set of conditions and obligations. Activating it
+// with ActivateRuleSet replaces all the enforcer's rules at once.
+type RuleSet struct {
+	Version     string       `json:"version"`
+	Conditions  []Condition  `json:"conditions"`
+	Obligations []Obligation `json:"obligations"`
+}
+
+// ruleSets holds the rule sets added to an enforcer and the rules that were
+// active before each activation.
+type ruleSets struct {
+	versions map[string]*RuleSet
+	active   string
+	history  []*RuleSet // most recent last
+}
+
+// AddRuleSet adds a rule set that ActivateRuleSet can activate. Versions are
+// immutable: adding a version twice fails.
+func (u *UconEnforcer) AddRuleSet(set *RuleSet) error {
+	if set == nil {
+		return errors.New("rule set cannot be nil")
+	}
+	if set.Version == "" {
+		return errors.New("rule set version cannot be empty")
+	}
+	if _, _, err := prepareRuleSet(set); err != nil {
+		return fmt.Errorf("rule set %s: %w", set.Version, err)
+	}
+	copied := &RuleSet{
+		Version:     set.Version,
+		Conditions:  append([]Condition(nil), set.Conditions...),
+		Obligations: append([]Obligation(nil), set.Obligations...),
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if _, exists := u.ruleSets.versions[set.Version]; exists {
+		return fmt.Errorf("rule set %s already exists", set.Version)
+	}
+	if u.ruleSets.versions == nil {
+		u.ruleSets.versions = make(map[string]*RuleSet)
+	}
+	u.ruleSets.versions[set.Version] = copied
+	return nil
+}
+
+// GetRuleSets returns the versions of the rule sets, sorted.
+func (u *UconEnforcer) GetRuleSets() []string {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	versions := make([]string, 0, len(u.ruleSets.versions))
+	for version := range u.ruleSets.versions {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// GetActiveRuleSet returns the version of the active rule set, or "" if the
+// rules were never replaced by one.
+func (u *UconEnforcer) GetActiveRuleSet() string {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.ruleSets.active
+}
+
+// ActivateRuleSet atomically replaces the conditions and obligations with
+// those of the rule set with the given version: every evaluation sees either
+// the previous rules or the new ones. Monitored sessions are re-evaluated
+// against the new conditions right away. Rules added or removed afterwards
+// change the active rules, not the rule set.
+func (u *UconEnforcer) ActivateRuleSet(version string) error {
+	u.mu.RLock()
+	set, exists := u.ruleSets.versions[version]
+	u.mu.RUnlock()
+	if !exists {
+		return fmt.Errorf("cannot find rule set %s", version)
+	}
+	conditions, obligations, err := prepareRuleSet(set)
+	if err != nil {
+		return fmt.Errorf("rule set %s: %w", version, err)
+	}
+
+	u.mu.Lock()
+	previous := u.activeRules()
+	u.ruleSets.history = append(u.ruleSets.history, previous)
+	if len(u.ruleSets.history) > MaxRuleSetHistory {
+		u.ruleSets.history = u.ruleSets.history[1:]
+	}
+	u.installRules(version, conditions, obligations)
+	u.mu.Unlock()
+
+	u.rulesChanged(previous.Version, version)
+	return nil
+}
+
+// RollbackRuleSet restores the rules that were active before the last
+// ActivateRuleSet, including any changes made to them before it, and
+// re-evaluates monitored sessions against them.
+func (u *UconEnforcer) RollbackRuleSet() error {
+	u.mu.Lock()
+	if len(u.ruleSets.history) == 0 {
+		u.mu.Unlock()
+		return errors.New("no rule set activation to roll back")
+	}
+	last := len(u.ruleSets.history) - 1
+	restored := u.ruleSets.history[last]
+	u.ruleSets.history = u.ruleSets.history[:last]
+	// The snapshot was taken from valid active rules, so preparing cannot fail.
+	conditions, obligations, _ := prepareRuleSet(restored)
+	from := u.ruleSets.active
+	u.installRules(restored.Version, conditions, obligations)
+	u.mu.Unlock()
+
+	u.rulesChanged(from, restored.Version)
+	return nil
+}
+
+// prepareRuleSet validates the rules of a set and returns them by ID.
+func prepareRuleSet(set *RuleSet) (map[string]Condition, map[string]Obligation, error) {
+	conditions := make(map[string]Condition, len(set.Conditions))
+	for _, condition := range set.Conditions {
+		if err := validateRollout(condition.Rollout); err != nil {
+			return nil, nil, fmt.Errorf("condition %s: %w", condition.ID, err)
+		}
+		conditions[condition.ID] = condition
+	}
+	obligations := make(map[string]Obligation, len(set.Obligations))
+	for i := range set.Obligations {
+		obl, err := prepareObligation(&set.Obligations[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		obligations[obl.ID] = obl
+	}
+	if _, err := orderObligations(obligations); err != nil {
+		return nil, nil, err
+	}
+	return conditions, obligations, nil
+}
+
+// activeRules returns a copy of the active rules. u.mu must be held.
+func (u *UconEnforcer) activeRules() *RuleSet {
+	set := &RuleSet{Version: u.ruleSets.active}
+	for _, condition := range u.conditions {
+		set.Conditions = append(set.Conditions, condition)
+	}
+	for _, obligation := range u.obligations {
+		set.Obligations = append(set.Obligations, obligation)
+	}
+	return set
+}
+
+// installRules replaces the active rules. u.mu must be held.
+func (u *UconEnforcer) installRules(version string, conditions map[string]Condition, obligations map[string]Obligation) {
+	u.conditions = conditions
+	u.obligations = obligations
+	u.orderRulesLocked()
+	u.ruleSets.active = version
+}
+
+// rulesChanged drops the decisions made under the previous rules and wakes
+// every monitoring loop to check its session's conditions against the new
+// ones.
+func (u *UconEnforcer) rulesChanged(from string, to string) {
+	u.InvalidateDecisions()
+	u.logger.Log(LevelInfo, "rule set activated", map[string]interface{}{"from": from, "to": to})
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	for _, wake := range u.wakeups {
+		select {
+		case wake <- struct{}{}:
+		default:
+			// A wakeup is already pending.
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRuleSets(t *testing.T) {
+	uconE := GetUconEnforcer().(*UconEnforcer)
+	_ = uconE.AddCondition(&Condition{ID: "legacy", Name: "attribute_equals", Kind: "always", Expr: "department:eng"})
+
+	office := &RuleSet{Version: "v1", Conditions: []Condition{{ID: "geofence", Name: "location", Kind: "always", Expr: "office"}}}
+	lab := &RuleSet{Version: "v2", Conditions: []Condition{{ID: "geofence", Name: "location", Kind: "always", Expr: "lab"}}}
+	for _, set := range []*RuleSet{office, lab} {
+		if err := uconE.AddRuleSet(set); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := uconE.AddRuleSet(office); err == nil {
+		t.Error("Expected an error adding an existing version")
+	}
+	cyclic := &RuleSet{Version: "v3", Obligations: []Obligation{
+		{ID: "a", Name: "notify", Kind: "pre", After: []string{"b"}},
+		{ID: "b", Name: "notify", Kind: "pre", After: []string{"a"}},
+	}}
+	if err := uconE.AddRuleSet(cyclic); err == nil {
+		t.Error("Expected an error adding a rule set with cyclic obligations")
+	}
+	if err := uconE.ActivateRuleSet("v3"); err == nil {
+		t.Error("Expected an error activating an unknown version")
+	}
+	if versions := uconE.GetRuleSets(); len(versions) != 2 || versions[0] != "v1" || versions[1] != "v2" {
+		t.Errorf("Unexpected rule sets %v", versions)
+	}
+
+	if err := uconE.ActivateRuleSet("v1"); err != nil {
+		t.Fatal(err)
+	}
+	if conditions := uconE.GetConditions(); len(conditions) != 1 || conditions[0].Expr != "office" || uconE.GetActiveRuleSet() != "v1" {
+		t.Fatalf("Expected v1 to be active, got %q with %+v", uconE.GetActiveRuleSet(), conditions)
+	}
+	// Changes to the active rules are kept by the rollback snapshot, not the rule set.
+	_ = uconE.AddCondition(&Condition{ID: "vip", Name: "vip_level", Kind: "always", Expr: "1"})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"location": "office", "vip_level": 2})
+	session, _ := uconE.GetSession(sessionID)
+	// Monitor with a long interval, so that only the activation evaluates the session.
+	uconE.mu.Lock()
+	uconE.monitoringActive[sessionID] = true
+	uconE.mu.Unlock()
+	uconE.startMonitoring(session, time.Hour)
+	waitFor(t, func() bool {
+		uconE.mu.RLock()
+		defer uconE.mu.RUnlock()
+		return uconE.wakeups[sessionID] != nil
+	})
+
+	if err := uconE.ActivateRuleSet("v2"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return !session.IfActive() })
+
+	if err := uconE.RollbackRuleSet(); err != nil {
+		t.Fatal(err)
+	}
+	if conditions := uconE.GetConditions(); len(conditions) != 2 || conditions[0].Expr != "office" || conditions[1].ID != "vip" || uconE.GetActiveRuleSet() != "v1" {
+		t.Errorf("Expected v1 and its changes to be restored, got %q with %+v", uconE.GetActiveRuleSet(), conditions)
+	}
+	other, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"location": "office", "vip_level": 2})
+	if ok, err := uconE.EvaluateConditions(other); !ok || err != nil {
+		t.Errorf("Expected the restored rules to allow access from the office, got %v, %v", ok, err)
+	}
+
+	if err := uconE.RollbackRuleSet(); err != nil {
+		t.Fatal(err)
+	}
+	if conditions := uconE.GetConditions(); len(conditions) != 1 || conditions[0].ID != "legacy" || uconE.GetActiveRuleSet() != "" {
+		t.Errorf("Expected the rules from before any rule set, got %q with %+v", uconE.GetActiveRuleSet(), conditions)
+	}
+	if err := uconE.RollbackRuleSet(); err == nil {
+		t.Error("Expected an error with nothing left to roll back")
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// ErrSchemaViolation is wrapped by errors for session attributes that do not
+// match the declared attribute schema.
+var ErrSchemaViolation = errors.New("attribute schema violation")
+
+// AttributeType is the type of a declared session attribute.
+type AttributeType string
+
+const (
+	AttributeAny    AttributeType = ""
+	AttributeString AttributeType = "string"
+	AttributeInt    AttributeType = "int"    // any integer, or a float without fraction
+	AttributeNumber AttributeType = "number" // any integer or float
+	AttributeBool   AttributeType = "bool"
+	AttributeTime   AttributeType = "time" // time.Time or an RFC 3339 string
+	AttributeList   AttributeType = "list" // any slice
+)
+
+// Sensitivity classifies declared attributes for tooling that exports or
+// displays them, e.g. to redact confidential values.
+type Sensitivity string
+
+const (
+	SensitivityPublic       Sensitivity = ""
+	SensitivityInternal     Sensitivity = "internal"
+	SensitivityConfidential Sensitivity = "confidential"
+	SensitivityRestricted   Sensitivity = "restricted"
+)
+
+// AttributeSchema declares a session attribute. Declared attributes are
+// validated by CreateSession, CreateSessionWithPurpose, UpdateSessionAttribute,
+// UpdateSessionAttributes and IncrementAttribute, and on the enforcer's own
+// writes: telemetry, MQTT, subject and SPIFFE updates, attribute providers
+// and obligations.
+type AttributeSchema struct {
+	Name string        `json:"name"`
+	Type AttributeType `json:"type,omitempty"`
+	// Allowed, if not empty, lists the only values the attribute can take.
+	Allowed []interface{} `json:"allowed,omitempty"`
+	// Required attributes must be given when the session is created.
+	Required    bool        `json:"required,omitempty"`
+	Sensitivity Sensitivity `json:"sensitivity,omitempty"`
+}
+
+// WithStrictAttributeSchema rejects session attributes that have not been
+// declared with DeclareAttribute.
+func WithStrictAttributeSchema() Option {
+	return func(u *UconEnforcer) {
+		u.strictAttributes = true
+	}
+}
+
+// DeclareAttribute adds or replaces the schema of a session attribute.
+// Existing sessions are not revalidated.
+func (u *UconEnforcer) DeclareAttribute(schema *AttributeSchema) error {
+	if schema == nil {
+		return errors.New("attribute schema cannot be nil")
+	}
+	if err := schema.validate(); err != nil {
+		return err
+	}
+	u.mu.Lock()
+	if u.attributeSchema == nil {
+		u.attributeSchema = make(map[string]AttributeSchema)
+	}
+	u.attributeSchema[schema.Name] = *schema
+	u.mu.Unlock()
+	return nil
+}
+
+// validate checks that the schema can be declared.
+func (schema *AttributeSchema) validate() error {
+	if schema.Name == "" {
+		return errors.New("attribute schema name cannot be empty")
+	}
+	switch schema.Type {
+	case AttributeAny, AttributeString, AttributeInt, AttributeNumber, AttributeBool, AttributeTime, AttributeList:
+	default:
+		return fmt.Errorf("attribute %s: unknown type %q", schema.Name, schema.Type)
+	}
+	for _, value := range schema.Allowed {
+		if err := schema.checkType(value); err != nil {
+			return fmt.Errorf("attribute %s: allowed value %v: %w", schema.Name, value, err)
+		}
+	}
+	return nil
+}
+
+// GetAttributeSchema returns the declared attributes, ordered by name.
+func (u *UconEnforcer) GetAttributeSchema() []AttributeSchema {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	schema := make([]AttributeSchema, 0, len(u.attributeSchema))
+	for _, attribute := range u.attributeSchema {
+		schema = append(schema, attribute)
+	}
+	sort.Slice(schema, func(i, j int) bool { return schema[i].Name < schema[j].Name })
+	return schema
+}
+
+// validateNewAttributes checks the attributes of a session being created,
+// including that required attributes are present.
+func (u *UconEnforcer) validateNewAttributes(attributes map[string]interface{}) error {
+	if err := u.validateAttributes(attributes); err != nil {
+		return err
+	}
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	for _, schema := range u.attributeSchema {
+		if _, ok := attributes[schema.Name]; schema.Required && !ok {
+			return fmt.Errorf("%w: attribute %s is required", ErrSchemaViolation, schema.Name)
+		}
+	}
+	return nil
+}
+
+// validateAttributes checks attribute values against their declared schema.
+func (u *UconEnforcer) validateAttributes(attributes map[string]interface{}) error {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	if len(u.attributeSchema) == 0 && !u.strictAttributes {
+		return nil
+	}
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := u.validateAttributeLocked(key, attributes[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *UconEnforcer) validateAttributeLocked(key string, value interface{}) error {
+	schema, ok := u.attributeSchema[key]
+	if !ok {
+		if u.strictAttributes {
+			return fmt.Errorf("%w: attribute %s is not declared", ErrSchemaViolation, key)
+		}
+		return nil
+	}
+	if value == nil {
+		if schema.Required {
+			return fmt.Errorf("%w: attribute %s is required", ErrSchemaViolation, key)
+		}
+		return nil
+	}
+	if err := schema.checkType(value); err != nil {
+		return fmt.Errorf("%w: attribute %s: %w", ErrSchemaViolation, key, err)
+	}
+	if len(schema.Allowed) > 0 && !schema.allows(value) {
+		return fmt.Errorf("%w: attribute %s: value %v is not allowed", ErrSchemaViolation, key, value)
+	}
+	return nil
+}
+
+// checkType reports whether value has the declared type.
+func (s *AttributeSchema) checkType(value interface{}) error {
+	ok := true
+	switch s.Type {
+	case AttributeString:
+		_, ok = value.(string)
+	case AttributeInt:
+		_, ok = toInt64(value)
+	case AttributeNumber:
+		_, ok = toFloat64(value)
+	case AttributeBool:
+		_, ok = value.(bool)
+	case AttributeTime:
+		switch v := value.(type) {
+		case time.Time:
+		case string:
+			_, err := time.Parse(time.RFC3339, v)
+			ok = err == nil
+		default:
+			ok = false
+		}
+	case AttributeList:
+		ok = value != nil && reflect.TypeOf(value).Kind() == reflect.Slice
+	}
+	if !ok {
+		return fmt.Errorf("%v (%T) is not of type %s", value, value, s.Type)
+	}
+	return nil
+}
+
+// allows reports whether value is one of the allowed values. Numbers are
+// compared by value, so 1 matches an allowed 1.0.
+func (s *AttributeSchema) allows(value interface{}) bool {
+	number, numeric := toFloat64(value)
+	for _, allowed := range s.Allowed {
+		if n, ok := toFloat64(allowed); ok && numeric && n == number {
+			return true
+		}
+		if reflect.DeepEqual(allowed, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateIncrement checks that key is declared as an integer attribute when
+// it is declared at all.
+func (u *UconEnforcer) validateIncrement(key string) error {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	schema, ok := u.attributeSchema[key]
+	if !ok {
+		if u.strictAttributes {
+			return fmt.Errorf("%w: attribute %s is not declared", ErrSchemaViolation, key)
+		}
+		return nil
+	}
+	if schema.Type != AttributeAny && schema.Type != AttributeInt && schema.Type != AttributeNumber {
+		return fmt.Errorf("%w: attribute %s is of type %s and cannot be incremented", ErrSchemaViolation, key, schema.Type)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAttributeSchema(t *testing.T) {
+	uconE := GetUconEnforcer()
+	if err := uconE.DeclareAttribute(&AttributeSchema{Name: "vip_level", Type: AttributeInt, Required: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := uconE.DeclareAttribute(&AttributeSchema{Name: "tier", Type: AttributeString, Allowed: []interface{}{"free", "pro"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := uconE.DeclareAttribute(&AttributeSchema{Name: "bad", Type: "complex"}); err == nil {
+		t.Error("Expected an unknown type to be rejected")
+	}
+	if err := uconE.DeclareAttribute(&AttributeSchema{Name: "bad", Type: AttributeInt, Allowed: []interface{}{"one"}}); err == nil {
+		t.Error("Expected an allowed value of the wrong type to be rejected")
+	}
+
+	if _, err := uconE.CreateSession("alice", "read", "document1", nil); !errors.Is(err, ErrSchemaViolation) {
+		t.Errorf("Expected a missing required attribute to be rejected, got %v", err)
+	}
+	if _, err := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"vip_level": "3"}); !errors.Is(err, ErrSchemaViolation) {
+		t.Errorf("Expected a string vip_level to be rejected, got %v", err)
+	}
+	if _, err := uconE.CreateSessionWithPurpose("alice", "read", "document1", "support", map[string]interface{}{"vip_level": 3, "tier": "gold"}); !errors.Is(err, ErrSchemaViolation) {
+		t.Errorf("Expected a disallowed tier to be rejected, got %v", err)
+	}
+	sessionID, err := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"vip_level": 3.0, "tier": "pro", "note": "free text"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := uconE.UpdateSessionAttribute(sessionID, "vip_level", true); !errors.Is(err, ErrSchemaViolation) {
+		t.Errorf("Expected a bool vip_level to be rejected, got %v", err)
+	}
+	if err := uconE.UpdateSessionAttributes(sessionID, map[string]interface{}{"tier": "free", "vip_level": nil}); !errors.Is(err, ErrSchemaViolation) {
+		t.Errorf("Expected clearing a required attribute to be rejected, got %v", err)
+	}
+	session, _ := uconE.GetSession(sessionID)
+	if session.GetAttribute("tier") != "pro" {
+		t.Error("Expected a rejected batch to leave the attributes unchanged")
+	}
+	if err := uconE.UpdateSessionAttribute(sessionID, "tier", "free"); err != nil {
+		t.Error(err)
+	}
+	if _, err := uconE.IncrementAttribute(sessionID, "tier", 1); !errors.Is(err, ErrSchemaViolation) {
+		t.Errorf("Expected incrementing a string attribute to be rejected, got %v", err)
+	}
+	if n, err := uconE.IncrementAttribute(sessionID, "vip_level", 1); err != nil || n != 4 {
+		t.Errorf("Expected vip_level 4, got %d, %v", n, err)
+	}
+
+	// The enforcer's own writes are validated as well.
+	_, _ = uconE.EnforceWithSession(sessionID)
+	defer uconE.StopMonitoring(sessionID)
+	_ = uconE.AddTelemetryMapping(TelemetryMapping{Signal: "plan", Attribute: "tier"})
+	if _, err := uconE.IngestTelemetry([]TelemetryReport{{SessionID: sessionID, Signals: map[string]interface{}{"plan": "gold"}}}); !errors.Is(err, ErrSchemaViolation) {
+		t.Errorf("Expected a disallowed tier from telemetry to be rejected, got %v", err)
+	}
+	if err := uconE.(*UconEnforcer).executeSetAttribute("vip_level:high", session); !errors.Is(err, ErrSchemaViolation) {
+		t.Errorf("Expected an obligation setting a string vip_level to be rejected, got %v", err)
+	}
+	if n, _ := toInt64(session.GetAttribute("vip_level")); session.GetAttribute("tier") != "free" || n != 4 {
+		t.Errorf("Expected rejected writes to leave the attributes unchanged, got %v", session.GetAttributes())
+	}
+
+	schema := uconE.GetAttributeSchema()
+	if len(schema) != 2 || schema[0].Name != "tier" || schema[1].Name != "vip_level" {
+		t.Errorf("Expected the declared attributes ordered by name, got %v", schema)
+	}
+}
+
+func TestStrictAttributeSchema(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithStrictAttributeSchema())
+	uconE.DeclareAttribute(&AttributeSchema{Name: "expires", Type: AttributeTime, Sensitivity: SensitivityInternal})
+
+	if _, err := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"location": "office"}); !errors.Is(err, ErrSchemaViolation) {
+		t.Errorf("Expected an undeclared attribute to be rejected, got %v", err)
+	}
+	if _, err := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"expires": "tomorrow"}); !errors.Is(err, ErrSchemaViolation) {
+		t.Errorf("Expected an invalid time to be rejected, got %v", err)
+	}
+	sessionID, err := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"expires": "2030-01-01T00:00:00Z"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uconE.IncrementAttribute(sessionID, "count", 1); !errors.Is(err, ErrSchemaViolation) {
+		t.Errorf("Expected incrementing an undeclared attribute to be rejected, got %v", err)
+	}
+}
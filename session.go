@@ -15,8 +15,12 @@
 package ucon
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -25,13 +29,46 @@ type Session struct {
 	subject string
 	action  string
 	object  string
+	purpose string
 
 	attributes map[string]interface{}
-	active     bool
+	flags      atomic.Uint32 // flagActive and flagMonitored, see setFlag
 	startTime  time.Time
 	endTime    time.Time
 	stopReason string
 
+	store     SessionStore         // optional, persists every state change
+	onStop    func(s *Session)     // optional, called after the session stopped
+	fetchedAt map[string]time.Time // last successful fetch per attribute provider
+
+	lastHeartbeat time.Time
+	notices       map[string]bool      // one-time notifications already sent
+	chargedAt     map[string]time.Time // last time charged per time budget
+	consent       string               // consent the session relies on, see consent_check
+	directives    []Directive          // content transformations required by obligations
+	denial        denialHints          // why the last condition evaluation denied the session
+	fulfillments  map[string]*ObligationFulfillment
+	trace         []TraceEntry       // ring buffer of monitoring evaluations
+	traceNext     int                // index of the oldest entry once trace is full
+	version       uint64             // bumped on every state change, keys cached decisions
+	attrVersion   uint64             // bumped on every attribute change, keys cached conditions
+	grantSource   []string           // policy rule that authorized the session
+	grant         *GrantSnapshot     // what was known at the first grant
+	attempts      map[string]int     // completed executions per obligation phase and ID
+	subjects      *subjectAttributes // attributes shared by the subject's sessions
+	priority      Priority           // priority class, see WithPriorities
+	binding       string             // hash of the bound TLS channel, set once by BindTLSChannel
+
+	suspendedUntil    time.Time          // end of the resume window while suspended
+	breakGlass        string             // justification of a break-glass session
+	review            *BreakGlassOutcome // review of a break-glass session
+	resumeHash        string             // SHA-256 of the resume token secret
+	monitorGeneration uint64             // identifies the current monitoring loop
+	storeDegraded     bool               // granted while the store was unreachable
+	fence             uint64             // fencing token while this node owns the session, see claim
+	dryRun            bool               // detached copy evaluated by dryRunCondition
+	origin            *Session           // session a dry-run copy was made from
+
 	mutex sync.RWMutex
 }
 
@@ -39,6 +76,40 @@ const (
 	NormalStopReason = ""
 )
 
+// Flags of Session.flags. They are written with the session lock held, so
+// that they change together with the fields they describe, and read
+// without it.
+const (
+	flagActive uint32 = 1 << iota
+	flagMonitored
+)
+
+// setFlag sets or clears one of the flags of the session.
+func (s *Session) setFlag(flag uint32, on bool) {
+	for {
+		old := s.flags.Load()
+		state := old &^ flag
+		if on {
+			state |= flag
+		}
+		if s.flags.CompareAndSwap(old, state) {
+			return
+		}
+	}
+}
+
+func (s *Session) hasFlag(flag uint32) bool {
+	return s.flags.Load()&flag != 0
+}
+
+var (
+	// ErrSessionNotFound is wrapped by errors for unknown session ids.
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrSessionNotActive is returned when a stopped session is enforced or
+	// updated through an operation that requires it to be active.
+	ErrSessionNotActive = errors.New("session is not active")
+)
+
 func (s *Session) GetId() string {
 	return s.id
 }
@@ -55,53 +126,313 @@ func (s *Session) GetObject() string {
 	return s.object
 }
 
+// GetPurpose returns the purpose the session was created for, or "".
+func (s *Session) GetPurpose() string {
+	return s.purpose
+}
+
+// GetAttribute returns a session attribute or, if the session has none of
+// that name, the attribute of its subject.
 func (s *Session) GetAttribute(key string) interface{} {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
-	return s.attributes[key]
+	if value, ok := s.attributes[key]; ok || s.subjects == nil {
+		return value
+	}
+	value, _ := s.subjects.get(s.subject, key)
+	return value
+}
+
+// GetAttributes returns a copy of the session attributes, including those of
+// its subject that the session does not override.
+func (s *Session) GetAttributes() map[string]interface{} {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.attributesLocked()
+}
+
+func (s *Session) attributesLocked() map[string]interface{} {
+	var attributes map[string]interface{}
+	if s.subjects != nil {
+		attributes = s.subjects.all(s.subject)
+	} else {
+		attributes = make(map[string]interface{}, len(s.attributes))
+	}
+	for k, v := range s.attributes {
+		attributes[k] = v
+	}
+	return attributes
 }
 
 func (s *Session) UpdateAttribute(key string, val interface{}) error {
+	_, err := s.updateAttributes(map[string]interface{}{key: val}, false)
+	return err
+}
+
+// UpdateAttributeIfActive is UpdateAttribute, but fails with
+// ErrSessionNotActive once the session has stopped, so that late updates
+// cannot alter the record of a revoked session.
+func (s *Session) UpdateAttributeIfActive(key string, val interface{}) error {
+	_, err := s.updateAttributes(map[string]interface{}{key: val}, true)
+	return err
+}
+
+// UpdateAttributes sets several attributes at once. The changes are applied
+// and persisted together, so monitoring and cached decisions never observe a
+// partial update; if persisting fails, none of them is applied.
+func (s *Session) UpdateAttributes(attributes map[string]interface{}) error {
+	_, err := s.updateAttributes(attributes, false)
+	return err
+}
+
+// UpdateAttributesIfActive is UpdateAttributes, but fails with
+// ErrSessionNotActive once the session has stopped.
+func (s *Session) UpdateAttributesIfActive(attributes map[string]interface{}) error {
+	_, err := s.updateAttributes(attributes, true)
+	return err
+}
+
+// updateAttributes sets attributes and reports whether any of them changed.
+func (s *Session) updateAttributes(attributes map[string]interface{}, requireActive bool) (bool, error) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
-	s.attributes[key] = val
-	return nil
+	if requireActive && !s.hasFlag(flagActive) {
+		return false, fmt.Errorf("%w: %s", ErrSessionNotActive, s.id)
+	}
+	changed := false
+	for key, val := range attributes {
+		prev, existed := s.attributes[key]
+		changed = changed || !existed || !reflect.DeepEqual(prev, val)
+	}
+	if !changed {
+		// Unchanged values, e.g. refetched by an attribute provider, are not
+		// written again, so cached conditions and decisions stay valid.
+		return false, nil
+	}
+	old := make(map[string]interface{}, len(attributes))
+	for key, val := range attributes {
+		if prev, existed := s.attributes[key]; existed {
+			old[key] = prev
+		}
+		s.attributes[key] = val
+	}
+	s.attrVersion++
+	if err := s.persistLocked(); err != nil {
+		for key := range attributes {
+			if prev, existed := old[key]; existed {
+				s.attributes[key] = prev
+			} else {
+				delete(s.attributes, key)
+			}
+		}
+		return false, err
+	}
+	return true, nil
 }
 
+// IncrementAttribute atomically adds delta to an integer attribute and returns
+// the new value. A missing attribute counts as zero. If the session store
+// implements AttributeIncrementer, the store performs the increment so that
+// it stays atomic across processes sharing the store.
+func (s *Session) IncrementAttribute(key string, delta int64) (int64, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	old, existed := s.attributes[key]
+	current := int64(0)
+	if existed {
+		var ok bool
+		if current, ok = toInt64(old); !ok {
+			return 0, fmt.Errorf("attribute %s of session %s is not an integer", key, s.id)
+		}
+	}
+
+	if incrementer, ok := s.store.(AttributeIncrementer); ok {
+		val, err := incrementer.IncrementAttribute(s.id, key, delta)
+		if err != nil {
+			return 0, fmt.Errorf("failed to increment attribute %s of session %s: %w", key, s.id, err)
+		}
+		s.attributes[key] = counterValue(old, val)
+		s.version++
+		s.attrVersion++
+		return val, nil
+	}
+
+	val := current + delta
+	s.attributes[key] = counterValue(old, val)
+	s.attrVersion++
+	if err := s.persistLocked(); err != nil {
+		if existed {
+			s.attributes[key] = old
+		} else {
+			delete(s.attributes, key)
+		}
+		return 0, err
+	}
+	return val, nil
+}
+
+// counterValue keeps plain int counters as int so that handlers asserting
+// .(int) keep working; other counters are stored as int64.
+func counterValue(old interface{}, val int64) interface{} {
+	if _, isInt := old.(int); isInt || old == nil {
+		return int(val)
+	}
+	return val
+}
+
+// Stop ends the session. The end time and stop reason are recorded before the
+// session stops being active, so once IfActive returns false, GetEndTime and
+// GetStopReason return them.
 func (s *Session) Stop(reason string) error {
 	s.mutex.Lock()
-	if !s.active {
+	if !s.hasFlag(flagActive) {
 		s.mutex.Unlock()
 		return fmt.Errorf("session already stopped")
 	}
 
-	s.active = false
 	s.endTime = time.Now()
 	s.stopReason = reason
+	s.setFlag(flagActive, false)
+	err := s.persistLocked()
+	onStop := s.onStop
 	s.mutex.Unlock()
+
+	if onStop != nil {
+		onStop(s)
+	}
+	return err
+}
+
+func (s *Session) setMonitored(monitored bool) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.setFlag(flagMonitored, monitored)
+	return s.persistLocked()
+}
+
+// persistLocked bumps the session version and saves the session to its
+// store, if any. The caller must hold the write lock so that snapshots reach
+// the store in mutation order.
+func (s *Session) persistLocked() error {
+	s.version++
+	if s.store == nil {
+		return nil
+	}
+	if err := s.store.SaveSession(s.recordLocked()); err != nil {
+		return fmt.Errorf("failed to persist session %s: %w", s.id, err)
+	}
 	return nil
 }
 
-func (s *Session) IfActive() bool {
+func (s *Session) getVersion() uint64 {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.version
+}
+
+// attributeVersion identifies the state of the attributes the session sees,
+// its own and those of its subject.
+func (s *Session) attributeVersion() attributeVersion {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	version := attributeVersion{session: s.attrVersion}
+	if s.subjects != nil {
+		version.subjects = s.subjects.getVersion()
+	}
+	return version
+}
+
+// GetGrantSource returns the policy rule that authorized the session when it
+// was last enforced, e.g. [alice document1 read], or nil if it has not been
+// granted yet.
+func (s *Session) GetGrantSource() []string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return append([]string(nil), s.grantSource...)
+}
+
+// ToRecord returns a serializable snapshot of the session.
+func (s *Session) ToRecord() *SessionRecord {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
-	return s.active
+	return s.recordLocked()
+}
+
+func (s *Session) recordLocked() *SessionRecord {
+	attributes := make(map[string]interface{}, len(s.attributes))
+	for k, v := range s.attributes {
+		attributes[k] = v
+	}
+	return &SessionRecord{
+		ID:         s.id,
+		Subject:    s.subject,
+		Action:     s.action,
+		Object:     s.object,
+		Purpose:    s.purpose,
+		Attributes: attributes,
+		Active:     s.hasFlag(flagActive),
+		Monitored:  s.hasFlag(flagMonitored),
+		StartTime:  s.startTime,
+		EndTime:    s.endTime,
+		StopReason: s.stopReason,
+
+		GrantSource:        append([]string(nil), s.grantSource...),
+		Grant:              s.grant.clone(),
+		ObligationAttempts: copyAttempts(s.attempts),
+		SuspendedUntil:     s.suspendedUntilLocked(),
+		ResumeHash:         s.resumeHash,
+		BreakGlass:         s.breakGlass,
+		Fence:              s.fence,
+		Review:             s.review.clone(),
+		ChannelBinding:     s.binding,
+	}
+}
+
+// markOnce records a one-time notice and reports whether it is new.
+func (s *Session) markOnce(notice string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.notices[notice] {
+		return false
+	}
+	if s.notices == nil {
+		s.notices = make(map[string]bool)
+	}
+	s.notices[notice] = true
+	return true
+}
+
+// IfActive reports whether the session has not stopped. It reads an atomic
+// flag without taking the session lock, so request paths checking sessions
+// do not wait for attribute writers. A false result is final unless a
+// replicated record reactivates the session, see Stop for what it implies.
+func (s *Session) IfActive() bool {
+	return s.hasFlag(flagActive)
 }
 
 func (s *Session) GetStopReason() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
 	return s.stopReason
 }
 
 func (s *Session) GetStartTime() time.Time {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
 	return s.startTime
 }
 
 func (s *Session) GetEndTime() time.Time {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
 	return s.endTime
 }
 
 func (s *Session) GetDuration() time.Duration {
-	if s.active {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if s.hasFlag(flagActive) {
 		return time.Since(s.startTime)
 	}
 	return s.endTime.Sub(s.startTime)
@@ -109,6 +440,8 @@ func (s *Session) GetDuration() time.Duration {
 
 type SessionManager struct {
 	sessions map[string]*Session
+	store    SessionStore
+	onStop   func(s *Session)
 	mutex    sync.RWMutex
 }
 
@@ -119,35 +452,130 @@ func NewSessionManager() *SessionManager {
 	}
 }
 
+// SetStopHook registers a function called whenever a session created or
+// restored from now on stops.
+func (sm *SessionManager) SetStopHook(onStop func(s *Session)) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+	sm.onStop = onStop
+}
+
+// SetStore configures a persistent store for sessions created or restored from now on.
+func (sm *SessionManager) SetStore(store SessionStore) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+	sm.store = store
+}
+
 func (sm *SessionManager) GetSessionById(id string) (*Session, error) {
 	sm.mutex.RLock()
 	defer sm.mutex.RUnlock()
 	s, exists := sm.sessions[id]
 	if !exists {
-		return nil, fmt.Errorf("cannot find session with id %s", id)
+		return nil, fmt.Errorf("%w: %s", ErrSessionNotFound, id)
 	}
 	return s, nil
 }
 
+// GetSessions returns all sessions, ordered by start time.
+func (sm *SessionManager) GetSessions() []*Session {
+	sm.mutex.RLock()
+	sessions := make([]*Session, 0, len(sm.sessions))
+	for _, s := range sm.sessions {
+		sessions = append(sessions, s)
+	}
+	sm.mutex.RUnlock()
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].startTime.Before(sessions[j].startTime)
+	})
+	return sessions
+}
+
 func (sm *SessionManager) CreateSession(sub string, act string, obj string, attributes map[string]interface{}) (string, error) {
+	return sm.CreateSessionWithPurpose(sub, act, obj, "", attributes)
+}
+
+// CreateSessionWithPurpose creates a session for accessing obj for a declared purpose.
+func (sm *SessionManager) CreateSessionWithPurpose(sub string, act string, obj string, purpose string, attributes map[string]interface{}) (string, error) {
 	sessionID := fmt.Sprintf("session_%d", time.Now().UnixNano())
 	session := &Session{
 		id:         sessionID,
 		subject:    sub,
 		action:     act,
 		object:     obj,
-		active:     true,
+		purpose:    purpose,
 		attributes: attributes,
 		startTime:  time.Now(),
 		mutex:      sync.RWMutex{},
 	}
+	session.setFlag(flagActive, true)
+	if session.attributes == nil {
+		session.attributes = make(map[string]interface{})
+	}
 
 	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+	session.onStop = sm.onStop
+	if sm.store != nil {
+		session.store = sm.store
+		if err := sm.store.SaveSession(session.recordLocked()); err != nil {
+			return "", fmt.Errorf("failed to persist session %s: %w", sessionID, err)
+		}
+	}
 	sm.sessions[sessionID] = session
-	sm.mutex.Unlock()
 	return sessionID, nil
 }
 
+// RestoreSession adds a session rebuilt from a stored record. It returns false
+// if a session with the same id is already present.
+func (sm *SessionManager) RestoreSession(record *SessionRecord) (*Session, bool) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+	if s, exists := sm.sessions[record.ID]; exists {
+		return s, false
+	}
+	session := NewSession(record, sm.store, sm.onStop)
+	sm.sessions[record.ID] = session
+	return session, true
+}
+
+// NewSession builds a session from a record, for ISessionManager
+// implementations other than SessionManager. Changes to the session are
+// saved to store, if not nil, and onStop, if not nil, is called once it stops.
+func NewSession(record *SessionRecord, store SessionStore, onStop func(s *Session)) *Session {
+	attributes := record.Attributes
+	if attributes == nil {
+		attributes = make(map[string]interface{})
+	}
+	session := &Session{
+		id:         record.ID,
+		subject:    record.Subject,
+		action:     record.Action,
+		object:     record.Object,
+		purpose:    record.Purpose,
+		attributes: attributes,
+		startTime:  record.StartTime,
+		endTime:    record.EndTime,
+		stopReason: record.StopReason,
+		store:      store,
+		onStop:     onStop,
+		mutex:      sync.RWMutex{},
+
+		grantSource: append([]string(nil), record.GrantSource...),
+		grant:       record.Grant.clone(),
+		attempts:    copyAttempts(record.ObligationAttempts),
+
+		suspendedUntil: record.suspendedUntil(),
+		resumeHash:     record.ResumeHash,
+		breakGlass:     record.BreakGlass,
+		review:         record.Review.clone(),
+		binding:        record.ChannelBinding,
+	}
+	session.setFlag(flagActive, record.Active)
+	session.setFlag(flagMonitored, record.Monitored)
+	return session
+}
+
 func (sm *SessionManager) UpdateSessionAttribute(sessionID string, key string, val interface{}) error {
 	session, err := sm.GetSessionById(sessionID)
 	if err != nil {
@@ -159,9 +587,75 @@ func (sm *SessionManager) UpdateSessionAttribute(sessionID string, key string, v
 	return nil
 }
 
+func (sm *SessionManager) UpdateSessionAttributes(sessionID string, attributes map[string]interface{}) error {
+	session, err := sm.GetSessionById(sessionID)
+	if err != nil {
+		return err
+	}
+	return session.UpdateAttributes(attributes)
+}
+
+// UpdateSessionAttributesIfActive is UpdateSessionAttributes for active sessions only.
+func (sm *SessionManager) UpdateSessionAttributesIfActive(sessionID string, attributes map[string]interface{}) error {
+	session, err := sm.GetSessionById(sessionID)
+	if err != nil {
+		return err
+	}
+	return session.UpdateAttributesIfActive(attributes)
+}
+
+func (sm *SessionManager) IncrementSessionAttribute(sessionID string, key string, delta int64) (int64, error) {
+	session, err := sm.GetSessionById(sessionID)
+	if err != nil {
+		return 0, err
+	}
+	return session.IncrementAttribute(key, delta)
+}
+
 func (sm *SessionManager) DeleteSession(sessionID string) error {
 	sm.mutex.Lock()
 	defer sm.mutex.Unlock()
+	if sm.store != nil {
+		if err := sm.store.DeleteSession(sessionID); err != nil {
+			return err
+		}
+	}
 	delete(sm.sessions, sessionID)
 	return nil
 }
+
+// toInt64 converts integral attribute values, including numbers decoded from
+// JSON by a session store, to int64.
+func toInt64(val interface{}) (int64, bool) {
+	switch v := val.(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint:
+		return int64(v), true
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		return int64(v), true
+	case float32:
+		if float32(int64(v)) == v {
+			return int64(v), true
+		}
+	case float64:
+		if float64(int64(v)) == v {
+			return int64(v), true
+		}
+	}
+	return 0, false
+}
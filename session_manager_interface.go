@@ -0,0 +1,45 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+// ISessionManager is the interface of SessionManager, the registry of the
+// sessions of an enforcer. Supply a custom implementation, e.g. sharded,
+// remote or a test double, with WithSessionManager; sessions it does not get
+// from a SessionManager are built with NewSession.
+//
+// The enforcer calls SetStopHook and, with a session store, SetStore once
+// while it is created, and expects them to apply to every session created or
+// restored afterwards.
+type ISessionManager interface {
+	SetStopHook(onStop func(s *Session))
+	SetStore(store SessionStore)
+
+	CreateSession(sub string, act string, obj string, attributes map[string]interface{}) (string, error)
+	CreateSessionWithPurpose(sub string, act string, obj string, purpose string, attributes map[string]interface{}) (string, error)
+	// RestoreSession adds a session rebuilt from a stored record. It returns
+	// the present session and false if one with the same id exists.
+	RestoreSession(record *SessionRecord) (*Session, bool)
+	// GetSessionById returns an error wrapping ErrSessionNotFound for
+	// unknown sessions.
+	GetSessionById(id string) (*Session, error)
+	// GetSessions returns all sessions, ordered by start time.
+	GetSessions() []*Session
+	DeleteSession(sessionID string) error
+
+	UpdateSessionAttribute(sessionID string, key string, val interface{}) error
+	UpdateSessionAttributes(sessionID string, attributes map[string]interface{}) error
+	UpdateSessionAttributesIfActive(sessionID string, attributes map[string]interface{}) error
+	IncrementSessionAttribute(sessionID string, key string, delta int64) (int64, error)
+}
@@ -0,0 +1,232 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SessionRecord is the serializable state of a session.
+type SessionRecord struct {
+	ID         string                 `json:"id"`
+	Subject    string                 `json:"subject"`
+	Action     string                 `json:"action"`
+	Object     string                 `json:"object"`
+	Purpose    string                 `json:"purpose,omitempty"`
+	Attributes map[string]interface{} `json:"attributes"`
+	Active     bool                   `json:"active"`
+	Monitored  bool                   `json:"monitored"`
+	StartTime  time.Time              `json:"start_time"`
+	EndTime    time.Time              `json:"end_time"`
+	StopReason string                 `json:"stop_reason"`
+
+	GrantSource []string `json:"grant_source,omitempty"`
+	// Grant is the snapshot taken when the session was first granted.
+	Grant *GrantSnapshot `json:"grant,omitempty"`
+	// ObligationAttempts counts the completed executions of each obligation,
+	// keyed by phase and ID, so idempotency keys survive restarts. Counts of
+	// ongoing obligations are only as recent as the last save of the session.
+	ObligationAttempts map[string]int `json:"obligation_attempts,omitempty"`
+	// SuspendedUntil is the end of the resume window of a suspended session,
+	// nil if it is not suspended, and ResumeHash the hash of its resume token.
+	SuspendedUntil *time.Time `json:"suspended_until,omitempty"`
+	ResumeHash     string     `json:"resume_hash,omitempty"`
+	// BreakGlass is the justification of a break-glass session.
+	BreakGlass string `json:"break_glass,omitempty"`
+	// Fence is the fencing token of the node monitoring the session, see
+	// HeartbeatStore. Stores reject writes with an older token. It is not
+	// restored: a node only writes a fence after claiming the session.
+	Fence uint64 `json:"fence,omitempty"`
+	// Review is the outcome of the review of a break-glass session, once
+	// ReviewBreakGlass recorded it.
+	Review *BreakGlassOutcome `json:"review,omitempty"`
+	// ChannelBinding is the hash of the TLS channel the session is bound to,
+	// see BindTLSChannel.
+	ChannelBinding string `json:"channel_binding,omitempty"`
+}
+
+// SessionStore persists session state so it survives process restarts.
+type SessionStore interface {
+	SaveSession(record *SessionRecord) error
+	LoadSessions() ([]*SessionRecord, error)
+	DeleteSession(id string) error
+}
+
+// AttributeIncrementer is implemented by session stores that can atomically
+// increment an integer attribute themselves, e.g. with a database-side update.
+type AttributeIncrementer interface {
+	IncrementAttribute(id string, key string, delta int64) (int64, error)
+}
+
+// FileSessionStore is a SessionStore that keeps one file per session in a
+// directory, encoded with its Codec.
+type FileSessionStore struct {
+	dir   string
+	codec Codec
+	mutex sync.Mutex
+}
+
+// NewFileSessionStore creates a FileSessionStore of JSON files rooted at dir,
+// creating the directory if needed.
+func NewFileSessionStore(dir string) (*FileSessionStore, error) {
+	return NewFileSessionStoreWithCodec(dir, JSONCodec{})
+}
+
+// NewFileSessionStoreWithCodec is NewFileSessionStore with files encoded by
+// codec and named after it, e.g. <id>.pb for ProtobufCodec.
+func NewFileSessionStoreWithCodec(dir string, codec Codec) (*FileSessionStore, error) {
+	if codec == nil {
+		return nil, errors.New("codec cannot be nil")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create session store directory: %w", err)
+	}
+	return &FileSessionStore{dir: dir, codec: codec}, nil
+}
+
+func (fs *FileSessionStore) path(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || id == "." || id == ".." {
+		return "", fmt.Errorf("invalid session id %q", id)
+	}
+	return filepath.Join(fs.dir, id+"."+fs.codec.Name()), nil
+}
+
+// SaveSession writes the record, replacing any previous state of the session.
+// Records with a Fence are rejected with ErrFenced if the session was claimed
+// since with a newer fencing token, see ClaimSession.
+func (fs *FileSessionStore) SaveSession(record *SessionRecord) error {
+	if record == nil {
+		return errors.New("session record cannot be nil")
+	}
+	path, err := fs.path(record.ID)
+	if err != nil {
+		return err
+	}
+	data, err := fs.codec.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode session %s: %w", record.ID, err)
+	}
+	if record.Fence > 0 {
+		return fs.withLock(record.ID, ".owner", func(owner string) error {
+			if err := checkFence(owner, record); err != nil {
+				return err
+			}
+			return fs.write(path, record.ID, data)
+		})
+	}
+	return fs.write(path, record.ID, data)
+}
+
+func (fs *FileSessionStore) write(path string, id string, data []byte) error {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write session %s: %w", id, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write session %s: %w", id, err)
+	}
+	return nil
+}
+
+// IncrementAttribute atomically increments an integer attribute of a stored session.
+func (fs *FileSessionStore) IncrementAttribute(id string, key string, delta int64) (int64, error) {
+	path, err := fs.path(id)
+	if err != nil {
+		return 0, err
+	}
+
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read session %s: %w", id, err)
+	}
+	record := &SessionRecord{}
+	if err := fs.codec.Unmarshal(data, record); err != nil {
+		return 0, fmt.Errorf("failed to decode session %s: %w", id, err)
+	}
+
+	val := delta
+	if old, exists := record.Attributes[key]; exists {
+		current, ok := toInt64(old)
+		if !ok {
+			return 0, fmt.Errorf("attribute %s of session %s is not an integer", key, id)
+		}
+		val += current
+	}
+	if record.Attributes == nil {
+		record.Attributes = make(map[string]interface{})
+	}
+	record.Attributes[key] = val
+
+	if data, err = fs.codec.Marshal(record); err != nil {
+		return 0, fmt.Errorf("failed to encode session %s: %w", id, err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return 0, fmt.Errorf("failed to write session %s: %w", id, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return 0, fmt.Errorf("failed to write session %s: %w", id, err)
+	}
+	return val, nil
+}
+
+// LoadSessions reads all stored sessions.
+func (fs *FileSessionStore) LoadSessions() ([]*SessionRecord, error) {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	paths, err := filepath.Glob(filepath.Join(fs.dir, "*."+fs.codec.Name()))
+	if err != nil {
+		return nil, err
+	}
+	records := make([]*SessionRecord, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		record := &SessionRecord{}
+		if err := fs.codec.Unmarshal(data, record); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// DeleteSession removes a stored session. Deleting an unknown session is not an error.
+func (fs *FileSessionStore) DeleteSession(id string) error {
+	path, err := fs.path(id)
+	if err != nil {
+		return err
+	}
+
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete session %s: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFileSessionStore(t *testing.T) {
+	store, err := NewFileSessionStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	record := &SessionRecord{ID: "session_1", Subject: "alice", Action: "read", Object: "document1", Active: true}
+	if err := store.SaveSession(record); err != nil {
+		t.Fatalf("Failed to save session: %v", err)
+	}
+	records, err := store.LoadSessions()
+	if err != nil || len(records) != 1 || records[0].Subject != "alice" {
+		t.Fatalf("Expected one stored session for alice, got %v, %v", records, err)
+	}
+
+	if err := store.DeleteSession("session_1"); err != nil {
+		t.Fatalf("Failed to delete session: %v", err)
+	}
+	if err := store.DeleteSession("session_1"); err != nil {
+		t.Errorf("Deleting a missing session should succeed: %v", err)
+	}
+	if err := store.SaveSession(&SessionRecord{ID: "../escape"}); err == nil {
+		t.Error("Expected path-like session id to be rejected")
+	}
+}
+
+func TestRecoverMonitoring(t *testing.T) {
+	store, _ := NewFileSessionStore(t.TempDir())
+
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithSessionStore(store))
+	_ = uconE.AddCondition(&Condition{ID: "location_always", Name: "location", Kind: "always", Expr: "office"})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{
+		"location":  "office",
+		"vip_level": 3,
+	})
+	if session, err := uconE.EnforceWithSession(sessionID); session == nil {
+		t.Fatalf("Failed to enforce: %v", err)
+	}
+	idleID, _ := uconE.CreateSession("bob", "read", "document1", map[string]interface{}{})
+
+	// Simulate a restart: a fresh enforcer on the same store.
+	restarted := NewUconEnforcer(e, WithSessionStore(store))
+	_ = restarted.AddCondition(&Condition{ID: "location_always", Name: "location", Kind: "always", Expr: "office"})
+
+	session, err := restarted.GetSession(sessionID)
+	if err != nil {
+		t.Fatalf("Expected session to be recovered: %v", err)
+	}
+	if !session.IfActive() {
+		t.Fatal("Expected recovered session to be active")
+	}
+	if _, err := restarted.GetSession(idleID); err != nil {
+		t.Errorf("Expected unmonitored session to be recovered too: %v", err)
+	}
+	if ok, err := restarted.(*UconEnforcer).checkVipLevel("2", session); !ok || err != nil {
+		t.Errorf("Expected numeric attributes to survive the round trip, got %v, %v", ok, err)
+	}
+
+	// The recovered session is monitored again, so a violation revokes it.
+	_ = session.UpdateAttribute("location", "home")
+	time.Sleep(500 * time.Millisecond)
+	if session.IfActive() {
+		t.Error("Expected recovered session to be monitored and stopped")
+	}
+
+	idle, _ := restarted.GetSession(idleID)
+	if !idle.IfActive() {
+		t.Error("Expected unmonitored session to stay active")
+	}
+}
+
+// unreadableStore is a store whose sessions cannot be loaded.
+type unreadableStore struct{ flakyStore }
+
+func (s *unreadableStore) LoadSessions() ([]*SessionRecord, error) {
+	return nil, errors.New("permission denied")
+}
+
+func TestRecoverFailureIsLogged(t *testing.T) {
+	logger := &countingLogger{}
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	NewUconEnforcer(e, WithSessionStore(&unreadableStore{}), WithLogger(logger))
+	if n := logger.count("failed to recover sessions from store"); n != 1 {
+		t.Errorf("Expected the failed recovery to be logged once, got %d", n)
+	}
+}
+
+func TestIncrementAttribute(t *testing.T) {
+	fileStore, _ := NewFileSessionStore(t.TempDir())
+	walStore, _ := NewWALSessionStore(filepath.Join(t.TempDir(), "sessions.wal"))
+	defer walStore.Close()
+
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	enforcers := map[string]IUconEnforcer{
+		"memory": NewUconEnforcer(e),
+		"file":   NewUconEnforcer(e, WithSessionStore(fileStore)),
+		"wal":    NewUconEnforcer(e, WithSessionStore(walStore)),
+	}
+
+	for name, uconE := range enforcers {
+		sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{
+			"prints_used": 0,
+			"label":       "draft",
+		})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := uconE.IncrementAttribute(sessionID, "prints_used", 1); err != nil {
+					t.Errorf("%s: failed to increment: %v", name, err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		session, _ := uconE.GetSession(sessionID)
+		if got := session.GetAttribute("prints_used"); got != 50 {
+			t.Errorf("%s: expected prints_used 50, got %v", name, got)
+		}
+		if val, err := uconE.IncrementAttribute(sessionID, "downloads_remaining", -1); err != nil || val != -1 {
+			t.Errorf("%s: expected missing counter to start at zero, got %v, %v", name, val, err)
+		}
+		if _, err := uconE.IncrementAttribute(sessionID, "label", 1); err == nil {
+			t.Errorf("%s: expected incrementing a string attribute to fail", name)
+		}
+	}
+
+	records, _ := fileStore.LoadSessions()
+	if len(records) != 1 || records[0].Attributes["prints_used"] != float64(50) {
+		t.Errorf("Expected stored counter 50, got %+v", records)
+	}
+}
+
+// countingStore counts saves and fails them once fail is set.
+type countingStore struct {
+	saves int
+	fail  bool
+}
+
+func (s *countingStore) SaveSession(record *SessionRecord) error {
+	if s.fail {
+		return errors.New("disk full")
+	}
+	s.saves++
+	return nil
+}
+
+func (s *countingStore) LoadSessions() ([]*SessionRecord, error) { return nil, nil }
+
+func (s *countingStore) DeleteSession(id string) error { return nil }
+
+func TestUpdateSessionAttributes(t *testing.T) {
+	store := &countingStore{}
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithSessionStore(store))
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"role": "user"})
+	saves := store.saves
+	err := uconE.UpdateSessionAttributes(sessionID, map[string]interface{}{"role": "admin", "location": "office", "vip": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.saves != saves+1 {
+		t.Errorf("Expected a single save, got %d", store.saves-saves)
+	}
+
+	store.fail = true
+	if err := uconE.UpdateSessionAttributes(sessionID, map[string]interface{}{"role": "guest", "team": "red"}); err == nil {
+		t.Fatal("Expected the failed save to be reported")
+	}
+	session, _ := uconE.GetSession(sessionID)
+	if session.GetAttribute("role") != "admin" || session.GetAttribute("team") != nil || session.GetAttribute("location") != "office" {
+		t.Errorf("Expected the failed batch to be rolled back, got %+v", session.ToRecord().Attributes)
+	}
+	if err := uconE.UpdateSessionAttributes("missing", map[string]interface{}{"role": "admin"}); err == nil {
+		t.Error("Expected an unknown session to be rejected")
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestStopVisibility checks the ordering documented on Stop: a reader that
+// sees the session inactive also sees its end time and stop reason.
+func TestStopVisibility(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		session := NewSession(&SessionRecord{ID: "s", Active: true, StartTime: time.Now()}, nil, nil)
+		var wg sync.WaitGroup
+		for r := 0; r < 4; r++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for session.IfActive() {
+					runtime.Gosched()
+				}
+				if session.GetStopReason() != "done" || session.GetEndTime().IsZero() {
+					t.Errorf("Expected the stop to be visible once inactive, got reason %q and end %v", session.GetStopReason(), session.GetEndTime())
+				}
+			}()
+		}
+		if err := session.Stop("done"); err != nil {
+			t.Fatal(err)
+		}
+		if session.IfActive() {
+			t.Fatal("Expected the session to be inactive once Stop returned")
+		}
+		wg.Wait()
+	}
+}
+
+func TestIfActiveWithoutLock(t *testing.T) {
+	session := NewSession(&SessionRecord{ID: "s", Active: true}, nil, nil)
+	// An attribute writer holding the session lock does not block IfActive.
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+	done := make(chan bool, 1)
+	go func() { done <- session.IfActive() }()
+	select {
+	case active := <-done:
+		if !active {
+			t.Error("Expected the session to be active")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected IfActive not to wait for the session lock")
+	}
+}
+
+func TestSessionFlags(t *testing.T) {
+	session := NewSession(&SessionRecord{ID: "s", Active: true, Monitored: true}, nil, nil)
+	if record := session.ToRecord(); !record.Active || !record.Monitored {
+		t.Errorf("Expected the flags of the record, got %+v", record)
+	}
+	_ = session.setMonitored(false)
+	if record := session.ToRecord(); !record.Active || record.Monitored {
+		t.Errorf("Expected only the monitored flag to be cleared, got %+v", record)
+	}
+	if err := session.Stop("done"); err != nil || session.Stop("again") == nil {
+		t.Errorf("Expected a session to stop exactly once, got %v", err)
+	}
+	session.applyRecord(&SessionRecord{ID: "s", Active: true})
+	if !session.IfActive() {
+		t.Error("Expected a replicated record to reactivate the session")
+	}
+}
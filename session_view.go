@@ -0,0 +1,65 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import "time"
+
+// SessionView is the read-only side of a session, passed to condition
+// handlers so that evaluating a condition cannot change the session.
+// Mutation is reserved to obligations and the enforcer.
+type SessionView interface {
+	GetId() string
+	GetSubject() string
+	GetAction() string
+	GetObject() string
+	GetPurpose() string
+	GetAttribute(key string) interface{}
+	// GetAttributes returns a copy of the session attributes, including
+	// those of its subject.
+	GetAttributes() map[string]interface{}
+	GetLabels() map[string]string
+	GetPriority() Priority
+	GetGrantSource() []string
+	GetGrantSnapshot() *GrantSnapshot
+	IfActive() bool
+	GetStartTime() time.Time
+	GetDuration() time.Duration
+}
+
+// sessionView wraps a session so that handlers cannot type-assert their
+// SessionView back to a *Session.
+type sessionView struct {
+	session *Session
+}
+
+// View returns a read-only view of the session.
+func (s *Session) View() SessionView {
+	return sessionView{session: s}
+}
+
+func (v sessionView) GetId() string                         { return v.session.GetId() }
+func (v sessionView) GetSubject() string                    { return v.session.GetSubject() }
+func (v sessionView) GetAction() string                     { return v.session.GetAction() }
+func (v sessionView) GetObject() string                     { return v.session.GetObject() }
+func (v sessionView) GetPurpose() string                    { return v.session.GetPurpose() }
+func (v sessionView) GetAttribute(key string) interface{}   { return v.session.GetAttribute(key) }
+func (v sessionView) GetAttributes() map[string]interface{} { return v.session.GetAttributes() }
+func (v sessionView) GetLabels() map[string]string          { return v.session.GetLabels() }
+func (v sessionView) GetPriority() Priority                 { return v.session.GetPriority() }
+func (v sessionView) GetGrantSource() []string              { return v.session.GetGrantSource() }
+func (v sessionView) GetGrantSnapshot() *GrantSnapshot      { return v.session.GetGrantSnapshot() }
+func (v sessionView) IfActive() bool                        { return v.session.IfActive() }
+func (v sessionView) GetStartTime() time.Time               { return v.session.GetStartTime() }
+func (v sessionView) GetDuration() time.Duration            { return v.session.GetDuration() }
@@ -0,0 +1,44 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import "testing"
+
+func TestSessionView(t *testing.T) {
+	uconE := GetUconEnforcer()
+	var received SessionView
+	_ = uconE.RegisterConditionHandler("inspect", func(expr string, session SessionView) (bool, error) {
+		received = session
+		attributes := session.GetAttributes()
+		attributes["role"] = "admin"
+		return session.GetAttribute("role") == expr, nil
+	})
+	_ = uconE.AddCondition(&Condition{ID: "inspect", Name: "inspect", Kind: "always", Expr: "user"})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"role": "user"})
+	session, _ := uconE.GetSession(sessionID)
+	if ok, err := uconE.EvaluateConditions(sessionID); err != nil || !ok {
+		t.Fatalf("expected the condition to pass, got %v, %v", ok, err)
+	}
+	if _, ok := received.(*Session); ok {
+		t.Error("condition handler received a mutable *Session")
+	}
+	if received.GetId() != sessionID || received.GetSubject() != "alice" {
+		t.Errorf("view does not describe the session: %s %s", received.GetId(), received.GetSubject())
+	}
+	if role := session.GetAttribute("role"); role != "user" {
+		t.Errorf("modifying GetAttributes changed the session: role = %v", role)
+	}
+}
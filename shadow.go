@@ -0,0 +1,55 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+// Counters reported to CounterMetrics for shadow conditions and obligations,
+// by condition or obligation ID. Evaluations count every time a shadow rule
+// runs; failures count the times it would have denied or revoked access.
+const (
+	MetricShadowEvaluation = "shadow_evaluation"
+	MetricShadowFailure    = "shadow_failure"
+)
+
+// shadowCondition records the would-be outcome of a shadow condition. The
+// condition never affects the decision, so it always passes.
+func (u *UconEnforcer) shadowCondition(condition *Condition, session *Session, result bool, err error) (bool, error) {
+	u.incCounter(MetricShadowEvaluation, condition.ID)
+	if result && err == nil {
+		return true, nil
+	}
+	u.incCounter(MetricShadowFailure, condition.ID)
+	fields := sessionFields(session)
+	fields["condition"] = condition.ID
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	u.logger.Log(LevelDebug, "shadow condition would deny access", fields)
+	return true, nil
+}
+
+// shadowObligation records the would-be outcome of a dry-run shadow
+// obligation. Its failures never deny or revoke access.
+func (u *UconEnforcer) shadowObligation(obligation *Obligation, session *Session, err error) error {
+	u.incCounter(MetricShadowEvaluation, obligation.ID)
+	if err == nil {
+		return nil
+	}
+	u.incCounter(MetricShadowFailure, obligation.ID)
+	fields := sessionFields(session)
+	fields["obligation"] = obligation.ID
+	fields["error"] = err.Error()
+	u.logger.Log(LevelDebug, "shadow obligation would deny access", fields)
+	return nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestShadowRules(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e)
+	_ = uconE.RegisterObligationHandler("notify", func(expr string, session *Session) error {
+		return errors.New("notification service unavailable")
+	})
+	_ = uconE.AddCondition(&Condition{ID: "geofence", Name: "location", Kind: "always", Expr: "office", Shadow: true})
+	_ = uconE.AddCondition(&Condition{ID: "pending", Name: "not_yet_deployed", Kind: "always", Shadow: true})
+	_ = uconE.AddObligation(&Obligation{ID: "notice", Name: "notify", Kind: "pre", Shadow: true})
+
+	home, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"location": "home"})
+	if session, err := uconE.EnforceWithSession(home); err != nil || session == nil {
+		t.Fatalf("Expected shadow rules not to deny access, got %v", err)
+	}
+	if err := uconE.ExecuteObligationsByType(home, "pre"); err != nil {
+		t.Errorf("Expected the shadow obligation's failure to be ignored, got %v", err)
+	}
+	office, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"location": "office"})
+	if ok, err := uconE.EvaluateConditions(office); !ok || err != nil {
+		t.Errorf("Expected the conditions to pass, got %v, %v", ok, err)
+	}
+
+	counters := uconE.GetMetrics().(*InMemoryMetrics).Snapshot().Counters
+	if counters[MetricShadowFailure]["geofence"] < 1 || counters[MetricShadowEvaluation]["geofence"] <= counters[MetricShadowFailure]["geofence"] {
+		t.Errorf("Expected the geofence to be counted as evaluated and failing at home only, got %v", counters)
+	}
+	if counters[MetricShadowFailure]["pending"] < 2 || counters[MetricShadowFailure]["notice"] < 1 {
+		t.Errorf("Expected failures of the unknown handler and the obligation to be counted, got %v", counters)
+	}
+
+	// Without Shadow the same condition is enforced.
+	_ = uconE.AddCondition(&Condition{ID: "geofence", Name: "location", Kind: "always", Expr: "office"})
+	if ok, _ := uconE.EvaluateConditions(home); ok {
+		t.Error("Expected the enforced geofence to deny access from home")
+	}
+}
+
+func TestShadowObligationDryRun(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e)
+	_ = uconE.AddObligation(&Obligation{ID: "count", Name: "increment_counter", Kind: "pre", Expr: "downloads", Shadow: true})
+	_ = uconE.AddObligation(&Obligation{ID: "notify", Name: "webhook", Kind: "pre", Expr: server.URL, Shadow: true})
+	_ = uconE.AddObligation(&Obligation{ID: "credits", Name: "consume_entitlement", Kind: "pre", Expr: "credits:5", Shadow: true})
+	uconE.GetMeter().Grant("alice", "credits", 2)
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	if err := uconE.ExecuteObligationsByType(sessionID, "pre"); err != nil {
+		t.Fatal(err)
+	}
+	session, _ := uconE.GetSession(sessionID)
+	if session.GetAttribute("downloads") != nil || atomic.LoadInt32(&calls) != 0 || uconE.GetMeter().Remaining("alice", "credits") != 2 {
+		t.Errorf("Expected shadow obligations to change nothing, got downloads %v, %d webhook calls and %d credits",
+			session.GetAttribute("downloads"), calls, uconE.GetMeter().Remaining("alice", "credits"))
+	}
+	counters := uconE.GetMetrics().(*InMemoryMetrics).Snapshot().Counters
+	if counters[MetricShadowFailure]["credits"] != 1 || counters[MetricShadowFailure]["count"] != 0 || counters[MetricShadowFailure]["notify"] != 0 {
+		t.Errorf("Expected only the exhausted entitlement to count as a would-be failure, got %v", counters)
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SimulationResult is the outcome of SimulateSession. Attributes are those
+// the session would see after enrichment and attribute providers, Rule is
+// the policy rule that would grant access and Obligations are the IDs of the
+// pre obligations that would be executed before access is granted.
+type SimulationResult struct {
+	Allowed     bool                   `json:"allowed"`
+	Reason      string                 `json:"reason,omitempty"`
+	Attributes  map[string]interface{} `json:"attributes"`
+	Conditions  []ConditionResult      `json:"conditions,omitempty"`
+	Rule        []string               `json:"rule,omitempty"`
+	Obligations []string               `json:"obligations,omitempty"`
+}
+
+// SimulateSession answers whether a session would be allowed right now,
+// without creating it. The request goes through the enrichers, validators
+// and attribute schema like CreateSession, then an ephemeral session that is
+// neither stored nor monitored is checked against maintenance windows, the
+// attribute providers, the active conditions and the policy. Conditions are
+// dry-run, emitting no events and recording no metrics. Obligations are
+// not executed, since they may have side effects; the pre obligations that
+// would run are listed instead. The returned error is for invalid requests
+// and policy evaluation failures; denials are reported in the result.
+func (u *UconEnforcer) SimulateSession(request *SessionRequest) (*SimulationResult, error) {
+	if request == nil {
+		return nil, errors.New("session request cannot be nil")
+	}
+	if request.Subject == "" || request.Action == "" || request.Object == "" {
+		return nil, errors.New("subject, action and object are required")
+	}
+	// Hooks work on a copy so the caller's request is left untouched.
+	simulated := *request
+	simulated.Attributes = make(map[string]interface{}, len(request.Attributes))
+	for key, value := range request.Attributes {
+		simulated.Attributes[key] = value
+	}
+	result := &SimulationResult{Attributes: simulated.Attributes}
+
+	u.enrichSession(&simulated)
+	if err := u.validateSession(&simulated); err != nil {
+		result.Reason = err.Error()
+		return result, nil
+	}
+	if err := u.validateNewAttributes(simulated.Attributes); err != nil {
+		result.Reason = err.Error()
+		return result, nil
+	}
+
+	session := NewSession(&SessionRecord{
+		ID:         fmt.Sprintf("simulation_%d", time.Now().UnixNano()),
+		Subject:    simulated.Subject,
+		Action:     simulated.Action,
+		Object:     simulated.Object,
+		Purpose:    simulated.Purpose,
+		Attributes: simulated.Attributes,
+		Active:     true,
+		StartTime:  time.Now(),
+	}, nil, nil)
+	session.dryRun = true
+	u.attachSession(session)
+	defer func() { result.Attributes = session.GetAttributes() }()
+
+	if window, ok := u.activeMaintenance(session.GetObject(), time.Now()); ok {
+		result.Reason = fmt.Sprintf("%v until %s (window %s)", ErrUnderMaintenance, window.End.Format(time.RFC3339), window.ID)
+		return result, nil
+	}
+	if err := u.refreshAttributes(session); err != nil {
+		result.Reason = err.Error()
+		return result, nil
+	}
+
+	for _, condition := range u.conditionList() {
+		cond := condition // Create a copy to avoid memory aliasing
+		if !conditionApplies(&cond, session) {
+			continue
+		}
+		passed, err := u.dryRunCondition(&cond, session, u.getFailurePolicy())
+		entry := ConditionResult{ID: cond.ID, Name: cond.Name, Passed: passed && err == nil}
+		if err != nil {
+			entry.Error = err.Error()
+		}
+		result.Conditions = append(result.Conditions, entry)
+		if !entry.Passed && result.Reason == "" {
+			result.Reason = fmt.Sprintf("condition %s not met", cond.ID)
+		}
+	}
+
+	allowed, rule, err := u.EnforceEx(session.GetSubject(), session.GetObject(), session.GetAction())
+	if err != nil {
+		return nil, err
+	}
+	if !allowed && result.Reason == "" {
+		result.Reason = "denied by policy"
+	}
+	result.Rule = rule
+	for _, obligation := range u.obligationList() {
+		if obligation.Kind == "pre" && obligationApplies(&obligation, session) {
+			result.Obligations = append(result.Obligations, obligation.ID)
+		}
+	}
+	result.Allowed = result.Reason == ""
+	return result, nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSimulateSession(t *testing.T) {
+	uconE := GetUconEnforcer()
+	var executed int32
+	_ = uconE.RegisterObligationHandler("notify", func(expr string, session *Session) error {
+		atomic.AddInt32(&executed, 1)
+		return nil
+	})
+	uconE.RegisterAttributeEnricher("department", AttributeEnricherFunc(func(req *SessionRequest) (map[string]interface{}, error) {
+		return map[string]interface{}{"department": "eng"}, nil
+	}))
+	uconE.AddSessionValidator(SessionValidatorFunc(func(req *SessionRequest) error {
+		if req.Subject == "mallory" {
+			return errors.New("blocked subject")
+		}
+		return nil
+	}))
+	_ = uconE.AddCondition(&Condition{ID: "geofence", Name: "location", Kind: "always", Expr: "office"})
+	_ = uconE.AddObligation(&Obligation{ID: "notice", Name: "notify", Kind: "pre"})
+	_ = uconE.AddObligation(&Obligation{ID: "audit", Name: "notify", Kind: "post"})
+	_ = uconE.AddMaintenanceWindow(&MaintenanceWindow{ID: "upgrade", Objects: []string{"document2"}, Start: time.Now().Add(-time.Minute), End: time.Now().Add(time.Hour)})
+
+	attributes := map[string]interface{}{"location": "office"}
+	result, err := uconE.SimulateSession(&SessionRequest{Subject: "alice", Action: "read", Object: "document1", Attributes: attributes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Allowed || result.Reason != "" || len(result.Conditions) != 1 || !result.Conditions[0].Passed {
+		t.Errorf("Expected alice to be allowed from the office, got %+v", result)
+	}
+	if strings.Join(result.Rule, ",") != "alice,document1,read" || len(result.Obligations) != 1 || result.Obligations[0] != "notice" {
+		t.Errorf("Expected the matched rule and the pre obligation, got %+v", result)
+	}
+	if result.Attributes["department"] != "eng" || len(attributes) != 1 {
+		t.Errorf("Expected the enriched attributes in the result only, got %v and %v", result.Attributes, attributes)
+	}
+	if atomic.LoadInt32(&executed) != 0 || len(uconE.GetSessions()) != 0 {
+		t.Error("Expected a simulation to execute no obligations and create no session")
+	}
+
+	for _, tc := range []struct {
+		request *SessionRequest
+		reason  string
+	}{
+		{&SessionRequest{Subject: "alice", Action: "read", Object: "document1", Attributes: map[string]interface{}{"location": "home"}}, "condition geofence not met"},
+		{&SessionRequest{Subject: "bob", Action: "write", Object: "document1", Attributes: map[string]interface{}{"location": "office"}}, "denied by policy"},
+		{&SessionRequest{Subject: "mallory", Action: "read", Object: "document1"}, ErrSessionRejected.Error()},
+		{&SessionRequest{Subject: "alice", Action: "read", Object: "document2"}, ErrUnderMaintenance.Error()},
+	} {
+		result, err := uconE.SimulateSession(tc.request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Allowed || !strings.Contains(result.Reason, tc.reason) {
+			t.Errorf("Expected %s to be denied with %q, got %+v", tc.request.Subject, tc.reason, result)
+		}
+	}
+
+	if _, err := uconE.SimulateSession(&SessionRequest{Subject: "alice", Action: "read"}); err == nil {
+		t.Error("Expected an error for a request without an object")
+	}
+}
+
+func TestSimulateSessionDryRun(t *testing.T) {
+	uconE := GetUconEnforcer()
+	var events []EventType
+	uconE.AddEventListener(func(event Event) {
+		events = append(events, event.Type)
+	})
+	_ = uconE.AddCondition(&Condition{ID: "single_ip", Name: "max_concurrent_sources", Kind: "always", Expr: "ip:1"})
+	_ = uconE.AddCondition(&Condition{ID: "license", Name: "expiry", Kind: "always", Expr: "license_expiry warn=168h"})
+	_ = uconE.AddCondition(&Condition{ID: "vip", Name: "vip_level", Kind: "always", Expr: "3", Shadow: true})
+
+	expiry := time.Now().Add(24 * time.Hour).Format(time.RFC3339)
+	request := &SessionRequest{Subject: "alice", Action: "read", Object: "document1",
+		Attributes: map[string]interface{}{"ip": "10.0.0.1", "license_expiry": expiry}}
+	result, err := uconE.SimulateSession(request)
+	if err != nil || !result.Allowed {
+		t.Fatalf("Expected the first source of alice to be allowed, got %+v, %v", result, err)
+	}
+	if len(events) != 0 {
+		t.Errorf("Expected a simulation to emit no events, got %v", events)
+	}
+	if counters := uconE.GetMetrics().(*InMemoryMetrics).Snapshot().Counters; len(counters) != 0 {
+		t.Errorf("Expected a simulation to count no shadow evaluations, got %v", counters)
+	}
+
+	// The source limit counts the simulated session after the active ones.
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"ip": "10.0.0.2", "license_expiry": expiry})
+	if session, err := uconE.EnforceWithSession(sessionID); session == nil || err != nil {
+		t.Fatalf("Expected the session to be granted, got %v", err)
+	}
+	if result, err := uconE.SimulateSession(request); err != nil || result.Allowed || result.Reason != "condition single_ip not met" {
+		t.Errorf("Expected a second source of alice to be denied, got %+v, %v", result, err)
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// Session attributes set by the SVID helpers.
+const (
+	AttrSPIFFETrustDomain = "spiffe_trust_domain"
+	AttrSPIFFEPath        = "spiffe_path"
+	AttrSVIDType          = "svid_type"
+	AttrSVIDExpiry        = "svid_expiry"
+	AttrSVIDAudience      = "svid_audience"
+)
+
+// SPIFFEID is a parsed SPIFFE ID, spiffe://<trust domain><path>.
+type SPIFFEID struct {
+	TrustDomain string
+	Path        string
+}
+
+// String returns the SPIFFE ID as a URI.
+func (id SPIFFEID) String() string {
+	return "spiffe://" + id.TrustDomain + id.Path
+}
+
+// ParseSPIFFEID parses and validates a SPIFFE ID.
+func ParseSPIFFEID(s string) (SPIFFEID, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return SPIFFEID{}, fmt.Errorf("invalid SPIFFE ID %q: %w", s, err)
+	}
+	switch {
+	case u.Scheme != "spiffe":
+		return SPIFFEID{}, fmt.Errorf("invalid SPIFFE ID %q: scheme must be spiffe", s)
+	case u.Host == "" || u.Host != strings.ToLower(u.Host) || u.Port() != "":
+		return SPIFFEID{}, fmt.Errorf("invalid SPIFFE ID %q: trust domain must be a lowercase name without port", s)
+	case u.User != nil || u.RawQuery != "" || u.Fragment != "" || u.Opaque != "":
+		return SPIFFEID{}, fmt.Errorf("invalid SPIFFE ID %q: user info, query and fragment are not allowed", s)
+	case strings.HasSuffix(u.Path, "/") || strings.Contains(u.Path, "//"):
+		return SPIFFEID{}, fmt.Errorf("invalid SPIFFE ID %q: empty path segment", s)
+	}
+	return SPIFFEID{TrustDomain: u.Host, Path: u.Path}, nil
+}
+
+// X509SVIDAttributes extracts the SPIFFE ID of an X.509-SVID, to be used as
+// the session subject, and the session attributes describing it. The
+// certificate must already have been verified against the trust bundle, as
+// done by the TLS handshake.
+func X509SVIDAttributes(cert *x509.Certificate) (SPIFFEID, map[string]interface{}, error) {
+	if len(cert.URIs) != 1 {
+		return SPIFFEID{}, nil, fmt.Errorf("an X.509-SVID has exactly one URI SAN, got %d", len(cert.URIs))
+	}
+	id, err := ParseSPIFFEID(cert.URIs[0].String())
+	if err != nil {
+		return SPIFFEID{}, nil, err
+	}
+	return id, map[string]interface{}{
+		AttrSPIFFETrustDomain: id.TrustDomain,
+		AttrSPIFFEPath:        id.Path,
+		AttrSVIDType:          "x509",
+		AttrSVIDExpiry:        cert.NotAfter.UTC().Format(time.RFC3339Nano),
+	}, nil
+}
+
+// JWTSVIDAttributes is X509SVIDAttributes for the claims of a JWT-SVID whose
+// signature has already been validated.
+func JWTSVIDAttributes(claims map[string]interface{}) (SPIFFEID, map[string]interface{}, error) {
+	sub, _ := claims["sub"].(string)
+	id, err := ParseSPIFFEID(sub)
+	if err != nil {
+		return SPIFFEID{}, nil, err
+	}
+	exp, ok := claims["exp"]
+	if !ok {
+		return SPIFFEID{}, nil, errors.New("JWT-SVID has no exp claim")
+	}
+	expiry, err := toTime(exp)
+	if err != nil {
+		return SPIFFEID{}, nil, fmt.Errorf("invalid exp claim: %w", err)
+	}
+	attributes := map[string]interface{}{
+		AttrSPIFFETrustDomain: id.TrustDomain,
+		AttrSPIFFEPath:        id.Path,
+		AttrSVIDType:          "jwt",
+		AttrSVIDExpiry:        expiry.UTC().Format(time.RFC3339Nano),
+	}
+	switch aud := claims["aud"].(type) {
+	case string:
+		attributes[AttrSVIDAudience] = aud
+	case []interface{}:
+		audiences := make([]string, 0, len(aud))
+		for _, a := range aud {
+			audiences = append(audiences, fmt.Sprint(a))
+		}
+		attributes[AttrSVIDAudience] = strings.Join(audiences, ",")
+	}
+	return id, attributes, nil
+}
+
+// RefreshSVID updates a session with a rotated SVID (a *x509.Certificate or
+// verified JWT-SVID claims) of the same workload, extending its svid_expiry.
+func (u *UconEnforcer) RefreshSVID(sessionID string, svid interface{}) error {
+	session, err := u.GetSession(sessionID)
+	if err != nil {
+		return err
+	}
+	var id SPIFFEID
+	var attributes map[string]interface{}
+	switch v := svid.(type) {
+	case *x509.Certificate:
+		id, attributes, err = X509SVIDAttributes(v)
+	case map[string]interface{}:
+		id, attributes, err = JWTSVIDAttributes(v)
+	default:
+		return fmt.Errorf("unsupported SVID type %T", svid)
+	}
+	if err != nil {
+		return err
+	}
+	if id.String() != session.GetSubject() {
+		return fmt.Errorf("SVID of %s does not belong to session %s of %s", id, sessionID, session.GetSubject())
+	}
+	return u.setAttributes(session, attributes, true)
+}
+
+// checkSVIDValid is the "svid_valid" condition: the session subject is a
+// SPIFFE ID and its SVID has not expired. Expr optionally restricts the
+// workload: "[trust_domain=<domain>] [path=<pattern>]", with path.Match
+// patterns, e.g. "trust_domain=prod.example.org path=/ns/payments/sa/*".
+func (u *UconEnforcer) checkSVIDValid(expr string, session *Session) (bool, error) {
+	id, err := ParseSPIFFEID(session.GetSubject())
+	if err != nil {
+		return false, nil
+	}
+	for _, field := range strings.Fields(expr) {
+		name, value, _ := strings.Cut(field, "=")
+		switch name {
+		case "trust_domain":
+			if id.TrustDomain != value {
+				return false, nil
+			}
+		case "path":
+			if ok, err := path.Match(value, id.Path); err != nil || !ok {
+				return false, err
+			}
+		default:
+			return false, fmt.Errorf("unknown option %s in %s, expected trust_domain= or path=", name, expr)
+		}
+	}
+
+	val := session.GetAttribute(AttrSVIDExpiry)
+	if val == nil {
+		return false, &MissingAttributeError{Key: AttrSVIDExpiry}
+	}
+	expiry, err := toTime(val)
+	if err != nil {
+		return false, err
+	}
+	return time.Now().Before(expiry), nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"crypto/x509"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestParseSPIFFEID(t *testing.T) {
+	id, err := ParseSPIFFEID("spiffe://prod.example.org/ns/payments/sa/api")
+	if err != nil || id.TrustDomain != "prod.example.org" || id.Path != "/ns/payments/sa/api" {
+		t.Errorf("Unexpected result %+v, %v", id, err)
+	}
+	if id.String() != "spiffe://prod.example.org/ns/payments/sa/api" {
+		t.Errorf("Unexpected string %s", id)
+	}
+	for _, invalid := range []string{
+		"https://prod.example.org/api",
+		"spiffe://Prod.example.org/api",
+		"spiffe://prod.example.org:443/api",
+		"spiffe://prod.example.org/api/",
+		"spiffe://prod.example.org/api?x=1",
+		"spiffe:///api",
+	} {
+		if _, err := ParseSPIFFEID(invalid); err == nil {
+			t.Errorf("Expected %s to be rejected", invalid)
+		}
+	}
+}
+
+func TestSVIDSession(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	_, _ = e.AddPolicy("spiffe://prod.example.org/ns/payments/sa/api", "ledger", "call")
+	uconE := NewUconEnforcer(e)
+	_ = uconE.AddCondition(&Condition{ID: "svid", Name: "svid_valid", Kind: "always", Expr: "trust_domain=prod.example.org path=/ns/payments/sa/*"})
+
+	uri, _ := url.Parse("spiffe://prod.example.org/ns/payments/sa/api")
+	svid := &x509.Certificate{URIs: []*url.URL{uri}, NotAfter: time.Now().Add(400 * time.Millisecond)}
+	id, attributes, err := X509SVIDAttributes(svid)
+	if err != nil {
+		t.Fatalf("X509SVIDAttributes: %v", err)
+	}
+	sessionID, _ := uconE.CreateSession(id.String(), "call", "ledger", attributes)
+	session, err := uconE.EnforceWithSession(sessionID)
+	if err != nil || session == nil {
+		t.Fatalf("Expected the workload to be granted access, got %v", err)
+	}
+
+	// A rotated SVID keeps the session alive past the first SVID's expiry.
+	rotated := &x509.Certificate{URIs: []*url.URL{uri}, NotAfter: time.Now().Add(800 * time.Millisecond)}
+	if err := uconE.RefreshSVID(sessionID, rotated); err != nil {
+		t.Fatalf("RefreshSVID: %v", err)
+	}
+	other, _ := url.Parse("spiffe://prod.example.org/ns/other/sa/api")
+	if err := uconE.RefreshSVID(sessionID, &x509.Certificate{URIs: []*url.URL{other}, NotAfter: time.Now().Add(time.Hour)}); err == nil {
+		t.Error("Expected an SVID of another workload to be rejected")
+	}
+	time.Sleep(500 * time.Millisecond)
+	if !session.IfActive() {
+		t.Fatalf("Expected the session to survive rotation, stopped: %q", session.GetStopReason())
+	}
+
+	time.Sleep(600 * time.Millisecond)
+	if session.IfActive() {
+		_ = uconE.StopMonitoring(sessionID)
+		t.Error("Expected the session to be revoked once the SVID expired")
+	}
+}
+
+func TestJWTSVIDAttributes(t *testing.T) {
+	id, attributes, err := JWTSVIDAttributes(map[string]interface{}{
+		"sub": "spiffe://prod.example.org/ns/payments/sa/api",
+		"aud": []interface{}{"ledger", "audit"},
+		"exp": float64(time.Now().Add(time.Hour).Unix()),
+	})
+	if err != nil || id.Path != "/ns/payments/sa/api" {
+		t.Fatalf("Unexpected result %+v, %v", id, err)
+	}
+	if attributes[AttrSVIDType] != "jwt" || attributes[AttrSVIDAudience] != "ledger,audit" {
+		t.Errorf("Unexpected attributes %v", attributes)
+	}
+	if _, _, err := JWTSVIDAttributes(map[string]interface{}{"sub": "spiffe://prod.example.org/api"}); err == nil {
+		t.Error("Expected a JWT-SVID without exp to be rejected")
+	}
+}
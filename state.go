@@ -0,0 +1,213 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// StateVersion is the version of the snapshot format written by DumpState.
+const StateVersion = 1
+
+// StateSnapshot is the runtime state of an enforcer: its rules and sessions,
+// including pending obligations and monitoring metadata.
+type StateSnapshot struct {
+	Version     int            `json:"version"`
+	CreatedAt   time.Time      `json:"created_at"`
+	Conditions  []Condition    `json:"conditions"`
+	Obligations []Obligation   `json:"obligations"`
+	Sessions    []SessionState `json:"sessions"`
+}
+
+// SessionState is a session in a StateSnapshot: its record plus the runtime
+// state that is not persisted by session stores.
+type SessionState struct {
+	*SessionRecord
+	LastHeartbeat *time.Time              `json:"last_heartbeat,omitempty"`
+	Fulfillments  []ObligationFulfillment `json:"fulfillments,omitempty"`
+	Directives    []Directive             `json:"directives,omitempty"`
+	Notices       []string                `json:"notices,omitempty"`
+	ChargedAt     map[string]time.Time    `json:"charged_at,omitempty"`
+	FetchedAt     map[string]time.Time    `json:"fetched_at,omitempty"`
+	Consent       string                  `json:"consent,omitempty"`
+	Trace         []TraceEntry            `json:"trace,omitempty"`
+}
+
+// DumpState serializes the enforcer's runtime state to a JSON snapshot that
+// LoadState restores in another process. For a blue/green deploy, load the
+// snapshot in the new enforcer before the old one stops serving.
+func (u *UconEnforcer) DumpState() ([]byte, error) {
+	snapshot := &StateSnapshot{
+		Version:     StateVersion,
+		CreatedAt:   time.Now(),
+		Conditions:  u.conditionList(),
+		Obligations: u.obligationList(),
+	}
+	for _, session := range u.GetSessions() {
+		snapshot.Sessions = append(snapshot.Sessions, session.state())
+	}
+	return json.Marshal(snapshot)
+}
+
+// LoadState restores a snapshot written by DumpState. Rules replace those
+// with the same id. Sessions already known to the enforcer, e.g. restored
+// from a shared session store, keep their record but take the runtime state
+// of the snapshot, merged with their own: pending obligations, notices and
+// monitoring evaluations they do not have yet. Sessions that were being
+// monitored are monitored again.
+func (u *UconEnforcer) LoadState(data []byte) error {
+	snapshot := &StateSnapshot{}
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return fmt.Errorf("invalid state snapshot: %w", err)
+	}
+	if snapshot.Version != StateVersion {
+		return fmt.Errorf("unsupported state snapshot version %d", snapshot.Version)
+	}
+
+	for i := range snapshot.Conditions {
+		if err := u.AddCondition(&snapshot.Conditions[i]); err != nil {
+			return err
+		}
+	}
+	for i := range snapshot.Obligations {
+		if err := u.AddObligation(&snapshot.Obligations[i]); err != nil {
+			return err
+		}
+	}
+
+	for _, state := range snapshot.Sessions {
+		if state.SessionRecord == nil {
+			continue
+		}
+		session, _ := u.restoreSession(state.SessionRecord)
+		session.restoreState(state)
+		u.restoreTrace(session, state.Trace)
+		if state.Active && state.Monitored && session.IfActive() {
+			if err := u.StartMonitoring(session.GetId()); err != nil {
+				return fmt.Errorf("failed to resume monitoring of session %s: %w", session.GetId(), err)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Session) state() SessionState {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	state := SessionState{
+		SessionRecord: s.recordLocked(),
+		Trace:         s.traceLocked(),
+		Directives:    append([]Directive(nil), s.directives...),
+		ChargedAt:     copyTimes(s.chargedAt),
+		FetchedAt:     copyTimes(s.fetchedAt),
+		Consent:       s.consent,
+	}
+	if !s.lastHeartbeat.IsZero() {
+		lastHeartbeat := s.lastHeartbeat
+		state.LastHeartbeat = &lastHeartbeat
+	}
+	for _, f := range s.fulfillments {
+		state.Fulfillments = append(state.Fulfillments, *f)
+	}
+	sort.Slice(state.Fulfillments, func(i, j int) bool {
+		return state.Fulfillments[i].IssuedAt.Before(state.Fulfillments[j].IssuedAt)
+	})
+	for notice := range s.notices {
+		state.Notices = append(state.Notices, notice)
+	}
+	sort.Strings(state.Notices)
+	return state
+}
+
+// restoreState merges the runtime state of a snapshot into the session. What
+// the session already has, such as a fulfillment of the same obligation,
+// takes precedence; timestamps keep the later of both.
+func (s *Session) restoreState(state SessionState) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if state.LastHeartbeat != nil && state.LastHeartbeat.After(s.lastHeartbeat) {
+		s.lastHeartbeat = *state.LastHeartbeat
+	}
+	if len(s.directives) == 0 {
+		s.directives = state.Directives
+	}
+	s.chargedAt = mergeTimes(s.chargedAt, state.ChargedAt)
+	s.fetchedAt = mergeTimes(s.fetchedAt, state.FetchedAt)
+	if s.consent == "" {
+		s.consent = state.Consent
+	}
+	for i := range state.Fulfillments {
+		if s.fulfillments == nil {
+			s.fulfillments = make(map[string]*ObligationFulfillment)
+		}
+		f := state.Fulfillments[i]
+		if _, exists := s.fulfillments[f.ObligationID]; !exists {
+			s.fulfillments[f.ObligationID] = &f
+		}
+	}
+	for _, notice := range state.Notices {
+		if s.notices == nil {
+			s.notices = make(map[string]bool)
+		}
+		s.notices[notice] = true
+	}
+}
+
+// restoreTrace merges the monitoring evaluations of a snapshot into the
+// trace of the session, in time order, keeping the most recent.
+func (u *UconEnforcer) restoreTrace(session *Session, trace []TraceEntry) {
+	if len(trace) == 0 {
+		return
+	}
+	session.mutex.Lock()
+	merged := append(session.traceLocked(), trace...)
+	session.trace, session.traceNext = nil, 0
+	session.mutex.Unlock()
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].Time.Before(merged[j].Time) })
+	for i := range merged {
+		if i > 0 && merged[i].Time.Equal(merged[i-1].Time) {
+			continue // already in the trace of the session
+		}
+		u.recordTrace(session, &merged[i])
+	}
+}
+
+// mergeTimes adds the times of next to times, keeping the later of both.
+func mergeTimes(times map[string]time.Time, next map[string]time.Time) map[string]time.Time {
+	merged := copyTimes(times)
+	for k, v := range next {
+		if merged == nil {
+			merged = make(map[string]time.Time, len(next))
+		}
+		if v.After(merged[k]) {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+func copyTimes(times map[string]time.Time) map[string]time.Time {
+	if times == nil {
+		return nil
+	}
+	copied := make(map[string]time.Time, len(times))
+	for k, v := range times {
+		copied[k] = v
+	}
+	return copied
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDumpAndLoadState(t *testing.T) {
+	blue := GetUconEnforcer()
+	_ = blue.AddCondition(&Condition{ID: "office", Name: "location", Kind: "always", Expr: "office"})
+	_ = blue.AddObligation(&Obligation{ID: "terms", Name: "caller_action", Kind: "pre", Expr: "accept terms", Deadline: time.Hour})
+	_ = blue.AddObligation(&Obligation{ID: "hourly", Name: "access_logging", Kind: "ongoing", Expr: "hourly", Schedule: "@hourly"})
+
+	liveID, _ := blue.CreateSessionWithPurpose("alice", "read", "document1", "support", map[string]interface{}{"location": "office"})
+	if session, _ := blue.EnforceWithSession(liveID); session == nil {
+		t.Fatal("Expected access")
+	}
+	stoppedID, _ := blue.CreateSession("bob", "read", "document1", nil)
+	stopped, _ := blue.GetSession(stoppedID)
+	_ = stopped.Stop("done")
+
+	data, err := blue.DumpState()
+	_ = blue.StopMonitoring(liveID)
+	if err != nil {
+		t.Fatalf("DumpState: %v", err)
+	}
+	var snapshot StateSnapshot
+	_ = json.Unmarshal(data, &snapshot)
+	for _, state := range snapshot.Sessions {
+		if state.ID == stoppedID && state.LastHeartbeat != nil {
+			t.Errorf("Expected the unmonitored session to omit last_heartbeat, got %v", state.LastHeartbeat)
+		}
+	}
+
+	green := GetUconEnforcer()
+	if err := green.LoadState(data); err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	defer green.StopMonitoring(liveID)
+
+	if len(green.GetConditions()) != 1 || len(green.GetObligations()) != 2 {
+		t.Errorf("Expected the rules to be restored, got %d conditions and %d obligations", len(green.GetConditions()), len(green.GetObligations()))
+	}
+	live, err := green.GetSession(liveID)
+	if err != nil {
+		t.Fatalf("Expected the live session to be restored: %v", err)
+	}
+	if !live.IfActive() || live.GetPurpose() != "support" || live.GetAttribute("location") != "office" {
+		t.Errorf("Unexpected restored session: %+v", live.ToRecord())
+	}
+	if !green.(*UconEnforcer).monitoringActive[liveID] {
+		t.Error("Expected monitoring of the live session to resume")
+	}
+	if restored, _ := green.GetSession(stoppedID); restored == nil || restored.IfActive() || restored.GetStopReason() != "done" {
+		t.Error("Expected the stopped session to be restored as stopped")
+	}
+
+	// The pending obligation survives the handover.
+	if err := green.FulfillObligation(liveID, "terms", nil); err != nil {
+		t.Errorf("Expected the pending obligation to be restored: %v", err)
+	}
+}
+
+func TestLoadStateMergesKnownSessions(t *testing.T) {
+	dir := t.TempDir()
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	newEnforcer := func() *UconEnforcer {
+		store, _ := NewFileSessionStore(dir)
+		return NewUconEnforcer(e, WithSessionStore(store)).(*UconEnforcer)
+	}
+	blue := newEnforcer()
+	_ = blue.AddObligation(&Obligation{ID: "terms", Name: "caller_action", Kind: "pre", Expr: "accept terms", Deadline: time.Hour})
+	sessionID, _ := blue.CreateSession("alice", "read", "document1", nil)
+	if session, _ := blue.EnforceWithSession(sessionID); session == nil {
+		t.Fatal("Expected access")
+	}
+	session, _ := blue.GetSession(sessionID)
+	blue.recordTrace(session, &TraceEntry{Time: time.Now(), Verdict: VerdictContinue})
+	data, err := blue.DumpState()
+	if err != nil {
+		t.Fatalf("DumpState: %v", err)
+	}
+
+	// Green shares the session store, so it knows the session already.
+	green := newEnforcer()
+	blue.releaseMonitoring(sessionID) // blue hands the session over without stopping it
+	if _, err := green.GetSession(sessionID); err != nil {
+		t.Fatalf("Expected the session to be restored from the store: %v", err)
+	}
+	if err := green.LoadState(data); err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	defer green.StopMonitoring(sessionID)
+	if trace, _ := green.GetSessionTrace(sessionID); len(trace) != 1 || trace[0].Verdict != VerdictContinue {
+		t.Errorf("Expected the trace to be merged, got %+v", trace)
+	}
+	if !green.monitoringActive[sessionID] {
+		t.Error("Expected monitoring of the session to resume")
+	}
+	if err := green.FulfillObligation(sessionID, "terms", nil); err != nil {
+		t.Errorf("Expected the pending obligation to be merged: %v", err)
+	}
+}
+
+func TestLoadStateRejectsUnknownVersion(t *testing.T) {
+	if err := GetUconEnforcer().LoadState([]byte(`{"version": 99}`)); err == nil {
+		t.Error("Expected an unknown snapshot version to be rejected")
+	}
+}
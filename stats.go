@@ -0,0 +1,113 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// SubjectUsage summarizes the sessions of one subject.
+type SubjectUsage struct {
+	Subject         string  `json:"subject"`
+	Sessions        int     `json:"sessions"`
+	ActiveSessions  int     `json:"active_sessions"`
+	DurationSeconds float64 `json:"duration_seconds"`
+}
+
+// DashboardStats is a pre-aggregated view of the sessions for dashboards.
+type DashboardStats struct {
+	GeneratedAt         time.Time      `json:"generated_at"`
+	Window              string         `json:"window"`
+	ActiveSessions      int            `json:"active_sessions"`
+	ActiveByObject      map[string]int `json:"active_by_object"`
+	RevocationsByReason map[string]int `json:"revocations_by_reason"`
+	TopSubjects         []SubjectUsage `json:"top_subjects"`
+}
+
+// GetDashboardStats aggregates the current sessions: active sessions by object,
+// revocations (stops other than NormalStopReason) within the last window by
+// reason, and the limit subjects with the most sessions (ties broken by total
+// session time). A limit <= 0 returns all subjects.
+func (u *UconEnforcer) GetDashboardStats(window time.Duration, limit int) *DashboardStats {
+	sessions := u.GetSessions()
+	records := make([]*SessionRecord, 0, len(sessions))
+	for _, session := range sessions {
+		records = append(records, session.ToRecord())
+	}
+	return aggregateSessions(records, time.Now(), window, limit)
+}
+
+func aggregateSessions(records []*SessionRecord, now time.Time, window time.Duration, limit int) *DashboardStats {
+	stats := &DashboardStats{
+		GeneratedAt:         now,
+		Window:              window.String(),
+		ActiveByObject:      make(map[string]int),
+		RevocationsByReason: make(map[string]int),
+	}
+	since := now.Add(-window)
+	usage := make(map[string]*SubjectUsage)
+
+	for _, r := range records {
+		u, ok := usage[r.Subject]
+		if !ok {
+			u = &SubjectUsage{Subject: r.Subject}
+			usage[r.Subject] = u
+		}
+		u.Sessions++
+
+		if r.Active {
+			stats.ActiveSessions++
+			stats.ActiveByObject[r.Object]++
+			u.ActiveSessions++
+			u.DurationSeconds += now.Sub(r.StartTime).Seconds()
+			continue
+		}
+		u.DurationSeconds += r.EndTime.Sub(r.StartTime).Seconds()
+		if r.StopReason != NormalStopReason && !r.EndTime.Before(since) {
+			stats.RevocationsByReason[revocationReason(r)]++
+		}
+	}
+
+	stats.TopSubjects = make([]SubjectUsage, 0, len(usage))
+	for _, u := range usage {
+		stats.TopSubjects = append(stats.TopSubjects, *u)
+	}
+	sort.Slice(stats.TopSubjects, func(i, j int) bool {
+		a, b := stats.TopSubjects[i], stats.TopSubjects[j]
+		if a.Sessions != b.Sessions {
+			return a.Sessions > b.Sessions
+		}
+		if a.DurationSeconds != b.DurationSeconds {
+			return a.DurationSeconds > b.DurationSeconds
+		}
+		return a.Subject < b.Subject
+	})
+	if limit > 0 && len(stats.TopSubjects) > limit {
+		stats.TopSubjects = stats.TopSubjects[:limit]
+	}
+	return stats
+}
+
+// revocationReason groups stop reasons that differ only in the session id or
+// error details, e.g. "Error evaluating conditions for session {session}".
+func revocationReason(r *SessionRecord) string {
+	reason := strings.ReplaceAll(strings.TrimSpace(r.StopReason), r.ID, "{session}")
+	if i := strings.Index(reason, ": "); i >= 0 {
+		reason = reason[:i]
+	}
+	return reason
+}
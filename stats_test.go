@@ -0,0 +1,85 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAggregateSessions(t *testing.T) {
+	now := time.Now()
+	records := []*SessionRecord{
+		{ID: "s1", Subject: "alice", Object: "doc1", Active: true, StartTime: now.Add(-10 * time.Minute)},
+		{ID: "s2", Subject: "alice", Object: "doc2", Active: true, StartTime: now.Add(-5 * time.Minute)},
+		{ID: "s3", Subject: "bob", Object: "doc1", Active: true, StartTime: now.Add(-time.Minute)},
+		{ID: "s4", Subject: "bob", Object: "doc1", StartTime: now.Add(-20 * time.Minute), EndTime: now.Add(-10 * time.Minute),
+			StopReason: "Conditions no longer met for session s4, revoking...\n"},
+		{ID: "s5", Subject: "carol", Object: "doc1", StartTime: now.Add(-30 * time.Minute), EndTime: now.Add(-time.Minute),
+			StopReason: "Conditions no longer met for session s5, revoking...\n"},
+		{ID: "s6", Subject: "carol", Object: "doc1", StartTime: now.Add(-3 * time.Hour), EndTime: now.Add(-2 * time.Hour),
+			StopReason: "Error evaluating conditions for session s6: unknown condition"},
+		{ID: "s7", Subject: "dave", Object: "doc1", StartTime: now.Add(-time.Minute), EndTime: now, StopReason: NormalStopReason},
+	}
+
+	stats := aggregateSessions(records, now, time.Hour, 2)
+	if stats.ActiveSessions != 3 || stats.ActiveByObject["doc1"] != 2 || stats.ActiveByObject["doc2"] != 1 {
+		t.Errorf("Unexpected active sessions: %d %v", stats.ActiveSessions, stats.ActiveByObject)
+	}
+	if len(stats.RevocationsByReason) != 1 || stats.RevocationsByReason["Conditions no longer met for session {session}, revoking..."] != 2 {
+		t.Errorf("Unexpected revocations: %v", stats.RevocationsByReason)
+	}
+	if len(stats.TopSubjects) != 2 || stats.TopSubjects[0].Subject != "carol" || stats.TopSubjects[1].Subject != "alice" {
+		t.Errorf("Unexpected top subjects: %+v", stats.TopSubjects)
+	}
+
+	stats = aggregateSessions(records, now, 4*time.Hour, 0)
+	if stats.RevocationsByReason["Error evaluating conditions for session {session}"] != 1 {
+		t.Errorf("Expected older revocation in a wider window: %v", stats.RevocationsByReason)
+	}
+	if len(stats.TopSubjects) != 4 {
+		t.Errorf("Expected all subjects without a limit, got %d", len(stats.TopSubjects))
+	}
+}
+
+func TestStatsAPI(t *testing.T) {
+	uconE := GetUconEnforcer()
+	_, _ = uconE.CreateSession("alice", "read", "document1", nil)
+	_, _ = uconE.CreateSession("bob", "read", "document1", nil)
+	handler := NewAPIHandler(uconE)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stats/active-by-object", nil))
+	byObject := map[string]int{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &byObject); err != nil || byObject["document1"] != 2 {
+		t.Errorf("Unexpected active-by-object: %s %v", rec.Body.String(), err)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stats?window=30m&limit=1", nil))
+	stats := &DashboardStats{}
+	if err := json.Unmarshal(rec.Body.Bytes(), stats); err != nil || stats.Window != "30m0s" || len(stats.TopSubjects) != 1 {
+		t.Errorf("Unexpected stats: %s %v", rec.Body.String(), err)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stats?window=soon", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid window, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// StorePolicy decides what EnforceWithSession does while the session store
+// is unreachable.
+type StorePolicy int
+
+const (
+	// StoreDeny denies access while the store is unreachable, so no usage
+	// goes unrecorded. This is the default.
+	StoreDeny StorePolicy = iota
+	// StoreServeFromMemory keeps enforcing with the in-memory sessions.
+	// Session writes are dropped while the store is unreachable and written
+	// again once it is back.
+	StoreServeFromMemory
+	// StoreAllowDegraded serves from memory like StoreServeFromMemory, but
+	// flags the sessions granted meanwhile, see Session.IsStoreDegraded.
+	StoreAllowDegraded
+)
+
+// DefaultStoreProbeInterval is how often EnforceWithSession tries to write to
+// an unreachable store to find out whether it is back.
+const DefaultStoreProbeInterval = time.Second
+
+// EventStoreHealth is emitted when the session store becomes unreachable or
+// reachable again. Data carries "available" and the "error".
+const EventStoreHealth EventType = "store_health"
+
+// ErrStoreUnavailable is wrapped by errors caused by an unreachable session store.
+var ErrStoreUnavailable = errors.New("session store unavailable")
+
+// WithStorePolicy sets what EnforceWithSession does while the session store
+// configured with WithSessionStore is unreachable.
+func WithStorePolicy(policy StorePolicy) Option {
+	return func(u *UconEnforcer) {
+		u.storePolicy = policy
+	}
+}
+
+// guardedStore is the session store as the sessions see it: it tracks
+// whether the store is reachable and applies the store policy to failed
+// writes.
+type guardedStore struct {
+	inner SessionStore
+	u     *UconEnforcer
+
+	mutex     sync.Mutex
+	down      bool
+	probedAt  time.Time
+	unwritten map[string]bool // sessions whose writes were dropped
+	pending   []storeTransition
+	draining  bool
+}
+
+// storeTransition is a change of the store's availability yet to be reported.
+type storeTransition struct {
+	event     Event
+	unwritten map[string]bool // sessions to write again once it is back
+}
+
+// guardedIncrementerStore is a guardedStore whose store increments
+// attributes itself. Increments cannot be served from memory, so their
+// errors are always returned.
+type guardedIncrementerStore struct {
+	*guardedStore
+	incrementer AttributeIncrementer
+}
+
+func (u *UconEnforcer) guardStore(store SessionStore) SessionStore {
+	g := &guardedStore{inner: store, u: u, unwritten: make(map[string]bool)}
+	u.storeGuard = g
+	if incrementer, ok := store.(AttributeIncrementer); ok {
+		return &guardedIncrementerStore{guardedStore: g, incrementer: incrementer}
+	}
+	return g
+}
+
+// SaveSession implements SessionStore.
+func (g *guardedStore) SaveSession(record *SessionRecord) error {
+	err := g.inner.SaveSession(record)
+	if errors.Is(err, ErrFenced) {
+		// The store is fine, but another node owns the session. The caller
+		// holds the session lock, so monitoring is given up asynchronously.
+		go g.u.sessionFenced(record.ID)
+		return err
+	}
+	g.recordResult(err)
+	if err == nil {
+		return nil
+	}
+	if g.u.storePolicy == StoreDeny {
+		return fmt.Errorf("%w: %w", ErrStoreUnavailable, err)
+	}
+	g.mutex.Lock()
+	g.unwritten[record.ID] = true
+	g.mutex.Unlock()
+	return nil
+}
+
+// LoadSessions implements SessionStore.
+func (g *guardedStore) LoadSessions() ([]*SessionRecord, error) {
+	return g.inner.LoadSessions()
+}
+
+// DeleteSession implements SessionStore.
+func (g *guardedStore) DeleteSession(id string) error {
+	err := g.inner.DeleteSession(id)
+	g.recordResult(err)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrStoreUnavailable, err)
+	}
+	return nil
+}
+
+// IncrementAttribute implements AttributeIncrementer.
+func (g *guardedIncrementerStore) IncrementAttribute(id string, key string, delta int64) (int64, error) {
+	val, err := g.incrementer.IncrementAttribute(id, key, delta)
+	g.recordResult(err)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrStoreUnavailable, err)
+	}
+	return val, nil
+}
+
+// recordResult updates the store health with the outcome of a store call.
+// Store calls are made with the session locked, so the health events and the
+// rewrite of dropped sessions happen in the background, in order.
+func (g *guardedStore) recordResult(err error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	if (err != nil) == g.down {
+		return
+	}
+	g.down = err != nil
+	data := map[string]interface{}{"available": !g.down}
+	message := "session store is available"
+	if err != nil {
+		data["error"] = err.Error()
+		message = fmt.Sprintf("session store is unavailable: %v", err)
+		g.probedAt = time.Now()
+	}
+	var unwritten map[string]bool
+	if !g.down {
+		unwritten = g.unwritten
+		g.unwritten = make(map[string]bool)
+	}
+	g.pending = append(g.pending, storeTransition{
+		event:     Event{Type: EventStoreHealth, Time: time.Now(), Message: message, Data: data},
+		unwritten: unwritten,
+	})
+	if !g.draining {
+		g.draining = true
+		go g.drain()
+	}
+}
+
+func (g *guardedStore) drain() {
+	for {
+		g.mutex.Lock()
+		if len(g.pending) == 0 {
+			g.draining = false
+			g.mutex.Unlock()
+			return
+		}
+		transition := g.pending[0]
+		g.pending = g.pending[1:]
+		g.mutex.Unlock()
+
+		g.u.emit(transition.event)
+		g.rewrite(transition.unwritten)
+	}
+}
+
+// rewrite saves the sessions whose writes were dropped while the store was
+// unreachable.
+func (g *guardedStore) rewrite(unwritten map[string]bool) {
+	for id := range unwritten {
+		session, err := g.u.sessions.GetSessionById(id)
+		if err != nil {
+			continue
+		}
+		if err := g.SaveSession(session.ToRecord()); err != nil {
+			g.u.logger.Log(LevelWarn, "failed to rewrite session after store outage", map[string]interface{}{"session": id, "error": err.Error()})
+		}
+	}
+}
+
+// available reports whether the store is reachable. While it is not, a
+// record of the session is written at most every DefaultStoreProbeInterval
+// to find out whether it is back.
+func (g *guardedStore) available(session *Session) bool {
+	g.mutex.Lock()
+	if !g.down {
+		g.mutex.Unlock()
+		return true
+	}
+	if time.Since(g.probedAt) < DefaultStoreProbeInterval {
+		g.mutex.Unlock()
+		return false
+	}
+	g.probedAt = time.Now()
+	g.mutex.Unlock()
+
+	err := g.inner.SaveSession(session.ToRecord())
+	g.recordResult(err)
+	return err == nil
+}
+
+// checkStore applies the store policy before a session is enforced.
+func (u *UconEnforcer) checkStore(session *Session) error {
+	if u.storeGuard == nil {
+		return nil
+	}
+	if u.storeGuard.available(session) {
+		session.setStoreDegraded(false)
+		return nil
+	}
+	switch u.storePolicy {
+	case StoreServeFromMemory:
+		return nil
+	case StoreAllowDegraded:
+		session.setStoreDegraded(true)
+		return nil
+	default:
+		return fmt.Errorf("session %s: %w", session.GetId(), ErrStoreUnavailable)
+	}
+}
+
+// IsStoreDegraded reports whether the session was last granted while the
+// session store was unreachable, under StoreAllowDegraded.
+func (s *Session) IsStoreDegraded() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.storeDegraded
+}
+
+func (s *Session) setStoreDegraded(degraded bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.storeDegraded = degraded
+}
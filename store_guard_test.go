@@ -0,0 +1,115 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyStore is a concurrency-safe store that fails while down is set.
+type flakyStore struct {
+	mu      sync.Mutex
+	down    bool
+	records map[string]*SessionRecord
+}
+
+func (s *flakyStore) setDown(down bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.down = down
+}
+
+func (s *flakyStore) saved(id string) *SessionRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.records[id]
+}
+
+func (s *flakyStore) SaveSession(record *SessionRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.down {
+		return errors.New("connection refused")
+	}
+	if s.records == nil {
+		s.records = make(map[string]*SessionRecord)
+	}
+	s.records[record.ID] = record
+	return nil
+}
+
+func (s *flakyStore) LoadSessions() ([]*SessionRecord, error) { return nil, nil }
+
+func (s *flakyStore) DeleteSession(id string) error { return nil }
+
+func TestStorePolicies(t *testing.T) {
+	tests := []struct {
+		policy   StorePolicy
+		allowed  bool
+		degraded bool
+	}{
+		{StoreDeny, false, false},
+		{StoreServeFromMemory, true, false},
+		{StoreAllowDegraded, true, true},
+	}
+	for _, tt := range tests {
+		store := &flakyStore{}
+		e := GetUconEnforcer().(*UconEnforcer).Enforcer
+		uconE := NewUconEnforcer(e, WithSessionStore(store), WithStorePolicy(tt.policy))
+		var mu sync.Mutex
+		var health []bool
+		uconE.AddEventListener(func(event Event) {
+			if event.Type == EventStoreHealth {
+				mu.Lock()
+				health = append(health, event.Data["available"].(bool))
+				mu.Unlock()
+			}
+		})
+
+		sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+		store.setDown(true)
+		_ = uconE.UpdateSessionAttribute(sessionID, "location", "office")
+		decision, err := uconE.Decide(sessionID)
+		if decision.Allowed != tt.allowed || decision.Degraded != tt.degraded {
+			t.Errorf("policy %d: expected allowed=%v degraded=%v, got %+v (%v)", tt.policy, tt.allowed, tt.degraded, decision, err)
+		}
+		if !tt.allowed && !errors.Is(err, ErrStoreUnavailable) {
+			t.Errorf("policy %d: expected ErrStoreUnavailable, got %v", tt.policy, err)
+		}
+
+		store.setDown(false)
+		other, _ := uconE.CreateSession("bob", "read", "document1", nil)
+		time.Sleep(50 * time.Millisecond)
+		if record := store.saved(sessionID); tt.allowed && (record == nil || record.Attributes["location"] != "office") {
+			t.Errorf("policy %d: expected the session written during the outage to be saved once the store is back, got %+v", tt.policy, record)
+		}
+		if store.saved(other) == nil {
+			t.Errorf("policy %d: expected new sessions to be saved", tt.policy)
+		}
+		mu.Lock()
+		if len(health) != 2 || health[0] || !health[1] {
+			t.Errorf("policy %d: expected unavailable then available events, got %v", tt.policy, health)
+		}
+		mu.Unlock()
+
+		if decision, err := uconE.Decide(sessionID); !decision.Allowed || decision.Degraded {
+			t.Errorf("policy %d: expected plain access once the store is back, got %+v (%v)", tt.policy, decision, err)
+		}
+		_ = uconE.StopMonitoring(sessionID)
+	}
+}
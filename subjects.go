@@ -0,0 +1,153 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// subjectAttributes holds the attributes maintained once per subject and
+// seen by all of the subject's sessions.
+type subjectAttributes struct {
+	attributes map[string]map[string]interface{}
+	version    uint64 // bumped on every change, keys cached conditions
+	mu         sync.RWMutex
+}
+
+func newSubjectAttributes() *subjectAttributes {
+	return &subjectAttributes{attributes: make(map[string]map[string]interface{})}
+}
+
+func (s *subjectAttributes) get(subject string, key string) (interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.attributes[subject][key]
+	return value, ok
+}
+
+func (s *subjectAttributes) getVersion() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.version
+}
+
+func (s *subjectAttributes) all(subject string) map[string]interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	attributes := make(map[string]interface{}, len(s.attributes[subject]))
+	for k, v := range s.attributes[subject] {
+		attributes[k] = v
+	}
+	return attributes
+}
+
+func (s *subjectAttributes) set(subject string, attributes map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.version++
+	current := s.attributes[subject]
+	if current == nil {
+		current = make(map[string]interface{}, len(attributes))
+		s.attributes[subject] = current
+	}
+	for k, v := range attributes {
+		if v == nil {
+			delete(current, k)
+			continue
+		}
+		current[k] = v
+	}
+	if len(current) == 0 {
+		delete(s.attributes, subject)
+	}
+}
+
+func (s *subjectAttributes) remove(subject string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.version++
+	delete(s.attributes, subject)
+}
+
+// add adds delta to a numeric attribute, keeping integers integral.
+func (s *subjectAttributes) add(subject string, key string, delta float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.version++
+	current := s.attributes[subject][key]
+	var value interface{}
+	if i, isInt := toInt64(current); (isInt || current == nil) && delta == float64(int64(delta)) {
+		value = i + int64(delta)
+	} else if f, ok := toFloat64(current); ok {
+		value = f + delta
+	} else {
+		return fmt.Errorf("subject attribute %s of %s is not a number", key, subject)
+	}
+	if s.attributes[subject] == nil {
+		s.attributes[subject] = make(map[string]interface{})
+	}
+	s.attributes[subject][key] = value
+	return nil
+}
+
+// SetSubjectAttributes sets attributes of a subject, such as vip_level or
+// risk_score, visible to all of the subject's sessions, current and future,
+// unless a session has an attribute of the same name. A nil value removes
+// the attribute. Sessions see the change at their next evaluation.
+func (u *UconEnforcer) SetSubjectAttributes(subject string, attributes map[string]interface{}) error {
+	if subject == "" {
+		return errors.New("subject cannot be empty")
+	}
+	u.subjects.set(subject, attributes)
+	u.InvalidateDecisions()
+	u.triggerSubjectConditions(subject, attributes)
+	return nil
+}
+
+// GetSubjectAttributes returns a copy of the attributes of a subject.
+func (u *UconEnforcer) GetSubjectAttributes(subject string) map[string]interface{} {
+	return u.subjects.all(subject)
+}
+
+// RemoveSubjectAttributes removes all attributes of a subject.
+func (u *UconEnforcer) RemoveSubjectAttributes(subject string) {
+	u.subjects.remove(subject)
+	u.InvalidateDecisions()
+}
+
+// attachSession gives a session created or restored by the session manager
+// access to the subject attributes, and its priority class.
+func (u *UconEnforcer) attachSession(session *Session) {
+	priority := u.priorityFor(session.GetSubject(), session.GetObject())
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+	session.subjects = u.subjects
+	session.priority = priority
+}
+
+// restoreSession is ISessionManager.RestoreSession for the enforcer's sessions.
+func (u *UconEnforcer) restoreSession(record *SessionRecord) (*Session, bool) {
+	session, restored := u.sessions.RestoreSession(record)
+	if restored {
+		u.attachSession(session)
+		u.gauges.update(session)
+		if session.IsBreakGlass() {
+			u.trackBreakGlassReview(session)
+		}
+	}
+	return session, restored
+}
@@ -0,0 +1,68 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import "testing"
+
+func TestSubjectAttributes(t *testing.T) {
+	uconE := GetUconEnforcer()
+	_ = uconE.SetSubjectAttributes("alice", map[string]interface{}{"vip_level": 3, "risk_score": 10})
+	_ = uconE.AddCondition(&Condition{ID: "vip", Name: "vip_level", Kind: "always", Expr: "2"})
+	_ = uconE.AddCondition(&Condition{ID: "low_risk", Name: "expression", Kind: "always", Expr: "risk_score < 20"})
+	_ = uconE.AddObligation(&Obligation{ID: "risk", Name: "attribute_update", Kind: "post", Expr: "subject.risk_score += 5"})
+
+	first, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	second, _ := uconE.CreateSession("alice", "write", "document1", map[string]interface{}{"vip_level": 1})
+	for _, id := range []string{first, second} {
+		session, _ := uconE.GetSession(id)
+		if session.GetAttribute("risk_score") != 10 {
+			t.Errorf("Expected session %s to see the subject attributes, got %v", id, session.GetAttributes())
+		}
+		if _, stored := session.ToRecord().Attributes["risk_score"]; stored {
+			t.Errorf("Subject attributes were copied into session %s", id)
+		}
+	}
+	if ok, err := uconE.EvaluateConditions(first); err != nil || !ok {
+		t.Errorf("Expected the subject's vip level to pass, got %v, %v", ok, err)
+	}
+	if ok, _ := uconE.EvaluateConditions(second); ok {
+		t.Error("Expected the session's own vip_level to shadow the subject's")
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := uconE.ExecuteObligationsByType(first, "post"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if score := uconE.GetSubjectAttributes("alice")["risk_score"]; score != int64(20) {
+		t.Fatalf("Expected risk_score 20, got %v (%T)", score, score)
+	}
+	session, _ := uconE.GetSession(second)
+	if session.GetAttribute("risk_score") != int64(20) {
+		t.Error("Expected every session of the subject to see the update")
+	}
+	if ok, _ := uconE.EvaluateConditions(first); ok {
+		t.Error("Expected the raised risk score to fail the condition")
+	}
+
+	_ = uconE.SetSubjectAttributes("alice", map[string]interface{}{"risk_score": nil})
+	if session.GetAttribute("risk_score") != nil {
+		t.Error("Expected a nil value to remove the subject attribute")
+	}
+	uconE.RemoveSubjectAttributes("alice")
+	if attributes := uconE.GetSubjectAttributes("alice"); len(attributes) != 0 {
+		t.Errorf("Expected no subject attributes, got %v", attributes)
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultResumeWindow is how long a suspended session can be resumed unless
+// SuspendSession is given another window.
+const DefaultResumeWindow = 5 * time.Minute
+
+// SuspensionExpiredReason is the stop reason of suspended sessions that were
+// not resumed in time.
+const SuspensionExpiredReason = "suspension expired"
+
+// Events of suspended sessions.
+const (
+	EventSessionSuspended EventType = "session_suspended"
+	EventSessionResumed   EventType = "session_resumed"
+)
+
+var (
+	// ErrSessionSuspended is returned when a suspended session is enforced
+	// or suspended again.
+	ErrSessionSuspended = errors.New("session is suspended")
+	// ErrInvalidResumeToken is returned for unknown, malformed or already
+	// used resume tokens.
+	ErrInvalidResumeToken = errors.New("invalid resume token")
+)
+
+// SuspendSession suspends an active session whose client lost connectivity,
+// e.g. during a network partition, and returns a one-time resume token.
+// Monitoring of the session pauses but the session keeps its grant, its
+// attributes and usage counters. If ResumeSession is not called with the
+// token within window (DefaultResumeWindow if 0), the session's post
+// obligations run and it stops with SuspensionExpiredReason.
+func (u *UconEnforcer) SuspendSession(sessionID string, window time.Duration) (string, error) {
+	session, err := u.GetSession(sessionID)
+	if err != nil {
+		return "", err
+	}
+	if window <= 0 {
+		window = DefaultResumeWindow
+	}
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate resume token: %w", err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(secret)
+
+	until := time.Now().Add(window)
+	if err := session.suspend(until, hashResumeSecret(encoded)); err != nil {
+		return "", err
+	}
+	u.releaseMonitoring(sessionID)
+	u.armSuspension(session, until)
+	u.emit(Event{
+		Type:      EventSessionSuspended,
+		SessionID: sessionID,
+		Message:   fmt.Sprintf("session %s suspended until %s", sessionID, until.Format(time.RFC3339)),
+		Data:      map[string]interface{}{"resume_until": until},
+	})
+	return sessionID + "." + encoded, nil
+}
+
+// ResumeSession resumes the session a token returned by SuspendSession
+// belongs to, without a new grant: monitoring restarts, and the time spent
+// suspended is neither charged to time budgets nor counted as missed
+// heartbeats.
+func (u *UconEnforcer) ResumeSession(token string) (*Session, error) {
+	i := strings.LastIndex(token, ".")
+	if i <= 0 {
+		return nil, ErrInvalidResumeToken
+	}
+	session, err := u.GetSession(token[:i])
+	if err != nil {
+		return nil, ErrInvalidResumeToken
+	}
+	if err := session.resume(hashResumeSecret(token[i+1:]), time.Now()); err != nil {
+		return nil, err
+	}
+	if err := u.StartMonitoring(session.GetId()); err != nil {
+		return nil, err
+	}
+	u.emit(Event{
+		Type:      EventSessionResumed,
+		SessionID: session.GetId(),
+		Message:   fmt.Sprintf("session %s resumed", session.GetId()),
+	})
+	return session, nil
+}
+
+// IsSuspended reports whether the session is suspended.
+func (s *Session) IsSuspended() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return !s.suspendedUntil.IsZero()
+}
+
+// suspendedUntilLocked returns the end of the resume window as stored in a
+// SessionRecord.
+func (s *Session) suspendedUntilLocked() *time.Time {
+	if s.suspendedUntil.IsZero() {
+		return nil
+	}
+	until := s.suspendedUntil
+	return &until
+}
+
+// suspendedUntil returns the end of the resume window, zero if the session is
+// not suspended.
+func (r *SessionRecord) suspendedUntil() time.Time {
+	if r.SuspendedUntil == nil {
+		return time.Time{}
+	}
+	return *r.SuspendedUntil
+}
+
+// armSuspension ends the session if it is still suspended at until.
+func (u *UconEnforcer) armSuspension(session *Session, until time.Time) {
+	time.AfterFunc(time.Until(until), func() {
+		if !session.suspensionExpired(until) {
+			return
+		}
+		if err := u.ExecuteObligationsByType(session.GetId(), "post"); err != nil {
+			u.logger.Log(LevelWarn, "post obligations failed for expired suspension", map[string]interface{}{"session": session.GetId(), "error": err.Error()})
+		}
+		_ = session.Stop(SuspensionExpiredReason)
+	})
+}
+
+func hashResumeSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *Session) suspend(until time.Time, resumeHash string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if !s.hasFlag(flagActive) {
+		return ErrSessionNotActive
+	}
+	if !s.suspendedUntil.IsZero() {
+		return ErrSessionSuspended
+	}
+	s.suspendedUntil = until
+	s.resumeHash = resumeHash
+	s.setFlag(flagMonitored, false)
+	return s.persistLocked()
+}
+
+func (s *Session) resume(resumeHash string, now time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.suspendedUntil.IsZero() || !hmac.Equal([]byte(s.resumeHash), []byte(resumeHash)) {
+		return ErrInvalidResumeToken
+	}
+	if !s.hasFlag(flagActive) || !now.Before(s.suspendedUntil) {
+		return fmt.Errorf("%w: resume window of session %s is over", ErrSessionNotActive, s.id)
+	}
+	s.suspendedUntil = time.Time{}
+	s.resumeHash = ""
+	s.lastHeartbeat = time.Time{}
+	for budget := range s.chargedAt {
+		s.chargedAt[budget] = now
+	}
+	return s.persistLocked()
+}
+
+// suspensionExpired reports whether the session is active and still in the
+// suspension that ends at until.
+func (s *Session) suspensionExpired(until time.Time) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.hasFlag(flagActive) && s.suspendedUntil.Equal(until)
+}
+
+// nextMonitorGeneration starts a new monitoring loop of the session, making
+// any loop left from before a suspension exit.
+func (s *Session) nextMonitorGeneration() uint64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.monitorGeneration++
+	return s.monitorGeneration
+}
+
+func (s *Session) currentMonitorGeneration() uint64 {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.monitorGeneration
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSuspendAndResumeSession(t *testing.T) {
+	uconE := GetUconEnforcer()
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"count": 3})
+	if _, err := uconE.EnforceWithSession(sessionID); err != nil {
+		t.Fatal(err)
+	}
+	_ = uconE.StartMonitoring(sessionID)
+
+	token, err := uconE.SuspendSession(sessionID, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uconE.SuspendSession(sessionID, time.Minute); !errors.Is(err, ErrSessionSuspended) {
+		t.Errorf("Expected ErrSessionSuspended, got %v", err)
+	}
+	if _, err := uconE.EnforceWithSession(sessionID); !errors.Is(err, ErrSessionSuspended) {
+		t.Errorf("Expected suspended session to be refused, got %v", err)
+	}
+	if _, err := uconE.ResumeSession(sessionID + ".bogus"); !errors.Is(err, ErrInvalidResumeToken) {
+		t.Errorf("Expected ErrInvalidResumeToken, got %v", err)
+	}
+
+	session, err := uconE.ResumeSession(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.GetId() != sessionID || session.IsSuspended() || !session.IfActive() {
+		t.Errorf("Expected resumed active session %s", sessionID)
+	}
+	if session.GetAttribute("count") != 3 {
+		t.Errorf("Expected attributes to survive suspension, got %v", session.GetAttribute("count"))
+	}
+	if encoded, _ := json.Marshal(session.ToRecord()); strings.Contains(string(encoded), "suspended_until") {
+		t.Errorf("Expected a resumed session to omit suspended_until, got %s", encoded)
+	}
+	if _, err := uconE.ResumeSession(token); !errors.Is(err, ErrInvalidResumeToken) {
+		t.Errorf("Expected a used token to be rejected, got %v", err)
+	}
+	if _, err := uconE.EnforceWithSession(sessionID); err != nil {
+		t.Errorf("Expected resumed session to be allowed, got %v", err)
+	}
+	_ = uconE.StopMonitoring(sessionID)
+}
+
+func TestSuspensionExpires(t *testing.T) {
+	uconE := GetUconEnforcer()
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	token, err := uconE.SuspendSession(sessionID, 100*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(300 * time.Millisecond)
+
+	session, _ := uconE.GetSession(sessionID)
+	if session.IfActive() || session.GetStopReason() != SuspensionExpiredReason {
+		t.Errorf("Expected session stopped with %q, got active=%v reason=%q", SuspensionExpiredReason, session.IfActive(), session.GetStopReason())
+	}
+	if _, err := uconE.ResumeSession(token); err == nil {
+		t.Error("Expected resume after the window to fail")
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TelemetryReport carries endpoint signals, such as screen lock state, VPN
+// status or network, for one session or for every active session of a
+// device (the session's device_id attribute).
+type TelemetryReport struct {
+	SessionID string                 `json:"session_id,omitempty"`
+	Device    string                 `json:"device,omitempty"`
+	Signals   map[string]interface{} `json:"signals"`
+}
+
+// TelemetryMapping translates a telemetry signal into a session attribute.
+// Signal is a dotted path into the report's signals, e.g. "vpn.connected".
+// Expr optionally computes the attribute from the signal, available as
+// value, e.g. "value == 'corp'"; without it the value is copied.
+type TelemetryMapping struct {
+	Signal    string `json:"signal"`
+	Attribute string `json:"attribute"`
+	Expr      string `json:"expr,omitempty"`
+}
+
+// AddTelemetryMapping adds or replaces the mapping of a signal. Signals
+// without a mapping are ignored, so endpoints cannot set arbitrary attributes.
+func (u *UconEnforcer) AddTelemetryMapping(mapping TelemetryMapping) error {
+	if err := u.validateTelemetryMapping(mapping); err != nil {
+		return err
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.telemetryMappings == nil {
+		u.telemetryMappings = make(map[string]TelemetryMapping)
+	}
+	u.telemetryMappings[mapping.Signal] = mapping
+	return nil
+}
+
+// validateTelemetryMapping checks that the mapping can be added.
+func (u *UconEnforcer) validateTelemetryMapping(mapping TelemetryMapping) error {
+	if mapping.Signal == "" || mapping.Attribute == "" {
+		return errors.New("telemetry mapping needs a signal and an attribute")
+	}
+	if mapping.Expr != "" {
+		if _, err := u.expressions.compile(mapping.Expr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetTelemetryMappings returns the telemetry mappings, ordered by signal.
+func (u *UconEnforcer) GetTelemetryMappings() []TelemetryMapping {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	mappings := make([]TelemetryMapping, 0, len(u.telemetryMappings))
+	for _, m := range u.telemetryMappings {
+		mappings = append(mappings, m)
+	}
+	sort.Slice(mappings, func(i, j int) bool { return mappings[i].Signal < mappings[j].Signal })
+	return mappings
+}
+
+// IngestTelemetry applies a batch of telemetry reports to the sessions they
+// target through the telemetry mappings, and returns the number of sessions
+// updated. Reports for unknown sessions or devices without active sessions
+// are skipped; the first error is returned after the whole batch is applied.
+// Updated attributes are picked up by the next evaluation of the sessions.
+func (u *UconEnforcer) IngestTelemetry(reports []TelemetryReport) (int, error) {
+	mappings := u.GetTelemetryMappings()
+	updated := 0
+	var firstErr error
+	for _, report := range reports {
+		sessions := u.telemetrySessions(report)
+		if len(sessions) == 0 {
+			continue
+		}
+		attributes, err := u.mapTelemetry(report.Signals, mappings)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if len(attributes) == 0 {
+			continue
+		}
+		for _, session := range sessions {
+			if err := u.setAttributes(session, attributes, true); err != nil {
+				if errors.Is(err, ErrSessionNotActive) {
+					continue
+				}
+				if firstErr == nil {
+					firstErr = fmt.Errorf("telemetry for session %s: %w", session.GetId(), err)
+				}
+				continue
+			}
+			updated++
+		}
+	}
+	return updated, firstErr
+}
+
+func (u *UconEnforcer) telemetrySessions(report TelemetryReport) []*Session {
+	if report.SessionID != "" {
+		session, err := u.GetSession(report.SessionID)
+		if err != nil || !session.IfActive() {
+			return nil
+		}
+		return []*Session{session}
+	}
+	if report.Device == "" {
+		return nil
+	}
+	var sessions []*Session
+	for _, session := range u.GetSessions() {
+		if session.IfActive() && fmt.Sprint(session.GetAttribute(DefaultDeviceAttribute)) == report.Device {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions
+}
+
+func (u *UconEnforcer) mapTelemetry(signals map[string]interface{}, mappings []TelemetryMapping) (map[string]interface{}, error) {
+	attributes := make(map[string]interface{})
+	for _, m := range mappings {
+		value, ok := lookupSignal(signals, m.Signal)
+		if !ok {
+			continue
+		}
+		if m.Expr != "" {
+			var err error
+			if value, err = u.expressions.evaluateParams(m.Expr, map[string]interface{}{"value": value}); err != nil {
+				return nil, fmt.Errorf("signal %s: %w", m.Signal, err)
+			}
+		}
+		attributes[m.Attribute] = value
+	}
+	return attributes, nil
+}
+
+// lookupSignal resolves a dotted path in nested signal objects.
+func lookupSignal(signals map[string]interface{}, name string) (interface{}, bool) {
+	if value, ok := signals[name]; ok {
+		return value, true
+	}
+	head, rest, nested := strings.Cut(name, ".")
+	if !nested {
+		return nil, false
+	}
+	inner, ok := signals[head].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	return lookupSignal(inner, rest)
+}
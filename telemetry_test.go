@@ -0,0 +1,93 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIngestTelemetry(t *testing.T) {
+	uconE := GetUconEnforcer()
+	_ = uconE.AddTelemetryMapping(TelemetryMapping{Signal: "screen.locked", Attribute: "screen_locked"})
+	_ = uconE.AddTelemetryMapping(TelemetryMapping{Signal: "network", Attribute: "on_corp_network", Expr: "value == 'corp'"})
+	if err := uconE.AddTelemetryMapping(TelemetryMapping{Signal: "vpn"}); err == nil {
+		t.Error("Expected a mapping without attribute to be rejected")
+	}
+	_ = uconE.AddCondition(&Condition{ID: "unlocked", Name: "attribute_equals", Kind: "always", Expr: "screen_locked:false"})
+
+	attributes := map[string]interface{}{"device_id": "laptop-42", "screen_locked": false, "role": "user"}
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", attributes)
+	session, _ := uconE.EnforceWithSession(sessionID)
+	if session == nil {
+		t.Fatal("Expected access")
+	}
+
+	updated, err := uconE.IngestTelemetry([]TelemetryReport{
+		{SessionID: sessionID, Signals: map[string]interface{}{"network": "corp", "role": "admin"}},
+		{Device: "unknown", Signals: map[string]interface{}{"network": "corp"}},
+	})
+	if err != nil || updated != 1 {
+		t.Fatalf("Expected one session updated, got %d, %v", updated, err)
+	}
+	if session.GetAttribute("on_corp_network") != true {
+		t.Error("Expected the mapped attribute to be set")
+	}
+	if session.GetAttribute("role") != "user" {
+		t.Error("Expected unmapped signals to be ignored")
+	}
+
+	// The screen of the device gets locked.
+	handler := NewAPIHandler(uconE)
+	req := httptest.NewRequest(http.MethodPost, "/telemetry", strings.NewReader(`[{"device": "laptop-42", "signals": {"screen": {"locked": true}}}]`))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"updated":1`) {
+		t.Fatalf("Unexpected response %d %s", rec.Code, rec.Body.String())
+	}
+
+	time.Sleep(400 * time.Millisecond)
+	if session.IfActive() {
+		_ = uconE.StopMonitoring(sessionID)
+		t.Error("Expected the session to be revoked once the screen is locked")
+	}
+}
+
+func TestTelemetryTriggersEvaluation(t *testing.T) {
+	uconE := GetUconEnforcer().(*UconEnforcer)
+	_ = uconE.AddTelemetryMapping(TelemetryMapping{Signal: "screen.locked", Attribute: "screen_locked"})
+	_ = uconE.AddCondition(&Condition{ID: "unlocked", Name: "attribute_equals", Kind: "always", Expr: "screen_locked:false", Attributes: []string{"screen_locked"}})
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"device_id": "laptop-42", "screen_locked": false})
+	session, _ := uconE.GetSession(sessionID)
+
+	// Monitor with a long interval, so that only a trigger evaluates the session.
+	uconE.mu.Lock()
+	uconE.monitoringActive[sessionID] = true
+	uconE.mu.Unlock()
+	uconE.startMonitoring(session, time.Hour)
+	waitFor(t, func() bool {
+		uconE.mu.RLock()
+		defer uconE.mu.RUnlock()
+		return uconE.wakeups[sessionID] != nil
+	})
+
+	if _, err := uconE.IngestTelemetry([]TelemetryReport{{Device: "laptop-42", Signals: map[string]interface{}{"screen": map[string]interface{}{"locked": true}}}}); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return !session.IfActive() })
+}
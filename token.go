@@ -0,0 +1,160 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultTokenTTL is the lifetime of session tokens unless WithTokenSigningKey sets another.
+const DefaultTokenTTL = 5 * time.Minute
+
+// TokenIssuer is the "iss" claim of session tokens.
+const TokenIssuer = "casbin-ucon"
+
+var (
+	// ErrInvalidToken is returned for malformed tokens or bad signatures.
+	ErrInvalidToken = errors.New("invalid session token")
+	// ErrTokenExpired is returned for tokens past their expiry.
+	ErrTokenExpired = errors.New("session token expired")
+)
+
+// TokenClaims are the claims of a session token. The token is a JWT signed
+// with HS256.
+type TokenClaims struct {
+	Issuer    string `json:"iss"`
+	SessionID string `json:"sid"`
+	Subject   string `json:"sub"`
+	Action    string `json:"act"`
+	Object    string `json:"obj"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// WithTokenSigningKey enables IssueToken and ValidateToken. Tokens are signed
+// with key and expire after ttl, or DefaultTokenTTL if ttl is 0.
+func WithTokenSigningKey(key []byte, ttl time.Duration) Option {
+	return func(u *UconEnforcer) {
+		u.tokenKey = key
+		u.tokenTTL = ttl
+	}
+}
+
+// IssueToken mints a short-lived JWT referencing an active session, so that
+// stateless services can carry the session securely.
+func (u *UconEnforcer) IssueToken(sessionID string) (string, error) {
+	if len(u.tokenKey) == 0 {
+		return "", errors.New("no token signing key configured")
+	}
+	session, err := u.GetSession(sessionID)
+	if err != nil {
+		return "", err
+	}
+	if !session.IfActive() {
+		return "", ErrSessionNotActive
+	}
+
+	ttl := u.tokenTTL
+	if ttl <= 0 {
+		ttl = DefaultTokenTTL
+	}
+	now := time.Now()
+	payload, err := json.Marshal(&TokenClaims{
+		Issuer:    TokenIssuer,
+		SessionID: sessionID,
+		Subject:   session.GetSubject(),
+		Action:    session.GetAction(),
+		Object:    session.GetObject(),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	signed := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signed + "." + u.signToken(signed), nil
+}
+
+// ValidateToken checks the signature and expiry of a token issued by
+// IssueToken, and that the session it references still exists, is active
+// and matches the token's claims.
+func (u *UconEnforcer) ValidateToken(token string) (*Session, error) {
+	if len(u.tokenKey) == 0 {
+		return nil, errors.New("no token signing key configured")
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != jwtHeader {
+		return nil, ErrInvalidToken
+	}
+	if !hmac.Equal([]byte(parts[2]), []byte(u.signToken(parts[0]+"."+parts[1]))) {
+		return nil, ErrInvalidToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	claims := &TokenClaims{}
+	if err := json.Unmarshal(payload, claims); err != nil || claims.Issuer != TokenIssuer {
+		return nil, ErrInvalidToken
+	}
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return nil, ErrTokenExpired
+	}
+
+	session, err := u.GetSession(claims.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSessionDenied, err)
+	}
+	if session.GetSubject() != claims.Subject || session.GetAction() != claims.Action || session.GetObject() != claims.Object {
+		return nil, ErrInvalidToken
+	}
+	if !session.IfActive() {
+		return nil, fmt.Errorf("%w: session %s was stopped", ErrSessionDenied, claims.SessionID)
+	}
+	return session, nil
+}
+
+func (u *UconEnforcer) signToken(signed string) string {
+	mac := hmac.New(sha256.New, u.tokenKey)
+	mac.Write([]byte(signed))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// BearerTokenSessionID extracts the session id from an "Authorization: Bearer"
+// session token, returning "" if the token does not validate.
+func BearerTokenSessionID(e IUconEnforcer) SessionIDExtractor {
+	return func(r *http.Request) string {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			return ""
+		}
+		session, err := e.ValidateToken(strings.TrimSpace(token))
+		if err != nil {
+			return ""
+		}
+		return session.GetId()
+	}
+}
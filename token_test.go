@@ -0,0 +1,73 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessionTokens(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithTokenSigningKey([]byte("secret"), time.Minute))
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	token, err := uconE.IssueToken(sessionID)
+	if err != nil {
+		t.Fatalf("IssueToken: %v", err)
+	}
+	if strings.Count(token, ".") != 2 {
+		t.Fatalf("Expected a JWT, got %q", token)
+	}
+
+	session, err := uconE.ValidateToken(token)
+	if err != nil || session.GetId() != sessionID {
+		t.Fatalf("Expected token to validate, got %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	if id := BearerTokenSessionID(uconE)(req); id != sessionID {
+		t.Errorf("Expected bearer extractor to return %s, got %q", sessionID, id)
+	}
+
+	tampered := token[:len(token)-2] + "xx"
+	if _, err := uconE.ValidateToken(tampered); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for tampered token, got %v", err)
+	}
+	other := NewUconEnforcer(e, WithTokenSigningKey([]byte("other"), time.Minute))
+	if _, err := other.ValidateToken(token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for foreign key, got %v", err)
+	}
+
+	_ = session.Stop("revoked")
+	if _, err := uconE.ValidateToken(token); !errors.Is(err, ErrSessionDenied) {
+		t.Errorf("Expected ErrSessionDenied for stopped session, got %v", err)
+	}
+	if _, err := uconE.IssueToken(sessionID); err == nil {
+		t.Error("Expected no token for a stopped session")
+	}
+
+	expiring := NewUconEnforcer(e, WithTokenSigningKey([]byte("secret"), time.Nanosecond))
+	expiredID, _ := expiring.CreateSession("alice", "read", "document1", nil)
+	expired, _ := expiring.IssueToken(expiredID)
+	if _, err := expiring.ValidateToken(expired); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("Expected ErrTokenExpired, got %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import "time"
+
+// DefaultTraceSize is the number of monitoring evaluations kept per session.
+const DefaultTraceSize = 100
+
+// ConditionResult is the outcome of one condition in a monitoring evaluation.
+type ConditionResult struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Error  string `json:"error,omitempty"`
+	// Cached is set when the result was reused because the session's
+	// attributes had not changed since the condition was last evaluated.
+	Cached bool `json:"cached,omitempty"`
+}
+
+// ObligationResult is the outcome of one ongoing obligation in a monitoring evaluation.
+type ObligationResult struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Error string `json:"error,omitempty"`
+}
+
+// TraceEntry records one monitoring evaluation of a session. StopReason is
+// set for the evaluation that revoked the session.
+type TraceEntry struct {
+	Time        time.Time          `json:"time"`
+	Conditions  []ConditionResult  `json:"conditions,omitempty"`
+	Obligations []ObligationResult `json:"obligations,omitempty"`
+	Verdict     Verdict            `json:"verdict,omitempty"`
+	StopReason  string             `json:"stop_reason,omitempty"`
+}
+
+// WithTraceSize sets how many monitoring evaluations are kept per session; 0
+// disables tracing.
+func WithTraceSize(size int) Option {
+	return func(u *UconEnforcer) {
+		u.traceSize = size
+	}
+}
+
+// GetSessionTrace returns the last monitoring evaluations of a session, oldest first.
+func (u *UconEnforcer) GetSessionTrace(sessionID string) ([]TraceEntry, error) {
+	session, err := u.GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	session.mutex.RLock()
+	defer session.mutex.RUnlock()
+	return session.traceLocked(), nil
+}
+
+// traceLocked copies the trace of the session, oldest first. s.mutex must
+// be held.
+func (s *Session) traceLocked() []TraceEntry {
+	trace := make([]TraceEntry, 0, len(s.trace))
+	for i := range s.trace {
+		entry := s.trace[(s.traceNext+i)%len(s.trace)]
+		// The ring buffer reuses the results of its entries.
+		entry.Conditions = append([]ConditionResult(nil), entry.Conditions...)
+		entry.Obligations = append([]ObligationResult(nil), entry.Obligations...)
+		trace = append(trace, entry)
+	}
+	return trace
+}
+
+// recordTrace copies an evaluation into the session's ring buffer. Once the
+// buffer is full, the oldest entry is overwritten in place, reusing its
+// result slices.
+func (u *UconEnforcer) recordTrace(session *Session, entry *TraceEntry) {
+	if u.traceSize <= 0 {
+		return
+	}
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+	var slot *TraceEntry
+	if len(session.trace) < u.traceSize {
+		session.trace = append(session.trace, TraceEntry{})
+		slot = &session.trace[len(session.trace)-1]
+	} else {
+		slot = &session.trace[session.traceNext]
+		session.traceNext = (session.traceNext + 1) % len(session.trace)
+	}
+	conditions := append(slot.Conditions[:0], entry.Conditions...)
+	obligations := append(slot.Obligations[:0], entry.Obligations...)
+	*slot = *entry
+	slot.Conditions, slot.Obligations = conditions, obligations
+}
+
+// resetTrace clears the trace of the state for a new evaluation, keeping the
+// capacity of its results so that stable ticks do not allocate.
+func (s *monitorState) resetTrace(now time.Time) *TraceEntry {
+	s.trace = TraceEntry{Time: now, Conditions: s.trace.Conditions[:0], Obligations: s.trace.Obligations[:0]}
+	return &s.trace
+}
+
+// stopMonitored revokes a monitored session and records the evaluation that revoked it.
+func (u *UconEnforcer) stopMonitored(session *Session, entry *TraceEntry, reason string) {
+	entry.StopReason = reason
+	u.recordTrace(session, entry)
+	_ = session.Stop(reason)
+}
+
+func (e *TraceEntry) addCondition(condition *Condition, passed bool, err error) {
+	if e == nil {
+		return
+	}
+	result := ConditionResult{ID: condition.ID, Name: condition.Name, Passed: passed}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	e.Conditions = append(e.Conditions, result)
+}
+
+// markCached flags the last condition result as reused from the cache.
+func (e *TraceEntry) markCached() {
+	if e == nil || len(e.Conditions) == 0 {
+		return
+	}
+	e.Conditions[len(e.Conditions)-1].Cached = true
+}
+
+func (e *TraceEntry) addObligation(obligation *Obligation, err error) {
+	if e == nil {
+		return
+	}
+	result := ObligationResult{ID: obligation.ID, Name: obligation.Name}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	e.Obligations = append(e.Obligations, result)
+}
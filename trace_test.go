@@ -0,0 +1,94 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessionTrace(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithTraceSize(3))
+	_ = uconE.AddCondition(&Condition{ID: "office", Name: "location", Kind: "always", Expr: "office"})
+	_ = uconE.AddObligation(&Obligation{ID: "count", Name: "increment_counter", Kind: "ongoing", Expr: "ticks"})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"location": "office"})
+	session, _ := uconE.EnforceWithSession(sessionID)
+	if session == nil {
+		t.Fatal("Expected access")
+	}
+
+	time.Sleep(1100 * time.Millisecond)
+	_ = uconE.UpdateSessionAttribute(sessionID, "location", "home")
+	time.Sleep(400 * time.Millisecond)
+	if session.IfActive() {
+		_ = uconE.StopMonitoring(sessionID)
+		t.Fatal("Expected the session to be revoked")
+	}
+
+	trace, err := uconE.GetSessionTrace(sessionID)
+	if err != nil {
+		t.Fatalf("GetSessionTrace: %v", err)
+	}
+	if len(trace) != 3 {
+		t.Fatalf("Expected the trace to be bounded to 3 entries, got %d", len(trace))
+	}
+	for i := 1; i < len(trace); i++ {
+		if !trace[i-1].Time.Before(trace[i].Time) {
+			t.Errorf("Expected trace entries oldest first, got %v before %v", trace[i-1].Time, trace[i].Time)
+		}
+	}
+
+	ok := trace[0]
+	if ok.StopReason != "" || len(ok.Conditions) != 1 || !ok.Conditions[0].Passed || len(ok.Obligations) != 1 {
+		t.Errorf("Unexpected passing entry: %+v", ok)
+	}
+	last := trace[len(trace)-1]
+	if !strings.Contains(last.StopReason, "Conditions no longer met") {
+		t.Errorf("Expected the last entry to carry the stop reason, got %q", last.StopReason)
+	}
+	if len(last.Conditions) != 1 || last.Conditions[0].ID != "office" || last.Conditions[0].Passed {
+		t.Errorf("Expected the last entry to show the failed condition, got %+v", last.Conditions)
+	}
+}
+
+func TestSessionTraceReusesEntries(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	u := NewUconEnforcer(e, WithTraceSize(2)).(*UconEnforcer)
+	sessionID, _ := u.CreateSession("alice", "read", "document1", nil)
+	session, _ := u.GetSession(sessionID)
+
+	state := &monitorState{}
+	record := func(id string) {
+		trace := state.resetTrace(time.Now())
+		trace.addCondition(&Condition{ID: id}, true, nil)
+		u.recordTrace(session, trace)
+	}
+	record("first")
+	record("second")
+	before, _ := u.GetSessionTrace(sessionID)
+	record("third")
+	record("fourth")
+
+	if before[0].Conditions[0].ID != "first" || before[1].Conditions[0].ID != "second" {
+		t.Errorf("Expected a returned trace to be unaffected by later evaluations, got %+v", before)
+	}
+	after, _ := u.GetSessionTrace(sessionID)
+	if len(after) != 2 || after[0].Conditions[0].ID != "third" || after[1].Conditions[0].ID != "fourth" {
+		t.Errorf("Expected the last two evaluations, got %+v", after)
+	}
+}
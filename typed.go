@@ -0,0 +1,94 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// TypedSession is a view of a session whose attributes are described by the
+// struct T. Attributes map to fields by their json tags, and attributes
+// without a field are ignored, so several views can share a session.
+//
+//	type DeviceAttributes struct {
+//		DeviceID     string `json:"device_id"`
+//		ScreenLocked bool   `json:"screen_locked"`
+//	}
+//	attrs, err := ucon.NewTypedSession[DeviceAttributes](session).Attributes()
+type TypedSession[T any] struct {
+	*Session
+}
+
+// NewTypedSession returns a typed view of session.
+func NewTypedSession[T any](session *Session) *TypedSession[T] {
+	return &TypedSession[T]{Session: session}
+}
+
+// Attributes decodes the session attributes into a T.
+func (s *TypedSession[T]) Attributes() (T, error) {
+	return decodeAttributes[T](s.Session.View())
+}
+
+func decodeAttributes[T any](session SessionView) (T, error) {
+	var attrs T
+	data, err := json.Marshal(session.GetAttributes())
+	if err != nil {
+		return attrs, fmt.Errorf("failed to encode attributes of session %s: %w", session.GetId(), err)
+	}
+	if err := json.Unmarshal(data, &attrs); err != nil {
+		return attrs, fmt.Errorf("attributes of session %s do not match %T: %w", session.GetId(), attrs, err)
+	}
+	return attrs, nil
+}
+
+// SetAttributes stores the fields of attrs as session attributes, atomically
+// like UpdateAttributes. Values are stored in their JSON form (numbers as
+// float64), as they would be after a round trip through a session store;
+// fields tagged omitempty are left untouched when empty.
+func (s *TypedSession[T]) SetAttributes(attrs T) error {
+	data, err := json.Marshal(attrs)
+	if err != nil {
+		return fmt.Errorf("failed to encode %T: %w", attrs, err)
+	}
+	attributes := make(map[string]interface{})
+	if err := json.Unmarshal(data, &attributes); err != nil {
+		return fmt.Errorf("%T is not a struct or map: %w", attrs, err)
+	}
+	return s.UpdateAttributes(attributes)
+}
+
+// TypedCondition adapts a condition handler working on T to a ConditionHandler.
+// A session whose attributes do not decode into T fails with an error.
+func TypedCondition[T any](handler func(expr string, attrs T, session SessionView) (bool, error)) ConditionHandler {
+	return func(expr string, session SessionView) (bool, error) {
+		attrs, err := decodeAttributes[T](session)
+		if err != nil {
+			return false, err
+		}
+		return handler(expr, attrs, session)
+	}
+}
+
+// TypedObligation adapts an obligation handler working on T to an ObligationHandler.
+func TypedObligation[T any](handler func(expr string, attrs T, session *Session) error) ObligationHandler {
+	return func(expr string, session *Session) error {
+		attrs, err := NewTypedSession[T](session).Attributes()
+		if err != nil {
+			return err
+		}
+		return handler(expr, attrs, session)
+	}
+}
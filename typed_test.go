@@ -0,0 +1,76 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import "testing"
+
+type deviceAttributes struct {
+	DeviceID     string  `json:"device_id"`
+	ScreenLocked bool    `json:"screen_locked"`
+	Battery      float64 `json:"battery"`
+	Owner        string  `json:"owner,omitempty"`
+}
+
+func TestTypedSession(t *testing.T) {
+	uconE := GetUconEnforcer()
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{
+		"device_id": "laptop-42",
+		"battery":   80,
+		"role":      "user",
+	})
+	session, _ := uconE.GetSession(sessionID)
+	typed := NewTypedSession[deviceAttributes](session)
+
+	attrs, err := typed.Attributes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attrs.DeviceID != "laptop-42" || attrs.Battery != 80 || attrs.ScreenLocked {
+		t.Fatalf("Unexpected attributes %+v", attrs)
+	}
+
+	attrs.ScreenLocked = true
+	if err := typed.SetAttributes(attrs); err != nil {
+		t.Fatal(err)
+	}
+	if session.GetAttribute("screen_locked") != true || session.GetAttribute("role") != "user" {
+		t.Errorf("Unexpected session attributes %+v", session.ToRecord().Attributes)
+	}
+	if _, ok := session.ToRecord().Attributes["owner"]; ok {
+		t.Error("Expected empty omitempty fields to be left out")
+	}
+
+	_ = session.UpdateAttribute("battery", "full")
+	if _, err := typed.Attributes(); err == nil {
+		t.Error("Expected a mismatching attribute to fail decoding")
+	}
+}
+
+func TestTypedCondition(t *testing.T) {
+	uconE := GetUconEnforcer()
+	_ = uconE.RegisterConditionHandler("unlocked", TypedCondition(func(expr string, attrs deviceAttributes, session SessionView) (bool, error) {
+		return !attrs.ScreenLocked && attrs.DeviceID == expr, nil
+	}))
+	_ = uconE.AddCondition(&Condition{ID: "unlocked", Name: "unlocked", Kind: "always", Expr: "laptop-42"})
+
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"device_id": "laptop-42"})
+	if ok, err := uconE.EvaluateConditions(sessionID); !ok || err != nil {
+		t.Fatalf("Expected the typed condition to pass, got %v, %v", ok, err)
+	}
+	_ = uconE.UpdateSessionAttribute(sessionID, "screen_locked", true)
+	if ok, _ := uconE.EvaluateConditions(sessionID); ok {
+		t.Error("Expected the typed condition to fail")
+	}
+}
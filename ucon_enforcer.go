@@ -15,8 +15,12 @@
 package ucon
 
 import (
+	"crypto/ed25519"
 	"errors"
 	"fmt"
+	"net/http"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -28,56 +32,316 @@ import (
 // UconEnforcer UCON enforcer that wraps casbin.Enforcer and extends UCON functionality.
 type UconEnforcer struct {
 	*casbin.Enforcer // Embed casbin.Enforcer for backward compatibility
-	sessions         *SessionManager
+	sessions         ISessionManager
 	conditions       map[string]Condition
 	obligations      map[string]Obligation
 	monitoringActive map[string]bool // Track which sessions are being monitored
 
+	// The rules in evaluation order, rebuilt by orderRulesLocked whenever
+	// they change so that monitoring ticks do not copy and sort them.
+	conditionOrder  []Condition
+	obligationOrder []Obligation
+
+	conditionHandlers  map[string]conditionFunc
+	obligationHandlers map[string]obligationFunc
+	failurePolicy      FailurePolicy
+	listeners          []EventListener
+	store              SessionStore
+	expressions        *expressionEngine
+	logger             Logger
+	monitorLogInterval time.Duration
+	httpClient         *http.Client
+	metrics            Metrics
+
+	providers           map[string]*providerEntry
+	degradedMaxAge      time.Duration
+	healthCheckInterval time.Duration
+	healthCheckOnce     sync.Once
+	heartbeatInterval   time.Duration
+	heartbeatMaxMissed  int
+	tokenKey            []byte
+	tokenTTL            time.Duration
+	meter               *Meter
+	timeLedger          *timeLedger
+	consents            ConsentStore
+	traceSize           int
+	revocationChecker   RevocationChecker
+	telemetryMappings   map[string]TelemetryMapping
+	analyzer            Analyzer
+	decisions           *decisionCache
+
+	stoppedSessionUpdates bool
+	maxMonitored          int
+	capacityPolicy        CapacityPolicy
+	slotFreed             chan struct{} // closed and replaced when a monitoring slot frees
+	evaluationDeadline    time.Duration
+	overrunPolicy         OverrunPolicy
+	negativeCache         *negativeCache
+	metricLabels          *metricLabels
+	objectAttributes      map[string]map[string]interface{}
+	objectProviders       map[string]ObjectAttributeProvider
+	subjects              *subjectAttributes
+	gauges                *sessionGauges
+	storePolicy           StorePolicy
+	storeGuard            *guardedStore
+	reservations          map[string]*reservation
+	reservedSessions      map[string]string // session id -> reservation id
+	waitlist              []string          // session ids, first come first served
+	admittingWaitlist     bool
+	receipts              ReceiptStore
+	receiptKey            ed25519.PrivateKey
+	decisionLog           *decisionLog
+	environment           map[string]interface{} // replaced, never modified, on change
+	environmentProviders  map[string]EnvironmentProvider
+	maintenance           map[string]MaintenanceWindow
+	attributeSchema       map[string]AttributeSchema
+	strictAttributes      bool
+	validators            []SessionValidator
+	enrichers             []enricherEntry
+	customConditions      map[string]bool          // condition names with a registered handler
+	wakeups               map[string]chan struct{} // session id -> monitoring loop, see triggerConditions
+	leaseNode             string
+	leaseTTL              time.Duration
+	heartbeatCoordination bool
+	lastHeartbeat         time.Time // last successful heartbeat of this node
+	ruleSets              ruleSets
+	breakGlassTTL         time.Duration
+	breakGlassReviews     map[string]*BreakGlassReview // session id -> review
+	approvals             map[string]*approval         // session id -> four-eyes approval
+	approvalProvider      ApprovalProvider
+	usageLedger           UsageLedger
+	retention             *Retention
+	retentionStop         chan struct{} // closed by StopRetention
+	retentionDone         chan struct{} // closed when the retention loop returns
+	lazyTTL               time.Duration
+	lazySessions          map[string]*time.Timer
+	priorities            []PriorityRule
+
 	mu sync.RWMutex
 }
 
 type Condition struct {
-	ID   string
-	Name string
-	Kind string // "one", "always"
-	Expr string
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Kind string `json:"kind"` // "one", "always"
+	Expr string `json:"expr"`
+
+	// Purposes restricts the condition to sessions created for one of these
+	// purposes; if empty it applies to every session.
+	Purposes []string `json:"purposes,omitempty"`
+
+	// Dwell is how long the condition must keep failing during ongoing
+	// monitoring before the session is revoked. It suppresses churn from
+	// conditions that oscillate, e.g. GPS jitter across a geofence boundary.
+	Dwell time.Duration `json:"dwell,omitempty"`
+
+	// Attributes declares the session attributes the condition reads, and
+	// that its result depends on nothing else. Monitoring then evaluates it
+	// again only when one of them changes, and right away when one is
+	// updated instead of at the next tick.
+	Attributes []string `json:"attributes,omitempty"`
+
+	// Shadow evaluates the condition without letting it affect decisions:
+	// would-be denials are logged and counted in CounterMetrics, so a new
+	// rule can be rolled out safely before it is enforced.
+	Shadow bool `json:"shadow,omitempty"`
+
+	// Rollout enforces a shadow condition for this percentage of subjects,
+	// see SetConditionRollout.
+	Rollout int `json:"rollout,omitempty"`
+
+	// BreakGlassBypass lets break-glass sessions pass the condition, see
+	// CreateBreakGlassSession.
+	BreakGlassBypass bool `json:"break_glass_bypass,omitempty"`
 }
 
 type Obligation struct {
-	ID   string
-	Name string
-	Kind string // "pre", "post", "ongoing"
-	Expr string
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Kind string `json:"kind"` // "pre", "post", "ongoing"
+	Expr string `json:"expr"`
+
+	// Purposes restricts the obligation to sessions created for one of these
+	// purposes; if empty it applies to every session.
+	Purposes []string `json:"purposes,omitempty"`
+
+	// Schedule is an optional cron spec (e.g. "@hourly", "*/15 * * * *") for
+	// "ongoing" obligations. Scheduled obligations run when the spec fires
+	// instead of on every monitoring tick.
+	Schedule string `json:"schedule,omitempty"`
+
+	// After lists the IDs of obligations that must run before this one when
+	// both are executed together, e.g. a notice that must follow
+	// authentication. Cycles are rejected by AddObligation.
+	After []string `json:"after,omitempty"`
+
+	// Deadline, if set, makes the obligation one the caller carries out: once
+	// it is executed the caller has Deadline to acknowledge it with
+	// FulfillObligation, or the session is revoked.
+	Deadline time.Duration `json:"deadline,omitempty"`
+
+	// Shadow dry-runs the obligation without letting its failures deny or
+	// revoke access; they are logged and counted in CounterMetrics instead.
+	// The handler works on a detached copy of the session, so its changes
+	// are discarded, and skips effects outside the session such as webhook
+	// calls. Shadow obligations have no fulfillment deadline.
+	Shadow bool `json:"shadow,omitempty"`
+
+	// Rollout enforces a shadow obligation for this percentage of subjects,
+	// see SetObligationRollout.
+	Rollout int `json:"rollout,omitempty"`
+
+	// BreakGlass restricts the obligation to break-glass sessions, e.g. to
+	// alert on-call staff, see CreateBreakGlassSession.
+	BreakGlass bool `json:"break_glass,omitempty"`
+
+	schedule *cronSchedule
 }
 
 // NewUconEnforcer creates a new UCON enforcer.
-func NewUconEnforcer(e *casbin.Enforcer) IUconEnforcer {
-	sm := NewSessionManager()
+func NewUconEnforcer(e *casbin.Enforcer, opts ...Option) IUconEnforcer {
+	u := &UconEnforcer{
+		Enforcer:            e,
+		sessions:            NewSessionManager(),
+		conditions:          make(map[string]Condition),
+		obligations:         make(map[string]Obligation),
+		monitoringActive:    make(map[string]bool),
+		slotFreed:           make(chan struct{}),
+		conditionHandlers:   make(map[string]conditionFunc),
+		obligationHandlers:  make(map[string]obligationFunc),
+		failurePolicy:       FailClosed,
+		expressions:         newExpressionEngine(),
+		logger:              NewDefaultLogger(LevelInfo),
+		monitorLogInterval:  DefaultMonitorLogInterval,
+		httpClient:          &http.Client{Timeout: 5 * time.Second},
+		metrics:             NewInMemoryMetrics(),
+		providers:           make(map[string]*providerEntry),
+		meter:               NewMeter(),
+		usageLedger:         NewInMemoryUsageLedger(),
+		timeLedger:          newTimeLedger(),
+		subjects:            newSubjectAttributes(),
+		gauges:              newSessionGauges(),
+		healthCheckInterval: DefaultHealthCheckInterval,
+		traceSize:           DefaultTraceSize,
+		breakGlassTTL:       DefaultBreakGlassTTL,
+		mu:                  sync.RWMutex{},
+	}
+
+	for name, handler := range builtinConditions() {
+		u.conditionHandlers[name] = handler
+	}
+	u.conditionHandlers["regex_match"] = u.checkRegexMatch
+	u.conditionHandlers["location"] = u.checkLocation
+	u.conditionHandlers["vip_level"] = u.checkVipLevel
+	u.conditionHandlers["expression"] = u.checkExpression
+	u.conditionHandlers["max_concurrent_sources"] = u.checkMaxConcurrentSources
+	u.conditionHandlers["expiry"] = u.checkExpiry
+	u.conditionHandlers["entitlement_remaining"] = u.checkEntitlementRemaining
+	u.conditionHandlers["time_budget"] = u.checkTimeBudget
+	u.conditionHandlers["client_certificate"] = u.checkClientCertificate
+	u.conditionHandlers["svid_valid"] = u.checkSVIDValid
+	for name, handler := range u.builtinObligations() {
+		u.obligationHandlers[name] = withoutKey(handler)
+	}
+	u.obligationHandlers["user_authentication"] = withoutKey(u.executeUserAuthentication)
+	u.obligationHandlers["vip_validation"] = withoutKey(u.executeVipValidation)
+	u.obligationHandlers["expression"] = withoutKey(u.executeExpression)
+	u.obligationHandlers["consume_entitlement"] = withoutKey(u.executeConsumeEntitlement)
+	u.obligationHandlers["consent_check"] = withoutKey(u.executeConsentCheck)
+	u.obligationHandlers["impersonation_audit"] = withoutKey(u.executeImpersonationAudit)
+	u.obligationHandlers["four_eyes_approval"] = withoutKey(u.executeFourEyesApproval)
+	u.obligationHandlers["mask_fields"] = withoutKey(u.executeMaskFields)
+	u.obligationHandlers["watermark"] = withoutKey(u.executeWatermark)
+	u.obligationHandlers["reduce_resolution"] = withoutKey(u.executeReduceResolution)
+	u.obligationHandlers["webhook"] = u.executeWebhook
+	u.obligationHandlers["usage_receipt"] = u.executeUsageReceipt
+	u.expressions.register("remaining", u.exprRemaining)
+	u.expressions.objects = u.GetObjectAttributes
+	u.expressions.environment = u.GetEnvironmentAttributes
+
+	for _, opt := range opts {
+		opt(u)
+	}
+	u.sessions.SetStopHook(u.sessionStopped)
+	if u.consents != nil {
+		u.watchConsents()
+	}
+	if u.retention != nil {
+		u.retentionStop, u.retentionDone = make(chan struct{}), make(chan struct{})
+		go u.retentionLoop(u.retentionStop, u.retentionDone)
+	}
+
+	if u.store != nil {
+		u.sessions.SetStore(u.guardStore(u.store))
+		if err := u.Recover(); err != nil {
+			u.logger.Log(LevelWarn, "failed to recover sessions from store", map[string]interface{}{"error": err.Error()})
+		}
+	}
+
+	return u
+}
 
-	return &UconEnforcer{
-		Enforcer:         e,
-		sessions:         sm,
-		conditions:       make(map[string]Condition),
-		obligations:      make(map[string]Obligation),
-		monitoringActive: make(map[string]bool),
-		mu:               sync.RWMutex{},
+// Recover reloads sessions from the configured session store and resumes
+// monitoring of those that were active and monitored when the process stopped.
+// Sessions already known to the enforcer are left untouched.
+func (u *UconEnforcer) Recover() error {
+	if u.store == nil {
+		return errors.New("no session store configured")
 	}
+
+	records, err := u.store.LoadSessions()
+	if err != nil {
+		return fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	for _, record := range records {
+		session, restored := u.restoreSession(record)
+		if restored && record.Active && record.SuspendedUntil != nil {
+			u.armSuspension(session, *record.SuspendedUntil)
+		}
+		if !restored || !record.Active || !record.Monitored {
+			continue
+		}
+		if err := u.StartMonitoring(session.GetId()); err != nil {
+			return fmt.Errorf("failed to resume monitoring of session %s: %w", session.GetId(), err)
+		}
+	}
+
+	return nil
 }
 
 // EnforceWithSession performs enforcement with session context.
 func (u *UconEnforcer) EnforceWithSession(sessionID string) (*Session, error) {
+	session, err := u.enforceWithSession(sessionID)
+	u.emitDecision(sessionID, session, err)
+	return session, err
+}
+
+func (u *UconEnforcer) enforceWithSession(sessionID string) (*Session, error) {
 	// Get session information
 	session, err := u.GetSession(sessionID)
 	if err != nil {
 		return nil, err
 	}
+	session.setDenialHints(denialHints{})
 
-	// Check if session is active
-	if !session.IfActive() {
-		return nil, errors.New("session is not active")
+	if err := u.checkAdmission(session); err != nil {
+		return nil, err
 	}
 
-	// 1. Evaluate conditions first
+	var epoch, version uint64
+	if u.decisions != nil {
+		if u.decisions.get(session, time.Now()) {
+			return session, nil
+		}
+		epoch = u.decisions.currentEpoch()
+		version = session.getVersion()
+	}
+
+	// 1. Refresh attributes from providers, then evaluate conditions
+	if err := u.refreshAttributes(session); err != nil {
+		return nil, err
+	}
 	conditionsOk, err := u.EvaluateConditions(sessionID)
 	if err != nil {
 		return nil, err
@@ -90,29 +354,65 @@ func (u *UconEnforcer) EnforceWithSession(sessionID string) (*Session, error) {
 	err = u.ExecuteObligationsByType(sessionID, "pre")
 	if err != nil {
 		// Pre-access obligations failure should deny access
-		fmt.Printf("Error: Failed to execute pre-access obligations: %v\n", err)
+		u.logger.Log(LevelError, "failed to execute pre-access obligations", map[string]interface{}{"session": sessionID, "error": err.Error()})
 		return nil, err
 	}
 
-	// 3. Perform basic Casbin policy enforcement
-	ok, err := u.Enforce(session.GetSubject(), session.GetObject(), session.GetAction())
+	// 3. Perform basic Casbin policy enforcement, remembering the matched rule
+	ok, rule, err := u.EnforceEx(session.GetSubject(), session.GetObject(), session.GetAction())
 	if err != nil {
 		return nil, err
 	}
+	if ok {
+		if err := session.recordGrant(rule, time.Now()); err != nil {
+			u.logger.Log(LevelWarn, "failed to persist grant source", map[string]interface{}{"session": sessionID, "error": err.Error()})
+		}
+	}
 
 	// 4. Start monitoring if access is granted
 	if ok {
 		// Start monitoring for ongoing obligations
-		_ = u.StartMonitoring(sessionID)
+		if err := u.StartMonitoring(sessionID); errors.Is(err, ErrCapacityExceeded) {
+			return nil, err
+		}
 	} else {
 		return nil, nil
 	}
+	if u.decisions != nil {
+		u.cacheDecision(session, version, epoch)
+	}
 	return session, nil
 }
 
-// CreateSession creates a new session.
+// checkAdmission checks that a session can be enforced at all, before its
+// conditions and policy are.
+func (u *UconEnforcer) checkAdmission(session *Session) error {
+	if !session.IfActive() {
+		return ErrSessionNotActive
+	}
+	if session.IsSuspended() {
+		return fmt.Errorf("session %s: %w", session.GetId(), ErrSessionSuspended)
+	}
+	if u.breakGlassExpired(session, time.Now()) {
+		_ = session.Stop(BreakGlassExpiredReason)
+		return fmt.Errorf("session %s: %w", session.GetId(), ErrBreakGlassExpired)
+	}
+	if id := u.reservationOf(session.GetId()); id != "" {
+		return fmt.Errorf("session %s: %w by %s", session.GetId(), ErrSessionReserved, id)
+	}
+	if err := u.checkLazyExpiry(session, time.Now()); err != nil {
+		return err
+	}
+	if window, ok := u.activeMaintenance(session.GetObject(), time.Now()); ok {
+		return fmt.Errorf("session %s: %w until %s (window %s)", session.GetId(), ErrUnderMaintenance, window.End.Format(time.RFC3339), window.ID)
+	}
+	return u.checkStore(session)
+}
+
+// CreateSession creates a new session. Sessions rejected by a SessionValidator
+// or the attribute schema are not created.
 func (u *UconEnforcer) CreateSession(sub string, act string, obj string, attributes map[string]interface{}) (string, error) {
-	return u.sessions.CreateSession(sub, act, obj, attributes)
+	return u.createSession(sub, act, obj, "", attributes)
 }
 
 // GetSession retrieves session information.
@@ -120,8 +420,54 @@ func (u *UconEnforcer) GetSession(sessionID string) (*Session, error) {
 	return u.sessions.GetSessionById(sessionID)
 }
 
+// GetSessions returns all sessions known to the enforcer, ordered by start time.
+func (u *UconEnforcer) GetSessions() []*Session {
+	return u.sessions.GetSessions()
+}
+
+// UpdateSessionAttribute sets an attribute of an active session. Stopped
+// sessions are refused with ErrSessionNotActive, unless the enforcer was
+// created with WithStoppedSessionUpdates.
 func (u *UconEnforcer) UpdateSessionAttribute(sessionID string, key string, val interface{}) error {
-	return u.sessions.UpdateSessionAttribute(sessionID, key, val)
+	return u.UpdateSessionAttributes(sessionID, map[string]interface{}{key: val})
+}
+
+// UpdateSessionAttributes sets several attributes of a session atomically,
+// so the next evaluation sees all of them or none. Like UpdateSessionAttribute
+// it refuses stopped sessions by default.
+func (u *UconEnforcer) UpdateSessionAttributes(sessionID string, attributes map[string]interface{}) error {
+	if err := u.validateAttributes(attributes); err != nil {
+		return err
+	}
+	var err error
+	if u.stoppedSessionUpdates {
+		err = u.sessions.UpdateSessionAttributes(sessionID, attributes)
+	} else {
+		err = u.sessions.UpdateSessionAttributesIfActive(sessionID, attributes)
+	}
+	if err != nil {
+		return err
+	}
+	if session, err := u.GetSession(sessionID); err == nil {
+		keys := attributeKeys(attributes)
+		u.triggerConditions(session, keys)
+	}
+	return nil
+}
+
+// IncrementAttribute atomically adds delta to an integer session attribute and returns the new value.
+func (u *UconEnforcer) IncrementAttribute(sessionID string, key string, delta int64) (int64, error) {
+	if err := u.validateIncrement(key); err != nil {
+		return 0, err
+	}
+	val, err := u.sessions.IncrementSessionAttribute(sessionID, key, delta)
+	if err != nil {
+		return 0, err
+	}
+	if session, err := u.GetSession(sessionID); err == nil {
+		u.triggerConditions(session, []string{key})
+	}
+	return val, nil
 }
 
 // RevokeSession revokes a session.
@@ -146,10 +492,56 @@ func (u *UconEnforcer) AddCondition(condition *Condition) error {
 	if condition == nil {
 		return errors.New("condition cannot be nil")
 	}
+	if err := validateRollout(condition.Rollout); err != nil {
+		return fmt.Errorf("condition %s: %w", condition.ID, err)
+	}
+	u.mu.Lock()
 	u.conditions[condition.ID] = *condition
+	u.orderRulesLocked()
+	u.mu.Unlock()
+	u.InvalidateDecisions()
 	return nil
 }
 
+// GetConditions returns all conditions, ordered by ID.
+func (u *UconEnforcer) GetConditions() []Condition {
+	return slices.Clone(u.conditionList())
+}
+
+// RemoveCondition removes a condition.
+func (u *UconEnforcer) RemoveCondition(id string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if _, exists := u.conditions[id]; !exists {
+		return fmt.Errorf("cannot find condition with id %s", id)
+	}
+	delete(u.conditions, id)
+	u.orderRulesLocked()
+	u.InvalidateDecisions()
+	return nil
+}
+
+// conditionList returns the conditions ordered by ID, to be evaluated without
+// holding the lock. The slice is shared and must not be modified.
+func (u *UconEnforcer) conditionList() []Condition {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.conditionOrder
+}
+
+// orderRulesLocked rebuilds conditionOrder and obligationOrder after the
+// rules changed. u.mu must be held for writing.
+func (u *UconEnforcer) orderRulesLocked() {
+	conditions := make([]Condition, 0, len(u.conditions))
+	for _, condition := range u.conditions {
+		conditions = append(conditions, condition)
+	}
+	sort.Slice(conditions, func(i, j int) bool { return conditions[i].ID < conditions[j].ID })
+	// AddObligation rejects cycles, so ordering cannot fail.
+	obligations, _ := orderObligations(u.obligations)
+	u.conditionOrder, u.obligationOrder = conditions, obligations
+}
+
 // EvaluateConditions evaluates all conditions for a session.
 func (u *UconEnforcer) EvaluateConditions(sessionID string) (bool, error) {
 	// Get session
@@ -158,40 +550,60 @@ func (u *UconEnforcer) EvaluateConditions(sessionID string) (bool, error) {
 		return false, err
 	}
 
-	if len(u.conditions) == 0 {
-		return true, nil
-	}
-
 	// Copy conditions to avoid holding lock during evaluation
-	conditionsCopy := make([]Condition, 0, len(u.conditions))
-	for _, condition := range u.conditions {
-		conditionsCopy = append(conditionsCopy, condition)
-	}
+	conditionsCopy := u.conditionList()
 
 	// Evaluate conditions without holding the lock
 	for _, condition := range conditionsCopy {
 		cond := condition // Create a copy to avoid memory aliasing
 		result, err := u.evaluateCondition(&cond, session)
 		if err != nil {
-			return false, err
+			session.setDenialHints(conditionHints(&cond, err, time.Now()))
+			return false, fmt.Errorf("session %s: %w", sessionID, err)
 		}
 		if !result {
+			session.setDenialHints(conditionHints(&cond, nil, time.Now()))
 			return false, nil // Any condition fails, deny access
 		}
 	}
+	session.setDenialHints(denialHints{})
 	return true, nil
 }
 
 // evaluateCondition evaluates a single condition against a session.
 func (u *UconEnforcer) evaluateCondition(condition *Condition, session *Session) (bool, error) {
-	switch condition.Name {
-	case "location":
-		return u.checkLocation(condition.Expr, session)
-	case "vip_level":
-		return u.checkVipLevel(condition.Expr, session)
-	default:
-		return false, fmt.Errorf("unknown condition type: %s", condition.Kind)
+	if !conditionApplies(condition, session) {
+		return true, nil
+	}
+	u.mu.RLock()
+	handler, ok := u.conditionHandlers[condition.Name]
+	u.mu.RUnlock()
+	if !ok {
+		err := fmt.Errorf("condition %s: %w for %s", condition.ID, ErrUnknownHandler, condition.Name)
+		if condition.Shadow && shadowed(condition.ID, condition.Rollout, session) {
+			return u.shadowCondition(condition, session, false, err)
+		}
+		return false, err
+	}
+
+	start := time.Now()
+	var result bool
+	err := u.cachedConditionFailure(condition, start)
+	if err == nil {
+		result, err = u.callConditionHandler(handler, condition, session)
+		u.observeLatency(MetricCondition, condition.ID, session, start)
+		u.rememberConditionFailure(condition, err, start)
+	}
+	if condition.Shadow && shadowed(condition.ID, condition.Rollout, session) {
+		return u.shadowCondition(condition, session, result, err)
+	}
+	if err != nil && u.getFailurePolicy() == FailOpen {
+		return true, nil
 	}
+	if err != nil && !errors.Is(err, ErrHandlerPanic) {
+		err = fmt.Errorf("condition %s: %w", condition.ID, err)
+	}
+	return result, err
 }
 
 func (u *UconEnforcer) checkLocation(expr string, session *Session) (bool, error) {
@@ -204,15 +616,15 @@ func (u *UconEnforcer) checkLocation(expr string, session *Session) (bool, error
 }
 
 func (u *UconEnforcer) checkVipLevel(expr string, session *Session) (bool, error) {
-	vipLevel, ok := session.GetAttribute("vip_level").(int)
+	vipLevel, ok := toInt64(session.GetAttribute("vip_level"))
 	if !ok {
 		return false, fmt.Errorf("vip_level attribute not found or not an integer")
 	}
 	requiredLevel, err := strconv.Atoi(expr)
 	if err != nil {
-		return false, fmt.Errorf("invalid vip_level expression: %v", err)
+		return false, fmt.Errorf("invalid vip_level expression: %w", err)
 	}
-	return vipLevel >= requiredLevel, nil
+	return vipLevel >= int64(requiredLevel), nil
 }
 
 // AddObligation adds an obligation.
@@ -220,10 +632,75 @@ func (u *UconEnforcer) AddObligation(obligation *Obligation) error {
 	if obligation == nil {
 		return errors.New("obligation cannot be nil")
 	}
-	u.obligations[obligation.ID] = *obligation
+	obl, err := prepareObligation(obligation)
+	if err != nil {
+		return err
+	}
+	u.mu.Lock()
+	obligations := make(map[string]Obligation, len(u.obligations)+1)
+	for id, existing := range u.obligations {
+		obligations[id] = existing
+	}
+	obligations[obl.ID] = obl
+	if _, err := orderObligations(obligations); err != nil {
+		u.mu.Unlock()
+		return fmt.Errorf("obligation %s: %w", obl.ID, err)
+	}
+	u.obligations[obl.ID] = obl
+	u.orderRulesLocked()
+	u.mu.Unlock()
+	u.InvalidateDecisions()
+	return nil
+}
+
+// prepareObligation validates an obligation and returns a copy with its
+// schedule parsed.
+func prepareObligation(obligation *Obligation) (Obligation, error) {
+	if err := validateRollout(obligation.Rollout); err != nil {
+		return Obligation{}, fmt.Errorf("obligation %s: %w", obligation.ID, err)
+	}
+	obl := *obligation
+	if obl.Schedule != "" {
+		if obl.Kind != "ongoing" {
+			return Obligation{}, fmt.Errorf("obligation %s: schedule is only supported for ongoing obligations", obl.ID)
+		}
+		schedule, err := parseCronSpec(obl.Schedule)
+		if err != nil {
+			return Obligation{}, fmt.Errorf("obligation %s: %w", obl.ID, err)
+		}
+		obl.schedule = schedule
+	}
+	return obl, nil
+}
+
+// GetObligations returns all obligations in execution order: dependencies
+// first, then by ID.
+func (u *UconEnforcer) GetObligations() []Obligation {
+	return slices.Clone(u.obligationList())
+}
+
+// RemoveObligation removes an obligation.
+func (u *UconEnforcer) RemoveObligation(id string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if _, exists := u.obligations[id]; !exists {
+		return fmt.Errorf("cannot find obligation with id %s", id)
+	}
+	delete(u.obligations, id)
+	u.orderRulesLocked()
+	u.InvalidateDecisions()
 	return nil
 }
 
+// obligationList returns the obligations in execution order, dependencies
+// first, then by ID, to be executed without holding the lock. The slice is
+// shared and must not be modified.
+func (u *UconEnforcer) obligationList() []Obligation {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.obligationOrder
+}
+
 // ExecuteObligations executes all obligations for a session (backward compatibility).
 func (u *UconEnforcer) ExecuteObligations(sessionID string) error {
 	session, err := u.GetSession(sessionID)
@@ -231,11 +708,11 @@ func (u *UconEnforcer) ExecuteObligations(sessionID string) error {
 		return err
 	}
 
-	for _, obligation := range u.obligations {
+	for _, obligation := range u.obligationList() {
 		obl := obligation // Create a copy to avoid memory aliasing
 		err := u.executeObligation(&obl, session)
 		if err != nil {
-			return fmt.Errorf("failed to execute obligation %s: %v", obl.ID, err)
+			return fmt.Errorf("failed to execute obligation %s of session %s: %w", obl.ID, sessionID, err)
 		}
 	}
 
@@ -249,12 +726,12 @@ func (u *UconEnforcer) ExecuteObligationsByType(sessionID string, kind string) e
 		return err
 	}
 
-	for _, obligation := range u.obligations {
+	for _, obligation := range u.obligationList() {
 		if obligation.Kind == kind {
 			obl := obligation // Create a copy to avoid memory aliasing
 			err := u.executeObligation(&obl, session)
 			if err != nil {
-				return fmt.Errorf("failed to execute %s obligation %s: %v", kind, obl.ID, err)
+				return fmt.Errorf("failed to execute %s obligation %s of session %s: %w", kind, obl.ID, sessionID, err)
 			}
 		}
 	}
@@ -262,18 +739,119 @@ func (u *UconEnforcer) ExecuteObligationsByType(sessionID string, kind string) e
 	return nil
 }
 
+// evaluateOngoingConditions evaluates the conditions at a monitoring tick. A
+// failing condition with a Dwell only counts once it has failed continuously
+// for that long; state tracks when each condition started failing. Pure
+// conditions are only evaluated again once the session's attributes change.
+// Results are recorded in trace, if not nil.
+func (u *UconEnforcer) evaluateOngoingConditions(session *Session, now time.Time, state *monitorState, trace *TraceEntry) (bool, error) {
+	failingSince := state.failingSince
+	for _, condition := range u.conditionList() {
+		cond := condition // Create a copy to avoid memory aliasing
+		result, cached, err := u.evaluateCachedCondition(&cond, session, state.results)
+		trace.addCondition(&cond, result, err)
+		if cached {
+			trace.markCached()
+		}
+		if err != nil {
+			return false, err
+		}
+		if result {
+			delete(failingSince, cond.ID)
+			continue
+		}
+		if cond.Dwell <= 0 {
+			return false, nil
+		}
+		since, failing := failingSince[cond.ID]
+		if !failing {
+			failingSince[cond.ID] = now
+			continue
+		}
+		if now.Sub(since) >= cond.Dwell {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// executeOngoingObligations executes the ongoing obligations due at the given
+// monitoring tick. Unscheduled obligations run on every tick, scheduled ones
+// only when their cron spec fires; nextRun tracks the per-session fire times.
+// Outcomes are recorded in trace, if not nil.
+func (u *UconEnforcer) executeOngoingObligations(session *Session, now time.Time, nextRun map[string]time.Time, trace *TraceEntry) error {
+	for _, obligation := range u.obligationList() {
+		if obligation.Kind != "ongoing" {
+			continue
+		}
+		if obligation.schedule != nil {
+			due, scheduled := nextRun[obligation.ID]
+			if !scheduled {
+				nextRun[obligation.ID] = obligation.schedule.next(now)
+				continue
+			}
+			if due.IsZero() || now.Before(due) {
+				continue
+			}
+			nextRun[obligation.ID] = obligation.schedule.next(now)
+		}
+
+		obl := obligation // Create a copy to avoid memory aliasing
+		err := u.executeObligation(&obl, session)
+		trace.addObligation(&obl, err)
+		if err != nil {
+			return fmt.Errorf("failed to execute ongoing obligation %s: %w", obl.ID, err)
+		}
+	}
+
+	return nil
+}
+
 // executeObligation executes a single obligation.
 func (u *UconEnforcer) executeObligation(obligation *Obligation, session *Session) error {
-	switch obligation.Name {
-	case "user_authentication":
-		return u.executeUserAuthentication(obligation.Expr, session)
-	case "vip_validation":
-		return u.executeVipValidation(obligation.Expr, session)
-	case "access_logging":
-		return u.executeAccessLogging(obligation.Expr, session)
-	default:
-		return fmt.Errorf("unknown obligation name: %s", obligation.Name)
+	if !obligationApplies(obligation, session) {
+		return nil
+	}
+	u.mu.RLock()
+	handler, ok := u.obligationHandlers[obligation.Name]
+	u.mu.RUnlock()
+	if !ok {
+		err := fmt.Errorf("%w for %s", ErrUnknownHandler, obligation.Name)
+		if obligation.Shadow && shadowed(obligation.ID, obligation.Rollout, session) {
+			return u.shadowObligation(obligation, session, err)
+		}
+		return err
+	}
+
+	attempt := session.obligationAttempt(obligation)
+	key := IdempotencyKey(session.GetId(), obligation.ID, obligation.Kind, attempt)
+
+	// A shadow obligation is dry-run: its handler works on a detached copy of
+	// the session and skips effects outside it, see Session.IsDryRun.
+	target := session
+	shadow := obligation.Shadow && shadowed(obligation.ID, obligation.Rollout, session)
+	if shadow {
+		target = session.dryRunCopy()
 	}
+
+	start := time.Now()
+	err := u.callObligationHandler(handler, obligation, target, key)
+	if err == nil && !shadow {
+		if perr := session.completeObligation(obligation, attempt); perr != nil {
+			u.logger.Log(LevelWarn, "failed to record obligation attempt", map[string]interface{}{"session": session.GetId(), "obligation": obligation.ID, "error": perr.Error()})
+		}
+	}
+	u.observeLatency(MetricObligation, obligation.ID, session, start)
+	if shadow {
+		return u.shadowObligation(obligation, session, err)
+	}
+	if err != nil && u.getFailurePolicy() == FailOpen {
+		return nil
+	}
+	if err == nil && obligation.Deadline > 0 {
+		u.requireFulfillment(obligation, session, start)
+	}
+	return err
 }
 
 func (u *UconEnforcer) executeUserAuthentication(expr string, session *Session) error {
@@ -290,7 +868,7 @@ func (u *UconEnforcer) executeUserAuthentication(expr string, session *Session)
 			session.GetSubject(), expr, expectedValue, actualValue)
 	}
 
-	fmt.Printf("[AUTH] User %s authentication verification passed: %s\n", session.GetSubject(), expr)
+	u.logger.Log(LevelInfo, "[AUTH] authentication verification passed: "+expr, sessionFields(session))
 	return nil
 }
 
@@ -300,38 +878,64 @@ func (u *UconEnforcer) executeVipValidation(expr string, session *Session) error
 	if vipLevel == "" {
 		return fmt.Errorf("user %s is not a VIP user", session.GetSubject())
 	}
-	if vipExpiry == "expired" {
-		return fmt.Errorf("user %s VIP membership has expired", session.GetSubject())
+	if vipExpiry != nil {
+		expiry, err := toTime(vipExpiry)
+		if err != nil {
+			return fmt.Errorf("user %s has an invalid VIP expiry: %w", session.GetSubject(), err)
+		}
+		if !time.Now().Before(expiry) {
+			return fmt.Errorf("user %s VIP membership has expired", session.GetSubject())
+		}
 	}
 
-	fmt.Printf("[VIP] User %s VIP status is valid (level: %s)\n", session.GetSubject(), vipLevel)
-	return nil
-}
-
-func (u *UconEnforcer) executeAccessLogging(expr string, session *Session) error {
-	fmt.Printf("[ACCESS LOG] %s: %s -> %s\n", expr, session.GetSubject(), session.GetObject())
+	fields := sessionFields(session)
+	fields["vip_level"] = vipLevel
+	u.logger.Log(LevelInfo, "[VIP] VIP status is valid", fields)
 	return nil
 }
 
-// StartMonitoring starts monitoring a session.
+// StartMonitoring starts monitoring a session. Under WithLazyMonitoring,
+// sessions that need no monitoring are governed by their TTL instead.
 func (u *UconEnforcer) StartMonitoring(sessionID string) error {
 	// Check if session exists
 	session, err := u.GetSession(sessionID)
 	if err != nil {
-		return errors.New("session not found")
+		return err
+	}
+	if u.governLazily(session) {
+		return nil
 	}
 
-	u.mu.Lock()
-	if u.monitoringActive[sessionID] {
+	interval, err := u.reserveMonitoring(session)
+	if err != nil {
+		return fmt.Errorf("cannot monitor session %s: %w", sessionID, err)
+	}
+	if interval == 0 {
 		return nil
 	}
-	u.monitoringActive[sessionID] = true
-	u.mu.Unlock()
+	u.startMonitoring(session, interval)
+	return nil
+}
 
-	go u.monitorSession(session)
-	fmt.Println("[MONITOR] Monitoring started")
+// startMonitoring starts the monitoring loop of a session holding a
+// monitoring slot, unless another node holds its monitoring lease.
+func (u *UconEnforcer) startMonitoring(session *Session, interval time.Duration) {
+	if !u.acquireLease(session) {
+		// Another node holds the lease and monitors the session.
+		u.releaseMonitoring(session.GetId())
+		fields := sessionFields(session)
+		fields["node"] = u.leaseNode
+		u.logger.Log(LevelDebug, "session monitored by another node", fields)
+		return
+	}
+	if err := session.setMonitored(true); err != nil {
+		u.logger.Log(LevelWarn, "failed to persist monitored session", map[string]interface{}{"session": session.GetId(), "error": err.Error()})
+	}
 
-	return nil
+	go u.monitorSession(session, interval, session.nextMonitorGeneration())
+	fields := sessionFields(session)
+	fields["interval"] = interval
+	u.logger.Log(LevelDebug, "monitoring started", fields)
 }
 
 // StopMonitoring stops monitoring a session.
@@ -342,56 +946,199 @@ func (u *UconEnforcer) StopMonitoring(sessionID string) error {
 	}
 
 	if err := u.ExecuteObligationsByType(sessionID, "post"); err != nil {
-		fmt.Printf("Warning: Failed to execute post-access obligations during session revocation: %v\n", err)
+		u.logger.Log(LevelWarn, "post obligations failed during session revocation", map[string]interface{}{"session": sessionID, "error": err.Error()})
 	}
 
 	_ = session.Stop(NormalStopReason)
 
-	fmt.Printf("[MONITOR] Stopped monitoring session %s for %s\n", sessionID, session.GetSubject())
+	u.logger.Log(LevelDebug, "monitoring stopped", sessionFields(session))
 	return nil
 }
 
+// monitorState is the evaluation state of one monitored session. Only one
+// evaluation of a session runs at a time, so it needs no locking.
+type monitorState struct {
+	// Next fire time of each scheduled ongoing obligation for this session.
+	nextRun map[string]time.Time
+	// Time each condition with a dwell time started failing.
+	failingSince map[string]time.Time
+	// Last result of each pure condition, see evaluateCachedCondition.
+	results map[string]cachedCondition
+	cycle   int
+	// Trace of the current evaluation, reused across ticks, see resetTrace.
+	trace TraceEntry
+	// When the monitoring lease was last renewed, see WithMonitoringLeases.
+	leaseRenewed time.Time
+	// When the status of the session was last logged, and the evaluations
+	// it passed since.
+	loggedAt    time.Time
+	evaluations int
+}
+
+// evaluation is the outcome of one monitoring evaluation. reason is set when
+// the session must be revoked.
+type evaluation struct {
+	trace  *TraceEntry
+	reason string
+}
+
 // monitorSession continuously monitors a session.
-func (u *UconEnforcer) monitorSession(session *Session) {
-	ticker := time.NewTicker(200 * time.Millisecond)
+func (u *UconEnforcer) monitorSession(session *Session, interval time.Duration, generation uint64) {
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
-	for range ticker.C {
+	state := &monitorState{
+		nextRun:      make(map[string]time.Time),
+		failingSince: make(map[string]time.Time),
+		results:      make(map[string]cachedCondition),
+		leaseRenewed: time.Now(),
+	}
+	// An evaluation still running past its deadline, and whether its result
+	// is to be discarded.
+	var pending chan evaluation
+	var discard bool
+
+	wake := make(chan struct{}, 1)
+	defer u.registerWakeup(session.GetId(), wake)()
+
+	for {
+		var now time.Time
+		triggered := false
+		select {
+		case now = <-ticker.C:
+		case <-wake:
+			now, triggered = time.Now(), true
+		}
+
 		// Check if monitoring is still active
+		u.mu.RLock()
 		isActive := u.monitoringActive[session.GetId()]
-		if !isActive {
+		u.mu.RUnlock()
+		if !isActive || session.currentMonitorGeneration() != generation {
 			return
 		}
 
-		if !session.IfActive() {
-			u.mu.Lock()
-			u.monitoringActive[session.GetId()] = false
-			u.mu.Unlock()
+		if !u.checkSession(session, now, state) {
 			return
 		}
 
-		// Check conditions during ongoing access
-		conditionsOk, err := u.EvaluateConditions(session.GetId())
-		if err != nil {
-			reason := fmt.Sprintf("Error evaluating conditions for session %s: %v\n", session.GetId(), err)
-			_ = session.Stop(reason)
-			return
+		if pending != nil {
+			select {
+			case result := <-pending:
+				pending = nil
+				if !discard && u.finishEvaluation(session, result, state) {
+					return
+				}
+			default:
+				// Do not pile up evaluations behind a slow one.
+				continue
+			}
 		}
 
-		if !conditionsOk {
-			reason := fmt.Sprintf("Conditions no longer met for session %s, revoking...\n", session.GetId())
-			_ = session.Stop(reason)
-			return
+		// A trigger only re-evaluates the conditions, under the same deadline
+		// as a tick.
+		evaluate := u.evaluateSession
+		if triggered {
+			evaluate = u.evaluateTriggered
 		}
-
-		// Execute ongoing obligations during continuous authorization
-		err = u.ExecuteObligationsByType(session.GetId(), "ongoing")
-		if err != nil {
-			reason := fmt.Sprintf("Failed to execute ongoing obligations for session %s: %v\n", session.GetId(), err)
-			_ = session.Stop(reason)
+		if u.evaluationDeadline <= 0 {
+			if u.finishEvaluation(session, evaluate(session, now, state), state) {
+				return
+			}
+			continue
+		}
+		results := make(chan evaluation, 1)
+		go func(now time.Time) {
+			results <- evaluate(session, now, state)
+		}(now)
+		result, overrun := u.awaitEvaluation(session, now, results)
+		if overrun {
+			pending, discard = results, u.overrunPolicy == OverrunFailOpen
+			continue
+		}
+		if u.finishEvaluation(session, result, state) {
 			return
 		}
+	}
+}
+
+// checkSession runs the checks of a monitoring tick that precede the
+// evaluation, and ends monitoring if one fails. It returns whether the
+// session is still monitored.
+func (u *UconEnforcer) checkSession(session *Session, now time.Time, state *monitorState) bool {
+	if !session.IfActive() {
+		u.releaseMonitoring(session.GetId())
+		return false
+	}
+	if !u.renewLease(session, now, state) {
+		u.loseLease(session)
+		return false
+	}
+
+	if u.heartbeatExpired(session, now) {
+		reason := fmt.Sprintf("Missed heartbeats for session %s, revoking...\n", session.GetId())
+		u.stopMonitored(session, &TraceEntry{Time: now}, reason)
+		return false
+	}
+	if u.breakGlassExpired(session, now) {
+		u.stopMonitored(session, &TraceEntry{Time: now}, BreakGlassExpiredReason)
+		return false
+	}
+	if id := u.overdueObligation(session, now); id != "" {
+		reason := fmt.Sprintf("Obligation %s not fulfilled in time for session %s, revoking...\n", id, session.GetId())
+		u.stopMonitored(session, &TraceEntry{Time: now}, reason)
+		return false
+	}
+	if window, ok := u.checkMaintenance(session, now); ok {
+		u.endForMaintenance(session, &TraceEntry{Time: now}, window)
+		return false
+	}
+	return true
+}
+
+// evaluateSession runs one monitoring evaluation: it refreshes the provider
+// attributes, checks the conditions, consults the analyzer and executes the
+// ongoing obligations.
+func (u *UconEnforcer) evaluateSession(session *Session, now time.Time, state *monitorState) evaluation {
+	trace := state.resetTrace(now)
+	revoke := func(format string, args ...interface{}) evaluation {
+		return evaluation{trace: trace, reason: fmt.Sprintf(format, args...)}
+	}
+
+	// Refresh provider attributes, then check conditions during ongoing access
+	if err := u.refreshAttributes(session); err != nil {
+		return revoke("Attribute provider check failed for session %s: %v\n", session.GetId(), err)
+	}
+	conditionsOk, err := u.evaluateOngoingConditions(session, now, state, trace)
+	if err != nil {
+		return revoke("Error evaluating conditions for session %s: %v\n", session.GetId(), err)
+	}
+
+	if !conditionsOk {
+		return revoke("Conditions no longer met for session %s, revoking...\n", session.GetId())
+	}
+
+	state.cycle++
+	if u.analyzeSession(session, now, state.cycle, trace) {
+		return revoke("Analyzer revoked session %s, revoking...\n", session.GetId())
+	}
+
+	// Execute ongoing obligations during continuous authorization
+	err = u.executeOngoingObligations(session, now, state.nextRun, trace)
+	if err != nil {
+		return revoke("Failed to execute ongoing obligations for session %s: %v\n", session.GetId(), err)
+	}
+	return evaluation{trace: trace}
+}
 
-		fmt.Printf("[MONITOR] Session %s is still valid\n", session.GetId())
+// finishEvaluation records an evaluation and revokes the session if the
+// evaluation requires it. It returns whether the session was revoked.
+func (u *UconEnforcer) finishEvaluation(session *Session, result evaluation, state *monitorState) bool {
+	if result.reason != "" {
+		u.stopMonitored(session, result.trace, result.reason)
+		return true
 	}
+	u.recordTrace(session, result.trace)
+	u.logMonitorStatus(session, state, result.trace.Time)
+	return false
 }
@@ -14,11 +14,209 @@
 
 package ucon
 
-import "testing"
+// Benchmarks of the session manager and of monitoring. They guard the hot
+// paths against regressions and give the contention profile that locking
+// changes are judged by; run them with several GOMAXPROCS values and a mutex
+// profile, e.g.
+//
+//	go test -run '^$' -bench . -benchmem -cpu 1,4,16 -mutexprofile mutex.out
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newBenchmarkEnforcer(b *testing.B) *UconEnforcer {
+	b.Helper()
+	base := GetUconEnforcer().(*UconEnforcer).Enforcer
+	return NewUconEnforcer(base, WithLogger(&DefaultLogger{Level: LevelError, Writer: io.Discard})).(*UconEnforcer)
+}
+
+func createBenchmarkSessions(b *testing.B, u *UconEnforcer, n int) []string {
+	b.Helper()
+	ids := make([]string, n)
+	for i := range ids {
+		id, err := u.CreateSession("alice", "read", "document1", map[string]interface{}{"department": "eng", "count": int64(0)})
+		if err != nil {
+			b.Fatal(err)
+		}
+		ids[i] = id
+	}
+	return ids
+}
+
+func newBenchmarkMonitorState() *monitorState {
+	return &monitorState{
+		nextRun:      make(map[string]time.Time),
+		failingSince: make(map[string]time.Time),
+		results:      make(map[string]cachedCondition),
+	}
+}
+
+func BenchmarkCreateSession(b *testing.B) {
+	u := newBenchmarkEnforcer(b)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := u.CreateSession("alice", "read", "document1", nil); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+func BenchmarkGetSession(b *testing.B) {
+	u := newBenchmarkEnforcer(b)
+	ids := createBenchmarkSessions(b, u, 1000)
+	var next atomic.Uint64
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := u.GetSession(ids[next.Add(1)%uint64(len(ids))]); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+// BenchmarkUpdateAttribute updates the attributes of distinct sessions, which
+// only contend on the session manager.
+func BenchmarkUpdateAttribute(b *testing.B) {
+	u := newBenchmarkEnforcer(b)
+	ids := createBenchmarkSessions(b, u, 1000)
+	var next atomic.Uint64
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := int64(0)
+		for pb.Next() {
+			i++
+			if err := u.UpdateSessionAttribute(ids[next.Add(1)%uint64(len(ids))], "count", i); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+// BenchmarkSharedSession has request paths checking one session while its
+// attributes are written, the contention between IfActive and attribute
+// writers.
+func BenchmarkSharedSession(b *testing.B) {
+	for _, writers := range []int{0, 1, 4} {
+		b.Run(fmt.Sprintf("writers=%d", writers), func(b *testing.B) {
+			u := newBenchmarkEnforcer(b)
+			session, _ := u.GetSession(createBenchmarkSessions(b, u, 1)[0])
+			done := make(chan struct{})
+			for w := 0; w < writers; w++ {
+				go func() {
+					for i := int64(0); ; i++ {
+						select {
+						case <-done:
+							return
+						default:
+							_ = session.UpdateAttribute("count", i)
+						}
+					}
+				}()
+			}
+			defer close(done)
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if !session.IfActive() {
+						b.Error("Expected the session to be active")
+						return
+					}
+				}
+			})
+		})
+	}
+}
+
+// BenchmarkEvaluateSession measures one monitoring evaluation of a session
+// that keeps passing its conditions, the cost paid per session per tick.
+func BenchmarkEvaluateSession(b *testing.B) {
+	for _, conditions := range []int{1, 10, 50} {
+		b.Run(fmt.Sprintf("conditions=%d", conditions), func(b *testing.B) {
+			u := newBenchmarkEnforcer(b)
+			for i := 0; i < conditions; i++ {
+				_ = u.AddCondition(&Condition{ID: fmt.Sprintf("dept_%d", i), Name: "attribute_equals", Kind: "always", Expr: "department:eng"})
+			}
+			session, _ := u.GetSession(createBenchmarkSessions(b, u, 1)[0])
+			state := newBenchmarkMonitorState()
+			now := time.Now()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if result := u.evaluateSession(session, now, state); result.reason != "" {
+					b.Fatal(result.reason)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkMonitorTick measures a whole monitoring tick of a stable session:
+// the checks, the evaluation and recording its trace. Without conditions it
+// is the overhead of monitoring itself.
+func BenchmarkMonitorTick(b *testing.B) {
+	for _, conditions := range []int{0, 1, 10} {
+		b.Run(fmt.Sprintf("conditions=%d", conditions), func(b *testing.B) {
+			u := newBenchmarkEnforcer(b)
+			for i := 0; i < conditions; i++ {
+				_ = u.AddCondition(&Condition{ID: fmt.Sprintf("dept_%d", i), Name: "attribute_equals", Kind: "always", Expr: "department:eng"})
+			}
+			session, _ := u.GetSession(createBenchmarkSessions(b, u, 1)[0])
+			state := newBenchmarkMonitorState()
+			now := time.Now()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if !u.checkSession(session, now, state) || u.finishEvaluation(session, u.evaluateSession(session, now, state), state) {
+					b.Fatal("Expected the session to stay monitored")
+				}
+			}
+		})
+	}
+}
 
-// BenchmarkPlaceholder is a placeholder benchmark. It doesn't test any real logic yet.
-func BenchmarkPlaceholder(b *testing.B) {
-	for i := 0; i < b.N; i++ {
-		// Placeholder operation: do nothing
+// BenchmarkMonitoredSessions measures EnforceWithSession while many sessions
+// are monitored in the background.
+func BenchmarkMonitoredSessions(b *testing.B) {
+	for _, monitored := range []int{100, 1000} {
+		b.Run(fmt.Sprintf("sessions=%d", monitored), func(b *testing.B) {
+			u := newBenchmarkEnforcer(b)
+			_ = u.AddCondition(&Condition{ID: "dept", Name: "attribute_equals", Kind: "always", Expr: "department:eng"})
+			ids := createBenchmarkSessions(b, u, monitored)
+			for _, id := range ids {
+				if err := u.StartMonitoring(id); err != nil {
+					b.Fatal(err)
+				}
+			}
+			defer func() {
+				for _, id := range ids {
+					_ = u.StopMonitoring(id)
+				}
+			}()
+			var next atomic.Uint64
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if _, err := u.EnforceWithSession(ids[next.Add(1)%uint64(len(ids))]); err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+		})
 	}
 }
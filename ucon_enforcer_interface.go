@@ -15,6 +15,10 @@
 package ucon
 
 import (
+	"context"
+	"crypto/tls"
+	"time"
+
 	"github.com/casbin/casbin/v2"
 )
 
@@ -25,23 +29,134 @@ type IUconEnforcer interface {
 
 	// Enhanced enforcement with session context
 	EnforceWithSession(sessionID string) (*Session, error)
+	Decide(sessionID string) (*Decision, error)
+	ReserveAccess(sessionID string, ttl time.Duration) (string, error)
+	CommitAccess(reservationID string) (*Session, error)
+	CancelReservation(reservationID string) error
+	WaitlistPosition(sessionID string) int
+	LeaveWaitlist(sessionID string) error
+	InvalidateDecisions()
 
 	// Session management
 	CreateSession(sub string, act string, obj string, attributes map[string]interface{}) (string, error)
+	CreateSessionWithPurpose(sub string, act string, obj string, purpose string, attributes map[string]interface{}) (string, error)
+	CreateBreakGlassSession(sub string, act string, obj string, justification string, attributes map[string]interface{}) (string, error)
+	GetBreakGlassReviews(pendingOnly bool) []BreakGlassReview
+	ReviewBreakGlass(sessionID string, reviewer string, notes string) error
+	AddSessionValidator(validator SessionValidator)
+	RegisterAttributeEnricher(name string, enricher AttributeEnricher) error
 	GetSession(sessionID string) (*Session, error)
+	GetSessions() []*Session
+	GetMySessions(subject string) []SubjectSession
+	RevokeMySessions(subject string, reason string, sessionIDs ...string) (int, error)
+	PreviewRevocation(selector SessionSelector) (*RevocationImpact, error)
+	RevokeSessions(selector SessionSelector, reason string) (int, error)
+	UpdateSubjectAttributes(subject string, attributes map[string]interface{}) (int, error)
+	RevokeSubjectSessions(subject string, reason string) int
+	SetSubjectAttributes(subject string, attributes map[string]interface{}) error
+	GetSubjectAttributes(subject string) map[string]interface{}
+	RemoveSubjectAttributes(subject string)
+	ApplyUserChange(change UserChange) error
+	SyncUsers(ctx context.Context, feed UserFeed) error
+	GetDashboardStats(window time.Duration, limit int) *DashboardStats
+	ActiveSessionCount() int
+	ActiveSessionCountBySubject(subject string) int
+	ActiveSessionCountByObject(object string) int
+	MonitoredSessionCount() int
+	MonitoringCapacity() int
+	GetMetrics() Metrics
+	GetMeter() *Meter
+	GetObjectUsageHistory(object string, timeRange TimeRange) ([]ObjectUsage, error)
+	GetTimeUsage(subject string, class string, period string) (time.Duration, error)
+	RegisterAttributeProvider(name string, provider AttributeProvider) error
+	GetProviderHealth() []ProviderHealth
+	SetObjectAttributes(object string, attributes map[string]interface{}) error
+	RemoveObjectAttributes(object string)
+	RegisterObjectAttributeProvider(name string, provider ObjectAttributeProvider) error
+	GetObjectAttributes(object string) (map[string]interface{}, error)
+	SetEnvironmentAttributes(attributes map[string]interface{})
+	RegisterEnvironmentProvider(name string, provider EnvironmentProvider) error
+	GetEnvironmentAttributes() (map[string]interface{}, error)
+	CheckProviders()
 	UpdateSessionAttribute(sessionID string, key string, val interface{}) error
+	UpdateSessionAttributes(sessionID string, attributes map[string]interface{}) error
+	RefreshSVID(sessionID string, svid interface{}) error
+	BindTLSChannel(sessionID string, state *tls.ConnectionState) error
+	AddTelemetryMapping(mapping TelemetryMapping) error
+	GetTelemetryMappings() []TelemetryMapping
+	IngestTelemetry(reports []TelemetryReport) (int, error)
+	Heartbeat(sessionID string) error
+	GetSessionTrace(sessionID string) ([]TraceEntry, error)
+	PruneHistory() RetentionReport
+	StopRetention()
+	GetEffectiveRules(sessionID string) (*EffectiveRules, error)
+	SimulateSession(request *SessionRequest) (*SimulationResult, error)
+	DumpState() ([]byte, error)
+	LoadState(data []byte) error
+	ExportConfig() ([]byte, error)
+	ImportConfig(data []byte) error
+	DiffConfig(data []byte) (*ConfigDiff, error)
+	ApplyReplicationEvent(event ReplicationEvent) error
+	FollowPrimary(ctx context.Context, url string, failoverAfter time.Duration) error
+	TakeOver() error
+	WatchMonitoringLeases(ctx context.Context) error
+	FulfillObligation(sessionID string, obligationID string, evidence map[string]interface{}) error
+	GetObligationFulfillments(sessionID string) ([]ObligationFulfillment, error)
+	ApproveSession(sessionID string, approver string) error
+	RejectSession(sessionID string, approver string, reason string) error
+	GetApproval(sessionID string) (Approval, bool)
+	GetPendingApprovals() []Approval
+	IssueToken(sessionID string) (string, error)
+	ValidateToken(token string) (*Session, error)
+	IncrementAttribute(sessionID string, key string, delta int64) (int64, error)
+	DeclareAttribute(schema *AttributeSchema) error
+	GetAttributeSchema() []AttributeSchema
 	RevokeSession(sessionID string) error
 
 	// Condition evaluation
 	AddCondition(condition *Condition) error
+	GetConditions() []Condition
+	RemoveCondition(id string) error
+	SetConditionRollout(id string, percent int) error
 	EvaluateConditions(sessionID string) (bool, error)
 
 	// Obligation management
 	AddObligation(obligation *Obligation) error
+	GetObligations() []Obligation
+	RemoveObligation(id string) error
+	SetObligationRollout(id string, percent int) error
 	ExecuteObligations(sessionID string) error
 	ExecuteObligationsByType(sessionID string, phase string) error
 
+	// Rule versioning
+	AddRuleSet(set *RuleSet) error
+	GetRuleSets() []string
+	GetActiveRuleSet() string
+	ActivateRuleSet(version string) error
+	RollbackRuleSet() error
+
+	// Handler registration
+	RegisterConditionHandler(name string, handler ConditionHandler) error
+	RegisterObligationHandler(name string, handler ObligationHandler) error
+	RegisterIdempotentObligationHandler(name string, handler IdempotentObligationHandler) error
+	RegisterContextConditionHandler(name string, handler ContextConditionHandler) error
+	RegisterContextObligationHandler(name string, handler ContextObligationHandler) error
+	SetFailurePolicy(policy FailurePolicy)
+	RegisterFunction(name string, fn ExpressionFunction) error
+
+	// Events
+	AddEventListener(listener EventListener)
+	ReplayDecisions(records []DecisionRecord) (*ReplayReport, error)
+
 	// Continuous monitoring
 	StartMonitoring(sessionID string) error
 	StopMonitoring(sessionID string) error
+	AddMaintenanceWindow(window *MaintenanceWindow) error
+	RemoveMaintenanceWindow(id string) error
+	GetMaintenanceWindows() []MaintenanceWindow
+	SuspendSession(sessionID string, window time.Duration) (string, error)
+	ResumeSession(token string) (*Session, error)
+
+	// Persistence
+	Recover() error
 }
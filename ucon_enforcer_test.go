@@ -15,7 +15,9 @@
 package ucon
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -307,3 +309,154 @@ func TestSessionRefusedDuringAccess(t *testing.T) {
 		t.Error("Expected session to be deleted after revocation")
 	}
 }
+
+func TestUpdateStoppedSession(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	for _, allowed := range []bool{false, true} {
+		var opts []Option
+		if allowed {
+			opts = append(opts, WithStoppedSessionUpdates())
+		}
+		uconE := NewUconEnforcer(e, opts...)
+		sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"screen_locked": false})
+		if err := uconE.UpdateSessionAttribute(sessionID, "screen_locked", true); err != nil {
+			t.Fatal(err)
+		}
+		session, _ := uconE.GetSession(sessionID)
+		_ = session.Stop("revoked")
+
+		err := uconE.UpdateSessionAttribute(sessionID, "screen_locked", false)
+		if allowed != (err == nil) {
+			t.Errorf("allowed=%v: unexpected update result %v", allowed, err)
+		}
+		if !allowed && !errors.Is(err, ErrSessionNotActive) {
+			t.Errorf("Expected ErrSessionNotActive, got %v", err)
+		}
+		if err := session.UpdateAttributeIfActive("screen_locked", false); !errors.Is(err, ErrSessionNotActive) {
+			t.Errorf("Expected UpdateAttributeIfActive to refuse a stopped session, got %v", err)
+		}
+	}
+}
+
+func TestGrantSource(t *testing.T) {
+	store, _ := NewFileSessionStore(t.TempDir())
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithSessionStore(store))
+
+	sessionID, _ := uconE.CreateSession("bob", "read", "document1", nil)
+	session, _ := uconE.GetSession(sessionID)
+	if session.GetGrantSource() != nil {
+		t.Error("Expected no grant source before enforcement")
+	}
+	if granted, _ := uconE.EnforceWithSession(sessionID); granted == nil {
+		t.Fatal("Expected access")
+	}
+	defer func() { _ = uconE.StopMonitoring(sessionID) }()
+
+	want := "bob document1 read"
+	if got := strings.Join(session.GetGrantSource(), " "); got != want {
+		t.Errorf("Expected grant source %q, got %q", want, got)
+	}
+	records, _ := store.LoadSessions()
+	if len(records) != 1 || strings.Join(records[0].GrantSource, " ") != want {
+		t.Errorf("Expected the grant source to be persisted, got %+v", records)
+	}
+
+	// A grant source that cannot be persisted is logged, not printed.
+	flaky := &flakyStore{}
+	logger := &countingLogger{}
+	uconE = NewUconEnforcer(e, WithSessionStore(flaky), WithLogger(logger))
+	sessionID, _ = uconE.CreateSession("bob", "read", "document1", nil)
+	flaky.setDown(true)
+	if granted, _ := uconE.EnforceWithSession(sessionID); granted == nil {
+		t.Fatal("Expected access")
+	}
+	_ = uconE.StopMonitoring(sessionID)
+	if n := logger.count("failed to persist grant source"); n != 1 {
+		t.Errorf("Expected the failed write to be logged once, got %d", n)
+	}
+}
+
+// recordingSessionManager is an ISessionManager test double building its
+// sessions with NewSession.
+type recordingSessionManager struct {
+	*SessionManager
+	created []string
+	onStop  func(s *Session)
+}
+
+func (m *recordingSessionManager) SetStopHook(onStop func(s *Session)) {
+	m.onStop = onStop
+	m.SessionManager.SetStopHook(onStop)
+}
+
+func (m *recordingSessionManager) CreateSessionWithPurpose(sub string, act string, obj string, purpose string, attributes map[string]interface{}) (string, error) {
+	id := fmt.Sprintf("custom_%d", len(m.created)+1)
+	session, _ := m.RestoreSession(&SessionRecord{
+		ID: id, Subject: sub, Action: act, Object: obj, Purpose: purpose,
+		Attributes: attributes, Active: true, StartTime: time.Now(),
+	})
+	if session.GetId() != id {
+		return "", fmt.Errorf("session %s already exists", id)
+	}
+	m.created = append(m.created, id)
+	return id, nil
+}
+
+func (m *recordingSessionManager) CreateSession(sub string, act string, obj string, attributes map[string]interface{}) (string, error) {
+	return m.CreateSessionWithPurpose(sub, act, obj, "", attributes)
+}
+
+func TestCustomSessionManager(t *testing.T) {
+	manager := &recordingSessionManager{SessionManager: NewSessionManager()}
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithSessionManager(manager))
+	if manager.onStop == nil {
+		t.Fatal("Expected the enforcer to set the stop hook")
+	}
+
+	sessionID, err := uconE.CreateSession("alice", "read", "document1", nil)
+	if err != nil || sessionID != "custom_1" {
+		t.Fatalf("Expected the custom manager to create the session, got %q, %v", sessionID, err)
+	}
+	if _, err := uconE.EnforceWithSession(sessionID); err != nil {
+		t.Fatalf("EnforceWithSession: %v", err)
+	}
+	if err := uconE.StopMonitoring(sessionID); err != nil {
+		t.Fatalf("StopMonitoring: %v", err)
+	}
+	if err := uconE.UpdateSessionAttribute(sessionID, "role", "user"); !errors.Is(err, ErrSessionNotActive) {
+		t.Errorf("Expected the stopped session to be refused, got %v", err)
+	}
+
+	session := NewSession(&SessionRecord{ID: "built", Subject: "bob", Active: true}, nil, nil)
+	if session.GetSubject() != "bob" || !session.IfActive() || session.GetAttribute("role") != nil {
+		t.Errorf("NewSession built an unexpected session %+v", session.ToRecord())
+	}
+}
+
+func TestStableTickAllocations(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	u := NewUconEnforcer(e, WithTraceSize(4), WithMonitorLogInterval(-1)).(*UconEnforcer)
+	_ = u.AddCondition(&Condition{ID: "dept", Name: "attribute_equals", Kind: "always", Expr: "department:eng"})
+	sessionID, _ := u.CreateSession("alice", "read", "document1", map[string]interface{}{"department": "eng"})
+	session, _ := u.GetSession(sessionID)
+	state := &monitorState{
+		nextRun:      make(map[string]time.Time),
+		failingSince: make(map[string]time.Time),
+		results:      make(map[string]cachedCondition),
+	}
+	now := time.Now()
+	tick := func() {
+		if !u.checkSession(session, now, state) || u.finishEvaluation(session, u.evaluateSession(session, now, state), state) {
+			t.Fatal("Expected the session to stay monitored")
+		}
+	}
+	// Fill the trace ring buffer first.
+	for i := 0; i < 4; i++ {
+		tick()
+	}
+	if allocs := testing.AllocsPerRun(100, tick); allocs != 0 {
+		t.Errorf("Expected a stable tick not to allocate, got %v allocations", allocs)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// ObjectUsage is one entry of the usage ledger: a granted session that used
+// an object and has ended.
+type ObjectUsage struct {
+	Object    string `json:"object"`
+	SessionID string `json:"session_id"`
+	Subject   string `json:"subject"`
+	Action    string `json:"action"`
+	Purpose   string `json:"purpose,omitempty"`
+	// StartTime is when the session was first granted.
+	StartTime       time.Time `json:"start_time"`
+	EndTime         time.Time `json:"end_time"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	// Rule is the policy rule that authorized the usage.
+	Rule       []string `json:"rule,omitempty"`
+	StopReason string   `json:"stop_reason,omitempty"`
+}
+
+// TimeRange selects ledger entries whose usage overlaps it. A nil From or To
+// leaves that end open.
+type TimeRange struct {
+	From *time.Time `json:"from,omitempty"`
+	To   *time.Time `json:"to,omitempty"`
+}
+
+func (r TimeRange) overlaps(start time.Time, end time.Time) bool {
+	return (r.From == nil || !end.Before(*r.From)) && (r.To == nil || !start.After(*r.To))
+}
+
+// UsageLedger is an append-only record of object usage.
+type UsageLedger interface {
+	AppendUsage(usage ObjectUsage) error
+	// ObjectUsage returns the entries of object overlapping timeRange,
+	// oldest first.
+	ObjectUsage(object string, timeRange TimeRange) ([]ObjectUsage, error)
+}
+
+// UsageLedgerPruner is implemented by UsageLedgers that WithRetention can
+// prune, such as InMemoryUsageLedger.
+type UsageLedgerPruner interface {
+	// PruneUsage removes the oldest entries of each object exceeding policy
+	// and returns how many it removed.
+	PruneUsage(policy RetentionPolicy, now time.Time) int
+}
+
+// InMemoryUsageLedger is a UsageLedger keeping the entries in memory.
+type InMemoryUsageLedger struct {
+	entries map[string][]ObjectUsage
+	mu      sync.RWMutex
+}
+
+// NewInMemoryUsageLedger creates an empty InMemoryUsageLedger.
+func NewInMemoryUsageLedger() *InMemoryUsageLedger {
+	return &InMemoryUsageLedger{entries: make(map[string][]ObjectUsage)}
+}
+
+// AppendUsage implements UsageLedger.
+func (l *InMemoryUsageLedger) AppendUsage(usage ObjectUsage) error {
+	usage.Rule = append([]string(nil), usage.Rule...)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries[usage.Object] = append(l.entries[usage.Object], usage)
+	return nil
+}
+
+// ObjectUsage implements UsageLedger.
+func (l *InMemoryUsageLedger) ObjectUsage(object string, timeRange TimeRange) ([]ObjectUsage, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	usages := []ObjectUsage{}
+	for _, usage := range l.entries[object] {
+		if timeRange.overlaps(usage.StartTime, usage.EndTime) {
+			usage.Rule = append([]string(nil), usage.Rule...)
+			usages = append(usages, usage)
+		}
+	}
+	sort.SliceStable(usages, func(i, j int) bool { return usages[i].StartTime.Before(usages[j].StartTime) })
+	return usages, nil
+}
+
+// PruneUsage implements UsageLedgerPruner. Entries are appended when sessions
+// stop, so they are ordered by end time.
+func (l *InMemoryUsageLedger) PruneUsage(policy RetentionPolicy, now time.Time) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	pruned := 0
+	for object, usages := range l.entries {
+		times := make([]time.Time, len(usages))
+		for i := range usages {
+			times[i] = usages[i].EndTime
+		}
+		n := policy.excess(times, now)
+		if n == 0 {
+			continue
+		}
+		if n == len(usages) {
+			delete(l.entries, object)
+		} else {
+			l.entries[object] = append([]ObjectUsage(nil), usages[n:]...)
+		}
+		pruned += n
+	}
+	return pruned
+}
+
+// WithUsageLedger replaces the default InMemoryUsageLedger, e.g. with one
+// backed by a database so the history survives restarts.
+func WithUsageLedger(ledger UsageLedger) Option {
+	return func(u *UconEnforcer) {
+		u.usageLedger = ledger
+	}
+}
+
+// GetObjectUsageHistory returns who used object within timeRange, when, for
+// how long and under which policy rule, oldest first. Sessions are recorded
+// when they stop, if they were granted.
+func (u *UconEnforcer) GetObjectUsageHistory(object string, timeRange TimeRange) ([]ObjectUsage, error) {
+	return u.usageLedger.ObjectUsage(object, timeRange)
+}
+
+// recordUsage appends a stopped session to the usage ledger.
+func (u *UconEnforcer) recordUsage(session *Session) {
+	grant := session.GetGrantSnapshot()
+	if grant == nil {
+		return
+	}
+	record := session.ToRecord()
+	usage := ObjectUsage{
+		Object:          record.Object,
+		SessionID:       record.ID,
+		Subject:         record.Subject,
+		Action:          record.Action,
+		Purpose:         record.Purpose,
+		StartTime:       grant.GrantedAt,
+		EndTime:         record.EndTime,
+		DurationSeconds: record.EndTime.Sub(grant.GrantedAt).Seconds(),
+		Rule:            record.GrantSource,
+		StopReason:      record.StopReason,
+	}
+	if err := u.usageLedger.AppendUsage(usage); err != nil {
+		u.logger.Log(LevelWarn, "failed to append to the usage ledger", map[string]interface{}{"session": record.ID, "object": record.Object, "error": err.Error()})
+	}
+}
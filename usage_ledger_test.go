@@ -0,0 +1,73 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestObjectUsageHistory(t *testing.T) {
+	uconE := GetUconEnforcer()
+	before := time.Now()
+	granted, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{})
+	if _, err := uconE.EnforceWithSession(granted); err != nil {
+		t.Fatal(err)
+	}
+	ungranted, _ := uconE.CreateSession("bob", "read", "document1", map[string]interface{}{})
+	active, _ := uconE.CreateSession("bob", "read", "document1", map[string]interface{}{})
+	if _, err := uconE.EnforceWithSession(active); err != nil {
+		t.Fatal(err)
+	}
+	_ = uconE.StopMonitoring(granted)
+	_ = uconE.StopMonitoring(ungranted)
+
+	history, err := uconE.GetObjectUsageHistory("document1", TimeRange{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 {
+		t.Fatalf("Expected only the stopped granted session, got %+v", history)
+	}
+	usage := history[0]
+	if usage.SessionID != granted || usage.Subject != "alice" || usage.Action != "read" || !slices.Equal(usage.Rule, []string{"alice", "document1", "read"}) {
+		t.Errorf("Unexpected usage %+v", usage)
+	}
+	if usage.StartTime.Before(before) || usage.EndTime.Before(usage.StartTime) || usage.DurationSeconds < 0 {
+		t.Errorf("Unexpected usage period %+v", usage)
+	}
+
+	future, past := time.Now().Add(time.Hour), before.Add(-time.Hour)
+	if history, _ := uconE.GetObjectUsageHistory("document1", TimeRange{From: &future}); len(history) != 0 {
+		t.Errorf("Expected no usage in the future, got %+v", history)
+	}
+	if history, _ := uconE.GetObjectUsageHistory("document1", TimeRange{To: &past}); len(history) != 0 {
+		t.Errorf("Expected no usage before the session, got %+v", history)
+	}
+	if history, _ := uconE.GetObjectUsageHistory("document2", TimeRange{}); len(history) != 0 {
+		t.Errorf("Expected no usage of another object, got %+v", history)
+	}
+	_ = uconE.StopMonitoring(active)
+	if history, _ := uconE.GetObjectUsageHistory("document1", TimeRange{From: &before}); len(history) != 2 || history[1].SessionID != active {
+		t.Errorf("Expected bob's session to be appended, got %+v", history)
+	}
+
+	if encoded, _ := json.Marshal(TimeRange{To: &past}); strings.Contains(string(encoded), "from") {
+		t.Errorf("Expected the open end to be omitted, got %s", encoded)
+	}
+}
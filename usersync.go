@@ -0,0 +1,256 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// UserDisabledReason is the stop reason of sessions revoked by a UserChange.
+const UserDisabledReason = "user disabled in identity provider"
+
+// UserChange is a change of a user in an identity provider.
+type UserChange struct {
+	// Subject is the session subject the user maps to.
+	Subject string
+	// Attributes are set on all sessions of the subject.
+	Attributes map[string]interface{}
+	// Revoke stops all active sessions of the subject, e.g. because the
+	// user was deactivated or deleted.
+	Revoke bool
+}
+
+// UserFeed delivers user changes from an identity provider. Next blocks until
+// changes are available or ctx is done; it may be backed by polling or by a
+// push subscription such as SCIM events.
+type UserFeed interface {
+	Next(ctx context.Context) ([]UserChange, error)
+}
+
+// UpdateSubjectAttributes sets attributes on every active session of subject
+// and returns the number of sessions updated.
+func (u *UconEnforcer) UpdateSubjectAttributes(subject string, attributes map[string]interface{}) (int, error) {
+	updated := 0
+	for _, session := range u.GetSessions() {
+		if session.GetSubject() != subject || !session.IfActive() {
+			continue
+		}
+		if err := u.setAttributes(session, attributes, true); err != nil {
+			if errors.Is(err, ErrSessionNotActive) {
+				continue
+			}
+			return updated, err
+		}
+		updated++
+	}
+	return updated, nil
+}
+
+// RevokeSubjectSessions stops every active session of subject with reason and
+// returns the number of sessions stopped.
+func (u *UconEnforcer) RevokeSubjectSessions(subject string, reason string) int {
+	revoked := 0
+	for _, session := range u.GetSessions() {
+		if session.GetSubject() == subject && session.IfActive() {
+			if err := session.Stop(reason); err == nil {
+				revoked++
+			}
+		}
+	}
+	return revoked
+}
+
+// ApplyUserChange propagates a user change to the subject's sessions.
+func (u *UconEnforcer) ApplyUserChange(change UserChange) error {
+	if len(change.Attributes) > 0 {
+		if _, err := u.UpdateSubjectAttributes(change.Subject, change.Attributes); err != nil {
+			return err
+		}
+	}
+	if change.Revoke {
+		u.RevokeSubjectSessions(change.Subject, UserDisabledReason)
+	}
+	return nil
+}
+
+// SyncUsers applies the changes delivered by feed until ctx is done. Feed
+// errors are logged and retried after a second.
+func (u *UconEnforcer) SyncUsers(ctx context.Context, feed UserFeed) error {
+	for {
+		changes, err := feed.Next(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			u.logger.Log(LevelWarn, "user feed failed", map[string]interface{}{"error": err.Error()})
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+		for _, change := range changes {
+			if err := u.ApplyUserChange(change); err != nil {
+				u.logger.Log(LevelWarn, "failed to apply user change", map[string]interface{}{
+					"subject": change.Subject, "error": err.Error(),
+				})
+			}
+		}
+	}
+}
+
+// SCIMPoller is a UserFeed that polls a SCIM 2.0 /Users endpoint for users
+// modified since the previous poll.
+type SCIMPoller struct {
+	// BaseURL is the SCIM base URL, e.g. "https://idp.example.com/scim/v2".
+	BaseURL string
+	// Token is sent as a bearer token if not empty.
+	Token string
+	// Interval is the time between polls.
+	Interval time.Duration
+	// Map converts a SCIM user resource to a UserChange; MapSCIMUser if nil.
+	Map func(user map[string]interface{}) (UserChange, bool)
+	// Client sends the requests; a client with a 10s timeout if nil.
+	Client *http.Client
+
+	since  time.Time
+	polled bool
+}
+
+// NewSCIMPoller creates a SCIMPoller. The first poll returns all users.
+func NewSCIMPoller(baseURL string, token string, interval time.Duration) *SCIMPoller {
+	return &SCIMPoller{BaseURL: baseURL, Token: token, Interval: interval}
+}
+
+// Next implements UserFeed.
+func (p *SCIMPoller) Next(ctx context.Context) ([]UserChange, error) {
+	if p.polled {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(p.Interval):
+		}
+	}
+	p.polled = true
+
+	mapUser := p.Map
+	if mapUser == nil {
+		mapUser = MapSCIMUser
+	}
+	var changes []UserChange
+	latest := p.since
+	for start := 1; ; {
+		page, err := p.fetch(ctx, start)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range page.Resources {
+			if change, ok := mapUser(user); ok {
+				changes = append(changes, change)
+			}
+			if meta, ok := user["meta"].(map[string]interface{}); ok {
+				if modified, err := time.Parse(time.RFC3339, fmt.Sprint(meta["lastModified"])); err == nil && modified.After(latest) {
+					latest = modified
+				}
+			}
+		}
+		start += len(page.Resources)
+		if len(page.Resources) == 0 || start > page.TotalResults {
+			break
+		}
+	}
+	p.since = latest
+	return changes, nil
+}
+
+type scimListResponse struct {
+	TotalResults int                      `json:"totalResults"`
+	Resources    []map[string]interface{} `json:"Resources"`
+}
+
+func (p *SCIMPoller) fetch(ctx context.Context, start int) (*scimListResponse, error) {
+	query := url.Values{"startIndex": {strconv.Itoa(start)}, "count": {"100"}}
+	if !p.since.IsZero() {
+		query.Set("filter", fmt.Sprintf("meta.lastModified gt %q", p.since.Format(time.RFC3339)))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.BaseURL+"/Users?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/scim+json")
+	if p.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.Token)
+	}
+
+	client := p.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("SCIM endpoint returned %s", resp.Status)
+	}
+	page := &scimListResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(page); err != nil {
+		return nil, fmt.Errorf("invalid SCIM response: %w", err)
+	}
+	return page, nil
+}
+
+const scimEnterpriseUser = "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"
+
+// MapSCIMUser maps a SCIM user to a UserChange for the subject userName. It
+// sets the "title", "department" and "groups" attributes, and revokes the
+// subject's sessions when the user is not active.
+func MapSCIMUser(user map[string]interface{}) (UserChange, bool) {
+	subject, _ := user["userName"].(string)
+	if subject == "" {
+		return UserChange{}, false
+	}
+	change := UserChange{Subject: subject, Attributes: make(map[string]interface{})}
+	if active, ok := user["active"].(bool); ok && !active {
+		change.Revoke = true
+	}
+	if title, ok := user["title"].(string); ok {
+		change.Attributes["title"] = title
+	}
+	if enterprise, ok := user[scimEnterpriseUser].(map[string]interface{}); ok {
+		if department, ok := enterprise["department"].(string); ok {
+			change.Attributes["department"] = department
+		}
+	}
+	if groups, ok := user["groups"].([]interface{}); ok {
+		names := make([]string, 0, len(groups))
+		for _, group := range groups {
+			if g, ok := group.(map[string]interface{}); ok {
+				names = append(names, fmt.Sprint(g["display"]))
+			}
+		}
+		change.Attributes["groups"] = names
+	}
+	return change, true
+}
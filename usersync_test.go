@@ -0,0 +1,90 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSCIMUserSync(t *testing.T) {
+	var terminated atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer scim-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		user := map[string]interface{}{
+			"userName": "alice",
+			"active":   true,
+			"title":    "Engineer",
+			"groups":   []interface{}{map[string]interface{}{"display": "staff"}},
+			"meta":     map[string]interface{}{"lastModified": "2025-01-01T00:00:00Z"},
+			scimEnterpriseUser: map[string]interface{}{
+				"department": "engineering",
+			},
+		}
+		resources := []interface{}{user}
+		if r.URL.Query().Get("filter") != "" {
+			resources = nil
+			if terminated.Load() {
+				user["active"] = false
+				user["meta"] = map[string]interface{}{"lastModified": "2025-01-02T00:00:00Z"}
+				resources = []interface{}{user}
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"totalResults": len(resources), "Resources": resources})
+	}))
+	defer server.Close()
+
+	uconE := GetUconEnforcer()
+	aliceID, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	bobID, _ := uconE.CreateSession("bob", "read", "document1", nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- uconE.SyncUsers(ctx, NewSCIMPoller(server.URL, "scim-token", 100*time.Millisecond))
+	}()
+
+	time.Sleep(300 * time.Millisecond)
+	alice, _ := uconE.GetSession(aliceID)
+	if alice.GetAttribute("department") != "engineering" || alice.GetAttribute("title") != "Engineer" {
+		t.Errorf("Expected SCIM attributes on alice's session, got %v", alice.ToRecord().Attributes)
+	}
+	if groups, _ := alice.GetAttribute("groups").([]string); len(groups) != 1 || groups[0] != "staff" {
+		t.Errorf("Unexpected groups: %v", alice.GetAttribute("groups"))
+	}
+
+	terminated.Store(true)
+	time.Sleep(300 * time.Millisecond)
+	if alice.IfActive() || alice.GetStopReason() != UserDisabledReason {
+		t.Errorf("Expected alice's session to be revoked, active=%v reason=%q", alice.IfActive(), alice.GetStopReason())
+	}
+	if bob, _ := uconE.GetSession(bobID); !bob.IfActive() {
+		t.Error("Expected bob's session to be unaffected")
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Expected SyncUsers to stop with context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrSessionRejected is wrapped by CreateSession errors for sessions rejected
+// by a SessionValidator.
+var ErrSessionRejected = errors.New("session rejected")
+
+// SessionRequest describes a session about to be created. Validators may
+// modify it, e.g. to normalize attribute values; the session is created from
+// the request as left by the last validator.
+type SessionRequest struct {
+	Subject    string
+	Action     string
+	Object     string
+	Purpose    string
+	Attributes map[string]interface{}
+}
+
+// SessionValidator checks sessions before they are created, e.g. rejecting
+// unknown subjects or requiring mandatory attributes, so bad sessions are
+// refused at creation instead of failing conditions later. An error rejects
+// the session.
+type SessionValidator interface {
+	ValidateSession(request *SessionRequest) error
+}
+
+// SessionValidatorFunc adapts a function to the SessionValidator interface.
+type SessionValidatorFunc func(request *SessionRequest) error
+
+// ValidateSession implements SessionValidator.
+func (f SessionValidatorFunc) ValidateSession(request *SessionRequest) error {
+	return f(request)
+}
+
+// AddSessionValidator appends a validator to the chain run by CreateSession
+// and CreateSessionWithPurpose. Validators run in the order they were added
+// and the first error stops the chain.
+func (u *UconEnforcer) AddSessionValidator(validator SessionValidator) {
+	if validator == nil {
+		return
+	}
+	u.mu.Lock()
+	u.validators = append(u.validators, validator)
+	u.mu.Unlock()
+}
+
+// createSession runs the enrichers, the validators and the attribute schema
+// and creates the session from the resulting request.
+func (u *UconEnforcer) createSession(sub string, act string, obj string, purpose string, attributes map[string]interface{}) (string, error) {
+	request := &SessionRequest{Subject: sub, Action: act, Object: obj, Purpose: purpose, Attributes: attributes}
+	u.mu.RLock()
+	hooks := len(u.enrichers) + len(u.validators)
+	u.mu.RUnlock()
+	if hooks > 0 {
+		// Hooks work on a copy so the caller's map is left untouched.
+		request.Attributes = make(map[string]interface{}, len(attributes))
+		for key, value := range attributes {
+			request.Attributes[key] = value
+		}
+	}
+	u.enrichSession(request)
+	if err := u.validateSession(request); err != nil {
+		return "", err
+	}
+	if err := u.validateNewAttributes(request.Attributes); err != nil {
+		return "", err
+	}
+	if request.Purpose == "" {
+		return u.sessionCreated(u.sessions.CreateSession(request.Subject, request.Action, request.Object, request.Attributes))
+	}
+	return u.sessionCreated(u.sessions.CreateSessionWithPurpose(request.Subject, request.Action, request.Object, request.Purpose, request.Attributes))
+}
+
+func (u *UconEnforcer) validateSession(request *SessionRequest) error {
+	u.mu.RLock()
+	validators := make([]SessionValidator, len(u.validators))
+	copy(validators, u.validators)
+	u.mu.RUnlock()
+
+	for _, validator := range validators {
+		if err := validator.ValidateSession(request); err != nil {
+			u.logger.Log(LevelInfo, "session rejected", map[string]interface{}{
+				"subject": request.Subject,
+				"action":  request.Action,
+				"object":  request.Object,
+				"error":   err.Error(),
+			})
+			return fmt.Errorf("%w: %w", ErrSessionRejected, err)
+		}
+	}
+	return nil
+}
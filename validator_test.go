@@ -0,0 +1,67 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSessionValidators(t *testing.T) {
+	uconE := GetUconEnforcer()
+	uconE.DeclareAttribute(&AttributeSchema{Name: "region", Type: AttributeString, Allowed: []interface{}{"eu", "us"}})
+	var order []string
+	uconE.AddSessionValidator(SessionValidatorFunc(func(req *SessionRequest) error {
+		order = append(order, "known")
+		if req.Subject != "alice" && req.Subject != "bob" {
+			return errors.New("unknown subject " + req.Subject)
+		}
+		return nil
+	}))
+	uconE.AddSessionValidator(SessionValidatorFunc(func(req *SessionRequest) error {
+		order = append(order, "normalize")
+		if region, ok := req.Attributes["region"].(string); ok {
+			req.Attributes["region"] = strings.ToLower(region)
+		}
+		return nil
+	}))
+
+	if _, err := uconE.CreateSession("mallory", "read", "document1", nil); !errors.Is(err, ErrSessionRejected) {
+		t.Errorf("Expected an unknown subject to be rejected, got %v", err)
+	}
+	if len(order) != 1 {
+		t.Errorf("Expected the chain to stop at the first error, ran %v", order)
+	}
+	if len(uconE.GetSessions()) != 0 {
+		t.Error("Expected no session to be created")
+	}
+
+	attributes := map[string]interface{}{"region": "EU"}
+	sessionID, err := uconE.CreateSessionWithPurpose("alice", "read", "document1", "support", attributes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, _ := uconE.GetSession(sessionID)
+	if session.GetAttribute("region") != "eu" || session.GetPurpose() != "support" {
+		t.Errorf("Expected the normalized region and the purpose, got %v and %q", session.GetAttribute("region"), session.GetPurpose())
+	}
+	if attributes["region"] != "EU" {
+		t.Error("Expected the caller's attributes to be left untouched")
+	}
+	if _, err := uconE.CreateSession("bob", "read", "document1", map[string]interface{}{"region": "APAC"}); !errors.Is(err, ErrSchemaViolation) {
+		t.Errorf("Expected the schema to be checked after the validators, got %v", err)
+	}
+}
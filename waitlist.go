@@ -0,0 +1,135 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Events of the CapacityWaitlist waitlist.
+const (
+	// EventWaitlistAdmitted is emitted when a monitoring slot is reserved
+	// for the first session on the waitlist. Data carries the
+	// "reservation" to pass to CommitAccess before it expires.
+	EventWaitlistAdmitted EventType = "waitlist_admitted"
+	// EventWaitlistDropped is emitted when the first session on the
+	// waitlist could not be given a slot, e.g. because its conditions no
+	// longer hold. Data carries the "error".
+	EventWaitlistDropped EventType = "waitlist_dropped"
+)
+
+// ErrWaitlisted is wrapped by the errors of sessions put on the waitlist
+// under CapacityWaitlist.
+var ErrWaitlisted = errors.New("waitlisted for a monitoring slot")
+
+// WaitlistPosition returns the position of a session on the waitlist,
+// starting at 1, or 0 if it is not waiting.
+func (u *UconEnforcer) WaitlistPosition(sessionID string) int {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	for i, id := range u.waitlist {
+		if id == sessionID {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// LeaveWaitlist takes a session off the waitlist. Stopped sessions leave it
+// by themselves.
+func (u *UconEnforcer) LeaveWaitlist(sessionID string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if !u.leaveWaitlistLocked(sessionID) {
+		return fmt.Errorf("session %s is not waitlisted", sessionID)
+	}
+	// The next session may now be first.
+	u.admitWaitlistLocked()
+	return nil
+}
+
+// waitlistLocked puts a session on the waitlist, unless it already is, and
+// returns the error telling its position.
+func (u *UconEnforcer) waitlistLocked(sessionID string) error {
+	position := 0
+	for i, id := range u.waitlist {
+		if id == sessionID {
+			position = i + 1
+			break
+		}
+	}
+	if position == 0 {
+		u.waitlist = append(u.waitlist, sessionID)
+		position = len(u.waitlist)
+	}
+	return fmt.Errorf("%w: %w at position %d", ErrCapacityExceeded, ErrWaitlisted, position)
+}
+
+func (u *UconEnforcer) leaveWaitlistLocked(sessionID string) bool {
+	for i, id := range u.waitlist {
+		if id == sessionID {
+			u.waitlist = append(u.waitlist[:i], u.waitlist[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// admitWaitlistLocked starts admitting waitlisted sessions into free slots,
+// unless that is already under way.
+func (u *UconEnforcer) admitWaitlistLocked() {
+	if u.admittingWaitlist || len(u.waitlist) == 0 {
+		return
+	}
+	u.admittingWaitlist = true
+	go u.admitWaitlist()
+}
+
+// admitWaitlist reserves free monitoring slots for the first sessions on the
+// waitlist, in order, until the slots or the waitlist run out. A session
+// that cannot be reserved is dropped from the waitlist.
+func (u *UconEnforcer) admitWaitlist() {
+	for {
+		u.mu.Lock()
+		if len(u.waitlist) == 0 || (u.maxMonitored > 0 && u.monitoredCountLocked() >= u.maxMonitored) {
+			u.admittingWaitlist = false
+			u.mu.Unlock()
+			return
+		}
+		sessionID := u.waitlist[0]
+		u.mu.Unlock()
+
+		reservationID, err := u.ReserveAccess(sessionID, DefaultReservationTTL)
+		if err == nil {
+			u.emit(Event{
+				Type:      EventWaitlistAdmitted,
+				SessionID: sessionID,
+				Message:   fmt.Sprintf("monitoring slot reserved for waitlisted session %s", sessionID),
+				Data:      map[string]interface{}{"reservation": reservationID},
+			})
+			continue
+		}
+		u.mu.Lock()
+		u.leaveWaitlistLocked(sessionID)
+		u.mu.Unlock()
+		u.emit(Event{
+			Type:      EventWaitlistDropped,
+			SessionID: sessionID,
+			Message:   fmt.Sprintf("waitlisted session %s dropped: %v", sessionID, err),
+			Data:      map[string]interface{}{"error": err.Error()},
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCapacityWaitlist(t *testing.T) {
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithMaxMonitoredSessions(1, CapacityWaitlist))
+	var mu sync.Mutex
+	admitted := make(map[string]string)
+	var dropped []string
+	uconE.AddEventListener(func(event Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch event.Type {
+		case EventWaitlistAdmitted:
+			admitted[event.SessionID] = event.Data["reservation"].(string)
+		case EventWaitlistDropped:
+			dropped = append(dropped, event.SessionID)
+		}
+	})
+
+	holder, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	if _, err := uconE.EnforceWithSession(holder); err != nil {
+		t.Fatal(err)
+	}
+	first, _ := uconE.CreateSession("bob", "read", "document1", nil)
+	second, _ := uconE.CreateSession("alice", "write", "document1", nil)
+	gone, _ := uconE.CreateSession("alice", "read", "document1", nil)
+	for _, id := range []string{first, second, gone} {
+		if _, err := uconE.EnforceWithSession(id); !errors.Is(err, ErrWaitlisted) || !errors.Is(err, ErrCapacityExceeded) {
+			t.Fatalf("Expected %s to be waitlisted, got %v", id, err)
+		}
+	}
+	if uconE.WaitlistPosition(first) != 1 || uconE.WaitlistPosition(second) != 2 || uconE.WaitlistPosition(holder) != 0 {
+		t.Errorf("Expected waitlist positions 1 and 2")
+	}
+	if err := uconE.LeaveWaitlist(gone); err != nil || uconE.WaitlistPosition(gone) != 0 {
+		t.Errorf("Expected the session to leave the waitlist, got %v", err)
+	}
+
+	// bob loses access to document1 while waiting, so alice's write is next.
+	_, _ = uconE.RemovePolicy("bob", "document1", "read")
+	defer func() { _, _ = uconE.AddPolicy("bob", "document1", "read") }()
+	_ = uconE.StopMonitoring(holder)
+	time.Sleep(100 * time.Millisecond)
+
+	mu.Lock()
+	reservationID := admitted[second]
+	if len(dropped) != 1 || dropped[0] != first || reservationID == "" {
+		t.Errorf("Expected bob dropped and alice admitted, got dropped %v and admitted %v", dropped, admitted)
+	}
+	mu.Unlock()
+	if uconE.WaitlistPosition(second) != 0 {
+		t.Errorf("Expected the admitted session to leave the waitlist")
+	}
+	if session, err := uconE.CommitAccess(reservationID); err != nil || session.GetId() != second {
+		t.Errorf("Expected the reservation to be committed, got %v", err)
+	}
+	_ = uconE.StopMonitoring(second)
+}
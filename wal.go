@@ -0,0 +1,475 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+)
+
+type walOp string
+
+const (
+	walCreate walOp = "create"
+	walSet    walOp = "set"
+	walUnset  walOp = "unset"
+	walState  walOp = "state"
+	walRecord walOp = "record"
+	walDelete walOp = "delete"
+)
+
+type walSessionState struct {
+	Active     bool      `json:"active"`
+	Monitored  bool      `json:"monitored"`
+	EndTime    time.Time `json:"end_time"`
+	StopReason string    `json:"stop_reason"`
+}
+
+// walEntry is one entry of the write-ahead log.
+type walEntry struct {
+	Seq       uint64           `json:"seq"`
+	Op        walOp            `json:"op"`
+	SessionID string           `json:"session_id"`
+	Time      time.Time        `json:"time"`
+	Record    *SessionRecord   `json:"record,omitempty"`
+	Key       string           `json:"key,omitempty"`
+	Value     interface{}      `json:"value"`
+	State     *walSessionState `json:"state,omitempty"`
+}
+
+// WALSessionStore is a SessionStore backed by an append-only write-ahead log.
+// Every session mutation (create, attribute update, state change, delete) is
+// appended to the log and synced to disk; the log is replayed when the store
+// is opened. Use Compact to bound the size of the log.
+type WALSessionStore struct {
+	path     string
+	codec    Codec
+	file     *os.File
+	seq      uint64
+	sessions map[string]*SessionRecord
+	mutex    sync.Mutex
+}
+
+// NewWALSessionStore opens the write-ahead log at path, creating it if needed,
+// and replays it. Entries are written as lines of JSON.
+func NewWALSessionStore(path string) (*WALSessionStore, error) {
+	return NewWALSessionStoreWithCodec(path, JSONCodec{})
+}
+
+// NewWALSessionStoreWithCodec is NewWALSessionStore with entries encoded by
+// codec. Entries of codecs other than JSONCodec are preceded by their length
+// as a varint instead of being separated by newlines.
+func NewWALSessionStoreWithCodec(path string, codec Codec) (*WALSessionStore, error) {
+	if codec == nil {
+		return nil, errors.New("codec cannot be nil")
+	}
+	ws := &WALSessionStore{
+		path:     path,
+		codec:    codec,
+		sessions: make(map[string]*SessionRecord),
+	}
+	size, err := ws.replay()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open write-ahead log: %w", err)
+	}
+	// Cut off a torn final entry, so that new entries do not follow it.
+	if err := file.Truncate(size); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to truncate write-ahead log: %w", err)
+	}
+	ws.file = file
+	return ws, nil
+}
+
+// walFrame is an encoded entry of the log and the offset just past it.
+type walFrame struct {
+	data []byte
+	end  int64
+}
+
+// replay rebuilds the in-memory view from the log and returns the length of
+// its intact part. A torn final entry, left by a crash in the middle of an
+// append, is ignored.
+func (ws *WALSessionStore) replay() (int64, error) {
+	data, err := os.ReadFile(ws.path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read write-ahead log: %w", err)
+	}
+
+	var size int64
+	frames := ws.split(data)
+	for i, frame := range frames {
+		if len(bytes.TrimSpace(frame.data)) == 0 {
+			size = frame.end
+			continue
+		}
+		entry := &walEntry{}
+		if err := ws.codec.Unmarshal(frame.data, entry); err != nil {
+			if i == len(frames)-1 {
+				break
+			}
+			return 0, fmt.Errorf("corrupt write-ahead log entry %d: %w", i+1, err)
+		}
+		ws.apply(entry)
+		ws.seq = entry.Seq
+		size = frame.end
+	}
+	return size, nil
+}
+
+// lineFramed reports whether entries are separated by newlines rather than
+// preceded by their length.
+func (ws *WALSessionStore) lineFramed() bool {
+	_, ok := ws.codec.(JSONCodec)
+	return ok
+}
+
+// split splits the log into its encoded entries. An entry cut short, a
+// final line without its newline or a length-prefixed entry shorter than
+// its length, is dropped.
+func (ws *WALSessionStore) split(data []byte) []walFrame {
+	var frames []walFrame
+	var offset int
+	for offset < len(data) {
+		if ws.lineFramed() {
+			n := bytes.IndexByte(data[offset:], '\n')
+			if n < 0 {
+				break
+			}
+			frames = append(frames, walFrame{data: data[offset : offset+n], end: int64(offset + n + 1)})
+			offset += n + 1
+			continue
+		}
+		n, k := binary.Uvarint(data[offset:])
+		if k <= 0 || n > uint64(len(data)-offset-k) {
+			break
+		}
+		start := offset + k
+		offset = start + int(n)
+		frames = append(frames, walFrame{data: data[start:offset], end: int64(offset)})
+	}
+	return frames
+}
+
+// appendEntry appends an encoded entry to buf.
+func (ws *WALSessionStore) appendEntry(buf []byte, entry *walEntry) ([]byte, error) {
+	data, err := ws.codec.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+	if ws.lineFramed() {
+		return append(append(buf, data...), '\n'), nil
+	}
+	return append(binary.AppendUvarint(buf, uint64(len(data))), data...), nil
+}
+
+func (ws *WALSessionStore) apply(entry *walEntry) {
+	if entry.Op == walCreate || entry.Op == walRecord {
+		ws.sessions[entry.SessionID] = copyRecord(entry.Record)
+		return
+	}
+
+	record, ok := ws.sessions[entry.SessionID]
+	if !ok {
+		return
+	}
+	switch entry.Op {
+	case walSet:
+		record.Attributes[entry.Key] = entry.Value
+	case walUnset:
+		delete(record.Attributes, entry.Key)
+	case walState:
+		record.Active = entry.State.Active
+		record.Monitored = entry.State.Monitored
+		record.EndTime = entry.State.EndTime
+		record.StopReason = entry.State.StopReason
+	case walDelete:
+		delete(ws.sessions, entry.SessionID)
+	}
+}
+
+// appendLocked writes entries to the log, syncs it, and applies them.
+func (ws *WALSessionStore) appendLocked(entries []*walEntry) error {
+	if ws.file == nil {
+		return errors.New("write-ahead log is closed")
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var buf []byte
+	now := time.Now()
+	seq := ws.seq
+	for _, entry := range entries {
+		seq++
+		entry.Seq = seq
+		entry.Time = now
+		var err error
+		if buf, err = ws.appendEntry(buf, entry); err != nil {
+			return fmt.Errorf("failed to encode write-ahead log entry: %w", err)
+		}
+	}
+	if _, err := ws.file.Write(buf); err != nil {
+		return fmt.Errorf("failed to append to write-ahead log: %w", err)
+	}
+	if err := ws.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync write-ahead log: %w", err)
+	}
+
+	ws.seq = seq
+	for _, entry := range entries {
+		ws.apply(entry)
+	}
+	return nil
+}
+
+// SaveSession logs the mutations between the previously stored state of the
+// session and record. Changes to fields other than the attributes and the
+// session state, e.g. a suspension or the grant snapshot, are logged as a
+// full record.
+func (ws *WALSessionStore) SaveSession(record *SessionRecord) error {
+	if record == nil {
+		return errors.New("session record cannot be nil")
+	}
+
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	prev, exists := ws.sessions[record.ID]
+	if !exists {
+		return ws.appendLocked([]*walEntry{{Op: walCreate, SessionID: record.ID, Record: copyRecord(record)}})
+	}
+	if !sameRecordFields(prev, record) {
+		return ws.appendLocked([]*walEntry{{Op: walRecord, SessionID: record.ID, Record: copyRecord(record)}})
+	}
+
+	var entries []*walEntry
+	for key, val := range record.Attributes {
+		if old, ok := prev.Attributes[key]; !ok || !reflect.DeepEqual(old, val) {
+			entries = append(entries, &walEntry{Op: walSet, SessionID: record.ID, Key: key, Value: val})
+		}
+	}
+	for key := range prev.Attributes {
+		if _, ok := record.Attributes[key]; !ok {
+			entries = append(entries, &walEntry{Op: walUnset, SessionID: record.ID, Key: key})
+		}
+	}
+	if prev.Active != record.Active || prev.Monitored != record.Monitored ||
+		!prev.EndTime.Equal(record.EndTime) || prev.StopReason != record.StopReason {
+		entries = append(entries, &walEntry{Op: walState, SessionID: record.ID, State: &walSessionState{
+			Active:     record.Active,
+			Monitored:  record.Monitored,
+			EndTime:    record.EndTime,
+			StopReason: record.StopReason,
+		}})
+	}
+	return ws.appendLocked(entries)
+}
+
+// IncrementAttribute atomically increments an integer attribute and logs the new value.
+func (ws *WALSessionStore) IncrementAttribute(id string, key string, delta int64) (int64, error) {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	record, exists := ws.sessions[id]
+	if !exists {
+		return 0, fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+	}
+	val := delta
+	if old, ok := record.Attributes[key]; ok {
+		current, isInt := toInt64(old)
+		if !isInt {
+			return 0, fmt.Errorf("attribute %s of session %s is not an integer", key, id)
+		}
+		val += current
+	}
+	if err := ws.appendLocked([]*walEntry{{Op: walSet, SessionID: id, Key: key, Value: val}}); err != nil {
+		return 0, err
+	}
+	return val, nil
+}
+
+// LoadSessions returns the sessions reconstructed from the log.
+func (ws *WALSessionStore) LoadSessions() ([]*SessionRecord, error) {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	records := make([]*SessionRecord, 0, len(ws.sessions))
+	for _, record := range ws.sessions {
+		records = append(records, copyRecord(record))
+	}
+	return records, nil
+}
+
+// DeleteSession logs the removal of a session.
+func (ws *WALSessionStore) DeleteSession(id string) error {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	if _, exists := ws.sessions[id]; !exists {
+		return nil
+	}
+	return ws.appendLocked([]*walEntry{{Op: walDelete, SessionID: id}})
+}
+
+// Compact rewrites the log so that it holds a single create entry per live
+// session, discarding the history of deleted sessions and superseded updates.
+func (ws *WALSessionStore) Compact() error {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	if ws.file == nil {
+		return errors.New("write-ahead log is closed")
+	}
+
+	tmp := ws.path + ".compact"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to compact write-ahead log: %w", err)
+	}
+	w := bufio.NewWriter(file)
+	var buf []byte
+	now := time.Now()
+	seq := ws.seq
+	for id, record := range ws.sessions {
+		seq++
+		buf, err = ws.appendEntry(buf[:0], &walEntry{Seq: seq, Op: walCreate, SessionID: id, Time: now, Record: record})
+		if err != nil {
+			_ = file.Close()
+			return fmt.Errorf("failed to compact write-ahead log: %w", err)
+		}
+		_, _ = w.Write(buf)
+	}
+	if err := w.Flush(); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to compact write-ahead log: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to compact write-ahead log: %w", err)
+	}
+	_ = file.Close()
+
+	if err := os.Rename(tmp, ws.path); err != nil {
+		return fmt.Errorf("failed to compact write-ahead log: %w", err)
+	}
+	_ = ws.file.Close()
+	ws.file, err = os.OpenFile(ws.path, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to reopen write-ahead log: %w", err)
+	}
+	ws.seq = seq
+	return nil
+}
+
+// Close closes the log file.
+func (ws *WALSessionStore) Close() error {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	if ws.file == nil {
+		return nil
+	}
+	err := ws.file.Close()
+	ws.file = nil
+	return err
+}
+
+// sameRecordFields reports whether two records differ at most in their
+// attributes and the fields logged by a state entry.
+func sameRecordFields(a, b *SessionRecord) bool {
+	return a.Subject == b.Subject && a.Action == b.Action && a.Object == b.Object &&
+		a.Purpose == b.Purpose && a.StartTime.Equal(b.StartTime) &&
+		equalStrings(a.GrantSource, b.GrantSource) && sameGrant(a.Grant, b.Grant) &&
+		equalCounts(a.ObligationAttempts, b.ObligationAttempts) &&
+		a.suspendedUntil().Equal(b.suspendedUntil()) && a.ResumeHash == b.ResumeHash &&
+		a.BreakGlass == b.BreakGlass && a.Fence == b.Fence && sameReview(a.Review, b.Review) &&
+		a.ChannelBinding == b.ChannelBinding
+}
+
+func sameReview(a, b *BreakGlassOutcome) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Reviewer == b.Reviewer && a.ReviewedAt.Equal(b.ReviewedAt) && a.Notes == b.Notes
+}
+
+func sameGrant(a, b *GrantSnapshot) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.GrantedAt.Equal(b.GrantedAt) && equalStrings(a.Rule, b.Rule) &&
+		reflect.DeepEqual(a.Attributes, b.Attributes)
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalCounts(a, b map[string]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if n, ok := b[k]; !ok || n != v {
+			return false
+		}
+	}
+	return true
+}
+
+func copyRecord(record *SessionRecord) *SessionRecord {
+	if record == nil {
+		return nil
+	}
+	c := *record
+	c.Attributes = make(map[string]interface{}, len(record.Attributes))
+	for k, v := range record.Attributes {
+		c.Attributes[k] = v
+	}
+	if record.GrantSource != nil {
+		c.GrantSource = append([]string(nil), record.GrantSource...)
+	}
+	if record.ObligationAttempts != nil {
+		c.ObligationAttempts = make(map[string]int, len(record.ObligationAttempts))
+		for k, v := range record.ObligationAttempts {
+			c.ObligationAttempts[k] = v
+		}
+	}
+	return &c
+}
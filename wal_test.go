@@ -0,0 +1,144 @@
+// Copyright 2025 The casbin Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ucon
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWALSessionStoreReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.wal")
+	store, err := NewWALSessionStore(path)
+	if err != nil {
+		t.Fatalf("Failed to open WAL: %v", err)
+	}
+
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithSessionStore(store))
+	aliceID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{"location": "office"})
+	bobID, _ := uconE.CreateSession("bob", "read", "document1", map[string]interface{}{})
+
+	_ = uconE.UpdateSessionAttribute(aliceID, "location", "home")
+	bob, _ := uconE.GetSession(bobID)
+	_ = bob.Stop("done")
+	_ = uconE.RevokeSession(bobID)
+	_ = store.Close()
+
+	data, _ := os.ReadFile(path)
+	for _, op := range []string{`"op":"create"`, `"op":"set"`, `"op":"state"`, `"op":"delete"`} {
+		if !strings.Contains(string(data), op) {
+			t.Errorf("Expected WAL to contain %s", op)
+		}
+	}
+
+	// A crash in the middle of an append leaves a torn final line.
+	f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+	_, _ = f.WriteString(`{"seq":99,"op":"se`)
+	_ = f.Close()
+
+	reopened, err := NewWALSessionStore(path)
+	if err != nil {
+		t.Fatalf("Failed to replay WAL: %v", err)
+	}
+	// The torn line is cut off, so entries appended after it replay.
+	_, _ = reopened.IncrementAttribute(aliceID, "visits", 1)
+	_ = reopened.Close()
+	reopened, err = NewWALSessionStore(path)
+	if err != nil {
+		t.Fatalf("Failed to replay WAL: %v", err)
+	}
+	defer reopened.Close()
+
+	restarted := NewUconEnforcer(e, WithSessionStore(reopened))
+	alice, err := restarted.GetSession(aliceID)
+	if err != nil {
+		t.Fatalf("Expected session to be replayed: %v", err)
+	}
+	if alice.GetAttribute("location") != "home" || alice.GetAttribute("visits") == nil {
+		t.Errorf("Expected replayed location 'home' and visits, got %v", alice.GetAttribute("location"))
+	}
+	if _, err := restarted.GetSession(bobID); err == nil {
+		t.Error("Expected deleted session not to be replayed")
+	}
+}
+
+func TestWALSessionStoreCompact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.wal")
+	store, _ := NewWALSessionStore(path)
+
+	record := &SessionRecord{ID: "session_1", Subject: "alice", Active: true, Attributes: map[string]interface{}{}}
+	_ = store.SaveSession(record)
+	for i := 0; i < 10; i++ {
+		record.Attributes["count"] = i
+		_ = store.SaveSession(record)
+	}
+	_ = store.SaveSession(&SessionRecord{ID: "session_2", Subject: "bob"})
+	_ = store.DeleteSession("session_2")
+
+	if err := store.Compact(); err != nil {
+		t.Fatalf("Failed to compact WAL: %v", err)
+	}
+	record.Attributes["count"] = 10
+	_ = store.SaveSession(record)
+	_ = store.Close()
+
+	data, _ := os.ReadFile(path)
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Errorf("Expected 2 entries after compaction and one update, got %d", lines)
+	}
+
+	reopened, _ := NewWALSessionStore(path)
+	defer reopened.Close()
+	records, _ := reopened.LoadSessions()
+	if len(records) != 1 || records[0].Attributes["count"] != float64(10) {
+		t.Errorf("Expected compacted session with count 10, got %+v", records)
+	}
+}
+
+func TestWALSessionStoreReplaysSuspension(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.wal")
+	store, _ := NewWALSessionStore(path)
+
+	e := GetUconEnforcer().(*UconEnforcer).Enforcer
+	uconE := NewUconEnforcer(e, WithSessionStore(store))
+	sessionID, _ := uconE.CreateSession("alice", "read", "document1", map[string]interface{}{})
+	token, err := uconE.SuspendSession(sessionID, time.Minute)
+	if err != nil {
+		t.Fatalf("Failed to suspend session: %v", err)
+	}
+	_ = store.Close()
+
+	reopened, err := NewWALSessionStore(path)
+	if err != nil {
+		t.Fatalf("Failed to replay WAL: %v", err)
+	}
+	defer reopened.Close()
+
+	restarted := NewUconEnforcer(e, WithSessionStore(reopened))
+	session, err := restarted.GetSession(sessionID)
+	if err != nil {
+		t.Fatalf("Expected session to be replayed: %v", err)
+	}
+	if !session.IsSuspended() {
+		t.Fatal("Expected replayed session to be suspended")
+	}
+	if _, err := restarted.ResumeSession(token); err != nil {
+		t.Errorf("Expected resume token to survive the replay: %v", err)
+	}
+}